@@ -0,0 +1,136 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/pkg/auditlog"
+)
+
+// AuditConfig configures the local audit log of requests changing
+// something, e. g. creating or deleting a cluster.
+type AuditConfig struct {
+	// FilePath is the file entries are appended to. If empty, or if
+	// FileSystem is nil, requests are not logged.
+	FilePath string
+
+	// FileSystem is the file system the log is stored in.
+	FileSystem afero.Fs
+
+	// Email is the email address of the user, if known.
+	Email string
+
+	// Logger receives a warning if an entry cannot be written.
+	Logger io.Writer
+}
+
+// DefaultAuditConfig is the audit log configuration used by NewWithConfig
+// and NewForEndpoint. The zero value disables the audit log.
+var DefaultAuditConfig AuditConfig
+
+// auditTransport is an http.RoundTripper recording requests changing
+// something in the audit log.
+type auditTransport struct {
+	inner    http.RoundTripper
+	config   AuditConfig
+	endpoint string
+
+	// commandLine is the redacted command line, logged even if
+	// GSCTL_DISABLE_CMDLINE_TRACKING is set, as it's not sent anywhere.
+	commandLine string
+}
+
+// setAudit wraps the given transport in an auditTransport, if the audit log
+// is enabled by the configuration.
+func setAudit(inner http.RoundTripper, endpoint string, config AuditConfig) http.RoundTripper {
+	if config.FilePath == "" || config.FileSystem == nil {
+		return inner
+	}
+
+	args := make([]string, len(os.Args))
+	copy(args, os.Args)
+
+	return &auditTransport{
+		inner:       inner,
+		config:      config,
+		endpoint:    endpoint,
+		commandLine: strings.Join(redactArgs(args), " "),
+	}
+}
+
+// auditConfigForEndpoint returns DefaultAuditConfig with the email address
+// of the user logged in to the given endpoint.
+func auditConfigForEndpoint(endpoint string) AuditConfig {
+	c := DefaultAuditConfig
+	if c.FilePath != "" && config.Config != nil {
+		if e := config.Config.EndpointConfig(endpoint); e != nil {
+			c.Email = e.Email
+		}
+	}
+	return c
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *auditTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !isMutating(r) {
+		return t.inner.RoundTrip(r)
+	}
+
+	resp, err := t.inner.RoundTrip(r)
+
+	entry := auditlog.Entry{
+		Time:        time.Now().UTC(),
+		Endpoint:    t.endpoint,
+		Email:       t.config.Email,
+		CommandLine: t.commandLine,
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		RequestID:   r.Header.Get("X-Request-ID"),
+		Targets:     auditlog.ParseTargets(r.URL.Path),
+	}
+
+	switch {
+	case err != nil:
+		entry.Outcome = auditlog.OutcomeError
+		entry.Error = err.Error()
+	case resp.StatusCode >= http.StatusBadRequest:
+		entry.Outcome = auditlog.OutcomeFailure
+		entry.StatusCode = resp.StatusCode
+	default:
+		entry.Outcome = auditlog.OutcomeSuccess
+		entry.StatusCode = resp.StatusCode
+
+		// Created resources are only known from the Location header.
+		if location, locationErr := url.Parse(resp.Header.Get("Location")); locationErr == nil {
+			entry.Targets = entry.Targets.Merge(auditlog.ParseTargets(location.Path))
+		}
+	}
+
+	writeErr := auditlog.Append(t.config.FileSystem, t.config.FilePath, entry)
+	if writeErr != nil && t.config.Logger != nil {
+		fmt.Fprintf(t.config.Logger, "Warning: could not write to the audit log %s: %s\n", t.config.FilePath, writeErr.Error())
+	}
+
+	return resp, err
+}
+
+// isMutating returns true for requests changing something. Logging in and
+// out is not considered a change.
+func isMutating(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	if r.Method == http.MethodPost && readOnlyPosts[r.URL.Path] {
+		return false
+	}
+	return resourceName(r.URL.Path) != "auth-tokens"
+}
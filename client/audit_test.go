@@ -0,0 +1,83 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/pkg/auditlog"
+)
+
+// TestAudit tests that only requests changing something are logged.
+func TestAudit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v5/clusters/":
+			w.Header().Set("Location", "/v5/clusters/f01r4/")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "f01r4"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v4/auth-tokens/":
+			w.Write([]byte(`{"auth_token": "token"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+	w, err := New(&Configuration{
+		Endpoint: server.URL,
+		Timeout:  5 * time.Second,
+		Audit: AuditConfig{
+			FilePath:   "/config/audit.jsonl",
+			FileSystem: fs,
+			Email:      "user@example.com",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = w.GetClusters(nil)
+	_, _ = w.CreateAuthToken("user@example.com", "secret", nil)
+	owner := "acme"
+	_, err = w.CreateClusterV5(&models.V5AddClusterRequest{Owner: &owner}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	params := w.DefaultAuxiliaryParams()
+	params.RequestID = "some-request-id"
+	_, _ = w.DeleteCluster("abc12", params)
+
+	entries, err := auditlog.Read(fs, "/config/audit.jsonl")
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %#v", entries)
+	}
+
+	created := entries[0]
+	if created.Method != http.MethodPost || created.ClusterID != "f01r4" || created.Outcome != auditlog.OutcomeSuccess || created.StatusCode != http.StatusCreated {
+		t.Errorf("Unexpected entry for cluster creation %#v", created)
+	}
+	if created.Endpoint != server.URL || created.Email != "user@example.com" || created.RequestID == "" || created.CommandLine == "" {
+		t.Errorf("Missing details in entry %#v", created)
+	}
+
+	deleted := entries[1]
+	if deleted.Method != http.MethodDelete || deleted.ClusterID != "abc12" || deleted.Outcome != auditlog.OutcomeFailure || deleted.StatusCode != http.StatusNotFound {
+		t.Errorf("Unexpected entry for cluster deletion %#v", deleted)
+	}
+	if deleted.RequestID != "some-request-id" {
+		t.Errorf("Expected request ID 'some-request-id', got %q", deleted.RequestID)
+	}
+}
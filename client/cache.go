@@ -0,0 +1,457 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/client/clienterror"
+)
+
+// CacheConfig configures the on-disk cache for API responses.
+type CacheConfig struct {
+	// Dir is the directory cached responses are stored in. If empty, or if
+	// FileSystem is nil, responses are not cached.
+	Dir string
+
+	// FileSystem is the file system the cache is stored in.
+	FileSystem afero.Fs
+
+	// TTLs maps request paths to the time a cached response is used without
+	// asking the API. Responses to other GET requests are cached as well,
+	// but revalidated on every use.
+	TTLs map[string]time.Duration
+
+	// Offline makes read requests use cached responses, regardless of their
+	// age, without contacting the API. Other requests fail.
+	Offline bool
+
+	// Logger receives a notice for every response served from the cache in
+	// offline mode.
+	Logger io.Writer
+
+	// Identity identifies the user responses are cached for, e. g. by the
+	// email address. It has to stay the same when an access token gets
+	// refreshed. If empty, responses are keyed by the Authorization header.
+	Identity string
+
+	// MaxAge is the time after which entries not written to are deleted.
+	// Defaults to DefaultCacheMaxAge.
+	MaxAge time.Duration
+}
+
+var (
+	// DefaultCacheTTLs are the TTLs for responses which rarely change.
+	DefaultCacheTTLs = map[string]time.Duration{
+		"/v4/info/":          time.Hour,
+		"/v4/releases/":      time.Hour,
+		"/v4/appcatalogs/":   time.Hour,
+		"/v4/organizations/": 10 * time.Minute,
+	}
+
+	// DefaultCacheMaxAge is the time after which unused entries are
+	// deleted, e. g. those of a previous identity.
+	DefaultCacheMaxAge = 30 * 24 * time.Hour
+
+	// DefaultCacheConfig is the cache configuration used by NewWithConfig
+	// and NewForEndpoint. The zero value disables the cache.
+	DefaultCacheConfig CacheConfig
+
+	// readOnlyPosts are POST requests which don't change anything. They are
+	// cached like GET requests, keyed by the request body.
+	readOnlyPosts = map[string]bool{
+		"/v5/clusters/by_label/": true,
+	}
+)
+
+// cacheEntry is a cached response as stored on disk.
+type cacheEntry struct {
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+
+	// Stored is the time the response was received or last revalidated.
+	Stored time.Time `json:"stored"`
+
+	// Invalidated is set after a request changing the resource.
+	Invalidated bool `json:"invalidated,omitempty"`
+}
+
+// cacheTransport is an http.RoundTripper caching API responses per
+// endpoint and operation.
+type cacheTransport struct {
+	inner  http.RoundTripper
+	config CacheConfig
+
+	// dir is the endpoint specific cache directory.
+	dir string
+
+	// mutex protects reported, pruned and entries on disk.
+	mutex    sync.Mutex
+	reported map[string]bool
+
+	// pruned is set once old entries have been deleted, which happens
+	// with the first write.
+	pruned bool
+}
+
+// setCache wraps the given transport in a cacheTransport, if the cache is
+// enabled by the configuration.
+func setCache(inner http.RoundTripper, endpoint string, config CacheConfig) http.RoundTripper {
+	if config.Dir == "" || config.FileSystem == nil {
+		return inner
+	}
+	if config.MaxAge == 0 {
+		config.MaxAge = DefaultCacheMaxAge
+	}
+
+	return &cacheTransport{
+		inner:    inner,
+		config:   config,
+		dir:      path.Join(config.Dir, hash([]byte(strings.TrimRight(endpoint, "/")))[:16]),
+		reported: map[string]bool{},
+	}
+}
+
+// cacheConfigForEndpoint returns DefaultCacheConfig with the identity of
+// the user of the given endpoint and token. SSO access tokens get
+// refreshed frequently, so the email address, or else the refresh token,
+// identifies SSO users.
+func cacheConfigForEndpoint(endpoint, token string) CacheConfig {
+	c := DefaultCacheConfig
+	if c.Dir == "" || config.Config == nil {
+		return c
+	}
+
+	e := config.Config.EndpointConfig(endpoint)
+	switch {
+	case token != "":
+		c.Identity = "token " + hash([]byte(token))
+	case e == nil || e.Scheme != "Bearer":
+		// The Authorization header is stable.
+	case e.Email != "":
+		c.Identity = "email " + e.Email
+	default:
+		c.Identity = "refresh-token " + hash([]byte(e.RefreshToken))
+	}
+
+	return c
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cacheTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	switch {
+	case r.Method == http.MethodGet, r.Method == http.MethodPost && readOnlyPosts[r.URL.Path]:
+		return t.cachedRoundTrip(r)
+	case r.Method == http.MethodHead, r.Method == http.MethodOptions:
+		if t.config.Offline {
+			return nil, microerror.Mask(clienterror.OfflineNotCachedError)
+		}
+		return t.inner.RoundTrip(r)
+	}
+
+	if t.config.Offline {
+		return nil, microerror.Mask(clienterror.OfflineModificationError)
+	}
+
+	resp, err := t.inner.RoundTrip(r)
+	if err == nil && resp.StatusCode < http.StatusBadRequest {
+		t.invalidate(r.URL.Path)
+	}
+
+	return resp, err
+}
+
+// cachedRoundTrip handles read requests.
+func (t *cacheTransport) cachedRoundTrip(r *http.Request) (*http.Response, error) {
+	key, r, err := cacheKey(r, t.config.Identity)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	entry := t.load(key)
+
+	if t.config.Offline {
+		if entry == nil {
+			return nil, microerror.Mask(clienterror.OfflineNotCachedError)
+		}
+		t.report(entry)
+		return entry.response(r), nil
+	}
+
+	if entry != nil && entry.isFresh(t.config.TTLs[entry.Path], time.Now()) {
+		return entry.response(r), nil
+	}
+
+	if entry != nil {
+		// Let the API tell us whether the cached response is still valid.
+		r = r.Clone(r.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" && r.Header.Get("If-None-Match") == "" {
+			r.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" && r.Header.Get("If-Modified-Since") == "" {
+			r.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.inner.RoundTrip(r)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		entry.Stored = time.Now()
+		entry.Invalidated = false
+		t.store(key, entry)
+
+		return entry.response(r), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.store(key, &cacheEntry{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Stored:     time.Now(),
+	})
+
+	return resp, nil
+}
+
+// report prints the age of a response served in offline mode, once per path.
+func (t *cacheTransport) report(entry *cacheEntry) {
+	if t.config.Logger == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.reported[entry.Path] {
+		return
+	}
+	t.reported[entry.Path] = true
+
+	age := time.Since(entry.Stored).Round(time.Minute)
+	fmt.Fprintf(t.config.Logger, "Offline: using the response to %s %s cached %s ago (%s). The data may be outdated.\n",
+		entry.Method, entry.Path, age, entry.Stored.Format(time.RFC3339))
+}
+
+// load returns the cached entry for the given key, or nil.
+func (t *cacheTransport) load(key string) *cacheEntry {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	data, err := afero.ReadFile(t.config.FileSystem, path.Join(t.dir, key+".json"))
+	if err != nil {
+		return nil
+	}
+
+	entry := &cacheEntry{}
+	err = json.Unmarshal(data, entry)
+	if err != nil {
+		return nil
+	}
+
+	return entry
+}
+
+// store writes an entry. Failures are ignored, as the cache is an
+// optimization only.
+func (t *cacheTransport) store(key string, entry *cacheEntry) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if !t.pruned {
+		t.pruned = true
+		t.prune(time.Now())
+	}
+
+	t.write(key, entry)
+}
+
+// prune deletes entries of all endpoints which have not been written to
+// within the maximum age. As entries get rewritten on revalidation, these
+// belong to identities or endpoints no longer used, or to requests no
+// longer made.
+func (t *cacheTransport) prune(now time.Time) {
+	dirs, err := afero.ReadDir(t.config.FileSystem, t.config.Dir)
+	if err != nil {
+		return
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		dirPath := path.Join(t.config.Dir, dir.Name())
+		files, err := afero.ReadDir(t.config.FileSystem, dirPath)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			if !file.IsDir() && now.Sub(file.ModTime()) > t.config.MaxAge {
+				_ = t.config.FileSystem.Remove(path.Join(dirPath, file.Name()))
+			}
+		}
+	}
+}
+
+func (t *cacheTransport) write(key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	err = t.config.FileSystem.MkdirAll(t.dir, 0700)
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first, so that concurrent gsctl processes
+	// never read a partial entry.
+	filePath := path.Join(t.dir, key+".json")
+	err = afero.WriteFile(t.config.FileSystem, filePath+".tmp", data, 0600)
+	if err != nil {
+		return
+	}
+	_ = t.config.FileSystem.Rename(filePath+".tmp", filePath)
+}
+
+// invalidate marks cached responses of the resource the given path belongs
+// to as invalid, independent of the API version. Changing
+// /v5/clusters/abc12/ e. g. invalidates the cluster list as well as
+// /v4/clusters/abc12/. Creating or deleting an auth token invalidates
+// everything. Other ways of switching identity need no invalidation, as
+// the identity is part of the cache key.
+func (t *cacheTransport) invalidate(requestPath string) {
+	resource := resourceName(requestPath)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	files, err := afero.ReadDir(t.config.FileSystem, t.dir)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		key := strings.TrimSuffix(file.Name(), ".json")
+
+		data, err := afero.ReadFile(t.config.FileSystem, path.Join(t.dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		entry := &cacheEntry{}
+		err = json.Unmarshal(data, entry)
+		if err != nil || entry.Invalidated {
+			continue
+		}
+
+		if resource == "auth-tokens" || resourceName(entry.Path) == resource {
+			entry.Invalidated = true
+			t.write(key, entry)
+		}
+	}
+}
+
+// isFresh returns true if the entry can be used without asking the API.
+func (e *cacheEntry) isFresh(ttl time.Duration, now time.Time) bool {
+	return !e.Invalidated && now.Sub(e.Stored) < ttl
+}
+
+// response creates an HTTP response from the entry.
+func (e *cacheEntry) response(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       r,
+	}
+}
+
+// cacheKey returns the key identifying the operation of a request and the
+// user it is made for. Responses are never shared between identities, so
+// switching identity, e. g. by logging in or via --auth-token, never serves
+// responses fetched for another one. Without an identity, the
+// Authorization header is used instead. As the body of read-only POST
+// requests is part of the key, it is read and replaced in the returned
+// request.
+func cacheKey(r *http.Request, identity string) (string, *http.Request, error) {
+	if identity == "" {
+		identity = r.Header.Get("Authorization")
+	}
+	key := r.Method + " " + r.URL.RequestURI() + " " + hash([]byte(identity))
+
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return "", nil, microerror.Mask(err)
+		}
+
+		r = r.Clone(r.Context())
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		key += " " + hash(body)
+	}
+
+	return hash([]byte(key)), r, nil
+}
+
+// resourceName returns the resource type of an API path, e. g. "clusters"
+// for /v4/clusters/abc12/nodepools/.
+func resourceName(requestPath string) string {
+	parts := strings.Split(strings.Trim(requestPath, "/"), "/")
+	if len(parts) < 2 {
+		return requestPath
+	}
+	return parts[1]
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
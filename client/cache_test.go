@@ -0,0 +1,345 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// cacheTestServer is an API counting requests per path. It sends an ETag
+// and answers matching conditional requests with 304.
+type cacheTestServer struct {
+	requests    map[string]int
+	notModified int
+}
+
+func (s *cacheTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests[r.Method+" "+r.URL.Path]++
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v4/info/":
+		w.Write([]byte(`{"general": {"installation_name": "codename", "provider": "aws"}}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/v4/clusters/":
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			s.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`[{"id": "abc12", "name": "Cached cluster"}]`))
+	case r.Method == http.MethodPost && r.URL.Path == "/v5/clusters/":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "def34"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newCacheTestClient(t *testing.T, url string, fs afero.Fs, offline bool, logger io.Writer) *Wrapper {
+	w, err := New(&Configuration{
+		Endpoint: url,
+		Timeout:  5 * time.Second,
+		Cache: CacheConfig{
+			Dir:        "/cache",
+			FileSystem: fs,
+			TTLs:       DefaultCacheTTLs,
+			Offline:    offline,
+			Logger:     logger,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// TestCache tests TTLs, revalidation and invalidation.
+func TestCache(t *testing.T) {
+	api := &cacheTestServer{requests: map[string]int{}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+	w := newCacheTestClient(t, server.URL, fs, false, nil)
+
+	for i := 0; i < 3; i++ {
+		response, err := w.GetInfo(nil)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+		if response.Payload.General.InstallationName != "codename" {
+			t.Errorf("Unexpected info %#v", response.Payload.General)
+		}
+	}
+	if n := api.requests["GET /v4/info/"]; n != 1 {
+		t.Errorf("Expected info to be fetched once within the TTL, got %d requests", n)
+	}
+
+	// The cluster list has no TTL, so it gets revalidated.
+	for i := 0; i < 2; i++ {
+		response, err := w.GetClusters(nil)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+		if len(response.Payload) != 1 || response.Payload[0].ID != "abc12" {
+			t.Errorf("Unexpected clusters %#v", response.Payload)
+		}
+	}
+	if api.requests["GET /v4/clusters/"] != 2 || api.notModified != 1 {
+		t.Errorf("Expected one full and one conditional request, got %d requests, %d not modified", api.requests["GET /v4/clusters/"], api.notModified)
+	}
+
+	// A new client for the same endpoint uses the cache on disk.
+	w = newCacheTestClient(t, server.URL, fs, false, nil)
+	_, err := w.GetInfo(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if n := api.requests["GET /v4/info/"]; n != 1 {
+		t.Errorf("Expected info to be served from disk, got %d requests", n)
+	}
+
+	// Creating a cluster invalidates cluster responses only.
+	owner := "acme"
+	_, err = w.CreateClusterV5(&models.V5AddClusterRequest{Owner: &owner}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	_, err = w.GetClusters(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	_, err = w.GetInfo(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if n := api.requests["GET /v4/clusters/"]; n != 3 {
+		t.Errorf("Expected the cluster list to be requested again, got %d requests", n)
+	}
+	if n := api.requests["GET /v4/info/"]; n != 1 {
+		t.Errorf("Expected info to stay cached, got %d requests", n)
+	}
+
+	// Other endpoints don't share the cache.
+	otherServer := httptest.NewServer(api)
+	defer otherServer.Close()
+	_, err = newCacheTestClient(t, otherServer.URL, fs, false, nil).GetInfo(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if n := api.requests["GET /v4/info/"]; n != 2 {
+		t.Errorf("Expected info to be requested for the other endpoint, got %d requests", n)
+	}
+}
+
+// TestCacheOffline tests serving cached responses without the API.
+func TestCacheOffline(t *testing.T) {
+	api := &cacheTestServer{requests: map[string]int{}}
+	server := httptest.NewServer(api)
+
+	fs := afero.NewMemMapFs()
+	_, err := newCacheTestClient(t, server.URL, fs, false, nil).GetClusters(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	server.Close()
+
+	logger := &bytes.Buffer{}
+	w := newCacheTestClient(t, server.URL, fs, true, logger)
+
+	for i := 0; i < 2; i++ {
+		response, err := w.GetClusters(nil)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+		if len(response.Payload) != 1 || response.Payload[0].Name != "Cached cluster" {
+			t.Errorf("Unexpected clusters %#v", response.Payload)
+		}
+	}
+	if n := strings.Count(logger.String(), "Offline: using the response to GET /v4/clusters/"); n != 1 {
+		t.Errorf("Expected one staleness notice, got %q", logger.String())
+	}
+
+	_, err = w.GetInfo(nil)
+	if !clienterror.IsOfflineError(err) || err.Error() != "Not available offline" {
+		t.Errorf("Expected offline error for uncached info, got %#v", err)
+	}
+
+	_, err = w.DeleteCluster("abc12", nil)
+	if !clienterror.IsOfflineError(err) || err.Error() != "Not possible offline" {
+		t.Errorf("Expected offline error for deletion, got %#v", err)
+	}
+}
+
+// TestCacheKey tests that read-only POST requests are keyed by body.
+func TestCacheKey(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		r, err := http.NewRequest(http.MethodPost, "https://api.example.com/v5/clusters/by_label/", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	key1, r, err := cacheKey(newRequest(`{"labels": "a=b"}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, _, _ := cacheKey(newRequest(`{"labels": "a=c"}`), "")
+	key3, _, _ := cacheKey(newRequest(`{"labels": "a=b"}`), "")
+
+	if key1 == key2 || key1 != key3 {
+		t.Errorf("Expected keys to depend on the body only")
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	if string(body) != `{"labels": "a=b"}` {
+		t.Errorf("Expected the body to be preserved, got %q", body)
+	}
+}
+
+// TestCacheKeyAuthorization tests that responses are not shared between
+// different auth tokens.
+func TestCacheKeyAuthorization(t *testing.T) {
+	newRequest := func(authorization string) *http.Request {
+		r, err := http.NewRequest(http.MethodGet, "https://api.example.com/v4/organizations/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Authorization", authorization)
+		return r
+	}
+
+	key1, _, _ := cacheKey(newRequest("giantswarm token-a"), "")
+	key2, _, _ := cacheKey(newRequest("giantswarm token-b"), "")
+	key3, _, _ := cacheKey(newRequest("giantswarm token-a"), "")
+
+	if key1 == key2 || key1 != key3 {
+		t.Errorf("Expected keys to depend on the Authorization header")
+	}
+
+	// With an identity, refreshing the access token keeps the key.
+	key1, _, _ = cacheKey(newRequest("Bearer token-a"), "email user@example.com")
+	key2, _, _ = cacheKey(newRequest("Bearer token-b"), "email user@example.com")
+	key3, _, _ = cacheKey(newRequest("Bearer token-a"), "email other@example.com")
+
+	if key1 != key2 || key1 == key3 {
+		t.Errorf("Expected keys to depend on the identity instead of the Authorization header")
+	}
+}
+
+// TestCacheOfflineSSO tests that in offline mode, responses cached with a
+// previous SSO access token are used and an expired token doesn't get
+// refreshed.
+func TestCacheOfflineSSO(t *testing.T) {
+	api := &cacheTestServer{requests: map[string]int{}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, `endpoints:
+  `+server.URL+`:
+    email: user@example.com
+    auth_scheme: Bearer
+    token: expired-token
+    refresh_token: refresh-token
+selected_endpoint: `+server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaultCacheConfig := DefaultCacheConfig
+	defer func() { DefaultCacheConfig = defaultCacheConfig }()
+	DefaultCacheConfig = CacheConfig{
+		Dir:        "/cache",
+		FileSystem: fs,
+		Offline:    true,
+	}
+
+	w, err := NewForEndpoint(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.conf.Cache.Identity != "email user@example.com" {
+		t.Errorf("Expected the email address as identity, got %q", w.conf.Cache.Identity)
+	}
+
+	// Cache the cluster list with a previous access token.
+	onlineCache := w.conf.Cache
+	onlineCache.Offline = false
+	online, err := New(&Configuration{
+		AuthHeaderGetter: func() (string, error) { return "Bearer previous-token", nil },
+		Endpoint:         server.URL,
+		Timeout:          5 * time.Second,
+		Cache:            onlineCache,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = online.GetClusters(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	response, err := w.GetClusters(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if len(response.Payload) != 1 || response.Payload[0].Name != "Cached cluster" {
+		t.Errorf("Unexpected clusters %#v", response.Payload)
+	}
+	if n := api.requests["GET /v4/clusters/"]; n != 1 {
+		t.Errorf("Expected no request in offline mode, got %d requests", n)
+	}
+}
+
+// TestCachePrune tests that entries not written to within the maximum age
+// are deleted.
+func TestCachePrune(t *testing.T) {
+	api := &cacheTestServer{requests: map[string]int{}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+	old := time.Now().Add(-DefaultCacheMaxAge - time.Hour)
+	files := map[string]time.Time{
+		"/cache/0123456789abcdef/old.json":     old,
+		"/cache/0123456789abcdef/old.json.tmp": old,
+		"/cache/fedcba9876543210/old.json":     old,
+		"/cache/fedcba9876543210/recent.json":  time.Now(),
+	}
+	for name, modTime := range files {
+		err := afero.WriteFile(fs, name, []byte(`{}`), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = fs.Chtimes(name, modTime, modTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := newCacheTestClient(t, server.URL, fs, false, nil).GetInfo(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	for name, modTime := range files {
+		exists, _ := afero.Exists(fs, name)
+		if expected := modTime != old; exists != expected {
+			t.Errorf("Expected %s to exist: %v, got %v", name, expected, exists)
+		}
+	}
+}
@@ -5,16 +5,19 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/giantswarm/apiextensions/v2/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/gscliauth/config"
 	gsclient "github.com/giantswarm/gsclientgen/v2/client"
+	"github.com/giantswarm/gsclientgen/v2/client/app_configs"
 	"github.com/giantswarm/gsclientgen/v2/client/apps"
 	"github.com/giantswarm/gsclientgen/v2/client/auth_tokens"
 	"github.com/giantswarm/gsclientgen/v2/client/cluster_labels"
@@ -56,7 +59,8 @@ type Configuration struct {
 	// Endpoint is the base URL of the API.
 	Endpoint string
 
-	// Timeout is the maximum time to wait for API requests to succeed.
+	// Timeout is the maximum time to wait for an API request to succeed.
+	// If requests are retried, it applies to each attempt.
 	Timeout time.Duration
 
 	// UserAgent identifier
@@ -64,6 +68,20 @@ type Configuration struct {
 
 	// ActivityName identifies the user action through the according header.
 	ActivityName string
+
+	// RetryPolicy defines whether and how idempotent requests are retried.
+	// The zero value disables retries.
+	RetryPolicy RetryPolicy
+
+	// RetryLogger, if set, receives a line for every retry.
+	RetryLogger io.Writer
+
+	// Cache configures the cache for API responses. The zero value disables
+	// the cache.
+	Cache CacheConfig
+
+	// Audit configures the local audit log. The zero value disables it.
+	Audit AuditConfig
 }
 
 // Wrapper is the structure holding representing our latest API client.
@@ -120,10 +138,13 @@ func New(conf *Configuration) (*Wrapper, error) {
 		TLSClientConfig: tlsConfig,
 	}
 	transport.Transport = setUserAgent(transport.Transport, conf.UserAgent)
+	transport.Transport = setRetries(transport.Transport, conf.RetryPolicy, conf.Timeout, conf.RetryLogger)
+	transport.Transport = setAudit(transport.Transport, conf.Endpoint, conf.Audit)
+	transport.Transport = setCache(transport.Transport, conf.Endpoint, conf.Cache)
 
 	rawClient := &http.Client{
 		Transport: transport.Transport,
-		Timeout:   conf.Timeout,
+		Timeout:   conf.RetryPolicy.totalTimeout(conf.Timeout),
 	}
 
 	return &Wrapper{
@@ -140,15 +161,68 @@ func New(conf *Configuration) (*Wrapper, error) {
 func NewWithConfig(endpointString, token string) (*Wrapper, error) {
 	endpoint := config.Config.ChooseEndpoint(endpointString)
 	ClientConfig := &Configuration{
-		AuthHeaderGetter: config.Config.AuthHeaderGetter(endpoint, token),
+		AuthHeaderGetter: authHeaderGetter(endpoint, token),
 		Endpoint:         endpoint,
 		Timeout:          20 * time.Second,
 		UserAgent:        config.UserAgent(),
+		RetryPolicy:      DefaultRetryPolicy,
+		RetryLogger:      DefaultRetryLogger,
+		Cache:            cacheConfigForEndpoint(endpoint, token),
+		Audit:            auditConfigForEndpoint(endpoint),
 	}
 
 	return New(ClientConfig)
 }
 
+// NewForEndpoint creates a new client wrapper for the given endpoint URL,
+// using the credentials stored for that endpoint. In contrast to
+// NewWithConfig, the endpoint does not get selected in the configuration,
+// so that several endpoints can be used concurrently.
+func NewForEndpoint(endpointURL string) (*Wrapper, error) {
+	clientConfig := &Configuration{
+		AuthHeaderGetter: authHeaderGetter(endpointURL, ""),
+		Endpoint:         endpointURL,
+		Timeout:          20 * time.Second,
+		UserAgent:        config.UserAgent(),
+		RetryPolicy:      DefaultRetryPolicy,
+		RetryLogger:      DefaultRetryLogger,
+		Cache:            cacheConfigForEndpoint(endpointURL, ""),
+		Audit:            auditConfigForEndpoint(endpointURL),
+	}
+
+	return New(clientConfig)
+}
+
+// authHeaderGetter returns the auth header getter for the given endpoint
+// and token. In offline mode, no request reaches the API, so the stored
+// token is used as it is instead of refreshing an expired SSO token.
+func authHeaderGetter(endpoint, token string) func() (string, error) {
+	if DefaultCacheConfig.Offline {
+		return func() (string, error) {
+			return config.Config.ChooseScheme(endpoint, token) + " " + config.Config.ChooseToken(endpoint, token), nil
+		}
+	}
+
+	return serializedAuthHeaderGetter(config.Config.AuthHeaderGetter(endpoint, token))
+}
+
+// authHeaderMutex serializes calls to auth header getters created from the
+// configuration. These may refresh an SSO token and write the config file,
+// which must not happen concurrently when clients for several endpoints are
+// used in parallel.
+var authHeaderMutex sync.Mutex
+
+// serializedAuthHeaderGetter wraps getter so that only one auth header
+// getter runs at a time.
+func serializedAuthHeaderGetter(getter func() (string, error)) func() (string, error) {
+	return func() (string, error) {
+		authHeaderMutex.Lock()
+		defer authHeaderMutex.Unlock()
+
+		return getter()
+	}
+}
+
 type roundTripperWithUserAgent struct {
 	inner http.RoundTripper
 	Agent string
@@ -253,7 +327,7 @@ func setParams(p *AuxiliaryParams, w *Wrapper, params paramSetter) {
 	// first take client-level config params
 	if w != nil && w.conf != nil {
 		if w.conf.Timeout > 0 {
-			params.SetTimeout(w.conf.Timeout)
+			params.SetTimeout(w.conf.RetryPolicy.totalTimeout(w.conf.Timeout))
 		}
 		if w.commandLine != "" {
 			params.SetXGiantSwarmCmdLine(&w.commandLine)
@@ -283,6 +357,23 @@ func setParams(p *AuxiliaryParams, w *Wrapper, params paramSetter) {
 	}
 }
 
+// newAPIError creates an APIError from err and records the request ID used,
+// so it can be reported to the user.
+func (w *Wrapper) newAPIError(err error, p *AuxiliaryParams) *clienterror.APIError {
+	apiErr := clienterror.New(err)
+	if apiErr == nil {
+		return nil
+	}
+
+	if p != nil && p.RequestID != "" {
+		apiErr.RequestID = p.RequestID
+	} else if w != nil {
+		apiErr.RequestID = w.requestID
+	}
+
+	return apiErr
+}
+
 func getAuthorization(w *Wrapper) (runtime.ClientAuthInfoWriter, error) {
 	authHeader, err := w.conf.AuthHeaderGetter()
 	if err != nil {
@@ -302,7 +393,7 @@ func (w *Wrapper) CreateAuthToken(email, password string, p *AuxiliaryParams) (*
 
 	response, err := w.gsclient.AuthTokens.CreateAuthToken(params, nil)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -316,7 +407,7 @@ func (w *Wrapper) DeleteAuthToken(authToken string, p *AuxiliaryParams) (*auth_t
 
 	response, err := w.gsclient.AuthTokens.DeleteAuthToken(params, httptransport.APIKeyAuth("Authorization", "header", "giantswarm "+authToken))
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -334,7 +425,7 @@ func (w *Wrapper) CreateClusterV4(addClusterRequest *models.V4AddClusterRequest,
 
 	response, err := w.gsclient.Clusters.AddCluster(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -352,7 +443,7 @@ func (w *Wrapper) CreateClusterV5(addClusterRequest *models.V5AddClusterRequest,
 
 	response, err := w.gsclient.Clusters.AddClusterV5(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -370,7 +461,7 @@ func (w *Wrapper) ModifyClusterV4(clusterID string, body *models.V4ModifyCluster
 
 	response, err := w.gsclient.Clusters.ModifyCluster(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -388,7 +479,7 @@ func (w *Wrapper) ModifyClusterV5(clusterID string, body *models.V5ModifyCluster
 
 	response, err := w.gsclient.Clusters.ModifyClusterV5(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -406,7 +497,7 @@ func (w *Wrapper) DeleteCluster(clusterID string, p *AuxiliaryParams) (*clusters
 
 	response, err := w.gsclient.Clusters.DeleteCluster(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -424,7 +515,7 @@ func (w *Wrapper) GetClusters(p *AuxiliaryParams) (*clusters.GetClustersOK, erro
 
 	response, err := w.gsclient.Clusters.GetClusters(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -442,7 +533,7 @@ func (w *Wrapper) GetClusterV4(clusterID string, p *AuxiliaryParams) (*clusters.
 
 	response, err := w.gsclient.Clusters.GetCluster(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -460,7 +551,7 @@ func (w *Wrapper) GetClusterV5(clusterID string, p *AuxiliaryParams) (*clusters.
 
 	response, err := w.gsclient.Clusters.GetClusterV5(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -478,7 +569,7 @@ func (w *Wrapper) CreateNodePool(clusterID string, addNodePoolRequest *models.V5
 
 	response, err := w.gsclient.NodePools.AddNodePool(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -496,7 +587,7 @@ func (w *Wrapper) GetNodePool(clusterID, nodePoolID string, p *AuxiliaryParams)
 
 	response, err := w.gsclient.NodePools.GetNodePool(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -514,7 +605,7 @@ func (w *Wrapper) GetNodePools(clusterID string, p *AuxiliaryParams) (*node_pool
 
 	response, err := w.gsclient.NodePools.GetNodePools(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -532,7 +623,7 @@ func (w *Wrapper) ModifyNodePool(clusterID, nodePoolID string, modifyNodePoolReq
 
 	response, err := w.gsclient.NodePools.ModifyNodePool(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -550,7 +641,7 @@ func (w *Wrapper) DeleteNodePool(clusterID, nodePoolID string, p *AuxiliaryParam
 
 	response, err := w.gsclient.NodePools.DeleteNodePool(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -569,7 +660,7 @@ func (w *Wrapper) GetDefaultCluster(p *AuxiliaryParams) (string, error) {
 
 	response, err := w.gsclient.Clusters.GetClusters(params, authWriter)
 	if err != nil {
-		return "", clienterror.New(err)
+		return "", w.newAPIError(err, p)
 	}
 
 	if len(response.Payload) == 1 {
@@ -591,7 +682,7 @@ func (w *Wrapper) CreateKeyPair(clusterID string, addKeyPairRequest *models.V4Ad
 
 	response, err := w.gsclient.KeyPairs.AddKeyPair(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -609,7 +700,7 @@ func (w *Wrapper) GetKeyPairs(clusterID string, p *AuxiliaryParams) (*key_pairs.
 
 	response, err := w.gsclient.KeyPairs.GetKeyPairs(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -627,7 +718,7 @@ func (w *Wrapper) GetInfo(p *AuxiliaryParams) (*info.GetInfoOK, error) {
 
 	response, err := w.gsclient.Info.GetInfo(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -645,7 +736,7 @@ func (w *Wrapper) GetReleases(p *AuxiliaryParams) (*releases.GetReleasesOK, erro
 
 	response, err := w.gsclient.Releases.GetReleases(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -663,7 +754,7 @@ func (w *Wrapper) GetOrganizations(p *AuxiliaryParams) (*organizations.GetOrgani
 
 	response, err := w.gsclient.Organizations.GetOrganizations(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -681,7 +772,7 @@ func (w *Wrapper) GetCredential(organizationID string, credentialID string, p *A
 
 	response, err := w.gsclient.Organizations.GetCredential(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -699,7 +790,7 @@ func (w *Wrapper) SetCredentials(organizationID string, addCredentialsRequest *m
 
 	response, err := w.gsclient.Organizations.AddCredentials(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -717,7 +808,7 @@ func (w *Wrapper) GetClusterStatus(clusterID string, p *AuxiliaryParams) (*Clust
 
 	response, err := w.gsclient.Clusters.GetClusterStatus(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	m, err := json.Marshal(response.Payload)
@@ -749,7 +840,7 @@ func (w *Wrapper) CreateApp(clusterID string, appName string, addAppRequest *mod
 
 	response, err := w.gsclient.Apps.CreateClusterAppV4(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -769,7 +860,7 @@ func (w *Wrapper) GetApp(clusterID string, appName string, p *AuxiliaryParams) (
 
 	response, err := w.gsclient.Apps.GetClusterAppsV4(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	apps := response.Payload
@@ -797,7 +888,7 @@ func (w *Wrapper) GetAppStatus(clusterID string, appName string, p *AuxiliaryPar
 
 	response, err := w.gsclient.Apps.GetClusterAppsV4(params, authWriter)
 	if err != nil {
-		return "", clienterror.New(err)
+		return "", w.newAPIError(err, p)
 	}
 
 	// type V4GetClusterAppsResponse []*V4GetClusterAppsResponseItems
@@ -824,7 +915,7 @@ func (w *Wrapper) DeleteApp(clusterID string, appName string, p *AuxiliaryParams
 
 	response, err := w.gsclient.Apps.DeleteClusterAppV4(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -843,7 +934,79 @@ func (w *Wrapper) ModifyApp(clusterID string, appName string, body *models.V4Mod
 
 	response, err := w.gsclient.Apps.ModifyClusterAppV4(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
+	}
+
+	return response, nil
+}
+
+// GetApps fetches the list of apps installed in a cluster using the gsclientgen client.
+func (w *Wrapper) GetApps(clusterID string, p *AuxiliaryParams) (*apps.GetClusterAppsV4OK, error) {
+	params := apps.NewGetClusterAppsV4Params().WithClusterID(clusterID)
+	setParams(p, w, params)
+
+	authWriter, err := getAuthorization(w)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	response, err := w.gsclient.Apps.GetClusterAppsV4(params, authWriter)
+	if err != nil {
+		return nil, w.newAPIError(err, p)
+	}
+
+	return response, nil
+}
+
+// CreateAppConfig creates the user values config map for an app.
+func (w *Wrapper) CreateAppConfig(clusterID string, appName string, body models.V4CreateAppConfigRequest, p *AuxiliaryParams) (*app_configs.CreateClusterAppConfigV4OK, error) {
+	params := app_configs.NewCreateClusterAppConfigV4Params().WithClusterID(clusterID).WithAppName(appName).WithBody(body)
+	setParams(p, w, params)
+
+	authWriter, err := getAuthorization(w)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	response, err := w.gsclient.AppConfigs.CreateClusterAppConfigV4(params, authWriter)
+	if err != nil {
+		return nil, w.newAPIError(err, p)
+	}
+
+	return response, nil
+}
+
+// ModifyAppConfig modifies the user values config map of an app.
+func (w *Wrapper) ModifyAppConfig(clusterID string, appName string, body models.V4CreateAppConfigRequest, p *AuxiliaryParams) (*app_configs.ModifyClusterAppConfigV4OK, error) {
+	params := app_configs.NewModifyClusterAppConfigV4Params().WithClusterID(clusterID).WithAppName(appName).WithBody(body)
+	setParams(p, w, params)
+
+	authWriter, err := getAuthorization(w)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	response, err := w.gsclient.AppConfigs.ModifyClusterAppConfigV4(params, authWriter)
+	if err != nil {
+		return nil, w.newAPIError(err, p)
+	}
+
+	return response, nil
+}
+
+// DeleteAppConfig deletes the user values config map of an app.
+func (w *Wrapper) DeleteAppConfig(clusterID string, appName string, p *AuxiliaryParams) (*app_configs.DeleteClusterAppConfigV4OK, error) {
+	params := app_configs.NewDeleteClusterAppConfigV4Params().WithClusterID(clusterID).WithAppName(appName)
+	setParams(p, w, params)
+
+	authWriter, err := getAuthorization(w)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	response, err := w.gsclient.AppConfigs.DeleteClusterAppConfigV4(params, authWriter)
+	if err != nil {
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -861,7 +1024,7 @@ func (w *Wrapper) UpdateClusterLabels(clusterID string, body *models.V5SetCluste
 
 	response, err := w.gsclient.ClusterLabels.SetClusterLabels(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	return response, nil
@@ -879,7 +1042,7 @@ func (w *Wrapper) GetClustersByLabel(body *models.V5ListClustersByLabelRequest,
 
 	response, err := w.gsclient.Clusters.GetV5ClustersByLabel(params, authWriter)
 	if err != nil {
-		return nil, clienterror.New(err)
+		return nil, w.newAPIError(err, p)
 	}
 
 	// wrap this into a GetClustersOK to be compatible with GetClusters
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -163,6 +164,48 @@ func TestTimeout(t *testing.T) {
 	}
 }
 
+// TestSerializedAuthHeaderGetter tests that auth header getters used by
+// several goroutines never run concurrently.
+func TestSerializedAuthHeaderGetter(t *testing.T) {
+	var mutex sync.Mutex
+	running := 0
+	maxRunning := 0
+
+	getter := serializedAuthHeaderGetter(func() (string, error) {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+
+		return "Bearer token", nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			header, err := getter()
+			if err != nil || header != "Bearer token" {
+				t.Errorf("Unexpected result %q, %#v", header, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning != 1 {
+		t.Errorf("Expected getters to run one at a time, got %d concurrently", maxRunning)
+	}
+}
+
 // TestUserAgent tests whether our user-agent header appears in requests.
 func TestUserAgent(t *testing.T) {
 	clientConfig := &Configuration{
@@ -296,6 +339,33 @@ func TestAuxiliaryParams(t *testing.T) { // Our test server.
 	gsClient.CreateAuthToken("foo", "bar", ap)
 }
 
+// TestAPIErrorRequestID checks whether API errors carry the request ID sent.
+func TestAPIErrorRequestID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found"}`))
+	}))
+	defer ts.Close()
+
+	gsClient, err := New(&Configuration{Endpoint: ts.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ap := gsClient.DefaultAuxiliaryParams()
+	ap.RequestID = "request-id"
+
+	_, err = gsClient.DeleteAuthToken("foo", ap)
+	clientAPIError, ok := err.(*clienterror.APIError)
+	if !ok {
+		t.Fatalf("Expected *clienterror.APIError, got %#v", err)
+	}
+	if clientAPIError.RequestID != "request-id" {
+		t.Errorf("Expected request ID 'request-id', got %q", clientAPIError.RequestID)
+	}
+}
+
 // TestCreateAuthToken checks out how creating an auth token works in
 // our new client.
 func TestCreateAuthToken(t *testing.T) { // Our test server.
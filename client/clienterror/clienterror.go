@@ -9,8 +9,11 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/giantswarm/microerror"
 	"github.com/go-openapi/runtime"
 
+	"github.com/giantswarm/gsclientgen/v2/client/app_configs"
+	"github.com/giantswarm/gsclientgen/v2/client/apps"
 	"github.com/giantswarm/gsclientgen/v2/client/auth_tokens"
 	"github.com/giantswarm/gsclientgen/v2/client/clusters"
 	"github.com/giantswarm/gsclientgen/v2/client/info"
@@ -49,6 +52,21 @@ type APIError struct {
 
 	// IsTemporary will be true if we think that a retry will help.
 	IsTemporary bool
+
+	// RequestID is the X-Request-ID sent with the request, if known.
+	RequestID string
+}
+
+// OfflineNotCachedError is returned by the response cache in offline mode
+// for a read request without a cached response.
+var OfflineNotCachedError = &microerror.Error{
+	Kind: "OfflineNotCachedError",
+}
+
+// OfflineModificationError is returned by the response cache in offline
+// mode for a request that would change something.
+var OfflineModificationError = &microerror.Error{
+	Kind: "OfflineModificationError",
 }
 
 // Error returns the error message and allows us to use our APIError
@@ -472,6 +490,13 @@ func New(err error) *APIError {
 			ErrorMessage:   "Not yet available",
 			ErrorDetails:   "It is not yet possible to create a key pair for this cluster. Please try again in a moment.",
 		}
+	} else if createKeyPairDefaultErr, ok := err.(*key_pairs.AddKeyPairDefault); ok {
+		return &APIError{
+			HTTPStatusCode: createKeyPairDefaultErr.Code(),
+			OriginalError:  createKeyPairDefaultErr,
+			ErrorMessage:   createKeyPairDefaultErr.Error(),
+			ErrorDetails:   createKeyPairDefaultErr.Payload.Message,
+		}
 	}
 
 	// get key pairs
@@ -574,6 +599,176 @@ func New(err error) *APIError {
 		}
 	}
 
+	// get apps
+	if getAppsUnauthorizedErr, ok := err.(*apps.GetClusterAppsV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  getAppsUnauthorizedErr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to list the apps of this cluster.",
+		}
+	}
+	if getAppsDefaultErr, ok := err.(*apps.GetClusterAppsV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: getAppsDefaultErr.Code(),
+			OriginalError:  getAppsDefaultErr,
+			ErrorMessage:   getAppsDefaultErr.Error(),
+			ErrorDetails:   getAppsDefaultErr.Payload.Message,
+		}
+	}
+
+	// create app
+	if createAppBadRequestErr, ok := err.(*apps.CreateClusterAppV4BadRequest); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusBadRequest,
+			OriginalError:  createAppBadRequestErr,
+			ErrorMessage:   "Invalid parameters",
+			ErrorDetails:   "The app cannot be installed. Some parameter(s) are considered invalid.\nDetails: " + createAppBadRequestErr.Payload.Message,
+		}
+	}
+	if createAppUnauthorizedErr, ok := err.(*apps.CreateClusterAppV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  createAppUnauthorizedErr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to install apps in this cluster.",
+		}
+	}
+	if createAppConflictErr, ok := err.(*apps.CreateClusterAppV4Conflict); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusConflict,
+			OriginalError:  createAppConflictErr,
+			ErrorMessage:   "App already exists",
+			ErrorDetails:   "An app with this name is already installed in the cluster. Use 'gsctl update app' to modify it.",
+		}
+	}
+	if createAppDefaultErr, ok := err.(*apps.CreateClusterAppV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: createAppDefaultErr.Code(),
+			OriginalError:  createAppDefaultErr,
+			ErrorMessage:   createAppDefaultErr.Error(),
+			ErrorDetails:   createAppDefaultErr.Payload.Message,
+		}
+	}
+
+	// modify app
+	if modifyAppBadRequestErr, ok := err.(*apps.ModifyClusterAppV4BadRequest); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusBadRequest,
+			OriginalError:  modifyAppBadRequestErr,
+			ErrorMessage:   "Invalid parameters",
+			ErrorDetails:   "The app cannot be modified. Some parameter(s) are considered invalid.\nDetails: " + modifyAppBadRequestErr.Payload.Message,
+		}
+	}
+	if modifyAppUnauthorizedErr, ok := err.(*apps.ModifyClusterAppV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  modifyAppUnauthorizedErr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to modify apps in this cluster.",
+		}
+	}
+	if modifyAppNotFoundErr, ok := err.(*apps.ModifyClusterAppV4NotFound); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusNotFound,
+			OriginalError:  modifyAppNotFoundErr,
+			ErrorMessage:   "Not found",
+			ErrorDetails:   "The cluster or app was not found or you don't have access to it.",
+		}
+	}
+	if modifyAppDefaultErr, ok := err.(*apps.ModifyClusterAppV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: modifyAppDefaultErr.Code(),
+			OriginalError:  modifyAppDefaultErr,
+			ErrorMessage:   modifyAppDefaultErr.Error(),
+			ErrorDetails:   modifyAppDefaultErr.Payload.Message,
+		}
+	}
+
+	// delete app
+	if deleteAppUnauthorizedErr, ok := err.(*apps.DeleteClusterAppV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  deleteAppUnauthorizedErr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to delete apps in this cluster.",
+		}
+	}
+	if deleteAppNotFoundErr, ok := err.(*apps.DeleteClusterAppV4NotFound); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusNotFound,
+			OriginalError:  deleteAppNotFoundErr,
+			ErrorMessage:   "Not found",
+			ErrorDetails:   "The cluster or app was not found or you don't have access to it.",
+		}
+	}
+	if deleteAppDefaultErr, ok := err.(*apps.DeleteClusterAppV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: deleteAppDefaultErr.Code(),
+			OriginalError:  deleteAppDefaultErr,
+			ErrorMessage:   deleteAppDefaultErr.Error(),
+			ErrorDetails:   deleteAppDefaultErr.Payload.Message,
+		}
+	}
+
+	// create/modify app config
+	if createAppConfigBadRequestErr, ok := err.(*app_configs.CreateClusterAppConfigV4BadRequest); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusBadRequest,
+			OriginalError:  createAppConfigBadRequestErr,
+			ErrorMessage:   "Invalid values",
+			ErrorDetails:   "The user values for the app could not be stored.\nDetails: " + createAppConfigBadRequestErr.Payload.Message,
+		}
+	}
+	if createAppConfigUnauthorizedErr, ok := err.(*app_configs.CreateClusterAppConfigV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  createAppConfigUnauthorizedErr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to configure apps in this cluster.",
+		}
+	}
+	if createAppConfigConflictErr, ok := err.(*app_configs.CreateClusterAppConfigV4Conflict); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusConflict,
+			OriginalError:  createAppConfigConflictErr,
+			ErrorMessage:   "App config already exists",
+			ErrorDetails:   "The app already has user values configured.",
+		}
+	}
+	if createAppConfigDefaultErr, ok := err.(*app_configs.CreateClusterAppConfigV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: createAppConfigDefaultErr.Code(),
+			OriginalError:  createAppConfigDefaultErr,
+			ErrorMessage:   createAppConfigDefaultErr.Error(),
+			ErrorDetails:   createAppConfigDefaultErr.Payload.Message,
+		}
+	}
+	if modifyAppConfigBadRequestErr, ok := err.(*app_configs.ModifyClusterAppConfigV4BadRequest); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusBadRequest,
+			OriginalError:  modifyAppConfigBadRequestErr,
+			ErrorMessage:   "Invalid values",
+			ErrorDetails:   "The user values for the app could not be stored.\nDetails: " + modifyAppConfigBadRequestErr.Payload.Message,
+		}
+	}
+	if modifyAppConfigUnauthorizedErr, ok := err.(*app_configs.ModifyClusterAppConfigV4Unauthorized); ok {
+		return &APIError{
+			HTTPStatusCode: http.StatusUnauthorized,
+			OriginalError:  modifyAppConfigUnauthorizedErr,
+			ErrorMessage:   "Unauthorized",
+			ErrorDetails:   "You don't have permission to configure apps in this cluster.",
+		}
+	}
+	if modifyAppConfigDefaultErr, ok := err.(*app_configs.ModifyClusterAppConfigV4Default); ok {
+		return &APIError{
+			HTTPStatusCode: modifyAppConfigDefaultErr.Code(),
+			OriginalError:  modifyAppConfigDefaultErr,
+			ErrorMessage:   modifyAppConfigDefaultErr.Error(),
+			ErrorDetails:   modifyAppConfigDefaultErr.Payload.Message,
+		}
+	}
+
 	// HTTP level error cases
 	if runtimeAPIError, ok := err.(*runtime.APIError); ok {
 		ae := &APIError{
@@ -615,6 +810,24 @@ func New(err error) *APIError {
 			HTTPMethod:    urlError.Op,
 		}
 
+		// Offline mode
+		switch microerror.Cause(urlError.Err) {
+		case OfflineNotCachedError:
+			ae.OriginalError = urlError.Err
+			ae.ErrorMessage = "Not available offline"
+			ae.ErrorDetails = "There is no cached response for this request. Please execute the command\n"
+			ae.ErrorDetails += "without --offline once while you have access to the API."
+
+			return ae
+		case OfflineModificationError:
+			ae.OriginalError = urlError.Err
+			ae.ErrorMessage = "Not possible offline"
+			ae.ErrorDetails = "Changes can only be made while you have access to the API.\n"
+			ae.ErrorDetails += "Please execute the command without --offline."
+
+			return ae
+		}
+
 		// Timeout / context deadline exceeded
 		if urlError.Err == context.DeadlineExceeded {
 			ae.IsTimeout = true
@@ -132,3 +132,18 @@ func IsCertificateInvalidError(err error) bool {
 
 	return false
 }
+
+// IsOfflineError checks whether the error was caused by a request
+// the response cache could not serve in offline mode.
+func IsOfflineError(err error) bool {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		apiErr, ok = microerror.Cause(err).(*APIError)
+	}
+	if !ok {
+		return false
+	}
+
+	cause := microerror.Cause(apiErr.OriginalError)
+	return cause == OfflineNotCachedError || cause == OfflineModificationError
+}
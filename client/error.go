@@ -1,11 +1,8 @@
 package client
 
 import (
-	"fmt"
 	"net/http"
-	"os"
 
-	"github.com/fatih/color"
 	"github.com/giantswarm/microerror"
 
 	"github.com/giantswarm/gsctl/client/clienterror"
@@ -61,14 +58,10 @@ func IsParseError(err error) bool {
 	return microerror.Cause(err) == ParseError
 }
 
-// HandleErrors handles the errors known to this package.
-// Handling normally means printing a user-readable error message
-// and exiting with code 1. If the given error is not recognized,
-// the function returns without action.
-func HandleErrors(err error) {
-
-	var headline = ""
-	var subtext = ""
+// ErrorMessage returns a user-readable headline and subtext for the errors
+// known to this package. If the given error is not recognized, the headline
+// is empty.
+func ErrorMessage(err error) (headline, subtext string) {
 	var httpStatusCode int
 	var message string
 	var details string
@@ -108,13 +101,5 @@ func HandleErrors(err error) {
 		headline = message
 	}
 
-	if headline == "" {
-		return
-	}
-
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	return headline, subtext
 }
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client/clienterror"
+)
+
+// TestErrorMessage tests the messages returned for errors known to the client.
+func TestErrorMessage(t *testing.T) {
+	var testCases = []struct {
+		err              error
+		expectedHeadline string
+		expectedSubtext  string
+	}{
+		{errors.New("unknown"), "", ""},
+		{microerror.Mask(endpointNotSpecifiedError), "No endpoint has been specified.", "Please use the '-e|--endpoint' flag or select an endpoint using 'gsctl select endpoint'."},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusInternalServerError, ErrorMessage: "Boom", ErrorDetails: "Details"}, "An internal error occurred.", "Details"},
+		{microerror.Mask(&clienterror.APIError{HTTPStatusCode: http.StatusBadRequest, ErrorMessage: "Bad", ErrorDetails: "Details"}), "Bad", "Details"},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusNotFound, ErrorMessage: "Not found"}, "Not found", ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			headline, subtext := ErrorMessage(tc.err)
+			if headline != tc.expectedHeadline || subtext != tc.expectedSubtext {
+				t.Errorf("Expected (%q, %q), got (%q, %q)", tc.expectedHeadline, tc.expectedSubtext, headline, subtext)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy defines how often and how fast failed requests are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	// Zero disables retries.
+	MaxRetries int
+
+	// InitialBackoff is the time to wait before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper limit for the time to wait between attempts,
+	// also when the server asks for a longer wait via Retry-After.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor the backoff grows by with every retry.
+	Multiplier float64
+}
+
+var (
+	// DefaultRetryPolicy is the retry policy used by NewWithConfig and NewForEndpoint.
+	DefaultRetryPolicy = RetryPolicy{
+		MaxRetries:     0,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+	}
+
+	// DefaultRetryLogger is where NewWithConfig and NewForEndpoint clients
+	// report retries. If nil, retries are not reported.
+	DefaultRetryLogger io.Writer
+)
+
+// backoff returns the time to wait before the given retry (starting at 1).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		d *= p.Multiplier
+		if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+
+	return time.Duration(d)
+}
+
+// totalTimeout returns the time all attempts of a request may take, given
+// the timeout for a single attempt. Zero means no timeout.
+func (p RetryPolicy) totalTimeout(attemptTimeout time.Duration) time.Duration {
+	if attemptTimeout == 0 || p.MaxRetries < 1 {
+		return attemptTimeout
+	}
+
+	total := time.Duration(p.MaxRetries+1) * attemptTimeout
+	for retry := 1; retry <= p.MaxRetries; retry++ {
+		total += p.backoff(retry)
+	}
+
+	return total
+}
+
+// retryTransport is an http.RoundTripper retrying idempotent requests
+// according to a RetryPolicy. As the very same request is sent again,
+// all attempts carry the same X-Request-ID header.
+type retryTransport struct {
+	inner   http.RoundTripper
+	policy  RetryPolicy
+	timeout time.Duration
+	logger  io.Writer
+}
+
+// setRetries wraps the given transport in a retryTransport, if retries are
+// enabled by the policy. timeout limits each attempt, so that a hanging
+// attempt leaves time for retries. The overall timeout of the request has to
+// allow for all attempts, see RetryPolicy.totalTimeout.
+func setRetries(inner http.RoundTripper, policy RetryPolicy, timeout time.Duration, logger io.Writer) http.RoundTripper {
+	if policy.MaxRetries < 1 {
+		return inner
+	}
+
+	return &retryTransport{
+		inner:   inner,
+		policy:  policy,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	for retry := 1; ; retry++ {
+		resp, err := t.roundTripAttempt(r)
+
+		if retry > t.policy.MaxRetries || !isRetryable(r, resp, err) {
+			return resp, err
+		}
+
+		// Requests with a body can only be retried if the body can be re-created.
+		if r.Body != nil && r.Body != http.NoBody {
+			if r.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := r.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			r = r.Clone(r.Context())
+			r.Body = body
+		}
+
+		wait := t.policy.backoff(retry)
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				wait = retryAfter
+				if t.policy.MaxBackoff > 0 && wait > t.policy.MaxBackoff {
+					wait = t.policy.MaxBackoff
+				}
+			}
+
+			// Drain and close the body, so that the connection can be reused.
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if t.logger != nil {
+			fmt.Fprintf(t.logger, "%s %s (request ID %s) failed: %s. Retrying in %s (retry %d of %d).\n",
+				r.Method, r.URL.Path, r.Header.Get("X-Request-ID"), reason, wait, retry, t.policy.MaxRetries)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return nil, r.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// roundTripAttempt sends the request once, limited to the attempt timeout.
+// The timeout covers reading the response body, too.
+func (t *retryTransport) roundTripAttempt(r *http.Request) (*http.Response, error) {
+	if t.timeout == 0 {
+		return t.inner.RoundTrip(r)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), t.timeout)
+	resp, err := t.inner.RoundTrip(r.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// cancelOnClose releases the context of an attempt when its response body
+// is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer.
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// isRetryable decides whether a request can be retried safely, based on
+// its method and outcome.
+//
+// GET, HEAD and OPTIONS requests are retried on connection errors and on
+// the status codes 429, 502, 503, and 504. DELETE requests are only retried
+// on 429 and 503, as in these cases the request has not been processed.
+// Other methods are never retried.
+func isRetryable(r *http.Request, resp *http.Response, err error) bool {
+	if r.Context().Err() != nil {
+		return false
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		if err != nil {
+			return true
+		}
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	case http.MethodDelete:
+		if err != nil {
+			return false
+		}
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseRetryAfter interprets the value of a Retry-After header, which can
+// either be a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		d := date.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
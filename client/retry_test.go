@@ -0,0 +1,240 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsctl/client/clienterror"
+)
+
+// TestRetries tests that idempotent requests are retried with the same request ID,
+// and others are not.
+func TestRetries(t *testing.T) {
+	var testCases = []struct {
+		method           string
+		statusCodes      []int
+		expectedAttempts int
+		expectedStatus   int
+	}{
+		// GET succeeding after two 503 responses.
+		{http.MethodGet, []int{503, 503, 200}, 3, 200},
+		// GET failing after all retries.
+		{http.MethodGet, []int{502, 502, 502, 502}, 4, 502},
+		// GET with a non-retryable error.
+		{http.MethodGet, []int{500, 200}, 1, 500},
+		// DELETE is retried on 503.
+		{http.MethodDelete, []int{503, 202}, 2, 202},
+		// DELETE is not retried on 502, as it may have been processed.
+		{http.MethodDelete, []int{502, 202}, 1, 502},
+		// POST is never retried.
+		{http.MethodPost, []int{503, 201}, 1, 503},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			attempts := 0
+			requestIDs := map[string]bool{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestIDs[r.Header.Get("X-Request-ID")] = true
+				status := tc.statusCodes[attempts]
+				attempts++
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			logger := &bytes.Buffer{}
+			transport := setRetries(http.DefaultTransport, RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, Multiplier: 2}, 0, logger)
+
+			req, err := http.NewRequest(tc.method, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("X-Request-ID", "some-request-id")
+
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("Unexpected error %#v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, resp.StatusCode)
+			}
+			if attempts != tc.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tc.expectedAttempts, attempts)
+			}
+			if len(requestIDs) != 1 || !requestIDs["some-request-id"] {
+				t.Errorf("Expected all attempts to use the same request ID, got %v", requestIDs)
+			}
+			if retries := strings.Count(logger.String(), "Retrying"); retries != tc.expectedAttempts-1 {
+				t.Errorf("Expected %d retries to be logged, got %d:\n%s", tc.expectedAttempts-1, retries, logger.String())
+			}
+		})
+	}
+}
+
+// TestRetryTimeout tests that the timeout applies to each attempt, and that
+// Retry-After can't make us wait longer than the maximum backoff.
+func TestRetryTimeout(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			// Hangs longer than the attempt timeout.
+			time.Sleep(500 * time.Millisecond)
+		case 2:
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, Multiplier: 2}
+	transport := setRetries(http.DefaultTransport, policy, 200*time.Millisecond, nil)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	defer resp.Body.Close()
+
+	// The body can still be read after RoundTrip returned.
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil || string(body) != "ok" {
+		t.Errorf("Expected body 'ok', got %q, %v", body, err)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Expected retries to be fast, took %s", d)
+	}
+}
+
+// TestRetriesWithClient tests that the client wrapper retries API calls
+// when configured to do so.
+func TestRetriesWithClient(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code": "UNAVAILABLE", "message": "Try again"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	clientWrapper, err := New(&Configuration{
+		Endpoint:    server.URL,
+		RetryPolicy: RetryPolicy{MaxRetries: 2, InitialBackoff: time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// As Retry-After is 0, the huge initial backoff must not be applied.
+	_, err = clientWrapper.GetClusters(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+
+	// Without retries, the first error is returned.
+	attempts = 0
+	clientWrapper, err = New(&Configuration{Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = clientWrapper.GetClusters(nil)
+	if !clienterror.IsServiceUnavailableError(err) {
+		t.Errorf("Expected service unavailable error, got %#v", err)
+	}
+}
+
+// TestParseRetryAfter tests parsing of Retry-After header values.
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2020 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 Jan 2020 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			d, ok := parseRetryAfter(tc.value, now)
+			if d != tc.expected || ok != tc.ok {
+				t.Errorf("Expected (%s, %v), got (%s, %v)", tc.expected, tc.ok, d, ok)
+			}
+		})
+	}
+}
+
+// TestBackoff tests the exponential backoff calculation.
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, e := range expected {
+		if got := p.backoff(i + 1); got != e {
+			t.Errorf("Retry %d: expected %s, got %s", i+1, e, got)
+		}
+	}
+}
+
+// TestTotalTimeout tests the overall timeout of a request with retries.
+func TestTotalTimeout(t *testing.T) {
+	var testCases = []struct {
+		policy   RetryPolicy
+		timeout  time.Duration
+		expected time.Duration
+	}{
+		// No retries.
+		{RetryPolicy{}, 20 * time.Second, 20 * time.Second},
+		// No timeout.
+		{RetryPolicy{MaxRetries: 3, InitialBackoff: time.Second}, 0, 0},
+		// Three attempts with two waits in between.
+		{RetryPolicy{MaxRetries: 2, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}, 20 * time.Second, 63 * time.Second},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if got := tc.policy.totalTimeout(tc.timeout); got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
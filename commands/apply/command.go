@@ -0,0 +1,289 @@
+// Package apply implements the "apply" command.
+package apply
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+)
+
+var (
+	// Command is the cobra command for 'gsctl apply'
+	Command = &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a cluster definition to an existing cluster",
+		Long: `Bring an existing cluster in line with a cluster definition file.
+
+The definition uses the same v5 format as 'gsctl create cluster -f'. The
+cluster is identified by the 'name' and 'owner' given in the definition,
+so these must match exactly one cluster.
+
+The following attributes are compared with the live cluster:
+
+- release_version: the cluster is upgraded if the version differs.
+- master_nodes.high_availability: the cluster is switched to high-availability
+  masters if requested. Switching back to a single master is not possible.
+- labels: labels are added, changed, or removed. Labels set to an empty value
+  are removed. Labels containing 'giantswarm.io' are only touched if they
+  appear in the definition.
+- nodepools: node pools are matched by name. Missing node pools are created,
+  scaling settings of existing ones are modified, and node pools not mentioned
+  in the definition are deleted. Other node pool attributes can't be changed
+  and are only reported.
+
+Sections omitted from the definition are left untouched. Before making any
+change, the planned changes are printed and confirmation is required.
+
+Examples:
+
+  gsctl apply -f my-cluster.yaml
+
+  gsctl apply -f my-cluster.yaml --dry-run
+
+  cat my-cluster.yaml | gsctl apply -f - --force
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "apply"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.InputYAMLFile, "file", "f", "", "Path to a v5 cluster definition YAML file. Use '-' to read from STDIN.")
+	Command.Flags().BoolVarP(&flags.DryRun, "dry-run", "", false, "Only print the planned changes, don't apply them.")
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required (risky!).")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	DryRun            bool
+	FileSystem        afero.Fs
+	Force             bool
+	InputYAMLFile     string
+	UserProvidedToken string
+	Verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments() Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		DryRun:            flags.DryRun,
+		FileSystem:        config.FileSystem,
+		Force:             flags.Force,
+		InputYAMLFile:     flags.InputYAMLFile,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+	}
+}
+
+// result is what we return from our business function.
+type result struct {
+	plan    *clusterdefinition.Plan
+	applied bool
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.InputYAMLFile == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--file")
+	}
+	if args.InputYAMLFile == "-" && !args.Force && !args.DryRun {
+		// STDIN is used for the definition, so it can't be used for confirmation.
+		return microerror.Maskf(errors.ConflictingFlagsError, "reading the definition from STDIN requires --force or --dry-run")
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+
+	if err == nil {
+		return
+	}
+
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsConflictingFlagsError(err):
+		headline = "Conflicting flags used"
+		subtext = "When reading the definition from STDIN, please use --force or --dry-run."
+	default:
+		headline = err.Error()
+	}
+
+	// print output
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
+}
+
+// applyDefinition is our business function. It determines the changes needed
+// and applies them after confirmation.
+func applyDefinition(args Arguments) (*result, error) {
+	def, err := clusterdefinition.ReadV5(args.FileSystem, args.InputYAMLFile)
+	switch {
+	case clusterdefinition.IsDefinitionNotReadable(err):
+		return nil, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
+	case clusterdefinition.IsClusterNameMissing(err):
+		return nil, microerror.Maskf(errors.ClusterNameOrIDMissingError, err.Error())
+	case clusterdefinition.IsClusterOwnerMissing(err):
+		return nil, microerror.Mask(errors.ClusterOwnerMissingError)
+	case err != nil:
+		return nil, microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	if args.Verbose {
+		fmt.Println(color.WhiteString("Looking up cluster '%s' owned by '%s'", def.Name, def.Owner))
+	}
+	cluster, nodePools, err := clusterdefinition.LookupCluster(def, clientWrapper, auxParams)
+	if clusterdefinition.IsClusterNotFound(err) {
+		return nil, microerror.Maskf(errors.ClusterNotFoundError, err.Error())
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	p, err := clusterdefinition.CreatePlan(def, cluster, nodePools)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r := &result{plan: p}
+
+	if p.IsEmpty() {
+		return r, nil
+	}
+
+	fmt.Printf("Planned changes for cluster '%s' (%s):\n\n", p.ClusterName, p.ClusterID)
+	fmt.Println(clusterdefinition.FormatPlan(p))
+	fmt.Println()
+
+	if args.DryRun {
+		return r, nil
+	}
+
+	if !args.Force {
+		confirmed := confirm.Ask("Do you want to apply these changes?")
+		if !confirmed {
+			return nil, microerror.Mask(errors.CommandAbortedError)
+		}
+	}
+
+	err = executePlan(p, clientWrapper, auxParams, args.Verbose)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r.applied = true
+
+	return r, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	r, err := applyDefinition(arguments)
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		headline := ""
+		subtext := ""
+
+		switch {
+		case clusterdefinition.IsNotV5Definition(err):
+			headline = "Unsupported definition format"
+			subtext = "Only v5 cluster definitions (containing 'api_version: v5') can be applied."
+		case clusterdefinition.IsClusterNotV5(err):
+			headline = "Cluster not supported"
+			subtext = "Only clusters supporting node pools can be managed via 'gsctl apply'."
+		case errors.IsClusterNotFoundError(err):
+			headline = "Cluster not found"
+			subtext = fmt.Sprintf("%s. To create the cluster, use 'gsctl create cluster -f %s'.", err.Error(), arguments.InputYAMLFile)
+		case clusterdefinition.IsMultipleClustersFound(err):
+			headline = "Cluster name is ambiguous"
+			subtext = err.Error()
+		case clusterdefinition.IsNodePoolNameMissing(err):
+			headline = "Node pool name missing"
+			subtext = "Every node pool in the definition needs a name, so it can be matched with an existing node pool."
+		case clusterdefinition.IsDuplicateNodePoolName(err):
+			headline = "Duplicate node pool name"
+			subtext = err.Error()
+		case clusterdefinition.IsRevertHAMasterNotAllowed(err):
+			headline = "Operation not permitted"
+			subtext = "It is not possible to change from multiple master nodes to a single master."
+		case errors.IsCommandAbortedError(err):
+			headline = "Not applying any changes."
+		default:
+			headline = err.Error()
+		}
+
+		// print output
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
+	}
+
+	switch {
+	case r.plan.IsEmpty():
+		fmt.Println(color.GreenString("Cluster '%s' (%s) is up to date.", r.plan.ClusterName, r.plan.ClusterID))
+	case r.applied:
+		fmt.Println(color.GreenString("Changes to cluster '%s' (%s) have been applied.", r.plan.ClusterName, r.plan.ClusterID))
+	default:
+		fmt.Println("Dry run, no changes have been applied.")
+	}
+
+	if r.plan.IsEmpty() {
+		// Otherwise, warnings have been printed as part of the plan.
+		for _, w := range r.plan.Warnings {
+			fmt.Println(color.YellowString("Warning: %s", w))
+		}
+	}
+}
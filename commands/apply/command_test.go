@@ -0,0 +1,211 @@
+package apply
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// configYAML is a mock configuration used by some of the tests.
+const configYAML = `last_version_check: 0001-01-01T00:00:00Z
+endpoints:
+  https://foo:
+    email: email@example.com
+    token: some-token
+    provider: aws
+selected_endpoint: https://foo
+updated: 2017-09-29T11:23:15+02:00
+`
+
+// Test_verifyPreconditions tests the checks happening before any API call.
+func Test_verifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			Arguments{APIEndpoint: "https://foo", AuthToken: "token", InputYAMLFile: "cluster.yaml"},
+			nil,
+		},
+		{
+			Arguments{AuthToken: "token", InputYAMLFile: "cluster.yaml"},
+			errors.IsEndpointMissingError,
+		},
+		{
+			Arguments{APIEndpoint: "https://foo", InputYAMLFile: "cluster.yaml"},
+			errors.IsNotLoggedInError,
+		},
+		{
+			Arguments{APIEndpoint: "https://foo", AuthToken: "token"},
+			errors.IsRequiredFlagMissingError,
+		},
+		{
+			Arguments{APIEndpoint: "https://foo", AuthToken: "token", InputYAMLFile: "-"},
+			errors.IsConflictingFlagsError,
+		},
+		{
+			Arguments{APIEndpoint: "https://foo", AuthToken: "token", InputYAMLFile: "-", DryRun: true},
+			nil,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil {
+				if err != nil {
+					t.Errorf("Case %d - Unexpected error %#v", i, err)
+				}
+			} else if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+			}
+		})
+	}
+}
+
+// Test_applyDefinition tests the full flow against a mock API.
+func Test_applyDefinition(t *testing.T) {
+	definitionYAML := `api_version: v5
+name: My cluster
+owner: acme
+release_version: 11.1.0
+labels:
+  environment: production
+nodepools:
+- name: general
+  scaling:
+    min: 2
+    max: 5
+- name: gpu
+  node_spec:
+    aws:
+      instance_type: p3.2xlarge
+`
+
+	var lock sync.Mutex
+	var requests []string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		lock.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		lock.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "f01r4", "name": "My cluster", "owner": "acme", "release_version": "11.0.0"},
+				{"id": "abc12", "name": "My cluster", "owner": "other-org", "release_version": "11.0.0"}
+			]`))
+		case "GET /v5/clusters/f01r4/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "f01r4", "name": "My cluster", "owner": "acme", "release_version": "11.0.0",
+				"master_nodes": {"high_availability": true},
+				"labels": {"environment": "testing", "giantswarm.io/cluster": "f01r4"}}`))
+		case "GET /v5/clusters/f01r4/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "a7rc4", "name": "general", "scaling": {"min": 1, "max": 3}},
+				{"id": "6feel", "name": "batch", "scaling": {"min": 3, "max": 10}}
+			]`))
+		case "PUT /v5/clusters/f01r4/labels/":
+			if string(body) != `{"labels":{"environment":"production"}}`+"\n" {
+				t.Errorf("Unexpected labels request body %q", string(body))
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"labels": {"environment": "production"}}`))
+		case "PATCH /v5/clusters/f01r4/":
+			if string(body) != `{"release_version":"11.1.0"}`+"\n" {
+				t.Errorf("Unexpected cluster modification request body %q", string(body))
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "f01r4", "release_version": "11.1.0"}`))
+		case "POST /v5/clusters/f01r4/nodepools/":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "n3wnp", "name": "gpu"}`))
+		case "PATCH /v5/clusters/f01r4/nodepools/a7rc4/":
+			if string(body) != `{"scaling":{"max":5,"min":2}}`+"\n" {
+				t.Errorf("Unexpected node pool modification request body %q", string(body))
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "a7rc4", "name": "general", "scaling": {"min": 2, "max": 5}}`))
+		case "DELETE /v5/clusters/f01r4/nodepools/6feel/":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"code": "RESOURCE_DELETION_STARTED", "message": "Deletion started"}`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = afero.WriteFile(fs, "/cluster.yaml", []byte(definitionYAML), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		APIEndpoint:   mockServer.URL,
+		AuthToken:     "token",
+		FileSystem:    fs,
+		Force:         true,
+		InputYAMLFile: "/cluster.yaml",
+	}
+
+	r, err := applyDefinition(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	if !r.applied {
+		t.Error("Expected changes to be applied")
+	}
+
+	expectedRequests := []string{
+		"GET /v4/clusters/",
+		"GET /v5/clusters/f01r4/",
+		"GET /v5/clusters/f01r4/nodepools/",
+		"PUT /v5/clusters/f01r4/labels/",
+		"PATCH /v5/clusters/f01r4/",
+		"POST /v5/clusters/f01r4/nodepools/",
+		"PATCH /v5/clusters/f01r4/nodepools/a7rc4/",
+		"DELETE /v5/clusters/f01r4/nodepools/6feel/",
+	}
+	if diff := cmp.Diff(expectedRequests, requests); diff != "" {
+		t.Errorf("Requests unequal. (-expected +got):\n%s", diff)
+	}
+
+	// Dry run must not send modifying requests.
+	requests = nil
+	args.DryRun = true
+	args.Force = false
+
+	r, err = applyDefinition(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if r.applied {
+		t.Error("Expected no changes to be applied in dry run")
+	}
+	if len(requests) != 3 {
+		t.Errorf("Expected 3 requests in dry run, got %v", requests)
+	}
+}
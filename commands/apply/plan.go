@@ -0,0 +1,88 @@
+package apply
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+)
+
+// executePlan sends the requests needed to apply the plan. It stops at the
+// first failing request.
+func executePlan(p *clusterdefinition.Plan, clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams, verbose bool) error {
+	if len(p.LabelChanges) > 0 {
+		request := &models.V5SetClusterLabelsRequest{Labels: map[string]*string{}}
+		for _, l := range p.LabelChanges {
+			request.Labels[l.Key] = l.To
+		}
+
+		if verbose {
+			fmt.Println(color.WhiteString("Updating cluster labels"))
+		}
+		_, err := clientWrapper.UpdateClusterLabels(p.ClusterID, request, auxParams)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	if p.ReleaseTo != "" || p.EnableHAMasters {
+		request := &models.V5ModifyClusterRequest{
+			ReleaseVersion: p.ReleaseTo,
+		}
+		if p.EnableHAMasters {
+			request.MasterNodes = &models.V5ModifyClusterRequestMasterNodes{HighAvailability: true}
+		}
+
+		if verbose {
+			fmt.Println(color.WhiteString("Modifying cluster"))
+		}
+		_, err := clientWrapper.ModifyClusterV5(p.ClusterID, request, auxParams)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	for _, np := range p.NodePoolsToCreate {
+		if verbose {
+			fmt.Println(color.WhiteString("Creating node pool '%s'", np.Name))
+		}
+		_, err := clientWrapper.CreateNodePool(p.ClusterID, clusterdefinition.AddNodePoolRequest(np), auxParams)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	for _, np := range p.NodePoolsToModify {
+		minTo := np.MinTo
+		request := &models.V5ModifyNodePoolRequest{
+			Scaling: &models.V5ModifyNodePoolRequestScaling{
+				Min: &minTo,
+				Max: np.MaxTo,
+			},
+		}
+
+		if verbose {
+			fmt.Println(color.WhiteString("Modifying node pool '%s' (%s)", np.Name, np.ID))
+		}
+		_, err := clientWrapper.ModifyNodePool(p.ClusterID, np.ID, request, auxParams)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	for _, np := range p.NodePoolsToDelete {
+		if verbose {
+			fmt.Println(color.WhiteString("Deleting node pool '%s' (%s)", np.Name, np.ID))
+		}
+		_, err := clientWrapper.DeleteNodePool(p.ClusterID, np.ID, auxParams)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+// Package auth holds the 'auth *' sub-commands.
+package auth
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/auth/refresh"
+	"github.com/giantswarm/gsctl/commands/auth/status"
+)
+
+var (
+	// Command is the command to inspect and manage auth tokens.
+	Command = &cobra.Command{
+		Use:   "auth",
+		Short: "Inspect and refresh authentication",
+		Long:  `Inspect and refresh the authentication tokens stored for your API endpoints.`,
+	}
+)
+
+func init() {
+	Command.AddCommand(refresh.Command)
+	Command.AddCommand(status.Command)
+}
@@ -0,0 +1,252 @@
+// Package refresh implements the 'auth refresh' sub-command.
+package refresh
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gscliauth/oidc"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/authtoken"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl auth refresh'
+	Command = &cobra.Command{
+		Use:   "refresh",
+		Short: "Refresh SSO tokens",
+		Long: `Obtains a new access token for endpoints you logged in to via SSO.
+
+Access tokens obtained via SSO expire after a while. gsctl refreshes them
+automatically when they are used after expiry. This command refreshes them
+proactively, e. g. before running a long series of commands in a pipeline,
+using the refresh token stored with the endpoint.
+
+By default the selected endpoint is refreshed. Use --all to refresh all
+endpoints using SSO.
+
+Examples:
+
+  gsctl auth refresh
+
+  gsctl auth refresh --endpoint api.example.com
+
+  gsctl auth refresh --all
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	cmdAll bool
+
+	arguments Arguments
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().BoolVarP(&cmdAll, "all", "", false, "Refresh the tokens of all endpoints using SSO.")
+}
+
+// refreshFunc obtains a new access token using a refresh token. It returns
+// the new access token and the email address from the new ID token.
+type refreshFunc func(refreshToken string) (accessToken string, email string, err error)
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint string
+	all         bool
+	refresh     refreshFunc
+	verbose     bool
+}
+
+// endpointResult is the outcome of refreshing the token of one endpoint.
+type endpointResult struct {
+	endpoint string
+	alias    string
+	email    string
+	expiry   string
+	err      error
+}
+
+func collectArguments() Arguments {
+	return Arguments{
+		apiEndpoint: config.Config.ChooseEndpoint(flags.APIEndpoint),
+		all:         cmdAll,
+		refresh:     refreshOIDC,
+		verbose:     flags.Verbose,
+	}
+}
+
+// refreshOIDC refreshes a token against our identity provider.
+func refreshOIDC(refreshToken string) (string, string, error) {
+	response, err := oidc.RefreshToken(refreshToken)
+	if err != nil {
+		return "", "", microerror.Mask(err)
+	}
+
+	idToken, err := oidc.ParseIDToken(response.IDToken)
+	if err != nil {
+		return "", "", microerror.Mask(err)
+	}
+
+	return response.AccessToken, idToken.Email, nil
+}
+
+func verifyPreconditions(args Arguments) error {
+	if flags.Token != "" {
+		return microerror.Mask(errors.TokenArgumentNotApplicableError)
+	}
+	if args.all {
+		if flags.APIEndpoint != "" {
+			return microerror.Maskf(errors.ConflictingFlagsError, "the flags --all and --endpoint cannot be combined")
+		}
+		return nil
+	}
+	if args.apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+
+	endpointConfig := config.Config.EndpointConfig(args.apiEndpoint)
+	if endpointConfig == nil || endpointConfig.Token == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if endpointConfig.Scheme != authtoken.SchemeBearer || endpointConfig.RefreshToken == "" {
+		return microerror.Maskf(errors.NoRefreshTokenError, "endpoint %s", args.apiEndpoint)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsTokenArgumentNotApplicableError(err):
+		headline = "The '--auth-token' flag cannot be used with the 'gsctl auth refresh' command."
+	case errors.IsConflictingFlagsError(err):
+		headline = "Conflicting flags used"
+		subtext = "When using --all, --endpoint must not be given."
+	case errors.IsNoRefreshTokenError(err):
+		headline = "No refresh token available"
+		subtext = fmt.Sprintf("Only tokens obtained via SSO can be refreshed. To authenticate for %s again, use 'gsctl login'.", arguments.apiEndpoint)
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
+}
+
+// refreshEndpoints returns the endpoints to refresh, sorted by URL.
+func refreshEndpoints(args Arguments) []string {
+	if !args.all {
+		return []string{args.apiEndpoint}
+	}
+
+	endpoints := []string{}
+	for _, endpoint := range config.Config.Endpoints() {
+		endpointConfig := config.Config.EndpointConfig(endpoint)
+		if endpointConfig != nil && endpointConfig.Scheme == authtoken.SchemeBearer && endpointConfig.RefreshToken != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	sort.Strings(endpoints)
+
+	return endpoints
+}
+
+// refreshTokens refreshes the tokens of the endpoints and stores the new
+// access tokens in the config, the same way the SSO login does.
+func refreshTokens(args Arguments) []endpointResult {
+	results := []endpointResult{}
+
+	for _, endpoint := range refreshEndpoints(args) {
+		endpointConfig := config.Config.EndpointConfig(endpoint)
+		r := endpointResult{
+			endpoint: endpoint,
+			alias:    endpointConfig.Alias,
+		}
+
+		if args.verbose {
+			fmt.Println(color.WhiteString("Refreshing token for endpoint %s", endpoint))
+		}
+
+		accessToken, email, err := args.refresh(endpointConfig.RefreshToken)
+		if err != nil {
+			r.err = microerror.Mask(err)
+			results = append(results, r)
+			continue
+		}
+
+		err = config.Config.StoreEndpointAuth(endpoint, endpointConfig.Alias, endpointConfig.Provider, email, authtoken.SchemeBearer, accessToken, endpointConfig.RefreshToken)
+		if err != nil {
+			r.err = microerror.Mask(err)
+			results = append(results, r)
+			continue
+		}
+
+		r.email = email
+		if expiry, ok := authtoken.Expiry(accessToken); ok {
+			r.expiry = util.ShortDate(expiry.UTC())
+		}
+		results = append(results, r)
+	}
+
+	return results
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	results := refreshTokens(arguments)
+
+	if len(results) == 0 {
+		fmt.Println("No endpoints using SSO found, so there is nothing to refresh.")
+		return
+	}
+
+	var lastErr error
+	for _, r := range results {
+		name := r.endpoint
+		if r.alias != "" {
+			name = fmt.Sprintf("%s (%s)", r.endpoint, r.alias)
+		}
+
+		if r.err != nil {
+			lastErr = r.err
+			fmt.Printf("%s Could not refresh the token for %s: %s\n", color.RedString("✗"), name, r.err.Error())
+			continue
+		}
+
+		if r.expiry != "" {
+			fmt.Printf("%s Refreshed the token for %s, logged in as %s. The new token expires %s.\n", color.GreenString("✓"), name, r.email, r.expiry)
+		} else {
+			fmt.Printf("%s Refreshed the token for %s, logged in as %s.\n", color.GreenString("✓"), name, r.email)
+		}
+	}
+
+	if lastErr != nil {
+		fmt.Println()
+		fmt.Println("To authenticate again, use 'gsctl login --sso'.")
+		errors.Exit(lastErr)
+	}
+}
@@ -0,0 +1,136 @@
+package refresh
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+const configYAML = `endpoints:
+  https://sso.example.com:
+    alias: sso
+    email: user@example.com
+    auth_scheme: Bearer
+    token: old-access-token
+    refresh_token: the-refresh-token
+  https://broken.example.com:
+    alias: broken
+    email: user@example.com
+    auth_scheme: Bearer
+    token: old-access-token
+    refresh_token: revoked-refresh-token
+  https://password.example.com:
+    email: user@example.com
+    token: some-token
+selected_endpoint: https://sso.example.com
+`
+
+var newAccessToken = testutils.JWT(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+
+func fakeRefresh(refreshToken string) (string, string, error) {
+	if refreshToken != "the-refresh-token" {
+		return "", "", microerror.Maskf(errors.SSOError, "refresh token revoked")
+	}
+	return newAccessToken, "new@example.com", nil
+}
+
+// TestVerifyPreconditions tests the validation of arguments.
+func TestVerifyPreconditions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{Arguments{apiEndpoint: "https://sso.example.com"}, nil},
+		{Arguments{all: true}, nil},
+		{Arguments{apiEndpoint: "https://password.example.com"}, errors.IsNoRefreshTokenError},
+		{Arguments{apiEndpoint: "https://unknown.example.com"}, errors.IsNotLoggedInError},
+		{Arguments{apiEndpoint: ""}, errors.IsEndpointMissingError},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil {
+				if err != nil {
+					t.Errorf("Case %d - Unexpected error: %#v", i, err)
+				}
+			} else if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+			}
+		})
+	}
+}
+
+// TestRefreshSelected tests refreshing the token of one endpoint.
+func TestRefreshSelected(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := refreshTokens(Arguments{apiEndpoint: "https://sso.example.com", refresh: fakeRefresh})
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].err != nil {
+		t.Fatalf("Unexpected error: %#v", results[0].err)
+	}
+	if results[0].expiry != "2030 Jan 01, 00:00 UTC" {
+		t.Errorf("Unexpected expiry %q", results[0].expiry)
+	}
+
+	endpointConfig := config.Config.EndpointConfig("https://sso.example.com")
+	if endpointConfig.Token != newAccessToken {
+		t.Errorf("Expected new access token to be stored, got %q", endpointConfig.Token)
+	}
+	if endpointConfig.RefreshToken != "the-refresh-token" {
+		t.Errorf("Expected refresh token to be kept, got %q", endpointConfig.RefreshToken)
+	}
+	if endpointConfig.Email != "new@example.com" {
+		t.Errorf("Expected email 'new@example.com', got %q", endpointConfig.Email)
+	}
+	if endpointConfig.Alias != "sso" {
+		t.Errorf("Expected alias 'sso', got %q", endpointConfig.Alias)
+	}
+}
+
+// TestRefreshAll tests refreshing all endpoints using SSO.
+func TestRefreshAll(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := refreshTokens(Arguments{all: true, refresh: fakeRefresh})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].endpoint != "https://broken.example.com" || !errors.IsSSOError(results[0].err) {
+		t.Errorf("Expected SSOError for broken endpoint, got %#v", results[0])
+	}
+	if results[1].endpoint != "https://sso.example.com" || results[1].err != nil {
+		t.Errorf("Expected success for sso endpoint, got %#v", results[1])
+	}
+
+	if token := config.Config.EndpointConfig("https://broken.example.com").Token; token != "old-access-token" {
+		t.Errorf("Expected token of broken endpoint to be unchanged, got %q", token)
+	}
+	if token := config.Config.EndpointConfig("https://password.example.com").Token; token != "some-token" {
+		t.Errorf("Expected token of password endpoint to be unchanged, got %q", token)
+	}
+}
@@ -0,0 +1,216 @@
+// Package status implements the 'auth status' sub-command.
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/authtoken"
+	"github.com/giantswarm/gsctl/pkg/output"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl auth status'
+	Command = &cobra.Command{
+		Use:   "status",
+		Short: "Show authentication details per endpoint",
+		Long: `Shows the authentication details stored for each API endpoint.
+
+For every endpoint, the email address, the auth scheme and, where known, the
+expiry of the token are shown, as well as whether a refresh token is stored.
+
+Tokens using the "Bearer" scheme have been obtained via SSO. They expire after
+a while and are refreshed automatically using the refresh token, or
+proactively using 'gsctl auth refresh'. Tokens using the "giantswarm" scheme
+have been obtained via email and password or --token-file. Their expiry is
+not known to gsctl.
+
+Examples:
+
+  gsctl auth status
+
+  gsctl auth status --output json
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	arguments Arguments
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint  string
+	outputFormat string
+}
+
+// endpointStatus is the authentication status of one endpoint.
+type endpointStatus struct {
+	Endpoint        string     `json:"endpoint"`
+	Alias           string     `json:"alias,omitempty"`
+	Selected        bool       `json:"selected"`
+	LoggedIn        bool       `json:"logged_in"`
+	Email           string     `json:"email,omitempty"`
+	Scheme          string     `json:"auth_scheme,omitempty"`
+	Expiry          *time.Time `json:"expiry,omitempty"`
+	Expired         bool       `json:"expired"`
+	HasRefreshToken bool       `json:"has_refresh_token"`
+}
+
+func collectArguments() Arguments {
+	return Arguments{
+		apiEndpoint:  config.Config.ChooseEndpoint(flags.APIEndpoint),
+		outputFormat: flags.OutputFormat,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	errors.HandleCommonErrors(err)
+	errors.PrintError(err, err.Error(), "")
+	errors.Exit(err)
+}
+
+// statuses returns the status of all endpoints, sorted by URL.
+func statuses(args Arguments, now time.Time) []endpointStatus {
+	endpoints := config.Config.Endpoints()
+	sort.Strings(endpoints)
+
+	result := []endpointStatus{}
+	for _, endpoint := range endpoints {
+		endpointConfig := config.Config.EndpointConfig(endpoint)
+		if endpointConfig == nil {
+			continue
+		}
+
+		s := endpointStatus{
+			Endpoint:        endpoint,
+			Alias:           endpointConfig.Alias,
+			Selected:        endpoint == args.apiEndpoint,
+			LoggedIn:        endpointConfig.Token != "",
+			HasRefreshToken: endpointConfig.RefreshToken != "",
+		}
+		if s.LoggedIn {
+			s.Email = endpointConfig.Email
+			s.Scheme = endpointConfig.Scheme
+			if s.Scheme == "" {
+				s.Scheme = authtoken.SchemeGiantSwarm
+			}
+			if expiry, ok := authtoken.Expiry(endpointConfig.Token); ok {
+				s.Expiry = &expiry
+				s.Expired = now.After(expiry)
+			}
+		}
+
+		result = append(result, s)
+	}
+
+	return result
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	s := statuses(arguments, time.Now())
+
+	if !output.IsTableFormat(arguments.outputFormat) {
+		printer, err := output.NewPrinter(arguments.outputFormat)
+		if err == nil {
+			var out string
+			out, err = printer.Sprint(s, ".endpoint")
+			if err == nil {
+				fmt.Println(out)
+				return
+			}
+		}
+
+		errors.PrintError(err, "Error while formatting output", err.Error())
+		errors.Exit(err)
+	}
+
+	if len(s) == 0 {
+		fmt.Printf("No endpoints configured.\n\nTo add an endpoint and authenticate for it, use\n\n\t%s\n\n",
+			color.YellowString("gsctl login <email> -e <endpoint>"))
+		return
+	}
+
+	fmt.Println(formatTable(s))
+}
+
+// formatTable returns a table with one row per endpoint.
+func formatTable(statuses []endpointStatus) string {
+	rows := []string{strings.Join([]string{
+		color.CyanString("ENDPOINT"),
+		color.CyanString("ALIAS"),
+		color.CyanString("SELECTED"),
+		color.CyanString("EMAIL"),
+		color.CyanString("SCHEME"),
+		color.CyanString("EXPIRES"),
+		color.CyanString("REFRESH TOKEN"),
+	}, "|")}
+
+	for _, s := range statuses {
+		alias := "n/a"
+		if s.Alias != "" {
+			alias = s.Alias
+		}
+		selected := "no"
+		if s.Selected {
+			selected = "yes"
+		}
+		refreshToken := "no"
+		if s.HasRefreshToken {
+			refreshToken = "yes"
+		}
+
+		email := "not logged in"
+		scheme := "n/a"
+		expires := "n/a"
+		if s.LoggedIn {
+			email = s.Email
+			scheme = s.Scheme
+			if s.Expiry != nil {
+				expires = util.ShortDate(s.Expiry.UTC())
+				if s.Expired {
+					expires = color.RedString(expires + " (expired)")
+				}
+			}
+		}
+
+		rows = append(rows, strings.Join([]string{s.Endpoint, alias, selected, email, scheme, expires, refreshToken}, "|"))
+	}
+
+	return columnize.SimpleFormat(rows)
+}
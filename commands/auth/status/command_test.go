@@ -0,0 +1,73 @@
+package status
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+var now = time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// TestStatuses tests collecting the status of all endpoints.
+func TestStatuses(t *testing.T) {
+	expired := now.Add(-time.Hour)
+	valid := now.Add(time.Hour)
+
+	configYAML := `endpoints:
+  https://sso.example.com:
+    alias: sso
+    email: user@example.com
+    auth_scheme: Bearer
+    token: ` + testutils.JWT(valid) + `
+    refresh_token: refresh-token
+  https://expired.example.com:
+    email: user@example.com
+    auth_scheme: Bearer
+    token: ` + testutils.JWT(expired) + `
+  https://password.example.com:
+    email: ci@example.com
+    token: some-token
+  https://loggedout.example.com:
+    alias: loggedout
+selected_endpoint: https://sso.example.com
+`
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := statuses(Arguments{apiEndpoint: "https://sso.example.com"}, now)
+	if len(s) != 4 {
+		t.Fatalf("Expected 4 statuses, got %d", len(s))
+	}
+
+	// sorted by endpoint URL
+	exp, loggedOut, password, sso := s[0], s[1], s[2], s[3]
+
+	if !sso.Selected || !sso.LoggedIn || sso.Scheme != "Bearer" || !sso.HasRefreshToken || sso.Expired {
+		t.Errorf("Unexpected status for SSO endpoint: %#v", sso)
+	}
+	if sso.Expiry == nil || !sso.Expiry.Equal(valid) {
+		t.Errorf("Expected expiry %s, got %v", valid, sso.Expiry)
+	}
+	if !exp.Expired || exp.HasRefreshToken || exp.Selected {
+		t.Errorf("Unexpected status for expired endpoint: %#v", exp)
+	}
+	if password.Scheme != "giantswarm" || password.Expiry != nil || password.Email != "ci@example.com" {
+		t.Errorf("Unexpected status for password endpoint: %#v", password)
+	}
+	if loggedOut.LoggedIn || loggedOut.Email != "" || loggedOut.Alias != "loggedout" {
+		t.Errorf("Unexpected status for logged out endpoint: %#v", loggedOut)
+	}
+
+	table := formatTable(s)
+	if !strings.Contains(table, "not logged in") || !strings.Contains(table, "(expired)") {
+		t.Errorf("Unexpected table:\n%s", table)
+	}
+}
@@ -0,0 +1,301 @@
+// Package app implements the "create app" command.
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/appvalues"
+)
+
+var (
+	// Command is the cobra command for 'gsctl create app'
+	Command = &cobra.Command{
+		Use: "app <cluster-name/cluster-id>/<app-name>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Install an app in a cluster",
+		Long: `Install an app from a catalog in a cluster.
+
+The app name given after the slash is the name of the app resource. Unless
+--chart-name is given, it is also used as the name of the chart to install
+from the catalog.
+
+User values for the app can be provided as a YAML file using --values-file.
+They are stored in a config map in the cluster and merged with the app's
+default values.
+
+Examples:
+
+  gsctl create app f01r4/nginx-ingress-controller \
+    --chart-name nginx-ingress-controller-app \
+    --catalog giantswarm --version 1.6.9 --namespace kube-system
+
+  gsctl create app "Cluster name"/external-dns \
+    --catalog giantswarm --version 1.2.0 --namespace kube-system \
+    --values-file ./external-dns-values.yaml
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	cmdChartName string
+
+	arguments Arguments
+)
+
+const (
+	activityName = "create-app"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.AppCatalog, "catalog", "", "", "Name of the catalog to install the app from.")
+	Command.Flags().StringVarP(&cmdChartName, "chart-name", "", "", "Name of the chart in the catalog. Defaults to the app name.")
+	Command.Flags().StringVarP(&flags.AppNamespace, "namespace", "", "", "Namespace in the cluster to install the app into.")
+	Command.Flags().StringVarP(&flags.AppVersion, "version", "", "", "Version of the app to install.")
+	Command.Flags().StringVarP(&flags.AppValuesFile, "values-file", "", "", "Path to a YAML file with user values for the app.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AppName           string
+	AuthToken         string
+	Catalog           string
+	ChartName         string
+	ClusterNameOrID   string
+	Namespace         string
+	UserProvidedToken string
+	Values            map[string]interface{}
+	Verbose           bool
+	Version           string
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments(fs afero.Fs, positionalArgs []string) (Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	parts := strings.Split(positionalArgs[0], "/")
+	if len(parts) < 2 {
+		return Arguments{}, microerror.Maskf(errors.InvalidAppArgumentError, "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details.")
+	}
+
+	chartName := cmdChartName
+	if chartName == "" {
+		chartName = parts[1]
+	}
+
+	var values map[string]interface{}
+	if flags.AppValuesFile != "" {
+		var err error
+		values, err = appvalues.ReadFile(fs, flags.AppValuesFile)
+		if appvalues.IsInvalidValues(err) {
+			return Arguments{}, microerror.Maskf(errors.YAMLNotParseableError, err.Error())
+		} else if err != nil {
+			return Arguments{}, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
+		}
+	}
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AppName:           parts[1],
+		AuthToken:         token,
+		Catalog:           flags.AppCatalog,
+		ChartName:         chartName,
+		ClusterNameOrID:   parts[0],
+		Namespace:         flags.AppNamespace,
+		UserProvidedToken: flags.Token,
+		Values:            values,
+		Verbose:           flags.Verbose,
+		Version:           flags.AppVersion,
+	}, nil
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if config.Config.Token == "" && args.AuthToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.ClusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+	if args.AppName == "" {
+		return microerror.Mask(errors.AppNameMissingError)
+	}
+	if args.Catalog == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--catalog")
+	}
+	if args.Namespace == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--namespace")
+	}
+	if args.Version == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--version")
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	var err error
+
+	arguments, err = collectArguments(afero.NewOsFs(), positionalArgs)
+	if err == nil {
+		err = verifyPreconditions(arguments)
+	}
+
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// createApp installs the app and, if given, stores its user values.
+func createApp(args Arguments) error {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	// The user values config map has to exist before the app is created,
+	// so that it is picked up on the first deployment.
+	if args.Values != nil {
+		if args.Verbose {
+			fmt.Println(color.WhiteString("Submitting user values for app '%s'", args.AppName))
+		}
+
+		_, err = clientWrapper.CreateAppConfig(clusterID, args.AppName, args.Values, auxParams)
+		if err != nil {
+			return microerror.Mask(handleAPIError(err))
+		}
+	}
+
+	requestBody := &models.V4CreateAppRequest{
+		Spec: &models.V4CreateAppRequestSpec{
+			Catalog:   &args.Catalog,
+			Name:      &args.ChartName,
+			Namespace: &args.Namespace,
+			Version:   &args.Version,
+		},
+	}
+
+	if args.Verbose {
+		fmt.Println(color.WhiteString("Submitting app creation request"))
+		bodyJSON, _ := json.Marshal(requestBody)
+		fmt.Println(color.WhiteString("Request body: ") + string(bodyJSON))
+	}
+
+	_, err = clientWrapper.CreateApp(clusterID, args.AppName, requestBody, auxParams)
+	if err != nil {
+		// Remove the user values again, so that the command can be re-run
+		// without running into a conflict.
+		if args.Values != nil {
+			_, deleteErr := clientWrapper.DeleteAppConfig(clusterID, args.AppName, auxParams)
+			if deleteErr != nil && args.Verbose {
+				fmt.Println(color.WhiteString("Could not delete user values for app '%s': %s", args.AppName, deleteErr.Error()))
+			}
+		}
+
+		return microerror.Mask(handleAPIError(err))
+	}
+
+	return nil
+}
+
+// handleAPIError maps client errors to the errors of this command.
+func handleAPIError(err error) error {
+	switch {
+	case clienterror.IsAccessForbiddenError(err):
+		return microerror.Mask(errors.AccessForbiddenError)
+	case clienterror.IsNotFoundError(err):
+		return microerror.Mask(errors.ClusterNotFoundError)
+	case clienterror.IsBadRequestError(err):
+		return microerror.Maskf(errors.BadRequestError, err.Error())
+	}
+
+	return err
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	err := createApp(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(color.GreenString("App '%s' will be installed in cluster '%s' shortly.", arguments.AppName, arguments.ClusterNameOrID))
+	fmt.Printf("Use 'gsctl show app %s/%s' to check the status.\n", arguments.ClusterNameOrID, arguments.AppName)
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsInvalidAppArgument(err):
+		headline = "Invalid argument syntax"
+		subtext = "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details."
+	case errors.IsAppNameMissing(err):
+		headline = "No app name specified."
+		subtext = "Please specify an app name. Use --help for details."
+	case errors.IsRequiredFlagMissingError(err):
+		headline = "Missing flag: " + strings.TrimPrefix(err.Error(), "required flag missing error: ")
+		subtext = "Please use --help to see details regarding the command's usage."
+	case errors.IsYAMLFileNotReadable(err):
+		headline = "Could not read values file"
+		subtext = err.Error()
+	case errors.IsYAMLNotParseable(err):
+		headline = "Could not parse values file"
+		subtext = "The values file must contain a YAML object. " + err.Error()
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = "Could not find a cluster with this name/ID. Check 'gsctl list clusters' to make sure."
+	case errors.IsBadRequestError(err):
+		headline = "Bad request"
+		subtext = err.Error()
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
@@ -0,0 +1,276 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// Test_CollectArguments tests whether flags and positional arguments are
+// parsed into the expected Arguments struct.
+func Test_CollectArguments(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+	afero.WriteFile(fs, "/values.yaml", []byte("replicas: 2\n"), 0644)
+
+	var testCases = []struct {
+		positionalArgs []string
+		flags          []string
+		resultingArgs  Arguments
+		errorMatcher   func(error) bool
+	}{
+		{
+			[]string{"f01r4/external-dns"},
+			[]string{"--catalog=giantswarm", "--version=1.2.0", "--namespace=kube-system"},
+			Arguments{
+				AppName:         "external-dns",
+				Catalog:         "giantswarm",
+				ChartName:       "external-dns",
+				ClusterNameOrID: "f01r4",
+				Namespace:       "kube-system",
+				Version:         "1.2.0",
+			},
+			nil,
+		},
+		{
+			[]string{"Cluster name/ingress"},
+			[]string{"--catalog=giantswarm", "--version=1.6.9", "--namespace=kube-system", "--chart-name=nginx-ingress-controller-app", "--values-file=/values.yaml"},
+			Arguments{
+				AppName:         "ingress",
+				Catalog:         "giantswarm",
+				ChartName:       "nginx-ingress-controller-app",
+				ClusterNameOrID: "Cluster name",
+				Namespace:       "kube-system",
+				Values:          map[string]interface{}{"replicas": 2},
+				Version:         "1.6.9",
+			},
+			nil,
+		},
+		{
+			[]string{"f01r4"},
+			[]string{},
+			Arguments{},
+			errors.IsInvalidAppArgument,
+		},
+		{
+			[]string{"f01r4/external-dns"},
+			[]string{"--values-file=/non-existing.yaml"},
+			Arguments{},
+			errors.IsYAMLFileNotReadable,
+		},
+	}
+
+	for i, tc := range testCases {
+		initFlags()
+		Command.ParseFlags(tc.flags)
+		args, err := collectArguments(fs, tc.positionalArgs)
+		if tc.errorMatcher != nil {
+			if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Unexpected error: %#v", i, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %#v", i, err)
+			continue
+		}
+
+		// Ignore fields depending on the environment.
+		args.APIEndpoint = ""
+		args.AuthToken = ""
+		args.UserProvidedToken = ""
+		args.Verbose = false
+
+		if diff := cmp.Diff(tc.resultingArgs, args); diff != "" {
+			t.Errorf("Case %d - Resulting args unequal. (-expected +got):\n%s", i, diff)
+		}
+	}
+}
+
+// Test_VerifyPreconditions tests the required flags.
+func Test_VerifyPreconditions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	base := Arguments{
+		APIEndpoint:     "https://foo",
+		AppName:         "external-dns",
+		AuthToken:       "token",
+		Catalog:         "giantswarm",
+		ClusterNameOrID: "f01r4",
+		Namespace:       "kube-system",
+		Version:         "1.2.0",
+	}
+
+	err := verifyPreconditions(base)
+	if err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	noCatalog := base
+	noCatalog.Catalog = ""
+	noNamespace := base
+	noNamespace.Namespace = ""
+	noVersion := base
+	noVersion.Version = ""
+
+	for i, args := range []Arguments{noCatalog, noNamespace, noVersion} {
+		err := verifyPreconditions(args)
+		if !errors.IsRequiredFlagMissingError(err) {
+			t.Errorf("Case %d - Expected RequiredFlagMissingError, got %#v", i, err)
+		}
+	}
+}
+
+// Test_CreateApp tests that the expected requests are sent to the API.
+func Test_CreateApp(t *testing.T) {
+	var appBody, configBody map[string]interface{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "f01r4", "name": "Name of the cluster", "owner": "acme"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v4/clusters/f01r4/apps/ingress/config/":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &configBody)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"code": "RESOURCE_CREATED", "message": "Config created"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v4/clusters/f01r4/apps/ingress/":
+			if configBody == nil {
+				t.Error("App was created before its user values")
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &appBody)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"metadata": {"name": "ingress"}, "spec": {"catalog": "giantswarm", "name": "nginx-ingress-controller-app", "namespace": "kube-system", "version": "1.6.9"}}`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+	flags.Token = ""
+
+	args := Arguments{
+		APIEndpoint:       mockServer.URL,
+		AppName:           "ingress",
+		AuthToken:         "token",
+		Catalog:           "giantswarm",
+		ChartName:         "nginx-ingress-controller-app",
+		ClusterNameOrID:   "Name of the cluster",
+		Namespace:         "kube-system",
+		UserProvidedToken: "token",
+		Values: map[string]interface{}{
+			"controller": map[string]interface{}{"replicas": 3},
+		},
+		Version: "1.6.9",
+	}
+
+	err := createApp(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	expectedApp := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"catalog":   "giantswarm",
+			"name":      "nginx-ingress-controller-app",
+			"namespace": "kube-system",
+			"version":   "1.6.9",
+		},
+	}
+	if diff := cmp.Diff(expectedApp, appBody); diff != "" {
+		t.Errorf("App request body unexpected (-expected +got):\n%s", diff)
+	}
+
+	expectedConfig := map[string]interface{}{
+		"controller": map[string]interface{}{"replicas": float64(3)},
+	}
+	if diff := cmp.Diff(expectedConfig, configBody); diff != "" {
+		t.Errorf("Config request body unexpected (-expected +got):\n%s", diff)
+	}
+}
+
+// Test_CreateAppFailureRemovesValues tests that the user values are deleted
+// again if the app can't be created, so that the command can be re-run.
+func Test_CreateAppFailureRemovesValues(t *testing.T) {
+	configExists := false
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "f01r4", "name": "Name of the cluster", "owner": "acme"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v4/clusters/f01r4/apps/ingress/config/":
+			if configExists {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"code": "RESOURCE_ALREADY_EXISTS", "message": "Config exists"}`))
+				return
+			}
+			configExists = true
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"code": "RESOURCE_CREATED", "message": "Config created"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/v4/clusters/f01r4/apps/ingress/config/":
+			configExists = false
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"code": "RESOURCE_DELETED", "message": "Config deleted"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v4/clusters/f01r4/apps/ingress/":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": "INVALID_INPUT", "message": "Unknown catalog"}`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+	flags.Token = ""
+
+	args := Arguments{
+		APIEndpoint:       mockServer.URL,
+		AppName:           "ingress",
+		AuthToken:         "token",
+		Catalog:           "unknown",
+		ChartName:         "nginx-ingress-controller-app",
+		ClusterNameOrID:   "f01r4",
+		Namespace:         "kube-system",
+		UserProvidedToken: "token",
+		Values:            map[string]interface{}{"replicas": 3},
+		Version:           "1.6.9",
+	}
+
+	// Running the command twice must fail the same way both times.
+	for i := 0; i < 2; i++ {
+		err := createApp(args)
+		if !errors.IsBadRequestError(err) {
+			t.Errorf("Attempt %d - Expected BadRequestError, got %#v", i, err)
+		}
+		if configExists {
+			t.Errorf("Attempt %d - Expected user values to be deleted", i)
+		}
+	}
+}
@@ -21,9 +21,11 @@ package cluster
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/fatih/color"
@@ -39,6 +41,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/types"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/wait"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -58,6 +61,8 @@ type Arguments struct {
 	UserProvidedToken     string
 	Verbose               bool
 	OutputFormat          string
+	Wait                  bool
+	WaitTimeout           time.Duration
 }
 
 // collectArguments gets arguments from flags and returns an Arguments object.
@@ -94,6 +99,8 @@ func collectArguments(cmd *cobra.Command) Arguments {
 		UserProvidedToken:     flags.Token,
 		Verbose:               flags.OutputFormat != formatting.OutputFormatJSON && flags.Verbose,
 		OutputFormat:          flags.OutputFormat,
+		Wait:                  flags.Wait,
+		WaitTimeout:           flags.WaitTimeout,
 	}
 }
 
@@ -119,8 +126,6 @@ type JSONOutput struct {
 	ID string `json:"id,omitempty"`
 	// Result of the command. should be 'created'
 	Result string `json:"result"`
-	// Error which occured
-	Error error `json:"error,omitempty"`
 }
 
 const (
@@ -202,6 +207,12 @@ suppress the creation of the default node pool by setting the flag
   gsctl create cluster \
     --owner acme \
     --create-default-nodepool=false
+
+To block until the cluster has been created, for example in CI pipelines, use
+--wait. The command exits with a non-zero exit code if the cluster is not
+created within the time given via --timeout:
+
+  gsctl create cluster --owner acme --wait --timeout 45m
 `,
 		PreRun: printValidation,
 		Run:    printResult,
@@ -227,6 +238,8 @@ func initFlags() {
 	Command.Flags().BoolVar(&flags.MasterHA, "master-ha", true, "When true, the cluster will provide high-availability Kubernetes masters.")
 	Command.Flags().BoolVarP(&flags.CreateDefaultNodePool, "create-default-nodepool", "", true, "Whether a default node pool should be created if none is specified in the definition. Requires node pool support.")
 	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' will change output to be JSON formatted.", formatting.OutputFormatJSON))
+	Command.Flags().BoolVarP(&flags.Wait, "wait", "", false, "Wait until the cluster has been created.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "timeout", "", wait.DefaultTimeout, "Maximum time to wait when using --wait.")
 }
 
 // printValidation runs our pre-checks.
@@ -242,7 +255,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 
 	err := verifyPreconditions(arguments)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		switch {
@@ -254,25 +267,26 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 }
 
 // printResult calls addCluster() and creates user-friendly output of the result
 func printResult(cmd *cobra.Command, positionalArgs []string) {
 	result, err := addCluster(arguments)
+	if err == nil && arguments.Wait {
+		err = waitForCluster(arguments, result.ID)
+	}
 
-	if arguments.OutputFormat == formatting.OutputFormatJSON {
-		printJSONOutput(result, err)
+	// Errors are printed as JSON by errors.PrintError.
+	if arguments.OutputFormat == formatting.OutputFormatJSON && err == nil {
+		printJSONOutput(result)
 		return
 	}
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline string
@@ -339,6 +353,9 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		case errors.IsOrganizationNotFoundError(err):
 			headline = "Organization not found"
 			subtext = "The organization set to own the cluster does not exist."
+		case wait.IsTimeout(err):
+			headline = "Timeout"
+			subtext = fmt.Sprintf("The cluster '%s' has been submitted, but creation did not complete in time. Use 'gsctl show cluster %s' to check its status.", result.ID, result.ID)
 		case errors.IsCouldNotCreateClusterError(err):
 			headline = "The cluster could not be created."
 			subtext = "You might try again in a few moments. If that doesn't work, please contact the Giant Swarm support team."
@@ -357,11 +374,8 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		}
 
 		// output error information
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	// success output
@@ -399,19 +413,38 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 	fmt.Printf("    %s \n\n", color.YellowString("gsctl create kubeconfig --help"))
 }
 
-func printJSONOutput(result *creationResult, creationErr error) {
+// waitForCluster polls the API until the cluster with the given ID has been created.
+func waitForCluster(args Arguments, clusterID string) error {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Keep STDOUT clean for JSON output.
+	var output io.Writer = os.Stdout
+	if args.OutputFormat == formatting.OutputFormatJSON {
+		output = os.Stderr
+	}
+
+	w, err := wait.New(wait.Config{
+		ClientWrapper: clientWrapper,
+		Output:        output,
+		Timeout:       args.WaitTimeout,
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return w.ClusterCreated(clusterID)
+}
+
+func printJSONOutput(result *creationResult) {
 	var outputBytes []byte
 	var err error
-	var jsonResult JSONOutput
 
-	// handle errors
-	if creationErr != nil {
-		jsonResult = JSONOutput{Result: "error", Error: creationErr}
-	} else {
-		jsonResult = JSONOutput{ID: result.ID, Result: "created"}
-		if result.HasErrors {
-			jsonResult.Result = "created-with-errors"
-		}
+	jsonResult := JSONOutput{ID: result.ID, Result: "created"}
+	if result.HasErrors {
+		jsonResult.Result = "created-with-errors"
 	}
 
 	outputBytes, err = json.MarshalIndent(jsonResult, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
@@ -421,8 +454,8 @@ func printJSONOutput(result *creationResult, creationErr error) {
 	}
 
 	fmt.Println(string(outputBytes))
-	if creationErr != nil || result.HasErrors {
-		os.Exit(1)
+	if result.HasErrors {
+		os.Exit(errors.ExitCodeGeneral)
 	}
 }
 
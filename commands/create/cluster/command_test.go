@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -15,6 +16,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/commands/types"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/wait"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -43,6 +45,7 @@ func Test_CollectArgs(t *testing.T) {
 				AuthToken:             "some-token",
 				CreateDefaultNodePool: true,
 				Scheme:                "giantswarm",
+				WaitTimeout:           wait.DefaultTimeout,
 				MasterHA:              nil,
 			},
 		},
@@ -53,6 +56,7 @@ func Test_CollectArgs(t *testing.T) {
 				AuthToken:             "some-token",
 				CreateDefaultNodePool: true,
 				Scheme:                "giantswarm",
+				WaitTimeout:           wait.DefaultTimeout,
 				MasterHA:              toBoolPtr(false),
 			},
 		},
@@ -70,6 +74,7 @@ func Test_CollectArgs(t *testing.T) {
 				Owner:                 "acme",
 				ReleaseVersion:        "1.2.3",
 				Scheme:                "giantswarm",
+				WaitTimeout:           wait.DefaultTimeout,
 				MasterHA:              nil,
 			},
 		},
@@ -83,6 +88,7 @@ func Test_CollectArgs(t *testing.T) {
 				CreateDefaultNodePool: true,
 				ReleaseVersion:        "1.2.3",
 				Scheme:                "giantswarm",
+				WaitTimeout:           wait.DefaultTimeout,
 				MasterHA:              nil,
 			},
 		},
@@ -93,10 +99,22 @@ func Test_CollectArgs(t *testing.T) {
 				AuthToken:             "some-token",
 				CreateDefaultNodePool: true,
 				Scheme:                "giantswarm",
+				WaitTimeout:           wait.DefaultTimeout,
 				MasterHA:              nil,
 				OutputFormat:          "json",
 			},
 		},
+		{
+			[]string{"--wait", "--timeout=1h"},
+			Arguments{
+				APIEndpoint:           "https://foo",
+				AuthToken:             "some-token",
+				CreateDefaultNodePool: true,
+				Scheme:                "giantswarm",
+				Wait:                  true,
+				WaitTimeout:           time.Hour,
+			},
+		},
 	}
 
 	fs := afero.NewMemMapFs()
@@ -321,8 +339,8 @@ func Test_CreateClusterSuccessfully(t *testing.T) {
 							AvailabilityZones: &types.AvailabilityZonesDefinition{
 								Zones: []string{"eu-central-1a", "eu-central-1b", "eu-central-1c"},
 							},
-							Scaling: &types.ScalingDefinition{
-								Min: 3,
+							Scaling: &types.NodePoolScalingDefinition{
+								Min: toInt64Ptr(3),
 								Max: 10,
 							},
 							NodeSpec: &types.NodeSpec{
@@ -1274,12 +1292,14 @@ selected_endpoint: ` + mockServer.URL
 		t.Error(err)
 	}
 
-	addClusterFunc := addCluster
-	print := printJSONOutput
+	result, err := addCluster(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
 
 	jsonRepresentation := testutils.CaptureOutput(func() {
 		// output
-		print(addClusterFunc(args))
+		printJSONOutput(result)
 	})
 
 	t.Log(jsonRepresentation)
@@ -1321,3 +1341,7 @@ func Test_collectArguments(t *testing.T) {
 		t.Errorf("Expected Verbose argument to be false. Got '%t'", argsVerboseFalse.Verbose)
 	}
 }
+
+func toInt64Ptr(i int64) *int64 {
+	return &i
+}
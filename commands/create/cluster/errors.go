@@ -1,36 +1,25 @@
 package cluster
 
-import "github.com/giantswarm/microerror"
+import (
+	"github.com/giantswarm/microerror"
 
-// unmashalToMapFailedError is used when a YAML cluster definition can't be unmarshalled into map[string]interface{}.
-var unmashalToMapFailedError = &microerror.Error{
-	Kind: "unmashalToMapFailedError",
-	Desc: "Could not unmarshal YAML into a map[string]interface{} structure. Seems like the YAML is invalid.",
-}
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+)
 
-// IsUnmashalToMapFailed asserts unmashalToMapFailedError.
+// IsUnmashalToMapFailed asserts that a YAML cluster definition could not
+// be unmarshalled into a map.
 func IsUnmashalToMapFailed(err error) bool {
-	return microerror.Cause(err) == unmashalToMapFailedError
-}
-
-// invalidV5DefinitionYAMLError is used when the YAML definition can't be parsed as valid v5.
-var invalidV5DefinitionYAMLError = &microerror.Error{
-	Kind: "invalidV5DefinitionYAMLError",
+	return clusterdefinition.IsUnmashalToMapFailed(err)
 }
 
-// IsInvalidV5DefinitionYAML asserts invalidV4DefinitionYAMLError.
+// IsInvalidV5DefinitionYAML asserts that the YAML definition can't be parsed as valid v5.
 func IsInvalidV5DefinitionYAML(err error) bool {
-	return microerror.Cause(err) == invalidV5DefinitionYAMLError
-}
-
-// invalidDefinitionYAMLError is used when the YAML definition can't be parsed as any valid cluster definition.
-var invalidDefinitionYAMLError = &microerror.Error{
-	Kind: "invalidDefinitionYAMLError",
+	return clusterdefinition.IsInvalidV5DefinitionYAML(err)
 }
 
-// IsInvalidDefinitionYAML asserts invalidDefinitionYAMLError.
+// IsInvalidDefinitionYAML asserts that the YAML definition can't be parsed as any valid cluster definition.
 func IsInvalidDefinitionYAML(err error) bool {
-	return microerror.Cause(err) == invalidDefinitionYAMLError
+	return clusterdefinition.IsInvalidDefinitionYAML(err)
 }
 
 var haMastersNotSupportedError = &microerror.Error{
@@ -246,7 +246,7 @@ nodepools:
 					{
 						Name:              "Database",
 						AvailabilityZones: &types.AvailabilityZonesDefinition{Zones: []string{"my-zone-1a", "my-zone-1b", "my-zone-1c"}},
-						Scaling:           &types.ScalingDefinition{Min: 3, Max: 10},
+						Scaling:           &types.NodePoolScalingDefinition{Min: toInt64Ptr(3), Max: 10},
 						NodeSpec:          &types.NodeSpec{AWS: &types.AWSSpecificDefinition{InstanceType: "m5.superlarge"}},
 					},
 					{
@@ -291,7 +291,7 @@ nodepools:
 					{
 						Name:              "Database",
 						AvailabilityZones: &types.AvailabilityZonesDefinition{Zones: []string{"my-zone-1a", "my-zone-1b", "my-zone-1c"}},
-						Scaling:           &types.ScalingDefinition{Min: 3, Max: 10},
+						Scaling:           &types.NodePoolScalingDefinition{Min: toInt64Ptr(3), Max: 10},
 						NodeSpec:          &types.NodeSpec{AWS: &types.AWSSpecificDefinition{InstanceType: "m5.superlarge"}},
 					},
 					{
@@ -11,6 +11,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/commands/types"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
 	"github.com/giantswarm/gsctl/pkg/provider"
 )
 
@@ -88,67 +89,6 @@ func createAddClusterBodyV5(def *types.ClusterDefinitionV5) *models.V5AddCluster
 	return b
 }
 
-func createAddNodePoolBody(def *types.NodePoolDefinition) *models.V5AddNodePoolRequest {
-	b := &models.V5AddNodePoolRequest{
-		Name:              def.Name,
-		AvailabilityZones: &models.V5AddNodePoolRequestAvailabilityZones{},
-		Scaling:           &models.V5AddNodePoolRequestScaling{},
-		NodeSpec:          &models.V5AddNodePoolRequestNodeSpec{},
-	}
-
-	if def.AvailabilityZones != nil {
-		if def.AvailabilityZones.Number != 0 {
-			b.AvailabilityZones.Number = def.AvailabilityZones.Number
-		}
-		if len(def.AvailabilityZones.Zones) != 0 {
-			b.AvailabilityZones.Zones = def.AvailabilityZones.Zones
-		}
-	}
-
-	if def.Scaling != nil {
-		if def.Scaling.Min >= 0 {
-			b.Scaling.Min = &def.Scaling.Min
-		}
-		if def.Scaling.Max != 0 {
-			b.Scaling.Max = def.Scaling.Max
-		}
-	}
-
-	if def.NodeSpec != nil {
-		if def.NodeSpec.AWS != nil {
-			b.NodeSpec.Aws = &models.V5AddNodePoolRequestNodeSpecAws{}
-
-			if def.NodeSpec.AWS.InstanceDistribution != nil {
-				b.NodeSpec.Aws.InstanceDistribution = &models.V5AddNodePoolRequestNodeSpecAwsInstanceDistribution{
-					OnDemandBaseCapacity:                &def.NodeSpec.AWS.InstanceDistribution.OnDemandBaseCapacity,
-					OnDemandPercentageAboveBaseCapacity: &def.NodeSpec.AWS.InstanceDistribution.OnDemandPercentageAboveBaseCapacity,
-				}
-			}
-
-			if def.NodeSpec.AWS.InstanceType != "" {
-				b.NodeSpec.Aws.InstanceType = def.NodeSpec.AWS.InstanceType
-			}
-
-			b.NodeSpec.Aws.UseAlikeInstanceTypes = &def.NodeSpec.AWS.UseAlikeInstanceTypes
-		}
-
-		if def.NodeSpec.Azure != nil {
-			b.NodeSpec.Azure = &models.V5AddNodePoolRequestNodeSpecAzure{}
-			if def.NodeSpec.Azure.VMSize != "" {
-				b.NodeSpec.Azure.VMSize = def.NodeSpec.Azure.VMSize
-			}
-			if def.NodeSpec.Azure.AzureSpotInstances != nil {
-				b.NodeSpec.Azure.SpotInstances = &models.V5AddNodePoolRequestNodeSpecAzureSpotInstances{
-					Enabled:  &def.NodeSpec.Azure.AzureSpotInstances.Enabled,
-					MaxPrice: &def.NodeSpec.Azure.AzureSpotInstances.MaxPrice,
-				}
-			}
-		}
-	}
-
-	return b
-}
-
 func addClusterV5(def *types.ClusterDefinitionV5, args Arguments, clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams) (string, bool, error) {
 	// Validate definition
 	if def.Owner == "" {
@@ -171,7 +111,7 @@ func addClusterV5(def *types.ClusterDefinitionV5, args Arguments, clientWrapper
 	// Create node pools.
 	if def.NodePools != nil && len(def.NodePools) > 0 {
 		for i, np := range def.NodePools {
-			nodePoolRequestBody := createAddNodePoolBody(np)
+			nodePoolRequestBody := clusterdefinition.AddNodePoolRequest(np)
 
 			if args.OutputFormat != formatting.OutputFormatJSON {
 				fmt.Printf("Adding node pool %d\n", i+1)
@@ -3,6 +3,7 @@ package create
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/commands/create/app"
 	"github.com/giantswarm/gsctl/commands/create/cluster"
 	"github.com/giantswarm/gsctl/commands/create/keypair"
 	"github.com/giantswarm/gsctl/commands/create/kubeconfig"
@@ -13,12 +14,13 @@ var (
 	// Command is the command to create things.
 	Command = &cobra.Command{
 		Use:   "create",
-		Short: "Create clusters, key pairs, node pools",
-		Long:  `Lets you create things like clusters, key pairs or kubectl configuration files`,
+		Short: "Create apps, clusters, key pairs, node pools",
+		Long:  `Lets you create things like apps, clusters, key pairs or kubectl configuration files`,
 	}
 )
 
 func init() {
+	Command.AddCommand(app.Command)
 	Command.AddCommand(cluster.Command)
 	Command.AddCommand(keypair.Command)
 	Command.AddCommand(kubeconfig.Command)
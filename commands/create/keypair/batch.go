@@ -0,0 +1,89 @@
+package keypair
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/batch"
+	"github.com/giantswarm/gsctl/util"
+)
+
+// createKeypairs creates a key pair for each cluster matching the selector,
+// after showing a summary and asking for confirmation once.
+func createKeypairs(args Arguments) ([]batch.Result, error) {
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusters, err := batch.Resolve(clientWrapper, args.apiEndpoint, args.selector, activityName)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	fmt.Printf("A key pair will be created for each of the following %d clusters:\n\n", len(clusters))
+	fmt.Println(batch.SummaryTable(clusters))
+	fmt.Println("")
+
+	if !args.force {
+		confirmed := confirm.Ask(fmt.Sprintf("Do you want to create %d key pairs?", len(clusters)))
+		if !confirmed {
+			return nil, microerror.Mask(errors.CommandAbortedError)
+		}
+	}
+
+	results := batch.Run(clusters, args.parallel, func(c batch.Cluster) (string, error) {
+		clusterArgs := args
+		clusterArgs.clusterNameOrID = c.ID
+
+		result, err := createKeypair(clusterArgs)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return fmt.Sprintf("Key pair %s created, expires in %s",
+			util.Truncate(formatting.CleanKeypairID(result.id), 10, true),
+			util.DurationPhrase(int(result.ttlHours))), nil
+	})
+
+	return results, nil
+}
+
+// printBatchResult creates key pairs for the clusters matching the selector
+// and prints the report. The exit code reflects all failures.
+func printBatchResult(args Arguments) {
+	results, err := createKeypairs(args)
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		var headline string
+
+		switch {
+		case errors.IsCommandAbortedError(err):
+			headline = "No key pairs created."
+		default:
+			headline = err.Error()
+		}
+
+		errors.PrintError(err, headline, "")
+		errors.Exit(err)
+	}
+
+	fmt.Println("")
+	fmt.Println(batch.Report(results))
+	fmt.Printf("\nCertificate and key files have been written to %s\n", config.CertsDirPath)
+
+	if code := batch.ExitCode(results); code != errors.ExitCodeOK {
+		fmt.Println(color.YellowString("Please check the failures listed above."))
+		os.Exit(code)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/batch"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -31,6 +32,15 @@ var (
 		Use:   "keypair",
 		Short: "Create key pair",
 		Long: `Creates a new key pair for a cluster
+
+Examples:
+
+  gsctl create keypair -c f01r4 --ttl 3h
+
+To create a key pair for each cluster matching a label selector, use
+--selector instead of --cluster:
+
+  gsctl create keypair --selector environment=testing --parallel 2
 `,
 		PreRun: printValidation,
 		Run:    printResult,
@@ -57,7 +67,9 @@ type Arguments struct {
 	description              string
 	fileSystem               afero.Fs
 	force                    bool
+	parallel                 int
 	scheme                   string
+	selector                 string
 	ttlHours                 int32
 	userProvidedToken        string
 	verbose                  bool
@@ -92,7 +104,9 @@ func collectArguments() (Arguments, error) {
 		commonNamePrefix:         flags.CNPrefix,
 		description:              description,
 		fileSystem:               config.FileSystem,
+		parallel:                 flags.Parallel,
 		scheme:                   scheme,
+		selector:                 flags.Selector,
 		ttlHours:                 int32(ttl.Hours()),
 		userProvidedToken:        flags.Token,
 		verbose:                  flags.Verbose,
@@ -121,9 +135,9 @@ func init() {
 	Command.Flags().StringVarP(&flags.CNPrefix, "cn-prefix", "", "", "The common name prefix for the issued certificates 'CN' field.")
 	Command.Flags().StringVarP(&flags.CertificateOrganizations, "certificate-organizations", "", "", "A comma separated list of organizations for the issued certificates 'O' fields.")
 	Command.Flags().StringVarP(&flags.TTL, "ttl", "", "1d", "Lifetime of the created key pair, e.g. 3h. Allowed units: h, d, w, m, y.")
-	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, there will be no confirmation for TTL > 30d.")
-
-	Command.MarkFlagRequired("cluster")
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, there will be no confirmation for TTL > 30d, nor for key pairs created using --selector.")
+	Command.Flags().StringVarP(&flags.Selector, "selector", "l", "", "Label selector query. Creates a key pair for each matching cluster instead of a single one.")
+	Command.Flags().IntVarP(&flags.Parallel, "parallel", "", batch.DefaultParallel, "Maximum number of key pairs to create at the same time when using --selector.")
 }
 
 func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
@@ -134,15 +148,13 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 	arguments, argsErr = collectArguments()
 	if argsErr != nil {
 		if errors.IsInvalidDurationError(argsErr) {
-			fmt.Println(color.RedString("The value passed with --ttl is invalid."))
-			fmt.Println("Please provide a number and a unit, e. g. '10h', '1d', '1w'.")
+			errors.PrintError(argsErr, "The value passed with --ttl is invalid.", "Please provide a number and a unit, e. g. '10h', '1d', '1w'.")
 		} else if errors.IsDurationExceededError(argsErr) {
-			fmt.Println(color.RedString("The expiration period passed with --ttl is too long."))
-			fmt.Println("The maximum possible value is the equivalent of 292 years.")
+			errors.PrintError(argsErr, "The expiration period passed with --ttl is too long.", "The maximum possible value is the equivalent of 292 years.")
 		} else {
-			fmt.Println(color.RedString(argsErr.Error()))
+			errors.PrintError(argsErr, argsErr.Error(), "")
 		}
-		os.Exit(1)
+		errors.Exit(argsErr)
 	}
 
 	if !arguments.force && arguments.ttlHours >= maxSafeTTLHours {
@@ -160,7 +172,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	headline := ""
@@ -173,16 +185,16 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 	case errors.IsInvalidCNPrefixError(err):
 		headline = "Bad characters in CN prefix (--cn-prefix)"
 		subtext = "Please use these characters only: a-z A-Z 0-9 . @ -"
+	case errors.IsConflictingFlagsError(err):
+		headline = "Conflicting flags used"
+		subtext = "Please use either --cluster or --selector, not both."
 	default:
 		headline = err.Error()
 	}
 
 	// print error output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
 }
 
 func verifyPreconditions(args Arguments) error {
@@ -192,9 +204,12 @@ func verifyPreconditions(args Arguments) error {
 	if config.Config.Token == "" && args.authToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.clusterNameOrID == "" {
+	if args.clusterNameOrID == "" && args.selector == "" {
 		return microerror.Mask(errors.ClusterNameOrIDMissingError)
 	}
+	if args.clusterNameOrID != "" && args.selector != "" {
+		return microerror.Mask(errors.ConflictingFlagsError)
+	}
 
 	// validate CN prefix character set
 	if args.commonNamePrefix != "" {
@@ -208,10 +223,15 @@ func verifyPreconditions(args Arguments) error {
 }
 
 func printResult(cmd *cobra.Command, cmdLineArgs []string) {
+	if arguments.selector != "" {
+		printBatchResult(arguments)
+		return
+	}
+
 	result, err := createKeypair(arguments)
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline string
@@ -227,11 +247,8 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 		}
 
 		// Print error output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	// Success output
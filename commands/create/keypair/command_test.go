@@ -6,9 +6,13 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/spf13/afero"
 
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/batch"
 	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
 )
 
 const keypairResponse = `{
@@ -117,3 +121,56 @@ func TestCommandExecution(t *testing.T) {
 	})
 	t.Log(output)
 }
+
+// TestCreateKeypairsBySelector creates key pairs for all clusters matching a selector.
+func TestCreateKeypairsBySelector(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := fakeapi.New(fakeapi.Config{})
+	for _, id := range []string{"a1111", "b2222"} {
+		server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: id, Owner: fakeapi.DefaultOrganization, Labels: map[string]string{"environment": "testing"}})
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	args := Arguments{
+		apiEndpoint:       ts.URL,
+		authToken:         "test-token",
+		description:       "test",
+		fileSystem:        fs,
+		force:             true,
+		parallel:          2,
+		selector:          "environment=testing",
+		ttlHours:          1,
+		userProvidedToken: "test-token",
+	}
+
+	err = verifyPreconditions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []batch.Result
+	testutils.CaptureOutput(func() {
+		results, err = createKeypairs(args)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("Unexpected error for cluster %s: %#v", r.Cluster.ID, r.Err)
+		}
+	}
+
+	args.clusterNameOrID = "a1111"
+	err = verifyPreconditions(args)
+	if !errors.IsConflictingFlagsError(err) {
+		t.Errorf("Expected ConflictingFlagsError, got %#v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/pkg/batch"
+)
+
+// createKubeconfigs adds a kubectl context for each cluster matching the
+// selector, after showing a summary and asking for confirmation once. The
+// current context is left unchanged.
+func createKubeconfigs(ctx context.Context, args Arguments) ([]batch.Result, error) {
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusters, err := batch.Resolve(clientWrapper, args.apiEndpoint, args.selector, createKubeconfigActivityName)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	fmt.Printf("A key pair and kubectl context will be created for each of the following %d clusters:\n\n", len(clusters))
+	fmt.Println(batch.SummaryTable(clusters, batch.Column{
+		Title: "CONTEXT",
+		Value: func(c batch.Cluster) string {
+			return "giantswarm-" + c.ID
+		},
+	}))
+	fmt.Println("")
+
+	if !args.force {
+		confirmed := confirm.Ask(fmt.Sprintf("Do you want to create %d kubectl contexts?", len(clusters)))
+		if !confirmed {
+			return nil, microerror.Mask(errors.CommandAbortedError)
+		}
+	}
+
+	results := batch.Run(clusters, args.parallel, func(c batch.Cluster) (string, error) {
+		clusterArgs := args
+		clusterArgs.clusterNameOrID = c.ID
+		clusterArgs.keepContext = true
+		clusterArgs.verbose = false
+
+		result, err := createKubeconfig(ctx, clusterArgs)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return fmt.Sprintf("Context '%s' added", result.contextName), nil
+	})
+
+	return results, nil
+}
+
+// printBatchResult creates kubeconfigs for the clusters matching the
+// selector and prints the report. The exit code reflects all failures.
+func printBatchResult(ctx context.Context, args Arguments) {
+	results, err := createKubeconfigs(ctx, args)
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		var headline string
+
+		switch {
+		case errors.IsCommandAbortedError(err):
+			headline = "No kubeconfigs created."
+		default:
+			headline = err.Error()
+		}
+
+		errors.PrintError(err, headline, "")
+		errors.Exit(err)
+	}
+
+	fmt.Println("")
+	fmt.Println(batch.Report(results))
+
+	if code := batch.ExitCode(results); code != errors.ExitCodeOK {
+		fmt.Println(color.YellowString("Please check the failures listed above."))
+		os.Exit(code)
+	}
+
+	fmt.Println(color.GreenString("\nSwitch to one of the new contexts using:\n"))
+	fmt.Println(color.YellowString("    kubectl config use-context <context>\n"))
+}
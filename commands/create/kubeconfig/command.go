@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"regexp"
-	"runtime"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/config"
@@ -24,13 +26,18 @@ import (
 	"github.com/giantswarm/micrologger"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/commands/kubeconfigcredential"
 	"github.com/giantswarm/gsctl/confirm"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/batch"
+	"github.com/giantswarm/gsctl/pkg/execcredential"
+	"github.com/giantswarm/gsctl/pkg/kubeconfigfile"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -47,21 +54,44 @@ authenticate with as a kubectl user.
 
 By default, your kubectl config is modified to add user, cluster, and context
 entries. The config file is assumed to be in $HOME/.kube/config. If set, the
-path from the $KUBECONFIG environment variable is used. Certificate files are
-stored in the "certs" subfolder of the gsctl config directory. See 'gsctl info'.
+paths from the $KUBECONFIG environment variable are used. As with kubectl,
+existing entries are updated in the file defining them, new entries are added
+to the first existing file. The previous content of a modified file is kept
+as <file>.bak. kubectl does not have to be installed.
+
+Certificate files are stored in the "certs" subfolder of the gsctl config
+directory. See 'gsctl info'. Use --embed-certs to store the credentials in
+the kubectl config instead.
 
 Alternatively, the --self-contained <path> flag can be used to create a new
 config file with included certificates.
 
+With --exec-plugin, no client certificate is stored. Instead, kubectl calls
+'gsctl kubeconfig-credential' whenever it needs credentials, which creates
+short-lived key pairs as needed and caches them in the "credentials"
+subfolder of the gsctl config directory. This way, kubectl access does not
+expire as long as you are logged in to the API endpoint. Unless --ttl is
+given, these key pairs are valid for 12 hours. kubectl looks up gsctl in
+your $PATH, so the kubeconfig keeps working when gsctl is upgraded.
+
 Examples:
 
   gsctl create kubeconfig -c my0c3
 
   gsctl create kubeconfig -c "Production cluster" --self-contained ./kubeconfig.yaml
 
+  gsctl create kubeconfig -c my0c3 --embed-certs
+
+  gsctl create kubeconfig -c my0c3 --exec-plugin
+
   gsctl create kubeconfig -c my0c3 --ttl 3h -d "Key pair living for only 3 hours"
 
   gsctl create kubeconfig -c "Development cluster" --certificate-organizations system:masters
+
+To add contexts for all clusters matching a label selector, use --selector
+instead of --cluster. The current context is not changed in this case:
+
+  gsctl create kubeconfig --selector environment=testing --parallel 2
 `,
 		PreRun: createKubeconfigPreRunOutput,
 		Run:    createKubeconfigRunOutput,
@@ -74,18 +104,24 @@ Examples:
 	// flag for setting a kubectl context name to use
 	cmdKubeconfigContextName = ""
 
+	// cmdKubeconfigEmbedCerts is the command line flag for storing
+	// credentials in the kubectl config instead of separate files
+	cmdKubeconfigEmbedCerts = false
+
+	// cmdKubeconfigExecPlugin is the command line flag for using
+	// 'gsctl kubeconfig-credential' as an exec credential plugin
+	cmdKubeconfigExecPlugin = false
+
 	arguments Arguments
+
+	// kubeconfigMutex serializes changes to the kubectl config file, as several
+	// kubeconfigs may be created at the same time when using --selector.
+	kubeconfigMutex sync.Mutex
 )
 
 const (
 	createKubeconfigActivityName = "create-kubeconfig"
 
-	// url to intallation instructions
-	kubectlInstallURL = "https://kubernetes.io/docs/tasks/tools/install-kubectl/"
-
-	// windows download page
-	kubectlWindowsInstallURL = "https://github.com/kubernetes/kubernetes/blob/master/CHANGELOG.md"
-
 	// workload cluster internal api prefix
 	tenantInternalAPIPrefix = "internal-api"
 
@@ -105,11 +141,20 @@ type Arguments struct {
 	cnPrefix          string
 	contextName       string
 	description       string
+	embedCerts        bool
+	execCommand       string
+	execConfigDirPath string
+	execPlugin        bool
+	execTTL           string
 	fileSystem        afero.Fs
 	force             bool
 	internalAPI       bool
+	keepContext       bool
+	kubeconfigPaths   []string
 	outputFormat      string
+	parallel          int
 	scheme            string
+	selector          string
 	selfContainedPath string
 	ttlHours          int32
 	useKubie          bool
@@ -147,7 +192,17 @@ func collectArguments(cmd *cobra.Command) (Arguments, error) {
 
 	contextName := cmdKubeconfigContextName
 
-	ttl, err := util.ParseDuration(flags.TTL)
+	ttlFlag := flags.TTL
+	execTTL := ""
+	if cmdKubeconfigExecPlugin {
+		if cmd.Flags().Changed("ttl") {
+			execTTL = flags.TTL
+		} else {
+			ttlFlag = fmt.Sprintf("%dh", int(execcredential.DefaultTTL.Hours()))
+		}
+	}
+
+	ttl, err := util.ParseDuration(ttlFlag)
 	if errors.IsInvalidDurationError(err) {
 		return Arguments{}, microerror.Mask(errors.InvalidDurationError)
 	} else if errors.IsDurationExceededError(err) {
@@ -156,6 +211,11 @@ func collectArguments(cmd *cobra.Command) (Arguments, error) {
 		return Arguments{}, microerror.Mask(err)
 	}
 
+	execConfigDirPath := ""
+	if flags.ConfigDirPath != config.DefaultConfigDirPath {
+		execConfigDirPath = flags.ConfigDirPath
+	}
+
 	// apply deprecated flag if used
 	if cmd.Flags().Changed("tenant-internal") && !cmd.Flags().Changed("internal-api") {
 		flags.InternalAPI = flags.TenantInternal
@@ -169,11 +229,19 @@ func collectArguments(cmd *cobra.Command) (Arguments, error) {
 		cnPrefix:          flags.CNPrefix,
 		contextName:       contextName,
 		description:       description,
+		embedCerts:        cmdKubeconfigEmbedCerts,
+		execCommand:       config.ProgramName,
+		execConfigDirPath: execConfigDirPath,
+		execPlugin:        cmdKubeconfigExecPlugin,
+		execTTL:           execTTL,
 		fileSystem:        config.FileSystem,
 		force:             flags.Force,
 		internalAPI:       flags.InternalAPI,
+		kubeconfigPaths:   kubeconfigfile.Paths(os.Getenv("KUBECONFIG"), config.HomeDirPath),
 		outputFormat:      flags.OutputFormat,
+		parallel:          flags.Parallel,
 		scheme:            scheme,
+		selector:          flags.Selector,
 		selfContainedPath: cmdKubeconfigSelfContained,
 		ttlHours:          int32(ttl.Hours()),
 		useKubie:          flags.UseKubie,
@@ -209,8 +277,6 @@ type JSONOutput struct {
 	Result string `json:"result"`
 	// KubeConfig is a string containing the kubeconfig
 	KubeConfig string `json:"kubeconfig,omitempty"`
-	// Error which occured
-	Error error `json:"error,omitempty"`
 }
 
 func init() {
@@ -219,6 +285,8 @@ func init() {
 	Command.Flags().StringVarP(&flags.CNPrefix, "cn-prefix", "", "", "The common name prefix for the issued certificates 'CN' field.")
 	Command.Flags().StringVarP(&cmdKubeconfigSelfContained, "self-contained", "", "", "Create a self-contained kubectl config with embedded credentials and write it to this path.")
 	Command.Flags().StringVarP(&cmdKubeconfigContextName, "context", "", "", "Set a custom context name. Defaults to 'giantswarm-<cluster-id>'.")
+	Command.Flags().BoolVarP(&cmdKubeconfigEmbedCerts, "embed-certs", "", false, "Store the certificates and key in the kubectl config instead of separate files.")
+	Command.Flags().BoolVarP(&cmdKubeconfigExecPlugin, "exec-plugin", "", false, "Let kubectl get short-lived credentials from gsctl when needed, instead of storing a client certificate.")
 	Command.Flags().StringVarP(&flags.CertificateOrganizations, "certificate-organizations", "", "", "A comma separated list of organizations for the issued certificates 'O' fields.")
 	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, --self-contained will overwrite existing files without interactive confirmation. Also, there will not be any confirmation for TTL > 30d.")
 	Command.Flags().BoolVarP(&flags.TenantInternal, "tenant-internal", "", false, "Replaced by --internal-api.")
@@ -226,8 +294,8 @@ func init() {
 	Command.Flags().BoolVarP(&flags.UseKubie, "kubie", "", false, "Use kubie to set context (requires kubie binary in your path)")
 	Command.Flags().StringVarP(&flags.TTL, "ttl", "", "1d", "Lifetime of the created key pair, e.g. 3h. Allowed units: h, d, w, m, y.")
 	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' will change output to be JSON formatted.", formatting.OutputFormatJSON))
-
-	Command.MarkFlagRequired("cluster")
+	Command.Flags().StringVarP(&flags.Selector, "selector", "l", "", "Label selector query. Adds a context for each matching cluster instead of a single one.")
+	Command.Flags().IntVarP(&flags.Parallel, "parallel", "", batch.DefaultParallel, "Maximum number of kubeconfigs to create at the same time when using --selector.")
 
 	// TODO: remove this flag by ~ March 2021
 	Command.Flags().MarkDeprecated("tenant-internal", "please use --internal-api instead.")
@@ -242,15 +310,13 @@ func createKubeconfigPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	arguments, argsErr = collectArguments(cmd)
 	if argsErr != nil {
 		if errors.IsInvalidDurationError(argsErr) {
-			fmt.Println(color.RedString("The value passed with --ttl is invalid."))
-			fmt.Println("Please provide a number and a unit, e. g. '10h', '1d', '1w'.")
+			errors.PrintError(argsErr, "The value passed with --ttl is invalid.", "Please provide a number and a unit, e. g. '10h', '1d', '1w'.")
 		} else if errors.IsDurationExceededError(argsErr) {
-			fmt.Println(color.RedString("The expiration period passed with --ttl is too long."))
-			fmt.Println("The maximum possible value is the equivalent of 292 years.")
+			errors.PrintError(argsErr, "The expiration period passed with --ttl is too long.", "The maximum possible value is the equivalent of 292 years.")
 		} else {
-			fmt.Println(color.RedString(argsErr.Error()))
+			errors.PrintError(argsErr, argsErr.Error(), "")
 		}
-		os.Exit(1)
+		errors.Exit(argsErr)
 	}
 
 	if !arguments.force && arguments.ttlHours >= maxSafeTTLHours {
@@ -268,7 +334,7 @@ func createKubeconfigPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	var headline string
@@ -277,16 +343,6 @@ func createKubeconfigPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	switch {
 	case errors.IsCommandAbortedError(err):
 		headline = "File not overwritten, no kubeconfig created."
-	case errors.IsKubectlMissingError(err):
-		headline = "kubectl is not installed"
-		if runtime.GOOS == "darwin" {
-			subtext = "Please install via 'brew install kubernetes-cli' or visit\n"
-			subtext += fmt.Sprintf("%s for information on how to install kubectl", kubectlInstallURL)
-		} else if runtime.GOOS == "linux" {
-			subtext = fmt.Sprintf("Please visit %s for information on how to install kubectl", kubectlInstallURL)
-		} else if runtime.GOOS == "windows" {
-			subtext = fmt.Sprintf("Please visit %s to download a recent kubectl binary.", kubectlWindowsInstallURL)
-		}
 	case errors.IsInvalidCNPrefixError(err):
 		headline = "Bad characters in CN prefix (--cn-prefix)"
 		subtext = "Please use these characters only: a-z A-Z 0-9 . @ -"
@@ -295,11 +351,8 @@ func createKubeconfigPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	}
 
 	// print output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
 
 }
 
@@ -312,9 +365,36 @@ func verifyCreateKubeconfigPreconditions(args Arguments, cmdLineArgs []string) e
 	if config.Config.Token == "" && args.authToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.clusterNameOrID == "" {
+	if args.clusterNameOrID == "" && args.selector == "" {
 		return microerror.Mask(errors.ClusterNameOrIDMissingError)
 	}
+	if args.selector != "" {
+		switch {
+		case args.clusterNameOrID != "":
+			return microerror.Maskf(errors.ConflictingFlagsError, "--selector and --cluster can not be used together")
+		case args.selfContainedPath != "":
+			return microerror.Maskf(errors.ConflictingFlagsError, "--selector and --self-contained can not be used together")
+		case args.contextName != "":
+			return microerror.Maskf(errors.ConflictingFlagsError, "--selector and --context can not be used together")
+		case args.useKubie:
+			return microerror.Maskf(errors.ConflictingFlagsError, "--selector and --kubie can not be used together")
+		case args.outputFormat != "":
+			return microerror.Maskf(errors.ConflictingFlagsError, "--selector and --output can not be used together")
+		}
+	}
+	if args.embedCerts && args.selfContainedPath != "" {
+		return microerror.Maskf(errors.ConflictingFlagsError, "--embed-certs and --self-contained can not be used together")
+	}
+	if args.execPlugin {
+		switch {
+		case args.selfContainedPath != "":
+			return microerror.Maskf(errors.ConflictingFlagsError, "--exec-plugin and --self-contained can not be used together")
+		case args.outputFormat != "":
+			return microerror.Maskf(errors.ConflictingFlagsError, "--exec-plugin and --output can not be used together")
+		case args.ttlHours < 1:
+			return microerror.Maskf(errors.InvalidDurationError, "the TTL must be at least one hour")
+		}
+	}
 	if args.outputFormat != "" && args.outputFormat != formatting.OutputFormatJSON {
 		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is is invalid for gsctl create kubeconfig. Valid options: '%s'", args.outputFormat, formatting.OutputFormatJSON))
 	}
@@ -327,11 +407,6 @@ func verifyCreateKubeconfigPreconditions(args Arguments, cmdLineArgs []string) e
 		}
 	}
 
-	kubectlOkay := util.CheckKubectl()
-	if !kubectlOkay {
-		return microerror.Mask(errors.KubectlMissingError)
-	}
-
 	// ask for confirmation to overwrite existing file
 	if args.selfContainedPath != "" && !args.force {
 		if _, err := os.Stat(args.selfContainedPath); !os.IsNotExist(err) {
@@ -349,34 +424,36 @@ func verifyCreateKubeconfigPreconditions(args Arguments, cmdLineArgs []string) e
 func createKubeconfigRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	ctx := context.Background()
 
+	if arguments.selector != "" {
+		printBatchResult(ctx, arguments)
+		return
+	}
+
 	result, err := createKubeconfig(ctx, arguments)
 
-	if arguments.outputFormat == formatting.OutputFormatJSON {
-		printJSONOutput(result, err)
+	// Errors are printed as JSON by errors.PrintError.
+	if arguments.outputFormat == formatting.OutputFormatJSON && err == nil {
+		printJSONOutput(result)
 		return
 	}
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline string
 		var subtext string
 
 		switch {
-		case util.IsCouldNotSetKubectlClusterError(err):
-			headline = "Error: " + err.Error()
-			subtext = "API endpoint would be: " + result.apiEndpoint
-			subtext += "\nCA file path would be: " + result.caCertPath
-		case util.IsCouldNotSetKubectlCredentialsError(err):
-			headline = "Error: " + err.Error()
-			subtext = "Client key path would be: " + result.clientKeyPath
-			subtext += "\nClient certificate path would be: " + result.clientCertPath
-		case util.IsCouldNotSetKubectlContextError(err):
-			headline = "Error: " + err.Error()
-		case util.IsCouldNotUseKubectlContextError(err):
-			headline = "Error: " + err.Error()
-			subtext = "Context name to select is: giantswarm-" + arguments.clusterNameOrID
+		case kubeconfigfile.IsInvalidKubeconfig(err):
+			headline = "Error: The kubectl config could not be parsed"
+			subtext = fmt.Sprintf("Details: %s\nPlease fix or remove the file.", err.Error())
+		case kubeconfigfile.IsLocked(err):
+			headline = "Error: The kubectl config is in use"
+			subtext = err.Error()
+		case kubeconfigfile.IsWrite(err):
+			headline = "Error: The kubectl config could not be written"
+			subtext = fmt.Sprintf("Details: %s", err.Error())
 		case errors.IsClusterNotFoundError(err):
 			headline = fmt.Sprintf("Error: Cluster '%s' does not exist.", arguments.clusterNameOrID)
 			subtext = "Please check the name/ID spelling or list clusters using 'gsctl list clusters'."
@@ -392,11 +469,8 @@ func createKubeconfigRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		}
 
 		// Print error output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	// Success output
@@ -439,7 +513,9 @@ func createKubeconfigRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 			panic(err)
 		}
 	} else {
-		if arguments.verbose {
+		if arguments.execPlugin {
+			fmt.Println("kubectl will get new credentials via 'gsctl kubeconfig-credential' when needed.")
+		} else if arguments.verbose {
 			fmt.Println(color.WhiteString("Certificate and key files written to:"))
 			fmt.Println(color.WhiteString(result.caCertPath))
 			fmt.Println(color.WhiteString(result.clientCertPath))
@@ -456,17 +532,11 @@ func createKubeconfigRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	}
 }
 
-func printJSONOutput(result createKubeconfigResult, creationErr error) {
+func printJSONOutput(result createKubeconfigResult) {
 	var outputBytes []byte
 	var err error
-	var jsonResult JSONOutput
 
-	// handle errors
-	if creationErr != nil {
-		jsonResult = JSONOutput{Result: "error", Error: creationErr}
-	} else {
-		jsonResult = JSONOutput{Result: "ok", KubeConfig: string(result.selfContainedYAMLBytes)}
-	}
+	jsonResult := JSONOutput{Result: "ok", KubeConfig: string(result.selfContainedYAMLBytes)}
 
 	outputBytes, err = json.MarshalIndent(jsonResult, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
 	if err != nil {
@@ -475,9 +545,6 @@ func printJSONOutput(result createKubeconfigResult, creationErr error) {
 	}
 
 	fmt.Println(string(outputBytes))
-	if creationErr != nil {
-		os.Exit(1)
-	}
 }
 
 // getClusterDetails fetches cluster details to get the workload cluster API endpoint,
@@ -581,32 +648,34 @@ func createKubeconfig(ctx context.Context, args Arguments) (createKubeconfigResu
 		result.selfContainedYAMLBytes = yamlBytes
 
 	} else if args.selfContainedPath == "" {
-		// modify the given kubeconfig file
-		result.caCertPath = util.StoreCaCertificate(args.fileSystem, config.CertsDirPath,
-			clusterID, response.Payload.CertificateAuthorityData)
-		result.clientCertPath = util.StoreClientCertificate(args.fileSystem, config.CertsDirPath,
-			clusterID, response.Payload.ID, response.Payload.ClientCertificateData)
-		result.clientKeyPath = util.StoreClientKey(args.fileSystem, config.CertsDirPath,
-			clusterID, response.Payload.ID, response.Payload.ClientKeyData)
 		result.contextName = args.contextName
 		if result.contextName == "" {
 			result.contextName = "giantswarm-" + clusterID
 		}
 
-		// edit kubectl config
-		if err := util.KubectlSetCluster(clusterID, result.apiEndpoint, result.caCertPath); err != nil {
-			return result, microerror.Mask(util.CouldNotSetKubectlClusterError)
-		}
-		if err := util.KubectlSetCredentials(clusterID, result.clientKeyPath, result.clientCertPath); err != nil {
-			return result, microerror.Mask(util.CouldNotSetKubectlCredentialsError)
-		}
-		if err := util.KubectlSetContext(result.contextName, clusterID); err != nil {
-			return result, microerror.Mask(util.CouldNotSetKubectlContextError)
+		if !args.embedCerts {
+			result.caCertPath = util.StoreCaCertificate(args.fileSystem, config.CertsDirPath,
+				clusterID, response.Payload.CertificateAuthorityData)
 		}
-		if !args.useKubie {
-			if err := util.KubectlUseContext(result.contextName); err != nil {
-				return result, microerror.Mask(util.CouldNotUseKubectlContextError)
+		if args.execPlugin {
+			// The key pair just created is handed to kubectl first.
+			c := execcredential.New(args.apiEndpoint, clusterID, response.Payload.ID,
+				response.Payload.ClientCertificateData, response.Payload.ClientKeyData,
+				time.Duration(response.Payload.TTLHours)*time.Hour, time.Now())
+			err = execcredential.Store(args.fileSystem, path.Join(config.ConfigDirPath, execcredential.DirName), c)
+			if err != nil {
+				return result, microerror.Maskf(errors.CouldNotWriteFileError, err.Error())
 			}
+		} else if !args.embedCerts {
+			result.clientCertPath = util.StoreClientCertificate(args.fileSystem, config.CertsDirPath,
+				clusterID, response.Payload.ID, response.Payload.ClientCertificateData)
+			result.clientKeyPath = util.StoreClientKey(args.fileSystem, config.CertsDirPath,
+				clusterID, response.Payload.ID, response.Payload.ClientKeyData)
+		}
+
+		err = updateKubeconfig(args, clusterID, result, response)
+		if err != nil {
+			return result, microerror.Mask(err)
 		}
 	} else {
 		// create a self-contained kubeconfig
@@ -626,6 +695,123 @@ func createKubeconfig(ctx context.Context, args Arguments) (createKubeconfigResu
 	return result, nil
 }
 
+// updateKubeconfig adds or updates the cluster, user and context entries in
+// the kubectl config, like 'kubectl config set-cluster', 'set-credentials',
+// 'set-context' and 'use-context' would do.
+func updateKubeconfig(args Arguments, clusterID string, result createKubeconfigResult, response *key_pairs.AddKeyPairOK) error {
+	paths := args.kubeconfigPaths
+	if len(paths) == 0 {
+		paths = kubeconfigfile.Paths(os.Getenv("KUBECONFIG"), config.HomeDirPath)
+	}
+
+	kubeconfigMutex.Lock()
+	defer kubeconfigMutex.Unlock()
+
+	k, err := kubeconfigfile.Open(kubeconfigfile.Config{
+		FileSystem: args.fileSystem,
+		Paths:      paths,
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	defer k.Close()
+
+	clusterName := "giantswarm-" + clusterID
+	userName := "giantswarm-" + clusterID + "-user"
+	merged := k.Merged()
+
+	// Existing entries are updated, so that settings we don't manage, like
+	// a context's namespace, are kept.
+	cluster := clientcmdapi.NewCluster()
+	if existing, ok := merged.Clusters[clusterName]; ok {
+		cluster = existing.DeepCopy()
+	}
+	cluster.Server = result.apiEndpoint
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	if existing, ok := merged.AuthInfos[userName]; ok {
+		authInfo = existing.DeepCopy()
+	}
+
+	if args.embedCerts {
+		cluster.CertificateAuthority = ""
+		cluster.CertificateAuthorityData = []byte(response.Payload.CertificateAuthorityData)
+	} else {
+		cluster.CertificateAuthority = result.caCertPath
+		cluster.CertificateAuthorityData = nil
+	}
+
+	switch {
+	case args.execPlugin:
+		authInfo.ClientCertificate = ""
+		authInfo.ClientCertificateData = nil
+		authInfo.ClientKey = ""
+		authInfo.ClientKeyData = nil
+		authInfo.Exec = execConfig(args, clusterID)
+	case args.embedCerts:
+		authInfo.ClientCertificate = ""
+		authInfo.ClientCertificateData = []byte(response.Payload.ClientCertificateData)
+		authInfo.ClientKey = ""
+		authInfo.ClientKeyData = []byte(response.Payload.ClientKeyData)
+		authInfo.Exec = nil
+	default:
+		authInfo.ClientCertificate = result.clientCertPath
+		authInfo.ClientCertificateData = nil
+		authInfo.ClientKey = result.clientKeyPath
+		authInfo.ClientKeyData = nil
+		authInfo.Exec = nil
+	}
+
+	context := clientcmdapi.NewContext()
+	if existing, ok := merged.Contexts[result.contextName]; ok {
+		context = existing.DeepCopy()
+	}
+	context.Cluster = clusterName
+	context.AuthInfo = userName
+
+	k.SetCluster(clusterName, cluster)
+	k.SetAuthInfo(userName, authInfo)
+	k.SetContext(result.contextName, context)
+	if !args.useKubie && !args.keepContext {
+		k.UseContext(result.contextName)
+	}
+
+	err = k.Save()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// execConfig returns the exec credential plugin configuration calling
+// 'gsctl kubeconfig-credential' for the cluster.
+func execConfig(args Arguments, clusterID string) *clientcmdapi.ExecConfig {
+	execArgs := []string{
+		kubeconfigcredential.Command.Name(),
+		"--cluster", clusterID,
+		"--endpoint", args.apiEndpoint,
+	}
+	if args.execTTL != "" {
+		execArgs = append(execArgs, "--ttl", args.execTTL)
+	}
+	if args.certOrgs != "" {
+		execArgs = append(execArgs, "--certificate-organizations", args.certOrgs)
+	}
+	if args.cnPrefix != "" {
+		execArgs = append(execArgs, "--cn-prefix", args.cnPrefix)
+	}
+	if args.execConfigDirPath != "" {
+		execArgs = append(execArgs, "--config-dir", args.execConfigDirPath)
+	}
+
+	return &clientcmdapi.ExecConfig{
+		APIVersion: execcredential.APIVersion,
+		Command:    args.execCommand,
+		Args:       execArgs,
+	}
+}
+
 func createKubeconfigYAML(ctx context.Context, clusterID, apiEndpoint string, response *key_pairs.AddKeyPairOK) ([]byte, error) {
 	var yamlBytes []byte
 	logger, err := micrologger.New(micrologger.Config{
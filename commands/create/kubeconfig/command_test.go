@@ -12,7 +12,9 @@ import (
 
 	"github.com/spf13/afero"
 
+	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/execcredential"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -278,6 +280,144 @@ func Test_CreateKubeconfigCustomContext(t *testing.T) {
 	}
 }
 
+// Test_CreateKubeconfigEmbedCerts tests embedding the credentials when
+// several kubeconfig files are given.
+func Test_CreateKubeconfigEmbedCerts(t *testing.T) {
+	mockServer := makeMockServer()
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	kubeConfigPath, err := testutils.TempKubeconfig(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configDir, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first file doesn't exist, so entries go to the second one.
+	missingPath := path.Join(path.Dir(kubeConfigPath), "missing")
+
+	args := Arguments{
+		authToken:       "auth-token",
+		apiEndpoint:     mockServer.URL,
+		clusterNameOrID: "test-cluster-id",
+		embedCerts:      true,
+		fileSystem:      fs,
+		kubeconfigPaths: []string{missingPath, kubeConfigPath},
+	}
+
+	err = verifyCreateKubeconfigPreconditions(args, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := createKubeconfig(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.caCertPath != "" || result.clientCertPath != "" || result.clientKeyPath != "" {
+		t.Errorf("Expected no certificate files, got %#v", result)
+	}
+
+	content, err := afero.ReadFile(fs, kubeConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"current-context: giantswarm-test-cluster-id",
+		"certificate-authority-data:",
+		"client-certificate-data:",
+		"client-key-data:",
+		"server: " + result.apiEndpoint,
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected %q in kubeconfig:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(string(content), configDir) {
+		t.Errorf("Expected no paths in kubeconfig:\n%s", content)
+	}
+
+	if exists, _ := afero.Exists(fs, missingPath); exists {
+		t.Errorf("Expected %s not to be created", missingPath)
+	}
+	if exists, _ := afero.Exists(fs, kubeConfigPath+".bak"); !exists {
+		t.Errorf("Expected backup of %s", kubeConfigPath)
+	}
+}
+
+// Test_CreateKubeconfigExecPlugin tests creating a user entry calling
+// 'gsctl kubeconfig-credential', with the first key pair cached.
+func Test_CreateKubeconfigExecPlugin(t *testing.T) {
+	mockServer := makeMockServer()
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	kubeConfigPath, err := testutils.TempKubeconfig(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configDir, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		authToken:         "auth-token",
+		apiEndpoint:       mockServer.URL,
+		clusterNameOrID:   "test-cluster-id",
+		execCommand:       "gsctl",
+		execConfigDirPath: "/custom/config",
+		execPlugin:        true,
+		execTTL:           "2d",
+		fileSystem:        fs,
+		kubeconfigPaths:   []string{kubeConfigPath},
+		ttlHours:          48,
+	}
+
+	err = verifyCreateKubeconfigPreconditions(args, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := createKubeconfig(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.caCertPath == "" || result.clientCertPath != "" || result.clientKeyPath != "" {
+		t.Errorf("Expected CA file only, got %#v", result)
+	}
+
+	content, err := afero.ReadFile(fs, kubeConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"current-context: giantswarm-test-cluster-id",
+		"certificate-authority: " + result.caCertPath,
+		"apiVersion: client.authentication.k8s.io/v1beta1",
+		"command: gsctl",
+		"- kubeconfig-credential\n      - --cluster\n      - test-cluster-id\n      - --endpoint\n      - " + mockServer.URL + "\n      - --ttl\n      - 2d\n      - --config-dir\n      - /custom/config\n",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected %q in kubeconfig:\n%s", expected, content)
+		}
+	}
+	if strings.Contains(string(content), "client-certificate") || strings.Contains(string(content), "client-key") {
+		t.Errorf("Expected no client certificate in kubeconfig:\n%s", content)
+	}
+
+	cached, err := execcredential.Load(fs, path.Join(configDir, execcredential.DirName), mockServer.URL, "test-cluster-id")
+	if err != nil {
+		t.Fatalf("Expected cached credential, got %#v", err)
+	}
+	if cached.KeyPairID != "48:b9:01:ce:34:8f:b2:08:d3:4f:8c:bb:5e:2f:d7:b6:bc:ae:5c:98" {
+		t.Errorf("Unexpected cached credential %#v", cached)
+	}
+}
+
 // Test_CreateKubeconfigNoConnection tests what happens if there is no API connection
 func Test_CreateKubeconfigNoConnection(t *testing.T) {
 	// temporary kubeconfig file
@@ -349,11 +489,9 @@ func Test_CreateKubeconfigJSONOutput(t *testing.T) {
 		t.Error("Expected non-empty result.selfContainedYAMLBytes, got empty slice")
 	}
 
-	print := printJSONOutput
-
 	jsonRepresentation := testutils.CaptureOutput(func() {
 		// output
-		print(result, err)
+		printJSONOutput(result)
 	})
 
 	// t.Error(jsonRepresentation)
@@ -375,3 +513,61 @@ func Test_CreateKubeconfigJSONOutput(t *testing.T) {
 		t.Error("Kubeconfig doesn't contain the key certificate-authority-data")
 	}
 }
+
+// Test_VerifyPreconditionsSelector tests flags conflicting with --selector.
+func Test_VerifyPreconditionsSelector(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := Arguments{
+		apiEndpoint: "https://mock-url",
+		authToken:   "token",
+		selector:    "environment=testing",
+	}
+
+	var testCases = []func(args *Arguments){
+		func(args *Arguments) { args.clusterNameOrID = "cluster-id" },
+		func(args *Arguments) { args.selfContainedPath = "/tmp/kubeconfig" },
+		func(args *Arguments) { args.contextName = "my-context" },
+		func(args *Arguments) { args.useKubie = true },
+		func(args *Arguments) { args.outputFormat = "json" },
+	}
+
+	for i, modify := range testCases {
+		args := base
+		modify(&args)
+
+		err := verifyCreateKubeconfigPreconditions(args, []string{})
+		if !errors.IsConflictingFlagsError(err) {
+			t.Errorf("Case %d - Expected ConflictingFlagsError, got %#v", i, err)
+		}
+	}
+
+	args := base
+	args.selector = ""
+	args.clusterNameOrID = "cluster-id"
+	args.embedCerts = true
+	args.selfContainedPath = "/tmp/kubeconfig"
+	err = verifyCreateKubeconfigPreconditions(args, []string{})
+	if !errors.IsConflictingFlagsError(err) {
+		t.Errorf("Expected ConflictingFlagsError for --embed-certs with --self-contained, got %#v", err)
+	}
+
+	args.embedCerts = false
+	args.execPlugin = true
+	args.ttlHours = 12
+	err = verifyCreateKubeconfigPreconditions(args, []string{})
+	if !errors.IsConflictingFlagsError(err) {
+		t.Errorf("Expected ConflictingFlagsError for --exec-plugin with --self-contained, got %#v", err)
+	}
+
+	args.selfContainedPath = ""
+	args.outputFormat = "json"
+	err = verifyCreateKubeconfigPreconditions(args, []string{})
+	if !errors.IsConflictingFlagsError(err) {
+		t.Errorf("Expected ConflictingFlagsError for --exec-plugin with --output, got %#v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/config"
@@ -20,6 +21,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/pkg/provider"
+	"github.com/giantswarm/gsctl/pkg/wait"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -130,6 +132,11 @@ Examples:
   By setting this value to '-1', the maximum price will be set
   to the on-demand price of the instance.
 
+  To block until the node pool's worker nodes are ready, add --wait. The
+  --timeout flag sets the maximum time to wait:
+
+  gsctl create nodepool f01r4 --wait --timeout 20m
+
 `,
 		// PreRun checks a few general things, like authentication.
 		PreRun: printValidation,
@@ -166,6 +173,8 @@ func initFlags() {
 	Command.Flags().BoolVarP(&flags.AWSUseAlikeInstanceTypes, "aws-use-alike-instance-types", "", false, "Use similar instance type in your node pool (AWS only). This list is maintained by Giant Swarm at the moment. Eg if you select m5.xlarge then the node pool can fall back on m4.xlarge too.")
 	Command.Flags().Int64VarP(&flags.AWSOnDemandBaseCapacity, "aws-on-demand-base-capacity", "", 0, "Number of on-demand instances that this node pool needs to have until spot instances are used (AWS only). Default is 0")
 	Command.Flags().Int64VarP(&flags.AWSSpotPercentage, "aws-spot-percentage", "", 0, "Percentage of spot instances used once the on-demand base capacity is fullfilled (AWS only). A number of 40 would mean that 60% will be on-demand and 40% will be spot instances.")
+	Command.Flags().BoolVarP(&flags.Wait, "wait", "", false, "Wait until the node pool's worker nodes are ready.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "timeout", "", wait.DefaultTimeout, "Maximum time to wait when using --wait.")
 	Command.Flags().BoolVarP(&flags.AzureSpotInstances, "azure-spot-instances", "", false, "Whether the node pool must use spot instances or on-demand.")
 	Command.Flags().Float64VarP(&flags.AzureSpotInstancesMaxPrice, "azure-spot-instances-max-price", "", -1, "Max bid hourly price for a single instance. -1 means on-demand price.")
 }
@@ -193,6 +202,8 @@ type Arguments struct {
 	Scheme                     string
 	UserProvidedToken          string
 	Verbose                    bool
+	Wait                       bool
+	WaitTimeout                time.Duration
 }
 
 type result struct {
@@ -259,6 +270,8 @@ func collectArguments(cmd *cobra.Command, positionalArgs []string) (Arguments, e
 		Scheme:                     scheme,
 		UserProvidedToken:          flags.Token,
 		Verbose:                    flags.Verbose,
+		Wait:                       flags.Wait,
+		WaitTimeout:                flags.WaitTimeout,
 	}, nil
 }
 
@@ -354,7 +367,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	headline := ""
@@ -374,11 +387,8 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	// print output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
 }
 
 // createNodePool is the business function sending our creation request to the API
@@ -490,9 +500,13 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	r, err := createNodePool(arguments, clusterID, clientWrapper)
+	if err == nil && r != nil && arguments.Wait {
+		fmt.Println(color.GreenString("New node pool '%s' (ID '%s') in cluster '%s' is launching.", r.nodePoolName, r.nodePoolID, clusterID))
+		err = waitForNodePool(arguments, clientWrapper, clusterID, r.nodePoolID)
+	}
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		headline := ""
@@ -500,16 +514,16 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 
 		switch {
 		// If there are specific errors to handle, add them here.
+		case wait.IsTimeout(err):
+			headline = "Timeout"
+			subtext = fmt.Sprintf("The worker nodes of node pool '%s' did not become ready in time.", r.nodePoolID)
 		default:
 			headline = err.Error()
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	if r == nil {
@@ -521,12 +535,29 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		os.Exit(1)
 	}
 
-	fmt.Println(color.GreenString("New node pool '%s' (ID '%s') in cluster '%s' is launching.", r.nodePoolName, r.nodePoolID, clusterID))
+	if arguments.Wait {
+		fmt.Println(color.GreenString("New node pool '%s' (ID '%s') in cluster '%s' is ready.", r.nodePoolName, r.nodePoolID, clusterID))
+	} else {
+		fmt.Println(color.GreenString("New node pool '%s' (ID '%s') in cluster '%s' is launching.", r.nodePoolName, r.nodePoolID, clusterID))
+	}
 	fmt.Printf("Use this command to inspect details for the new node pool:\n\n")
 	fmt.Println(color.YellowString("    gsctl show nodepool %s/%s", clusterID, r.nodePoolID))
 	fmt.Printf("\n")
 }
 
+// waitForNodePool polls the API until the node pool's worker nodes are ready.
+func waitForNodePool(args Arguments, clientWrapper *client.Wrapper, clusterID, nodePoolID string) error {
+	w, err := wait.New(wait.Config{
+		ClientWrapper: clientWrapper,
+		Timeout:       args.WaitTimeout,
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return w.NodePoolReady(clusterID, nodePoolID)
+}
+
 func getInstallationInfo(endpoint, userProvidedToken string) (*models.V4InfoResponse, error) {
 	clientWrapper, err := client.NewWithConfig(endpoint, userProvidedToken)
 	if err != nil {
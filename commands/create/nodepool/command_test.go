@@ -14,6 +14,7 @@ import (
 	"github.com/giantswarm/gsctl/pkg/provider"
 
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/wait"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -77,6 +78,7 @@ func TestCollectArgs(t *testing.T) {
 				APIEndpoint:                mockServer.URL,
 				AuthToken:                  "some-token",
 				ClusterNameOrID:            "cluster-id",
+				WaitTimeout:                wait.DefaultTimeout,
 				Name:                       "my-name",
 				Scheme:                     "giantswarm",
 				Provider:                   "aws",
@@ -101,6 +103,7 @@ func TestCollectArgs(t *testing.T) {
 				APIEndpoint:                mockServer.URL,
 				AuthToken:                  "some-token",
 				ClusterNameOrID:            "some-cluster-id",
+				WaitTimeout:                wait.DefaultTimeout,
 				Name:                       "my-nodepool-name",
 				Scheme:                     "giantswarm",
 				AvailabilityZonesNum:       3,
@@ -126,6 +129,7 @@ func TestCollectArgs(t *testing.T) {
 				APIEndpoint:                mockServer.URL,
 				AuthToken:                  "some-token",
 				ClusterNameOrID:            "a-cluster-id",
+				WaitTimeout:                wait.DefaultTimeout,
 				Scheme:                     "giantswarm",
 				AvailabilityZonesList:      []string{"myzonea", "myzoneb", "myzonec"},
 				Provider:                   "aws",
@@ -147,6 +151,7 @@ func TestCollectArgs(t *testing.T) {
 				APIEndpoint:                mockServer.URL,
 				AuthToken:                  "some-token",
 				ClusterNameOrID:            "another-cluster-id",
+				WaitTimeout:                wait.DefaultTimeout,
 				ScalingMax:                 0,
 				ScalingMin:                 5,
 				ScalingMinSet:              true,
@@ -170,6 +175,7 @@ func TestCollectArgs(t *testing.T) {
 				APIEndpoint:                mockServer.URL,
 				AuthToken:                  "some-token",
 				ClusterNameOrID:            "another-cluster-id",
+				WaitTimeout:                wait.DefaultTimeout,
 				ScalingMax:                 5,
 				Scheme:                     "giantswarm",
 				Provider:                   "aws",
@@ -191,6 +197,7 @@ func TestCollectArgs(t *testing.T) {
 				APIEndpoint:                mockServer.URL,
 				AuthToken:                  "some-token",
 				ClusterNameOrID:            "another-cluster-id",
+				WaitTimeout:                wait.DefaultTimeout,
 				VmSize:                     "something-large",
 				Scheme:                     "giantswarm",
 				Provider:                   "aws",
@@ -0,0 +1,222 @@
+// Package app implements the "delete app" command.
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/flags"
+)
+
+var (
+	// Command is the cobra command for 'gsctl delete app'
+	Command = &cobra.Command{
+		Use: "app <cluster-name/cluster-id>/<app-name>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Delete an app",
+		Long: `Uninstall an app from a cluster.
+
+Deleting an app removes all resources the app created in the cluster.
+Data stored in persistent volumes may be lost, depending on the app.
+
+Examples:
+
+  To delete app 'external-dns' from cluster 'f01r4', use this command:
+
+    gsctl delete app f01r4/external-dns
+
+  To prevent the confirmation questions, apply --force:
+
+    gsctl delete app f01r4/external-dns --force
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+)
+
+const (
+	activityName = "delete-app"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required (risky!).")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AppName           string
+	AuthToken         string
+	ClusterNameOrID   string
+	Force             bool
+	UserProvidedToken string
+	Verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments(positionalArgs []string) (*Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	parts := strings.Split(positionalArgs[0], "/")
+
+	if len(parts) < 2 {
+		return nil, microerror.Maskf(errors.InvalidAppArgumentError, "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details.")
+	}
+
+	return &Arguments{
+		APIEndpoint:       endpoint,
+		AppName:           parts[1],
+		AuthToken:         token,
+		ClusterNameOrID:   parts[0],
+		Force:             flags.Force,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+	}, nil
+}
+
+func verifyPreconditions(args *Arguments) error {
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.ClusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+	if args.AppName == "" {
+		return microerror.Mask(errors.AppNameMissingError)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments, err := collectArguments(positionalArgs)
+	if err == nil {
+		err = verifyPreconditions(arguments)
+	}
+
+	if err == nil {
+		return
+	}
+
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsInvalidAppArgument(err):
+		headline = "Invalid argument syntax"
+		subtext = "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details."
+	case errors.IsAppNameMissing(err):
+		headline = "No app name specified."
+		subtext = "Please specify an app name. Use --help for details."
+	default:
+		headline = "Unknown error"
+		subtext = fmt.Sprintf("Details: %#v", err)
+	}
+
+	// print output
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
+}
+
+// deleteApp is the business function sending our deletion request to the API
+// and returning true for success or an error.
+func deleteApp(args *Arguments) (bool, error) {
+	// confirmation
+	if !args.Force {
+		question := fmt.Sprintf("Do you really want to delete app '%s' from cluster '%s'?", args.AppName, args.ClusterNameOrID)
+		confirmed := confirm.Ask(question)
+		if !confirmed {
+			return false, nil
+		}
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	_, err = clientWrapper.DeleteApp(clusterID, args.AppName, auxParams)
+	if clienterror.IsAccessForbiddenError(err) {
+		return false, microerror.Mask(errors.AccessForbiddenError)
+	} else if clienterror.IsNotFoundError(err) {
+		// Check whether the cluster exists
+		_, detailsErr := clientWrapper.GetApps(clusterID, auxParams)
+		if detailsErr == nil {
+			// Cluster exists, app does not exist.
+			return false, microerror.Mask(errors.AppNotFoundError)
+		}
+
+		return false, microerror.Mask(errors.ClusterNotFoundError)
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return true, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	arguments, _ := collectArguments(positionalArgs)
+	deleted, err := deleteApp(arguments)
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		headline := ""
+		subtext := ""
+
+		switch {
+		case errors.IsAppNotFound(err):
+			headline = "App not found"
+			subtext = fmt.Sprintf("Could not find an app named '%s' in this cluster. Check 'gsctl list apps %s' to make sure.", arguments.AppName, arguments.ClusterNameOrID)
+		case errors.IsClusterNotFoundError(err):
+			headline = "Cluster not found"
+			subtext = fmt.Sprintf("Could not find a cluster with name/ID %s. Check 'gsctl list clusters' to make sure.", arguments.ClusterNameOrID)
+		default:
+			headline = err.Error()
+		}
+
+		// print output
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
+	}
+
+	if deleted {
+		fmt.Println(color.GreenString("App '%s' will be deleted from cluster '%s'.", arguments.AppName, arguments.ClusterNameOrID))
+	} else if arguments.Verbose {
+		fmt.Println(color.WhiteString("Aborted."))
+	}
+}
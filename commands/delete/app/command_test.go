@@ -0,0 +1,153 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+// configYAML is a mock configuration used by some of the tests.
+const configYAML = `last_version_check: 0001-01-01T00:00:00Z
+endpoints:
+  https://foo:
+    email: email@example.com
+    token: some-token
+selected_endpoint: https://foo
+updated: 2017-09-29T11:23:15+02:00
+`
+
+// TestCollectArgs tests whether collectArguments produces the expected results.
+func TestCollectArgs(t *testing.T) {
+	var testCases = []struct {
+		positionalArguments []string
+		flags               []string
+		resultingArgs       *Arguments
+		errorMatcher        func(error) bool
+	}{
+		{
+			[]string{"clusterid/external-dns"},
+			[]string{},
+			&Arguments{
+				APIEndpoint:     "https://foo",
+				AppName:         "external-dns",
+				AuthToken:       "some-token",
+				ClusterNameOrID: "clusterid",
+			},
+			nil,
+		},
+		{
+			[]string{"clusterid/external-dns"},
+			[]string{"--force"},
+			&Arguments{
+				APIEndpoint:     "https://foo",
+				AppName:         "external-dns",
+				AuthToken:       "some-token",
+				ClusterNameOrID: "clusterid",
+				Force:           true,
+			},
+			nil,
+		},
+		{
+			[]string{"string-without-slash"},
+			[]string{"--force"},
+			nil,
+			errors.IsInvalidAppArgument,
+		},
+	}
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			initFlags()
+			Command.ParseFlags(tc.flags)
+			args, err := collectArguments(tc.positionalArguments)
+			if err != nil {
+				if tc.errorMatcher == nil {
+					t.Errorf("Case %d - Unexpected error '%s'", i, err)
+				} else if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error of unexpected type: '%s'", i, err)
+				}
+			} else if tc.errorMatcher != nil {
+				t.Errorf("Case %d - Expected error but got nil", i)
+			}
+			if diff := cmp.Diff(tc.resultingArgs, args); diff != "" {
+				t.Errorf("Case %d - Resulting args unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+// TestDeleteApp tests the deletion request and its error cases.
+func TestDeleteApp(t *testing.T) {
+	var testCases = []struct {
+		deleteStatus int
+		errorMatcher func(error) bool
+	}{
+		{http.StatusOK, nil},
+		{http.StatusNotFound, errors.IsAppNotFound},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/v4/clusters/":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`[{"id": "f01r4", "name": "Name of the cluster", "owner": "acme"}]`))
+				case r.Method == http.MethodGet && r.URL.Path == "/v4/clusters/f01r4/apps/":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`[]`))
+				case r.Method == http.MethodDelete && r.URL.Path == "/v4/clusters/f01r4/apps/external-dns/":
+					w.WriteHeader(tc.deleteStatus)
+					if tc.deleteStatus == http.StatusOK {
+						w.Write([]byte(`{"code": "RESOURCE_DELETED", "message": "The app has been deleted."}`))
+					} else {
+						w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "The app could not be found."}`))
+					}
+				default:
+					t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+				}
+			}))
+			defer mockServer.Close()
+
+			fs := afero.NewMemMapFs()
+			_, err := testutils.TempConfig(fs, configYAML)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deleted, err := deleteApp(&Arguments{
+				APIEndpoint:       mockServer.URL,
+				AppName:           "external-dns",
+				AuthToken:         "token",
+				ClusterNameOrID:   "Name of the cluster",
+				Force:             true,
+				UserProvidedToken: "token",
+			})
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Unexpected error: %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Case %d - Unexpected error: %#v", i, err)
+			}
+			if !deleted {
+				t.Errorf("Case %d - Expected app to be deleted", i)
+			}
+		})
+	}
+}
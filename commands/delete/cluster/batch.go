@@ -0,0 +1,108 @@
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/pkg/batch"
+	"github.com/giantswarm/gsctl/pkg/wait"
+)
+
+// deleteClusters deletes all clusters matching the selector, after showing
+// a summary and asking for confirmation once.
+func deleteClusters(args Arguments) ([]batch.Result, error) {
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusters, err := batch.Resolve(clientWrapper, args.apiEndpoint, args.selector, deleteClusterActivityName)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	fmt.Printf("The following %d clusters will be deleted:\n\n", len(clusters))
+	fmt.Println(batch.SummaryTable(clusters))
+	fmt.Println("")
+
+	if !args.force {
+		count := strconv.Itoa(len(clusters))
+		confirmed := confirm.AskStrict(
+			fmt.Sprintf("Do you really want to delete these %s clusters? Please type '%s' to confirm", count, count),
+			count,
+		)
+		if !confirmed {
+			return nil, microerror.Mask(errors.CommandAbortedError)
+		}
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = deleteClusterActivityName
+
+	results := batch.Run(clusters, args.parallel, func(c batch.Cluster) (string, error) {
+		err := submitDeletion(clientWrapper, c.ID, auxParams)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		if !args.wait {
+			return "Deletion scheduled", nil
+		}
+
+		w, err := wait.New(wait.Config{
+			ClientWrapper: clientWrapper,
+			Output:        ioutil.Discard,
+			Timeout:       args.waitTimeout,
+		})
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		err = w.ClusterDeleted(c.ID)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return "Deleted", nil
+	})
+
+	return results, nil
+}
+
+// printBatchResult deletes the clusters matching the selector and prints
+// the report. The exit code reflects all failed deletions.
+func printBatchResult(args Arguments) {
+	results, err := deleteClusters(args)
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		var headline string
+
+		switch {
+		case errors.IsCommandAbortedError(err):
+			headline = "Not deleting."
+		default:
+			headline = err.Error()
+		}
+
+		errors.PrintError(err, headline, "")
+		errors.Exit(err)
+	}
+
+	fmt.Println("")
+	fmt.Println(batch.Report(results))
+
+	if code := batch.ExitCode(results); code != errors.ExitCodeOK {
+		fmt.Println(color.YellowString("Please check the failed deletions listed above."))
+		os.Exit(code)
+	}
+}
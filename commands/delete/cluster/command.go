@@ -4,7 +4,9 @@ package cluster
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/config"
@@ -18,6 +20,8 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/confirm"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/batch"
+	"github.com/giantswarm/gsctl/pkg/wait"
 )
 
 // Arguments represents all argument that can be passed to our
@@ -40,6 +44,13 @@ type Arguments struct {
 	verbose bool
 	// outputFormat
 	outputFormat string
+	// maximum number of deletions running at the same time
+	parallel int
+	// label selector choosing the clusters to delete
+	selector string
+	// wait for the deletion to complete
+	wait        bool
+	waitTimeout time.Duration
 }
 
 // JSONOutput contains the fields included in JSON output of the delete cluster command when called with json output flag
@@ -48,8 +59,6 @@ type JSONOutput struct {
 	Result string `json:"result"`
 	// ID of the cluster
 	ID string `json:"id"`
-	// Error which occured
-	Error error `json:"error,omitempty"`
 }
 
 func collectArguments(positionalArgs []string) Arguments {
@@ -79,6 +88,10 @@ func collectArguments(positionalArgs []string) Arguments {
 		userProvidedToken: flags.Token,
 		verbose:           flags.Verbose,
 		outputFormat:      flags.OutputFormat,
+		parallel:          flags.Parallel,
+		selector:          flags.Selector,
+		wait:              flags.Wait,
+		waitTimeout:       flags.WaitTimeout,
 	}
 }
 
@@ -96,7 +109,19 @@ worker nodes will be lost. There is no way to undo this.
 
 Example:
 
-	gsctl delete cluster c7t2o`,
+	gsctl delete cluster c7t2o
+
+To block until the cluster is gone, use --wait. The command exits with a
+non-zero exit code if the cluster still exists after the time given via
+--timeout:
+
+	gsctl delete cluster c7t2o --force --wait --timeout 30m
+
+To delete all clusters matching a label selector, use --selector instead of
+a cluster name or ID. The matching clusters are listed and you are asked to
+confirm once by typing the number of clusters:
+
+	gsctl delete cluster --selector environment=testing --parallel 2`,
 		PreRun: printValidation,
 		Run:    printResult,
 	}
@@ -108,6 +133,10 @@ func init() {
 	Command.Flags().StringVarP(&flags.ClusterID, "cluster", "c", "", "Name or ID of the cluster to delete")
 	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required (risky!).")
 	Command.Flags().StringVarP(&flags.OutputFormat, "output", "", "", fmt.Sprintf("Output format. Specifying '%s' will change output to be JSON formatted. It also disables any confirmations.", formatting.OutputFormatJSON))
+	Command.Flags().BoolVarP(&flags.Wait, "wait", "", false, "Wait until the cluster has been deleted.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "timeout", "", wait.DefaultTimeout, "Maximum time to wait when using --wait.")
+	Command.Flags().StringVarP(&flags.Selector, "selector", "l", "", "Label selector query. Deletes all matching clusters instead of a single one.")
+	Command.Flags().IntVarP(&flags.Parallel, "parallel", "", batch.DefaultParallel, "Maximum number of clusters to delete at the same time when using --selector.")
 
 	Command.Flags().MarkDeprecated("cluster", "You no longer need to pass the cluster ID with -c/--cluster. Use --help for details.")
 }
@@ -120,13 +149,16 @@ func printValidation(cmd *cobra.Command, args []string) {
 
 	err := validatePreconditions(arguments)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline = ""
 		var subtext = ""
 
 		switch {
+		case errors.IsConflictingFlagsError(err) && arguments.selector != "":
+			headline = "Conflicting flags/arguments"
+			subtext = "--selector can't be combined with a cluster name or ID, nor with --output."
 		case errors.IsConflictingFlagsError(err):
 			headline = "Conflicting flags/arguments"
 			subtext = "Please specify the cluster to be used as a positional argument, avoid -c/--cluster."
@@ -143,11 +175,8 @@ func printValidation(cmd *cobra.Command, args []string) {
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 }
 
@@ -156,7 +185,11 @@ func validatePreconditions(args Arguments) error {
 	if args.apiEndpoint == "" {
 		return microerror.Mask(errors.EndpointMissingError)
 	}
-	if args.clusterNameOrID == "" && args.legacyClusterID == "" {
+	if args.selector != "" {
+		if args.clusterNameOrID != "" || args.legacyClusterID != "" || args.outputFormat != "" {
+			return microerror.Mask(errors.ConflictingFlagsError)
+		}
+	} else if args.clusterNameOrID == "" && args.legacyClusterID == "" {
 		return microerror.Mask(errors.ClusterNameOrIDMissingError)
 	}
 	if args.clusterNameOrID != "" && args.legacyClusterID != "" {
@@ -173,6 +206,11 @@ func validatePreconditions(args Arguments) error {
 
 // interprets arguments/flags, eventually submits delete request
 func printResult(cmd *cobra.Command, args []string) {
+	if arguments.selector != "" {
+		printBatchResult(arguments)
+		return
+	}
+
 	clusterID := arguments.legacyClusterID
 	if arguments.clusterNameOrID != "" {
 		clusterID = arguments.clusterNameOrID
@@ -180,13 +218,14 @@ func printResult(cmd *cobra.Command, args []string) {
 
 	deleted, err := deleteCluster(arguments)
 
-	if arguments.outputFormat == formatting.OutputFormatJSON {
-		printJSONOutput(deleted, clusterID, err)
+	// Errors are printed as JSON by errors.PrintError.
+	if arguments.outputFormat == formatting.OutputFormatJSON && err == nil {
+		printJSONOutput(clusterID)
 		return
 	}
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline = ""
@@ -196,20 +235,25 @@ func printResult(cmd *cobra.Command, args []string) {
 		case errors.IsClusterNotFoundError(err):
 			headline = "Cluster not found"
 			subtext = "The cluster you tried to delete doesn't seem to exist. Check 'gsctl list clusters' to make sure."
+		case wait.IsTimeout(err):
+			headline = "Timeout"
+			subtext = fmt.Sprintf("Deletion of cluster '%s' has been scheduled, but did not complete in time.", clusterID)
 		default:
 			headline = err.Error()
 		}
 
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	// non-error output
-	if deleted {
+	if deleted && arguments.wait {
+		fmt.Println(color.GreenString("The cluster '%s' has been deleted.", clusterID))
+	} else if deleted {
 		fmt.Println(color.GreenString("The cluster '%s' will be deleted as soon as all workloads are terminated.", clusterID))
+	}
+	if deleted {
+		fmt.Printf("To remove the kubectl settings for this cluster, use 'gsctl delete kubeconfig -c %s'.\n", clusterID)
 	} else {
 		if arguments.verbose {
 			fmt.Println(color.GreenString("Aborted."))
@@ -217,16 +261,13 @@ func printResult(cmd *cobra.Command, args []string) {
 	}
 }
 
-func printJSONOutput(deleted bool, clusterID string, creationErr error) {
+func printJSONOutput(clusterID string) {
 	var outputBytes []byte
 	var err error
-	var jsonResult JSONOutput
 
-	// handle errors
-	if creationErr != nil {
-		jsonResult = JSONOutput{Result: "error", Error: creationErr}
-	} else {
-		jsonResult = JSONOutput{Result: "deletion scheduled", ID: clusterID}
+	jsonResult := JSONOutput{Result: "deletion scheduled", ID: clusterID}
+	if arguments.wait {
+		jsonResult.Result = "deleted"
 	}
 
 	outputBytes, err = json.MarshalIndent(jsonResult, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
@@ -236,9 +277,6 @@ func printJSONOutput(deleted bool, clusterID string, creationErr error) {
 	}
 
 	fmt.Println(string(outputBytes))
-	if creationErr != nil {
-		os.Exit(1)
-	}
 }
 
 // deleteCluster performs the cluster deletion API call
@@ -281,19 +319,50 @@ func deleteCluster(args Arguments) (bool, error) {
 	auxParams := clientWrapper.DefaultAuxiliaryParams()
 	auxParams.ActivityName = deleteClusterActivityName
 
-	// perform API call
-	_, err = clientWrapper.DeleteCluster(clusterID, auxParams)
+	err = submitDeletion(clientWrapper, clusterID, auxParams)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	if args.wait {
+		// Keep STDOUT clean for JSON output.
+		var output io.Writer = os.Stdout
+		if args.outputFormat == formatting.OutputFormatJSON {
+			output = os.Stderr
+		}
+
+		w, err := wait.New(wait.Config{
+			ClientWrapper: clientWrapper,
+			Output:        output,
+			Timeout:       args.waitTimeout,
+		})
+		if err != nil {
+			return true, microerror.Mask(err)
+		}
+
+		err = w.ClusterDeleted(clusterID)
+		if err != nil {
+			return true, microerror.Mask(err)
+		}
+	}
+
+	return true, nil
+}
+
+// submitDeletion performs the cluster deletion API call.
+func submitDeletion(clientWrapper *client.Wrapper, clusterID string, auxParams *client.AuxiliaryParams) error {
+	_, err := clientWrapper.DeleteCluster(clusterID, auxParams)
 	if err != nil {
 		// create specific error types for cases we care about
 		if clienterror.IsAccessForbiddenError(err) {
-			return false, microerror.Mask(errors.AccessForbiddenError)
+			return microerror.Mask(errors.AccessForbiddenError)
 		}
 		if clienterror.IsNotFoundError(err) {
-			return false, microerror.Mask(errors.ClusterNotFoundError)
+			return microerror.Mask(errors.ClusterNotFoundError)
 		}
 
-		return false, microerror.Maskf(errors.CouldNotDeleteClusterError, err.Error())
+		return microerror.Maskf(errors.CouldNotDeleteClusterError, err.Error())
 	}
 
-	return true, nil
+	return nil
 }
@@ -4,9 +4,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/batch"
 	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
 	"github.com/spf13/afero"
 )
 
@@ -106,6 +110,24 @@ func TestDeleteClusterFailures(t *testing.T) {
 			},
 			errorMatcher: errors.IsOutputFormatInvalid,
 		},
+		{
+			arguments: Arguments{
+				apiEndpoint:     "https://mock-url",
+				token:           "some token",
+				clusterNameOrID: "somecluster",
+				selector:        "environment=testing",
+			},
+			errorMatcher: errors.IsConflictingFlagsError,
+		},
+		{
+			arguments: Arguments{
+				apiEndpoint:  "https://mock-url",
+				token:        "some token",
+				selector:     "environment=testing",
+				outputFormat: "json",
+			},
+			errorMatcher: errors.IsConflictingFlagsError,
+		},
 	}
 
 	fs := afero.NewMemMapFs()
@@ -146,3 +168,48 @@ func TestCommandExecution(t *testing.T) {
 		Command.Execute()
 	})
 }
+
+// TestDeleteClustersBySelector deletes all clusters matching a selector.
+func TestDeleteClustersBySelector(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := fakeapi.New(fakeapi.Config{})
+	for _, id := range []string{"a1111", "b2222"} {
+		server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: id, Owner: fakeapi.DefaultOrganization, Labels: map[string]string{"environment": "testing"}})
+	}
+	server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: "c3333", Owner: fakeapi.DefaultOrganization, Labels: map[string]string{"environment": "production"}})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	args := Arguments{
+		apiEndpoint:       ts.URL,
+		force:             true,
+		parallel:          2,
+		selector:          "environment=testing",
+		token:             "some-token",
+		userProvidedToken: "some-token",
+		wait:              true,
+		waitTimeout:       5 * time.Second,
+	}
+
+	var results []batch.Result
+	testutils.CaptureOutput(func() {
+		results, err = deleteClusters(args)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil || r.Message != "Deleted" {
+			t.Errorf("Unexpected result %#v", r)
+		}
+	}
+}
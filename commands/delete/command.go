@@ -3,8 +3,10 @@ package delete
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/commands/delete/app"
 	"github.com/giantswarm/gsctl/commands/delete/cluster"
 	"github.com/giantswarm/gsctl/commands/delete/endpoint"
+	"github.com/giantswarm/gsctl/commands/delete/kubeconfig"
 	"github.com/giantswarm/gsctl/commands/delete/nodepool"
 )
 
@@ -13,12 +15,14 @@ var (
 	Command = &cobra.Command{
 		Use:   "delete",
 		Short: "Delete things",
-		Long:  `Lets you delete a cluster, a node pool, or an API endpoint`,
+		Long:  `Lets you delete an app, a cluster, a node pool, an API endpoint, or the kubectl settings for a cluster`,
 	}
 )
 
 func init() {
+	Command.AddCommand(app.Command)
 	Command.AddCommand(cluster.Command)
 	Command.AddCommand(nodepool.Command)
 	Command.AddCommand(endpoint.Command)
+	Command.AddCommand(kubeconfig.Command)
 }
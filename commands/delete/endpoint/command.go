@@ -3,14 +3,12 @@ package endpoint
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/config"
 	"github.com/giantswarm/microerror"
 	"github.com/spf13/cobra"
 
-	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/confirm"
 	"github.com/giantswarm/gsctl/flags"
@@ -88,11 +86,8 @@ func printValidation(cmd *cobra.Command, args []string) {
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 }
 
@@ -112,7 +107,7 @@ func printResult(cmd *cobra.Command, args []string) {
 
 	deleted, err := deleteEndpoint(arguments)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline = ""
@@ -130,10 +125,7 @@ func printResult(cmd *cobra.Command, args []string) {
 			headline = err.Error()
 		}
 
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
+		errors.PrintError(err, headline, subtext)
 	}
 
 	// Non-error output
@@ -0,0 +1,271 @@
+// Package kubeconfig implements the 'delete kubeconfig' command.
+package kubeconfig
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/execcredential"
+	"github.com/giantswarm/gsctl/pkg/kubeconfigfile"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl delete kubeconfig'
+	Command = &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "Remove kubectl settings for a cluster",
+		Long: `Removes the kubectl context, cluster and user entries created by
+'gsctl create kubeconfig' for a cluster, and deletes the cluster's
+certificate and key files in the "certs" subfolder of the gsctl config
+directory, as well as credentials cached for 'gsctl kubeconfig-credential'.
+The cluster itself is not affected.
+
+Contexts created with a custom name via --context are removed as well. If
+the removed context is the current context, no context is selected
+afterwards.
+
+The cluster can be given by ID or, if it still exists, by name. Use
+'gsctl prune kubeconfigs' to clean up the settings for all clusters which
+no longer exist.
+
+Example:
+
+  gsctl delete kubeconfig -c f01r4
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	arguments Arguments
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.ClusterID, "cluster", "c", "", "Name or ID of the cluster to remove the kubectl settings for")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint       string
+	authToken         string
+	certsDirPath      string
+	clusterNameOrID   string
+	credentialsDir    string
+	fileSystem        afero.Fs
+	kubeconfigPaths   []string
+	userProvidedToken string
+}
+
+func collectArguments() Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		apiEndpoint:       endpoint,
+		authToken:         token,
+		certsDirPath:      config.CertsDirPath,
+		clusterNameOrID:   flags.ClusterID,
+		credentialsDir:    path.Join(config.ConfigDirPath, execcredential.DirName),
+		fileSystem:        config.FileSystem,
+		kubeconfigPaths:   kubeconfigfile.Paths(os.Getenv("KUBECONFIG"), config.HomeDirPath),
+		userProvidedToken: flags.Token,
+	}
+}
+
+// result describes what has been removed.
+type result struct {
+	entries *kubeconfigfile.ClusterEntries
+	// deletedFiles are the credential files deleted.
+	deletedFiles []string
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.clusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	errors.Exit(err)
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	r, err := deleteKubeconfig(arguments)
+	if err != nil {
+		handleError(err)
+		errors.Exit(err)
+	}
+
+	var removed []string
+	if len(r.entries.ContextNames) > 0 {
+		removed = append(removed, "context "+strings.Join(r.entries.ContextNames, ", "))
+	}
+	if r.entries.AuthInfoName != "" {
+		removed = append(removed, "user "+r.entries.AuthInfoName)
+	}
+	if r.entries.ClusterName != "" {
+		removed = append(removed, "cluster "+r.entries.ClusterName)
+	}
+
+	fmt.Println(color.GreenString("The kubectl settings for cluster '%s' have been removed.", r.entries.ClusterID))
+	if len(removed) > 0 {
+		fmt.Printf("Removed %s.\n", strings.Join(removed, "; "))
+	}
+	if len(r.deletedFiles) > 0 {
+		fmt.Printf("Deleted %d credential files.\n", len(r.deletedFiles))
+	}
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsClusterNameOrIDMissingError(err):
+		headline = "No cluster given"
+		subtext = "Please name the cluster using -c / --cluster. See --help for details."
+	case IsNotFound(err):
+		headline = "No kubectl settings found"
+		subtext = err.Error()
+	case kubeconfigfile.IsInvalidKubeconfig(err):
+		headline = "Error: The kubectl config could not be parsed"
+		subtext = fmt.Sprintf("Details: %s\nPlease fix or remove the file.", err.Error())
+	case kubeconfigfile.IsLocked(err):
+		headline = "Error: The kubectl config is in use"
+		subtext = err.Error()
+	case kubeconfigfile.IsWrite(err):
+		headline = "Error: The kubectl config could not be written"
+		subtext = fmt.Sprintf("Details: %s", err.Error())
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
+
+// deleteKubeconfig is our business function. It removes the kubeconfig
+// entries of a cluster and the credential files no longer referenced.
+func deleteKubeconfig(args Arguments) (result, error) {
+	k, err := kubeconfigfile.Open(kubeconfigfile.Config{
+		FileSystem: args.fileSystem,
+		Paths:      args.kubeconfigPaths,
+	})
+	if err != nil {
+		return result{}, microerror.Mask(err)
+	}
+	defer k.Close()
+
+	entries := kubeconfigfile.FindClusterEntries(k.Merged())
+
+	clusterID := args.clusterNameOrID
+	r := result{entries: findEntries(entries, clusterID)}
+	if r.entries == nil {
+		// Not a cluster ID we have entries for, so it may be a name.
+		clusterID, err = resolveClusterID(args)
+		if err != nil {
+			return result{}, microerror.Mask(err)
+		}
+		r.entries = findEntries(entries, clusterID)
+	}
+
+	clusterFiles, err := util.CredentialFiles(args.fileSystem, args.certsDirPath, clusterID)
+	if err != nil {
+		return result{}, microerror.Mask(err)
+	}
+	cachedFiles, err := execcredential.Files(args.fileSystem, args.credentialsDir, clusterID)
+	if err != nil {
+		return result{}, microerror.Mask(err)
+	}
+	clusterFiles = append(clusterFiles, cachedFiles...)
+
+	if r.entries == nil {
+		if len(clusterFiles) == 0 {
+			return result{}, microerror.Maskf(notFoundError, "There are no kubectl settings for cluster '%s'.", args.clusterNameOrID)
+		}
+		// Only files are left over.
+		r.entries = &kubeconfigfile.ClusterEntries{ClusterID: clusterID}
+	}
+
+	k.DeleteClusterEntries(r.entries)
+	err = k.Save()
+	if err != nil {
+		return result{}, microerror.Mask(err)
+	}
+
+	// Files still referenced, e. g. by a context with a different cluster
+	// entry, are kept.
+	referenced := kubeconfigfile.ReferencedFiles(k.Files())
+	for _, f := range clusterFiles {
+		if referenced[f] {
+			continue
+		}
+		err = args.fileSystem.Remove(f)
+		if err != nil {
+			return r, microerror.Mask(err)
+		}
+		r.deletedFiles = append(r.deletedFiles, f)
+	}
+
+	return r, nil
+}
+
+func findEntries(entries []*kubeconfigfile.ClusterEntries, clusterID string) *kubeconfigfile.ClusterEntries {
+	for _, e := range entries {
+		if e.ClusterID == clusterID {
+			return e
+		}
+	}
+	return nil
+}
+
+// resolveClusterID looks up the cluster ID for a cluster name via the API.
+// Without authentication, the given value is returned unchanged.
+func resolveClusterID(args Arguments) (string, error) {
+	if args.apiEndpoint == "" || (config.Config.Token == "" && args.authToken == "") {
+		return args.clusterNameOrID, nil
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
+	if errors.IsClusterNotFoundError(err) {
+		return args.clusterNameOrID, nil
+	} else if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return clusterID, nil
+}
@@ -0,0 +1,152 @@
+package kubeconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+const kubeconfigYAML = `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: giantswarm-abc12
+  cluster:
+    server: https://api.abc12.example.com
+    certificate-authority: /certs/abc12-ca.crt
+- name: giantswarm-def34
+  cluster:
+    server: https://api.def34.example.com
+    certificate-authority: /certs/def34-ca.crt
+contexts:
+- name: giantswarm-abc12
+  context:
+    cluster: giantswarm-abc12
+    user: giantswarm-abc12-user
+- name: prod
+  context:
+    cluster: giantswarm-abc12
+    user: giantswarm-abc12-user
+- name: giantswarm-def34
+  context:
+    cluster: giantswarm-def34
+    user: giantswarm-def34-user
+users:
+- name: giantswarm-abc12-user
+  user:
+    client-certificate: /certs/abc12-a1b2c3d4e5-client.crt
+    client-key: /certs/abc12-a1b2c3d4e5-client.key
+- name: giantswarm-def34-user
+  user:
+    client-certificate: /certs/def34-f6a7b8c9d0-client.crt
+    client-key: /certs/def34-f6a7b8c9d0-client.key
+`
+
+// setUp creates a kubeconfig file and credential files.
+func setUp(t *testing.T) (afero.Fs, Arguments) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = afero.WriteFile(fs, "/home/.kube/config", []byte(kubeconfigYAML), 0600)
+	for _, f := range []string{
+		"abc12-ca.crt",
+		"abc12-a1b2c3d4e5-client.crt",
+		"abc12-a1b2c3d4e5-client.key",
+		"abc12-0000000000-client.crt",
+		"def34-ca.crt",
+		"def34-f6a7b8c9d0-client.crt",
+		"def34-f6a7b8c9d0-client.key",
+		"gh567-ca.crt",
+	} {
+		_ = afero.WriteFile(fs, "/certs/"+f, []byte("data"), 0600)
+	}
+	_ = afero.WriteFile(fs, "/credentials/abc12-0a1b2c3d.json", []byte("{}"), 0600)
+	_ = afero.WriteFile(fs, "/credentials/def34-0a1b2c3d.json", []byte("{}"), 0600)
+
+	args := Arguments{
+		certsDirPath:    "/certs",
+		credentialsDir:  "/credentials",
+		fileSystem:      fs,
+		kubeconfigPaths: []string{"/home/.kube/config"},
+	}
+
+	return fs, args
+}
+
+// TestDeleteKubeconfig tests removing the entries and files of a cluster.
+func TestDeleteKubeconfig(t *testing.T) {
+	fs, args := setUp(t)
+	args.clusterNameOrID = "abc12"
+
+	r, err := deleteKubeconfig(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	if strings.Join(r.entries.ContextNames, ",") != "giantswarm-abc12,prod" || r.entries.AuthInfoName != "giantswarm-abc12-user" || r.entries.ClusterName != "giantswarm-abc12" {
+		t.Errorf("Unexpected entries %#v", r.entries)
+	}
+	if len(r.deletedFiles) != 5 {
+		t.Errorf("Expected 5 deleted files, got %v", r.deletedFiles)
+	}
+
+	content, _ := afero.ReadFile(fs, "/home/.kube/config")
+	if strings.Contains(string(content), "abc12") || strings.Contains(string(content), "prod") {
+		t.Errorf("Expected entries for abc12 to be removed:\n%s", content)
+	}
+	if !strings.Contains(string(content), "giantswarm-def34-user") {
+		t.Errorf("Expected entries for def34 to be kept:\n%s", content)
+	}
+
+	for _, f := range []string{"/certs/abc12-ca.crt", "/certs/abc12-0000000000-client.crt", "/credentials/abc12-0a1b2c3d.json"} {
+		if exists, _ := afero.Exists(fs, f); exists {
+			t.Errorf("Expected %s to be deleted", f)
+		}
+	}
+	for _, f := range []string{"/certs/def34-ca.crt", "/certs/gh567-ca.crt", "/credentials/def34-0a1b2c3d.json"} {
+		if exists, _ := afero.Exists(fs, f); !exists {
+			t.Errorf("Expected %s to be kept", f)
+		}
+	}
+}
+
+// TestDeleteKubeconfigFilesOnly tests removing files of a cluster without
+// kubeconfig entries.
+func TestDeleteKubeconfigFilesOnly(t *testing.T) {
+	fs, args := setUp(t)
+	args.clusterNameOrID = "gh567"
+
+	r, err := deleteKubeconfig(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if len(r.deletedFiles) != 1 {
+		t.Errorf("Expected 1 deleted file, got %v", r.deletedFiles)
+	}
+	if exists, _ := afero.Exists(fs, "/home/.kube/config.bak"); exists {
+		t.Error("Expected kubeconfig not to be modified")
+	}
+}
+
+// TestDeleteKubeconfigErrors tests the handling of missing clusters.
+func TestDeleteKubeconfigErrors(t *testing.T) {
+	_, args := setUp(t)
+
+	err := verifyPreconditions(args)
+	if !errors.IsClusterNameOrIDMissingError(err) {
+		t.Errorf("Expected ClusterNameOrIDMissingError, got %#v", err)
+	}
+
+	args.clusterNameOrID = "xyz89"
+	_, err = deleteKubeconfig(args)
+	if !IsNotFound(err) {
+		t.Errorf("Expected notFoundError, got %#v", err)
+	}
+}
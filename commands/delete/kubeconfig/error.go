@@ -0,0 +1,14 @@
+package kubeconfig
+
+import "github.com/giantswarm/microerror"
+
+// notFoundError is used when there are neither kubeconfig entries nor
+// credential files for the given cluster.
+var notFoundError = &microerror.Error{
+	Kind: "notFoundError",
+}
+
+// IsNotFound asserts notFoundError.
+func IsNotFound(err error) bool {
+	return microerror.Cause(err) == notFoundError
+}
@@ -3,7 +3,6 @@ package nodepool
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/fatih/color"
@@ -142,7 +141,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	headline := ""
@@ -157,11 +156,8 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	// print output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
 }
 
 // deleteNodePool is the business function sending our deletion request to the API
@@ -218,7 +214,7 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 	arguments, _ := collectArguments(positionalArgs)
 	deleted, err := deleteNodePool(arguments)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		headline := ""
@@ -239,11 +235,8 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	if deleted {
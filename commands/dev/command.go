@@ -0,0 +1,23 @@
+// Package dev holds the hidden 'dev *' sub-commands, which support
+// development and testing of gsctl.
+package dev
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/dev/mockapi"
+)
+
+var (
+	// Command is the parent command of development tools.
+	Command = &cobra.Command{
+		Use:    "dev",
+		Short:  "Development tools",
+		Long:   `Tools for developing and testing gsctl.`,
+		Hidden: true,
+	}
+)
+
+func init() {
+	Command.AddCommand(mockapi.Command)
+}
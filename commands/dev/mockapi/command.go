@@ -0,0 +1,159 @@
+// Package mockapi implements the hidden 'dev mock-api' command.
+package mockapi
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
+)
+
+var (
+	// Command performs the "dev mock-api" function
+	Command = &cobra.Command{
+		Use:   "mock-api",
+		Short: "Run a fake Giant Swarm API locally",
+		Long: `Starts an in-memory fake of the Giant Swarm API on localhost.
+
+The fake API keeps clusters, node pools, key pairs, apps, labels,
+organizations and releases in memory, so that gsctl workflows can be
+exercised without a live installation. All state is lost when the command
+is stopped. Any email and password is accepted on login.
+
+Operations finish immediately: clusters are created, upgraded and deleted
+as soon as requested.
+
+Examples:
+
+  gsctl dev mock-api --port 8080
+
+  gsctl login dev@example.com -p secret -e http://localhost:8080
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	// cmdPort is the command line flag for the port to listen on.
+	cmdPort int
+
+	// cmdProvider is the command line flag for the provider to emulate.
+	cmdProvider string
+
+	arguments Arguments
+)
+
+// Arguments is an argument struct to pass to our business
+// function and to the validation function
+type Arguments struct {
+	port     int
+	provider string
+}
+
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().IntVarP(&cmdPort, "port", "", 8080, "TCP port to listen on.")
+	Command.Flags().StringVarP(&cmdProvider, "provider", "", fakeapi.DefaultProvider, "Provider to emulate. One of 'aws', 'azure' or 'kvm'.")
+}
+
+// collectArguments gathers arguments based on command line
+// flags and applies defaults.
+func collectArguments() Arguments {
+	return Arguments{
+		port:     cmdPort,
+		provider: cmdProvider,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.port < 1 || args.port > 65535 {
+		return microerror.Maskf(invalidPortError, "%d", args.port)
+	}
+
+	switch args.provider {
+	case "aws", "azure", "kvm":
+	default:
+		return microerror.Maskf(errors.ProviderNotSupportedError, "%s", args.provider)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	listener, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(arguments.port)))
+	if err != nil {
+		handleError(microerror.Mask(err))
+		os.Exit(errors.ExitCodeEnvironment)
+	}
+
+	endpoint := fmt.Sprintf("http://%s", listener.Addr().String())
+	fmt.Println(color.GreenString("Fake Giant Swarm API listening on %s", endpoint))
+	fmt.Printf("Log in using 'gsctl login <email> -p <password> -e %s'. Press Ctrl+C to stop.\n\n", endpoint)
+
+	err = serve(listener, arguments, os.Stdout)
+	if err != nil {
+		handleError(microerror.Mask(err))
+		os.Exit(errors.ExitCode(err))
+	}
+}
+
+// serve handles requests on the listener until it fails. Each request is
+// logged to logger.
+func serve(listener net.Listener, args Arguments, logger io.Writer) error {
+	server := &http.Server{
+		Handler: newHandler(args, logger),
+	}
+
+	return server.Serve(listener)
+}
+
+// newHandler creates the fake API.
+func newHandler(args Arguments, logger io.Writer) http.Handler {
+	return fakeapi.New(fakeapi.Config{
+		Provider: args.provider,
+		Logger:   logger,
+	})
+}
+
+func handleError(err error) {
+	errors.HandleCommonErrors(err)
+
+	var headline string
+	var subtext string
+
+	switch {
+	case IsInvalidPort(err):
+		headline = "Invalid port"
+		subtext = "Please pass a port number between 1 and 65535 via --port."
+	case errors.IsProviderNotSupportedError(err):
+		headline = "Provider not supported"
+		subtext = "Please pass one of 'aws', 'azure' or 'kvm' via --provider."
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
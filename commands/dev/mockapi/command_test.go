@@ -0,0 +1,70 @@
+package mockapi
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+)
+
+// TestVerifyPreconditions tests argument validation.
+func TestVerifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			Arguments{port: 8080, provider: "aws"},
+			nil,
+		},
+		{
+			Arguments{port: 0, provider: "aws"},
+			IsInvalidPort,
+		},
+		{
+			Arguments{port: 70000, provider: "kvm"},
+			IsInvalidPort,
+		},
+		{
+			Arguments{port: 8080, provider: "gcp"},
+			errors.IsProviderNotSupportedError,
+		},
+	}
+
+	for i, tc := range testCases {
+		err := verifyPreconditions(tc.args)
+		if tc.errorMatcher == nil {
+			if err != nil {
+				t.Errorf("Case %d - Unexpected error %#v", i, err)
+			}
+		} else if !tc.errorMatcher(err) {
+			t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+		}
+	}
+}
+
+// TestHandler checks that the handler serves the configured provider.
+func TestHandler(t *testing.T) {
+	ts := httptest.NewServer(newHandler(Arguments{port: 8080, provider: "azure"}, ioutil.Discard))
+	defer ts.Close()
+
+	clientWrapper, err := client.New(&client.Configuration{
+		Endpoint: ts.URL,
+		AuthHeaderGetter: func() (string, error) {
+			return "giantswarm test-token", nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := clientWrapper.GetInfo(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Payload.General.Provider != "azure" {
+		t.Errorf("Expected provider 'azure', got %q", info.Payload.General.Provider)
+	}
+}
@@ -0,0 +1,13 @@
+package mockapi
+
+import "github.com/giantswarm/microerror"
+
+// invalidPortError is used when the port given is not a valid TCP port.
+var invalidPortError = &microerror.Error{
+	Kind: "invalidPortError",
+}
+
+// IsInvalidPort asserts invalidPortError.
+func IsInvalidPort(err error) bool {
+	return microerror.Cause(err) == invalidPortError
+}
@@ -0,0 +1,233 @@
+// Package diff implements the "diff" command.
+package diff
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/diff/releases"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+)
+
+var (
+	// Command is the cobra command for 'gsctl diff'
+	Command = &cobra.Command{
+		Use:   "diff",
+		Short: "Compare a cluster definition with the live cluster",
+		Long: `Show the differences between a cluster definition file and the cluster it describes.
+
+The definition uses the same v5 format as 'gsctl create cluster -f'. The
+cluster is identified by the 'name' and 'owner' given in the definition.
+
+The comparison is the same one 'gsctl apply' uses to plan its changes, so
+the output shows what 'gsctl apply' would do:
+
+  + to be added (labels, node pools)
+  - to be removed (labels, node pools)
+  ~ to be changed (release version, master nodes, labels, node pool scaling)
+  ! differs, but can't be changed for an existing cluster or node pool
+
+Sections omitted from the definition are not compared. Labels containing
+'giantswarm.io' are only compared if they appear in the definition.
+
+The command exits with code 0 if there are no differences and with code 1
+if there are differences or no cluster matches the definition, so it can be
+used in CI.
+
+To compare two releases instead, use 'gsctl diff releases'.
+
+Examples:
+
+  gsctl diff -f my-cluster.yaml
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "diff"
+)
+
+func init() {
+	initFlags()
+
+	Command.AddCommand(releases.Command)
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.InputYAMLFile, "file", "f", "", "Path to a v5 cluster definition YAML file. Use '-' to read from STDIN.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	FileSystem        afero.Fs
+	InputYAMLFile     string
+	UserProvidedToken string
+	Verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments() Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		FileSystem:        config.FileSystem,
+		InputYAMLFile:     flags.InputYAMLFile,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+	}
+}
+
+// result is what we return from our business function.
+type result struct {
+	clusterName string
+	// clusterMissing is true if no cluster matches the definition.
+	clusterMissing bool
+	plan           *clusterdefinition.Plan
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.InputYAMLFile == "" {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--file")
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+
+	if err == nil {
+		return
+	}
+
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	errors.PrintError(err, err.Error(), "")
+	errors.Exit(err)
+}
+
+// diffDefinition is our business function. It fetches the cluster matching
+// the definition and compares both.
+func diffDefinition(args Arguments) (*result, error) {
+	def, err := clusterdefinition.ReadV5(args.FileSystem, args.InputYAMLFile)
+	switch {
+	case clusterdefinition.IsDefinitionNotReadable(err):
+		return nil, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
+	case clusterdefinition.IsClusterNameMissing(err):
+		return nil, microerror.Maskf(errors.ClusterNameOrIDMissingError, err.Error())
+	case clusterdefinition.IsClusterOwnerMissing(err):
+		return nil, microerror.Mask(errors.ClusterOwnerMissingError)
+	case err != nil:
+		return nil, microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	if args.Verbose {
+		fmt.Println(color.WhiteString("Looking up cluster '%s' owned by '%s'", def.Name, def.Owner))
+	}
+	cluster, nodePools, err := clusterdefinition.LookupCluster(def, clientWrapper, auxParams)
+	if clusterdefinition.IsClusterNotFound(err) {
+		return &result{clusterName: def.Name, clusterMissing: true}, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	p, err := clusterdefinition.CreatePlan(def, cluster, nodePools)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return &result{clusterName: def.Name, plan: p}, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	r, err := diffDefinition(arguments)
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		headline := ""
+		subtext := ""
+
+		switch {
+		case clusterdefinition.IsNotV5Definition(err):
+			headline = "Unsupported definition format"
+			subtext = "Only v5 cluster definitions (containing 'api_version: v5') can be compared."
+		case clusterdefinition.IsClusterNotV5(err):
+			headline = "Cluster not supported"
+			subtext = "Only clusters supporting node pools can be compared with a definition."
+		case clusterdefinition.IsMultipleClustersFound(err):
+			headline = "Cluster name is ambiguous"
+			subtext = err.Error()
+		case clusterdefinition.IsNodePoolNameMissing(err):
+			headline = "Node pool name missing"
+			subtext = "Every node pool in the definition needs a name, so it can be matched with an existing node pool."
+		case clusterdefinition.IsDuplicateNodePoolName(err):
+			headline = "Duplicate node pool name"
+			subtext = err.Error()
+		case clusterdefinition.IsRevertHAMasterNotAllowed(err):
+			headline = "Definition can't be applied"
+			subtext = "It is not possible to change from multiple master nodes to a single master."
+		default:
+			headline = err.Error()
+		}
+
+		// print output
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
+	}
+
+	if r.clusterMissing {
+		fmt.Println(color.GreenString("+ cluster '%s'", r.clusterName))
+		fmt.Printf("\nNo cluster matches the definition. Use 'gsctl create cluster -f %s' to create it.\n", arguments.InputYAMLFile)
+		errors.Exit(errors.DifferencesFoundError)
+	}
+
+	if r.plan.IsEmpty() && len(r.plan.Warnings) == 0 {
+		fmt.Println(color.GreenString("Cluster '%s' (%s) matches the definition.", r.plan.ClusterName, r.plan.ClusterID))
+		return
+	}
+
+	fmt.Printf("Differences between the definition and cluster '%s' (%s):\n\n", r.plan.ClusterName, r.plan.ClusterID)
+	fmt.Println(clusterdefinition.FormatPlan(r.plan))
+	errors.Exit(errors.DifferencesFoundError)
+}
@@ -0,0 +1,213 @@
+package diff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// configYAML is a mock configuration used by some of the tests.
+const configYAML = `last_version_check: 0001-01-01T00:00:00Z
+endpoints:
+  https://foo:
+    email: email@example.com
+    token: some-token
+    provider: aws
+selected_endpoint: https://foo
+updated: 2017-09-29T11:23:15+02:00
+`
+
+// Test_verifyPreconditions tests the checks happening before any API call.
+func Test_verifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			Arguments{APIEndpoint: "https://foo", AuthToken: "token", InputYAMLFile: "cluster.yaml"},
+			nil,
+		},
+		{
+			Arguments{AuthToken: "token", InputYAMLFile: "cluster.yaml"},
+			errors.IsEndpointMissingError,
+		},
+		{
+			Arguments{APIEndpoint: "https://foo", InputYAMLFile: "cluster.yaml"},
+			errors.IsNotLoggedInError,
+		},
+		{
+			Arguments{APIEndpoint: "https://foo", AuthToken: "token"},
+			errors.IsRequiredFlagMissingError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil {
+				if err != nil {
+					t.Errorf("Case %d - Unexpected error %#v", i, err)
+				}
+			} else if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+			}
+		})
+	}
+}
+
+// Test_diffDefinition tests the comparison against a mock API.
+func Test_diffDefinition(t *testing.T) {
+	var testCases = []struct {
+		definitionYAML string
+		expected       *result
+		errorMatcher   func(error) bool
+	}{
+		// No differences.
+		{
+			definitionYAML: `api_version: v5
+name: My cluster
+owner: acme
+release_version: 11.0.0
+labels:
+  environment: testing
+nodepools:
+- name: general
+  scaling:
+    min: 1
+    max: 3
+`,
+			expected: &result{
+				clusterName: "My cluster",
+				plan: &clusterdefinition.Plan{
+					ClusterID:    "f01r4",
+					ClusterName:  "My cluster",
+					LabelChanges: []clusterdefinition.LabelChange{},
+				},
+			},
+		},
+		// Release, label and node pool changes.
+		{
+			definitionYAML: `api_version: v5
+name: My cluster
+owner: acme
+release_version: 11.1.0
+labels:
+  environment: production
+nodepools:
+- name: general
+  scaling:
+    min: 2
+  node_spec:
+    aws:
+      instance_type: m5.2xlarge
+- name: gpu
+`,
+			expected: &result{
+				clusterName: "My cluster",
+				plan: &clusterdefinition.Plan{
+					ClusterID:   "f01r4",
+					ClusterName: "My cluster",
+					ReleaseFrom: "11.0.0",
+					ReleaseTo:   "11.1.0",
+					LabelChanges: []clusterdefinition.LabelChange{
+						{Key: "environment", From: toStringPtr("testing"), To: toStringPtr("production")},
+					},
+					NodePoolsToCreate: []*types.NodePoolDefinition{{Name: "gpu"}},
+					NodePoolsToModify: []clusterdefinition.NodePoolChange{
+						{ID: "a7rc4", Name: "general", MinFrom: 1, MinTo: 2, MaxFrom: 3, MaxTo: 3},
+					},
+					Warnings: []string{"Node pool 'general' (a7rc4): instance type can't be changed from m5.xlarge to m5.2xlarge."},
+				},
+			},
+		},
+		// Cluster does not exist.
+		{
+			definitionYAML: `api_version: v5
+name: Other cluster
+owner: acme
+`,
+			expected: &result{
+				clusterName:    "Other cluster",
+				clusterMissing: true,
+			},
+		},
+		// v4 definition.
+		{
+			definitionYAML: `name: My cluster
+owner: acme
+`,
+			errorMatcher: clusterdefinition.IsNotV5Definition,
+		},
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "f01r4", "name": "My cluster", "owner": "acme", "release_version": "11.0.0"}]`))
+		case "GET /v5/clusters/f01r4/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "f01r4", "name": "My cluster", "owner": "acme", "release_version": "11.0.0",
+				"labels": {"environment": "testing", "giantswarm.io/cluster": "f01r4"}}`))
+		case "GET /v5/clusters/f01r4/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "a7rc4", "name": "general", "scaling": {"min": 1, "max": 3}, "node_spec": {"aws": {"instance_type": "m5.xlarge"}}}]`))
+		default:
+			t.Errorf("Unsupported operation %s %s called in mock server", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_, err := testutils.TempConfig(fs, configYAML)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = afero.WriteFile(fs, "/cluster.yaml", []byte(tc.definitionYAML), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			args := Arguments{
+				APIEndpoint:   mockServer.URL,
+				AuthToken:     "token",
+				FileSystem:    fs,
+				InputYAMLFile: "/cluster.yaml",
+			}
+
+			r, err := diffDefinition(args)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+
+			if diff := cmp.Diff(tc.expected, r, cmp.AllowUnexported(result{})); diff != "" {
+				t.Errorf("Case %d - Result unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+func toStringPtr(s string) *string {
+	return &s
+}
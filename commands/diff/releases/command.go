@@ -0,0 +1,193 @@
+// Package releases implements the 'diff releases' command.
+package releases
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
+	"github.com/giantswarm/gsctl/pkg/releaseinfo"
+)
+
+var (
+	// Command is the cobra command for 'gsctl diff releases'
+	Command = &cobra.Command{
+		Use:   "releases <version> <version>",
+		Short: "Compare two releases",
+		Long: `Show what changes between two workload cluster releases.
+
+The output contains the Kubernetes versions of both releases including
+their end of life dates, the components that were changed, added or
+removed, and the changelogs of all releases in between, up to and including
+the second release.
+
+If upgrading directly from the first to the second release is not advisable,
+for example because a Kubernetes minor version would be skipped, the
+releases to upgrade through are listed as well.
+
+Examples:
+
+  gsctl diff releases 11.5.0 12.1.0
+  gsctl diff releases 11.5.0 12.1.0 --output json
+`,
+		Args: cobra.ExactArgs(2),
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint       string
+	authToken         string
+	fromVersion       string
+	outputFormat      string
+	toVersion         string
+	userProvidedToken string
+	verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments(positionalArgs []string) Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	args := Arguments{
+		apiEndpoint:       endpoint,
+		authToken:         token,
+		outputFormat:      flags.OutputFormat,
+		userProvidedToken: flags.Token,
+		verbose:           flags.Verbose,
+	}
+
+	if len(positionalArgs) == 2 {
+		args.fromVersion = positionalArgs[0]
+		args.toVersion = positionalArgs[1]
+	}
+
+	return args
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.authToken == "" && args.userProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.fromVersion == "" || args.toVersion == "" {
+		return microerror.Mask(errors.ReleaseVersionMissingError)
+	}
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments(positionalArgs)
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	errors.Exit(err)
+}
+
+// diffReleases fetches both releases and compares them.
+func diffReleases(args Arguments) (*releaseinfo.ReleaseDiff, error) {
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	releaseInfo, err := releaseinfo.New(releaseinfo.Config{ClientWrapper: clientWrapper})
+	if releaseinfo.IsNotAuthorized(err) {
+		return nil, microerror.Mask(errors.NotAuthorizedError)
+	} else if releaseinfo.IsInternalServerError(err) {
+		return nil, microerror.Maskf(errors.InternalServerError, err.Error())
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	d, err := releaseInfo.Diff(args.fromVersion, args.toVersion)
+	if releaseinfo.IsVersionNotFound(err) {
+		return nil, microerror.Mask(errors.ReleaseNotFoundError)
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return d, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	d, err := diffReleases(arguments)
+	if err != nil {
+		handleError(err)
+		errors.Exit(err)
+	}
+
+	if !output.IsTableFormat(arguments.outputFormat) {
+		printer, err := output.NewPrinter(arguments.outputFormat)
+		if err == nil {
+			err = printer.Print(os.Stdout, d, ".to.version")
+		}
+		if err != nil {
+			handleError(microerror.Mask(err))
+			errors.Exit(err)
+		}
+
+		return
+	}
+
+	fmt.Printf("%s %s -> %s\n", color.YellowString("Releases:"), d.From.Version, d.To.Version)
+	d.Print(os.Stdout)
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	var headline string
+	var subtext string
+
+	switch {
+	case errors.IsReleaseVersionMissingError(err):
+		headline = "Release versions missing"
+		subtext = "Please give the two release versions to compare, e. g. 'gsctl diff releases 11.5.0 12.1.0'."
+	case errors.IsReleaseNotFoundError(err):
+		headline = "Release not found"
+		subtext = "At least one of the given releases does not exist. Please check 'gsctl list releases' for available releases."
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
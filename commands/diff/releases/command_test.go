@@ -0,0 +1,99 @@
+package releases
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/releaseinfo"
+	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
+)
+
+// TestVerifyPreconditions tests the validation of arguments.
+func TestVerifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			Arguments{apiEndpoint: "https://foo", authToken: "token", fromVersion: "1.0.0", toVersion: "2.0.0", outputFormat: "table"},
+			nil,
+		},
+		{
+			Arguments{authToken: "token", fromVersion: "1.0.0", toVersion: "2.0.0", outputFormat: "table"},
+			errors.IsEndpointMissingError,
+		},
+		{
+			Arguments{apiEndpoint: "https://foo", fromVersion: "1.0.0", toVersion: "2.0.0", outputFormat: "table"},
+			errors.IsNotLoggedInError,
+		},
+		{
+			Arguments{apiEndpoint: "https://foo", authToken: "token", fromVersion: "1.0.0", outputFormat: "table"},
+			errors.IsReleaseVersionMissingError,
+		},
+		{
+			Arguments{apiEndpoint: "https://foo", authToken: "token", fromVersion: "1.0.0", toVersion: "2.0.0", outputFormat: "nope"},
+			errors.IsOutputFormatInvalid,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil && err != nil {
+				t.Errorf("Case %d - Unexpected error %#v", i, err)
+			} else if tc.errorMatcher != nil && !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+			}
+		})
+	}
+}
+
+// TestDiffReleases compares releases served by the fake API.
+func TestDiffReleases(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(fakeapi.New(fakeapi.Config{}))
+	defer ts.Close()
+
+	args := Arguments{
+		apiEndpoint:       ts.URL,
+		authToken:         "some-token",
+		fromVersion:       "9.3.0",
+		toVersion:         "12.1.0",
+		userProvidedToken: "some-token",
+	}
+
+	d, err := diffReleases(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	expectedComponents := []releaseinfo.ComponentChange{
+		{Name: "kubernetes", FromVersion: "1.15.5", ToVersion: "1.17.9"},
+	}
+	if diff := cmp.Diff(expectedComponents, d.Components); diff != "" {
+		t.Errorf("Unexpected components (-expected +got):\n%s", diff)
+	}
+	if len(d.Changelog) != 2 || d.Changelog[0].Version != "11.5.0" || d.Changelog[1].Version != "12.1.0" {
+		t.Errorf("Unexpected changelog %#v", d.Changelog)
+	}
+	if diff := cmp.Diff([]string{"11.5.0", "12.1.0"}, d.UpgradePath); diff != "" {
+		t.Errorf("Unexpected upgrade path (-expected +got):\n%s", diff)
+	}
+
+	args.toVersion = "99.0.0"
+	_, err = diffReleases(args)
+	if !errors.IsReleaseNotFoundError(err) {
+		t.Errorf("Expected ReleaseNotFoundError, got %#v", err)
+	}
+}
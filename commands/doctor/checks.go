@@ -0,0 +1,529 @@
+package doctor
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	rootcerts "github.com/hashicorp/go-rootcerts"
+	"github.com/spf13/afero"
+	"golang.org/x/net/http/httpproxy"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/giantswarm/gsctl/pkg/authtoken"
+)
+
+// Check states.
+const (
+	statusPass = "pass"
+	statusWarn = "warn"
+	statusFail = "fail"
+	statusSkip = "skip"
+)
+
+const (
+	// Latency above which the API check warns.
+	slowLatency = 2 * time.Second
+
+	// Clock offsets above which the clock check warns and fails.
+	clockSkewWarn = 30 * time.Second
+	clockSkewFail = 5 * time.Minute
+
+	// Certificates expiring within this time cause a warning.
+	certificateExpiryWarn = 14 * 24 * time.Hour
+)
+
+// checkResult is the outcome of a single check.
+type checkResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// endpointAuth is what we need to know about an endpoint from the config.
+type endpointAuth struct {
+	URL          string
+	Alias        string
+	Scheme       string
+	Token        string
+	RefreshToken string
+}
+
+// doctor runs the checks. All access to the environment goes through its
+// fields, so that checks can be tested.
+type doctor struct {
+	fs              afero.Fs
+	configFilePath  string
+	configError     error
+	endpoint        string
+	endpoints       []endpointAuth
+	kubeconfigPaths []string
+
+	now          func() time.Time
+	getenv       func(string) string
+	lookupHost   func(string) ([]string, error)
+	lookPath     func(string) (string, error)
+	checkKubectl func() bool
+	refreshToken func(string) error
+	httpClient   *http.Client
+}
+
+// run executes all checks in order.
+func (d *doctor) run() []checkResult {
+	results := []checkResult{d.checkConfigFile()}
+
+	for _, e := range d.endpoints {
+		results = append(results, d.checkAuth(e))
+	}
+
+	results = append(results, d.checkNetwork()...)
+	results = append(results, d.checkKubectlBinary(), d.checkKubie())
+	results = append(results, d.checkKubeconfigs()...)
+
+	return results
+}
+
+// checkConfigFile checks that the config file can be read and is private.
+func (d *doctor) checkConfigFile() checkResult {
+	r := checkResult{Name: "Config file"}
+
+	if d.configError != nil {
+		r.Status = statusFail
+		r.Message = fmt.Sprintf("%s could not be read: %s", d.configFilePath, d.configError.Error())
+		r.Hint = "Fix the YAML syntax, or move the file away and log in again."
+		return r
+	}
+
+	info, err := d.fs.Stat(d.configFilePath)
+	if err != nil {
+		r.Status = statusFail
+		r.Message = fmt.Sprintf("%s could not be read: %s", d.configFilePath, err.Error())
+		return r
+	}
+
+	if info.Mode().Perm()&0077 != 0 {
+		r.Status = statusWarn
+		r.Message = fmt.Sprintf("%s is accessible by other users (mode %s). It contains your credentials.", d.configFilePath, info.Mode().Perm())
+		r.Hint = fmt.Sprintf("Execute 'chmod 600 %s'.", d.configFilePath)
+		return r
+	}
+
+	r.Status = statusPass
+	r.Message = fmt.Sprintf("%s is valid", d.configFilePath)
+	return r
+}
+
+// checkAuth checks the token of an endpoint and whether an SSO token can
+// be refreshed.
+func (d *doctor) checkAuth(e endpointAuth) checkResult {
+	name := e.URL
+	if e.Alias != "" {
+		name = e.Alias
+	}
+	r := checkResult{Name: "Login " + name}
+	loginHint := fmt.Sprintf("Execute 'gsctl login --endpoint %s'.", name)
+
+	if e.Token == "" {
+		r.Status = statusWarn
+		r.Message = "Not logged in"
+		r.Hint = loginHint
+		return r
+	}
+
+	if e.Scheme != authtoken.SchemeBearer {
+		r.Status = statusPass
+		r.Message = "Logged in with a token that does not expire"
+		return r
+	}
+
+	expiry, ok := authtoken.Expiry(e.Token)
+	expiryMessage := "The SSO access token has no expiry date"
+	if ok {
+		expiry = expiry.UTC()
+		if expiry.Before(d.now()) {
+			expiryMessage = fmt.Sprintf("The SSO access token expired at %s", expiry.Format(time.RFC3339))
+		} else {
+			expiryMessage = fmt.Sprintf("The SSO access token is valid until %s", expiry.Format(time.RFC3339))
+		}
+	}
+
+	if e.RefreshToken == "" {
+		r.Status = statusWarn
+		r.Message = expiryMessage + ", and there is no refresh token."
+		r.Hint = loginHint
+		return r
+	}
+
+	err := d.refreshToken(e.RefreshToken)
+	if err != nil {
+		r.Status = statusFail
+		r.Message = expiryMessage + ", and the refresh token was rejected: " + err.Error()
+		r.Hint = fmt.Sprintf("Execute 'gsctl login --sso --endpoint %s'.", name)
+		return r
+	}
+
+	r.Status = statusPass
+	r.Message = expiryMessage + ". The refresh token works."
+	return r
+}
+
+// checkNetwork checks DNS, proxy and root certificate settings, and the
+// connection to the selected endpoint, including TLS and the clock.
+func (d *doctor) checkNetwork() []checkResult {
+	if d.endpoint == "" {
+		return []checkResult{{
+			Name:    "API endpoint",
+			Status:  statusSkip,
+			Message: "No endpoint selected, skipping network checks",
+			Hint:    "Execute 'gsctl select endpoint' or use the --endpoint flag.",
+		}}
+	}
+
+	u, err := url.Parse(d.endpoint)
+	if err != nil || u.Host == "" {
+		return []checkResult{{
+			Name:    "API endpoint",
+			Status:  statusFail,
+			Message: fmt.Sprintf("%s is not a valid URL", d.endpoint),
+		}}
+	}
+
+	results := []checkResult{d.checkDNS(u), d.checkProxy(u), d.checkRootCerts()}
+	if results[0].Status == statusFail {
+		return results
+	}
+
+	return append(results, d.checkConnection(u)...)
+}
+
+func (d *doctor) checkDNS(u *url.URL) checkResult {
+	r := checkResult{Name: "DNS"}
+
+	addresses, err := d.lookupHost(u.Hostname())
+	if err != nil {
+		r.Status = statusFail
+		r.Message = fmt.Sprintf("%s cannot be resolved: %s", u.Hostname(), err.Error())
+		r.Hint = "Please make sure the endpoint URL is correct and that you are in the right network or VPN."
+		return r
+	}
+
+	r.Status = statusPass
+	r.Message = fmt.Sprintf("%s resolves to %s", u.Hostname(), strings.Join(addresses, ", "))
+	return r
+}
+
+func (d *doctor) checkProxy(u *url.URL) checkResult {
+	r := checkResult{Name: "Proxy", Status: statusPass}
+
+	var settings []string
+	for _, name := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy"} {
+		if value := d.getenv(name); value != "" {
+			settings = append(settings, name+"="+redactURL(value))
+		}
+	}
+
+	proxyURL, err := proxyForURL(u, d.getenv)
+	switch {
+	case err != nil:
+		r.Status = statusFail
+		r.Message = fmt.Sprintf("The proxy settings are invalid: %s", err.Error())
+		r.Hint = "Please check the HTTPS_PROXY and HTTP_PROXY environment variables."
+	case proxyURL != nil:
+		r.Message = fmt.Sprintf("Requests to %s use the proxy %s", u.Host, redactURL(proxyURL.String()))
+	case len(settings) > 0:
+		r.Message = fmt.Sprintf("Requests to %s don't use a proxy", u.Host)
+	default:
+		r.Message = "No proxy configured"
+	}
+
+	if len(settings) > 0 {
+		r.Message += " (" + strings.Join(settings, ", ") + ")"
+	}
+
+	return r
+}
+
+func (d *doctor) checkRootCerts() checkResult {
+	r := checkResult{Name: "Root certificates"}
+	caFile := d.getenv("GSCTL_CAFILE")
+	caPath := d.getenv("GSCTL_CAPATH")
+
+	if caFile == "" && caPath == "" {
+		r.Status = statusPass
+		r.Message = "Using the system root certificates"
+		return r
+	}
+
+	var pool *x509.CertPool
+	var err error
+	source := caFile
+	if caFile != "" {
+		pool, err = rootcerts.LoadCAFile(caFile)
+	} else {
+		source = caPath
+		pool, err = rootcerts.LoadCAPath(caPath)
+	}
+
+	if err != nil {
+		r.Status = statusFail
+		r.Message = fmt.Sprintf("%s could not be loaded: %s", source, err.Error())
+		r.Hint = "Please check the GSCTL_CAFILE and GSCTL_CAPATH environment variables."
+		return r
+	}
+	if len(pool.Subjects()) == 0 {
+		r.Status = statusFail
+		r.Message = fmt.Sprintf("%s contains no certificates", source)
+		r.Hint = "GSCTL_CAFILE must point to a PEM file, GSCTL_CAPATH to a directory of PEM files."
+		return r
+	}
+
+	r.Status = statusPass
+	r.Message = fmt.Sprintf("Using %d certificate(s) from %s", len(pool.Subjects()), source)
+	return r
+}
+
+// checkConnection requests the API root, like 'gsctl ping', and checks
+// the TLS certificate, the latency and the clock offset.
+func (d *doctor) checkConnection(u *url.URL) []checkResult {
+	tlsCheck := checkResult{Name: "TLS"}
+	apiCheck := checkResult{Name: "API"}
+	clockCheck := checkResult{Name: "Clock"}
+
+	root, _ := u.Parse("/")
+	start := d.now()
+	resp, err := d.httpClient.Get(root.String())
+	latency := d.now().Sub(start)
+
+	if err != nil {
+		apiCheck.Status = statusFail
+		apiCheck.Message = fmt.Sprintf("%s cannot be reached: %s", u.Host, err.Error())
+		apiCheck.Hint = "Please make sure that you are in the right network or VPN and that the proxy settings are correct."
+
+		tlsCheck.Status = statusSkip
+		tlsCheck.Message = "No connection"
+		if isCertificateError(err) {
+			tlsCheck.Status = statusFail
+			tlsCheck.Message = err.Error()
+			tlsCheck.Hint = "If your organization uses its own certificate authority, set GSCTL_CAFILE to its certificate."
+			apiCheck.Status = statusSkip
+			apiCheck.Message = "TLS handshake failed"
+			apiCheck.Hint = ""
+		}
+
+		clockCheck.Status = statusSkip
+		clockCheck.Message = "No connection"
+
+		return []checkResult{tlsCheck, apiCheck, clockCheck}
+	}
+	defer resp.Body.Close()
+
+	// TLS
+	switch {
+	case resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0:
+		tlsCheck.Status = statusWarn
+		tlsCheck.Message = fmt.Sprintf("%s uses an insecure protocol", u.String())
+		tlsCheck.Hint = "Use an https:// endpoint URL."
+	default:
+		cert := resp.TLS.PeerCertificates[0]
+		tlsCheck.Status = statusPass
+		tlsCheck.Message = fmt.Sprintf("Certificate issued by '%s', valid until %s", cert.Issuer.CommonName, cert.NotAfter.Format(time.RFC3339))
+		if cert.NotAfter.Sub(d.now()) < certificateExpiryWarn {
+			tlsCheck.Status = statusWarn
+			tlsCheck.Hint = "The certificate expires soon. Please inform Giant Swarm support."
+		}
+	}
+
+	// API
+	switch {
+	case resp.StatusCode == http.StatusForbidden:
+		apiCheck.Status = statusFail
+		apiCheck.Message = "Access denied with status 403"
+		apiCheck.Hint = "Please make sure that you are in the right network or VPN."
+	case resp.StatusCode != http.StatusOK:
+		apiCheck.Status = statusFail
+		apiCheck.Message = fmt.Sprintf("Unexpected status %d", resp.StatusCode)
+		apiCheck.Hint = "Please check whether the endpoint URL points to the Giant Swarm API."
+	case latency > slowLatency:
+		apiCheck.Status = statusWarn
+		apiCheck.Message = fmt.Sprintf("Ping took %d ms", latency/time.Millisecond)
+		apiCheck.Hint = "The connection is slow. Consider using --retries."
+	default:
+		apiCheck.Status = statusPass
+		apiCheck.Message = fmt.Sprintf("Ping took %d ms", latency/time.Millisecond)
+	}
+
+	// Clock
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		clockCheck.Status = statusSkip
+		clockCheck.Message = "The API did not send its time"
+		return []checkResult{tlsCheck, apiCheck, clockCheck}
+	}
+
+	// The Date header has a precision of one second, and it's set
+	// somewhere during the request.
+	skew := d.now().Add(-latency / 2).Sub(serverTime).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	clockCheck.Status = statusPass
+	clockCheck.Message = fmt.Sprintf("Local time differs from the API by %s", skew)
+	if skew > clockSkewWarn {
+		clockCheck.Status = statusWarn
+		clockCheck.Hint = "Please synchronize your clock, e. g. via NTP. SSO tokens may be rejected otherwise."
+	}
+	if skew > clockSkewFail {
+		clockCheck.Status = statusFail
+	}
+
+	return []checkResult{tlsCheck, apiCheck, clockCheck}
+}
+
+func (d *doctor) checkKubectlBinary() checkResult {
+	if !d.checkKubectl() {
+		return checkResult{
+			Name:    "kubectl",
+			Status:  statusWarn,
+			Message: "kubectl was not found",
+			Hint:    "kubectl is needed to work with clusters. Please install it, see https://kubernetes.io/docs/tasks/tools/",
+		}
+	}
+
+	path, _ := d.lookPath("kubectl")
+	return checkResult{Name: "kubectl", Status: statusPass, Message: "Found " + path}
+}
+
+func (d *doctor) checkKubie() checkResult {
+	path, err := d.lookPath("kubie")
+	if err != nil {
+		return checkResult{Name: "kubie", Status: statusSkip, Message: "Not installed. It's only needed for 'gsctl create kubeconfig --kubie'."}
+	}
+
+	return checkResult{Name: "kubie", Status: statusPass, Message: "Found " + path}
+}
+
+// checkKubeconfigs checks that the files referenced by kubeconfig contexts
+// exist.
+func (d *doctor) checkKubeconfigs() []checkResult {
+	var results []checkResult
+
+	for _, kubeconfigPath := range d.kubeconfigPaths {
+		r := checkResult{Name: "Kubeconfig " + kubeconfigPath}
+
+		data, err := afero.ReadFile(d.fs, kubeconfigPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			r.Status = statusFail
+			r.Message = fmt.Sprintf("Could not be read: %s", err.Error())
+			results = append(results, r)
+			continue
+		}
+
+		kubeconfig, err := clientcmd.Load(data)
+		if err != nil {
+			r.Status = statusFail
+			r.Message = fmt.Sprintf("Could not be parsed: %s", err.Error())
+			r.Hint = "Please fix the file, otherwise kubectl and gsctl can't use it."
+			results = append(results, r)
+			continue
+		}
+
+		var problems []string
+		var contexts []string
+		for name := range kubeconfig.Contexts {
+			contexts = append(contexts, name)
+		}
+		sort.Strings(contexts)
+
+		for _, name := range contexts {
+			context := kubeconfig.Contexts[name]
+			var files []string
+			if cluster, ok := kubeconfig.Clusters[context.Cluster]; ok {
+				files = append(files, cluster.CertificateAuthority)
+			}
+			if authInfo, ok := kubeconfig.AuthInfos[context.AuthInfo]; ok {
+				files = append(files, authInfo.ClientCertificate, authInfo.ClientKey)
+			}
+
+			for _, file := range files {
+				if file == "" {
+					continue
+				}
+				exists, _ := afero.Exists(d.fs, file)
+				if !exists {
+					problems = append(problems, fmt.Sprintf("context '%s' references missing file %s", name, file))
+				}
+			}
+		}
+
+		if len(problems) > 0 {
+			r.Status = statusWarn
+			r.Message = strings.Join(problems, "; ")
+			r.Hint = "Create the kubeconfig again using 'gsctl create kubeconfig', or remove the context using 'kubectl config delete-context'."
+		} else {
+			r.Status = statusPass
+			r.Message = fmt.Sprintf("%d context(s), all referenced files exist", len(contexts))
+		}
+
+		results = append(results, r)
+	}
+
+	return results
+}
+
+// proxyForURL determines the proxy like http.ProxyFromEnvironment, but
+// using the given getenv function.
+func proxyForURL(u *url.URL, getenv func(string) string) (*url.URL, error) {
+	value := func(names ...string) string {
+		for _, name := range names {
+			if v := getenv(name); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	proxyConfig := &httpproxy.Config{
+		HTTPProxy:  value("HTTP_PROXY", "http_proxy"),
+		HTTPSProxy: value("HTTPS_PROXY", "https_proxy"),
+		NoProxy:    value("NO_PROXY", "no_proxy"),
+	}
+
+	proxyURL, err := proxyConfig.ProxyFunc()(u)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return proxyURL, nil
+}
+
+// redactURL removes a password from a URL.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), "REDACTED")
+	}
+	return u.String()
+}
+
+// isCertificateError returns true for TLS certificate validation errors.
+func isCertificateError(err error) bool {
+	var unknownAuthorityError x509.UnknownAuthorityError
+	var hostnameError x509.HostnameError
+	var certificateInvalidError x509.CertificateInvalidError
+
+	return errors.As(err, &unknownAuthorityError) || errors.As(err, &hostnameError) || errors.As(err, &certificateInvalidError)
+}
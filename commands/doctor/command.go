@@ -0,0 +1,269 @@
+// Package doctor implements the 'doctor' command.
+package doctor
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gscliauth/oidc"
+	"github.com/giantswarm/microerror"
+	rootcerts "github.com/hashicorp/go-rootcerts"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl doctor'
+	Command = &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with the environment and the API connection",
+		Long: `Runs a checklist to find out why gsctl doesn't work as expected.
+
+The checks cover:
+
+- the config file and its permissions
+- login and token expiry for every endpoint, including a refresh of SSO tokens
+- DNS, proxy settings, root certificates (GSCTL_CAFILE, GSCTL_CAPATH) and TLS
+  for the selected endpoint
+- API latency and the difference between the local and the API clock
+- whether kubectl and kubie are installed
+- kubeconfig contexts referencing certificate files that don't exist
+
+Every check results in pass, warn, fail or skip, with a hint how to solve
+problems. The command exits with a non-zero code if any check fails.
+
+Please include the output when contacting Giant Swarm support.
+
+Examples:
+
+  gsctl doctor
+
+  gsctl doctor --endpoint production
+
+  gsctl doctor --output json
+`,
+
+		// The config file may be broken, which is one of the things we check.
+		// So we don't let the root command fail on it.
+		PersistentPreRunE: initConfig,
+
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	arguments Arguments
+
+	// configError is the error from reading the config file, if any.
+	configError error
+)
+
+const (
+	requestTimeout = 10 * time.Second
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, "Output format. Specifying 'json' will print the results as JSON.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint  string
+	outputFormat string
+	verbose      bool
+}
+
+// result is the structured output of the command.
+type result struct {
+	Checks   []checkResult `json:"checks"`
+	Passed   int           `json:"passed"`
+	Warnings int           `json:"warnings"`
+	Failures int           `json:"failures"`
+	Skipped  int           `json:"skipped"`
+}
+
+// initConfig reads the configuration, remembering errors instead of
+// failing.
+func initConfig(cmd *cobra.Command, args []string) error {
+	configError = config.InitializeWithLogger(afero.NewOsFs(), flags.ConfigDirPath, ioutil.Discard)
+	return nil
+}
+
+func collectArguments() Arguments {
+	return Arguments{
+		apiEndpoint:  config.Config.ChooseEndpoint(flags.APIEndpoint),
+		outputFormat: flags.OutputFormat,
+		verbose:      flags.Verbose,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	switch args.outputFormat {
+	case "", output.FormatTable, output.FormatJSON:
+	default:
+		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	errors.HandleCommonErrors(err)
+	errors.PrintError(err, err.Error(), "")
+	errors.Exit(err)
+}
+
+// newDoctor creates a doctor using the real environment.
+func newDoctor(args Arguments) *doctor {
+	d := &doctor{
+		fs:             config.FileSystem,
+		configFilePath: config.ConfigFilePath,
+		configError:    configError,
+		endpoint:       args.apiEndpoint,
+
+		now:          time.Now,
+		getenv:       os.Getenv,
+		lookupHost:   net.LookupHost,
+		lookPath:     exec.LookPath,
+		checkKubectl: util.CheckKubectl,
+		refreshToken: func(refreshToken string) error {
+			_, err := oidc.RefreshToken(refreshToken)
+			return err
+		},
+	}
+
+	if d.fs == nil {
+		d.fs = afero.NewOsFs()
+	}
+
+	if configError == nil {
+		for _, endpoint := range config.Config.Endpoints() {
+			e := config.Config.EndpointConfig(endpoint)
+			d.endpoints = append(d.endpoints, endpointAuth{
+				URL:          endpoint,
+				Alias:        e.Alias,
+				Scheme:       e.Scheme,
+				Token:        e.Token,
+				RefreshToken: e.RefreshToken,
+			})
+		}
+	}
+
+	d.kubeconfigPaths = config.KubeConfigPaths
+	if len(d.kubeconfigPaths) == 0 {
+		d.kubeconfigPaths = clientcmd.NewDefaultClientConfigLoadingRules().GetLoadingPrecedence()
+	}
+
+	// The client used by all commands, except that certificate errors are
+	// reported instead of failing early.
+	tlsConfig := &tls.Config{}
+	_ = rootcerts.ConfigureTLS(tlsConfig, &rootcerts.Config{
+		CAFile: os.Getenv("GSCTL_CAFILE"),
+		CAPath: os.Getenv("GSCTL_CAPATH"),
+	})
+	d.httpClient = &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+
+	return d
+}
+
+// summarize counts the results by status.
+func summarize(checks []checkResult) result {
+	r := result{Checks: checks}
+	for _, c := range checks {
+		switch c.Status {
+		case statusPass:
+			r.Passed++
+		case statusWarn:
+			r.Warnings++
+		case statusFail:
+			r.Failures++
+		case statusSkip:
+			r.Skipped++
+		}
+	}
+	return r
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	r := summarize(newDoctor(arguments).run())
+
+	if arguments.outputFormat == output.FormatJSON {
+		printer, err := output.NewPrinter(arguments.outputFormat)
+		if err == nil {
+			err = printer.Print(os.Stdout, r, ".checks[*].name")
+		}
+		if err != nil {
+			errors.PrintError(err, err.Error(), "")
+			errors.Exit(err)
+		}
+	} else {
+		printTable(os.Stdout, r)
+	}
+
+	if r.Failures > 0 {
+		// Output has been printed already.
+		errors.Exit(microerror.Mask(errors.ChecksFailedError))
+	}
+}
+
+func printTable(w io.Writer, r result) {
+	width := 0
+	for _, c := range r.Checks {
+		if len(c.Name) > width {
+			width = len(c.Name)
+		}
+	}
+
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "%s  %-*s  %s\n", formatStatus(c.Status), width, c.Name, c.Message)
+		if c.Hint != "" {
+			fmt.Fprintf(w, "      %-*s  %s\n", width, "", color.YellowString(c.Hint))
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed, %d skipped\n", r.Passed, r.Warnings, r.Failures, r.Skipped)
+}
+
+func formatStatus(status string) string {
+	switch status {
+	case statusPass:
+		return color.GreenString("PASS")
+	case statusWarn:
+		return color.YellowString("WARN")
+	case statusFail:
+		return color.RedString("FAIL")
+	}
+	return "SKIP"
+}
@@ -138,6 +138,17 @@ func IsClusterNotFoundError(err error) bool {
 	return false
 }
 
+// NoClustersMatchSelectorError means that no cluster matches the label
+// selector given via --selector.
+var NoClustersMatchSelectorError = &microerror.Error{
+	Kind: "NoClustersMatchSelectorError",
+}
+
+// IsNoClustersMatchSelectorError asserts NoClustersMatchSelectorError.
+func IsNoClustersMatchSelectorError(err error) bool {
+	return microerror.Cause(err) == NoClustersMatchSelectorError
+}
+
 // NodePoolNotFoundError means that a node pool the user wants to interact with does not exist.
 var NodePoolNotFoundError = &microerror.Error{
 	Kind: "NodePoolNotFoundError",
@@ -148,6 +159,36 @@ func IsNodePoolNotFound(err error) bool {
 	return microerror.Cause(err) == NodePoolNotFoundError
 }
 
+// AppNameMissingError means a required app name has not been given as input
+var AppNameMissingError = &microerror.Error{
+	Kind: "AppNameMissingError",
+}
+
+// IsAppNameMissing asserts AppNameMissingError.
+func IsAppNameMissing(err error) bool {
+	return microerror.Cause(err) == AppNameMissingError
+}
+
+// InvalidAppArgumentError means that the <cluster>/<app> argument has not been given in the expected format.
+var InvalidAppArgumentError = &microerror.Error{
+	Kind: "InvalidAppArgumentError",
+}
+
+// IsInvalidAppArgument asserts InvalidAppArgumentError.
+func IsInvalidAppArgument(err error) bool {
+	return microerror.Cause(err) == InvalidAppArgumentError
+}
+
+// AppNotFoundError means that an app the user wants to interact with is not installed in the cluster.
+var AppNotFoundError = &microerror.Error{
+	Kind: "AppNotFoundError",
+}
+
+// IsAppNotFound asserts AppNotFoundError.
+func IsAppNotFound(err error) bool {
+	return microerror.Cause(err) == AppNotFoundError
+}
+
 // ReleaseVersionMissingError means the required release version argument is missing
 var ReleaseVersionMissingError = &microerror.Error{
 	Kind: "ReleaseVersionMissingError",
@@ -430,6 +471,38 @@ func IsEmptyPasswordError(err error) bool {
 	return microerror.Cause(err) == EmptyPasswordError
 }
 
+// EmptyTokenError means the auth token supplied by the user was empty
+var EmptyTokenError = &microerror.Error{
+	Kind: "EmptyTokenError",
+}
+
+// IsEmptyTokenError asserts EmptyTokenError.
+func IsEmptyTokenError(err error) bool {
+	return microerror.Cause(err) == EmptyTokenError
+}
+
+// TokenFileNotReadableError means the file given via --token-file
+// could not be read
+var TokenFileNotReadableError = &microerror.Error{
+	Kind: "TokenFileNotReadableError",
+}
+
+// IsTokenFileNotReadableError asserts TokenFileNotReadableError.
+func IsTokenFileNotReadableError(err error) bool {
+	return microerror.Cause(err) == TokenFileNotReadableError
+}
+
+// NoRefreshTokenError means no refresh token is stored for an endpoint,
+// so its token cannot be refreshed
+var NoRefreshTokenError = &microerror.Error{
+	Kind: "NoRefreshTokenError",
+}
+
+// IsNoRefreshTokenError asserts NoRefreshTokenError.
+func IsNoRefreshTokenError(err error) bool {
+	return microerror.Cause(err) == NoRefreshTokenError
+}
+
 // TokenArgumentNotApplicableError means the user used --auth-token argument
 // but it wasn't permitted for that command
 var TokenArgumentNotApplicableError = &microerror.Error{
@@ -577,6 +650,28 @@ func IsDurationExceededError(err error) bool {
 	return microerror.Cause(err) == DurationExceededError
 }
 
+// InvalidScheduleError means that the time given via --at or the
+// maintenance window given via --window is invalid.
+var InvalidScheduleError = &microerror.Error{
+	Kind: "InvalidScheduleError",
+}
+
+// IsInvalidScheduleError asserts InvalidScheduleError.
+func IsInvalidScheduleError(err error) bool {
+	return microerror.Cause(err) == InvalidScheduleError
+}
+
+// MaintenanceWindowClosedError means that the maintenance window closed
+// before an operation could be started.
+var MaintenanceWindowClosedError = &microerror.Error{
+	Kind: "MaintenanceWindowClosedError",
+}
+
+// IsMaintenanceWindowClosedError asserts MaintenanceWindowClosedError.
+func IsMaintenanceWindowClosedError(err error) bool {
+	return microerror.Cause(err) == MaintenanceWindowClosedError
+}
+
 // SSOError means something went wrong during the SSO process
 var SSOError = &microerror.Error{
 	Kind: "SSOError",
@@ -727,3 +822,36 @@ var NotPercentage = &microerror.Error{
 	Kind: "NotPercentage",
 	Desc: "Value should be in the range between 0 and 100.",
 }
+
+// TerminalRequiredError means that a command needs an interactive terminal,
+// but STDIN or STDOUT is not one.
+var TerminalRequiredError = &microerror.Error{
+	Kind: "TerminalRequiredError",
+}
+
+// IsTerminalRequiredError asserts TerminalRequiredError.
+func IsTerminalRequiredError(err error) bool {
+	return microerror.Cause(err) == TerminalRequiredError
+}
+
+// ChecksFailedError means that 'gsctl doctor' found at least one problem.
+var ChecksFailedError = &microerror.Error{
+	Kind: "ChecksFailedError",
+}
+
+// IsChecksFailedError asserts ChecksFailedError.
+func IsChecksFailedError(err error) bool {
+	return microerror.Cause(err) == ChecksFailedError
+}
+
+// DifferencesFoundError means that 'gsctl diff' found differences between
+// a cluster definition and the cluster. It results in exit code 1, so CI
+// pipelines can gate on it.
+var DifferencesFoundError = &microerror.Error{
+	Kind: "DifferencesFoundError",
+}
+
+// IsDifferencesFoundError asserts DifferencesFoundError.
+func IsDifferencesFoundError(err error) bool {
+	return microerror.Cause(err) == DifferencesFoundError
+}
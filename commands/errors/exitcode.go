@@ -0,0 +1,265 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/oidc"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
+)
+
+// Exit codes returned by gsctl. Scripts can rely on these values, so they
+// must not be changed. See docs/Exit-Codes.md for details.
+const (
+	// ExitCodeOK means the command succeeded.
+	ExitCodeOK = 0
+
+	// ExitCodeGeneral is used for errors not covered by a more specific code.
+	ExitCodeGeneral = 1
+
+	// ExitCodeInvalidInput means invalid flags, arguments or input files,
+	// including API responses with status 400 Bad Request.
+	ExitCodeInvalidInput = 2
+
+	// ExitCodeNotAuthenticated means the user is not logged in or the
+	// credentials were rejected (401 Unauthorized).
+	ExitCodeNotAuthenticated = 3
+
+	// ExitCodeForbidden means access was denied (403 Forbidden).
+	ExitCodeForbidden = 4
+
+	// ExitCodeNotFound means a cluster, node pool, app, release, organization
+	// or other resource does not exist (404 Not Found).
+	ExitCodeNotFound = 5
+
+	// ExitCodeConflict means the resource is not in a state that permits the
+	// action, e. g. no upgrade available (409 Conflict).
+	ExitCodeConflict = 6
+
+	// ExitCodeUnavailable means the API could not be reached or responded
+	// with a server error. Retrying later may help.
+	ExitCodeUnavailable = 7
+
+	// ExitCodeAborted means the user did not confirm the action, or the
+	// maintenance window closed before the action could be started.
+	ExitCodeAborted = 8
+
+	// ExitCodeEnvironment means a problem with the local environment, like
+	// a missing kubectl binary or a file that could not be written.
+	ExitCodeEnvironment = 9
+)
+
+// ExitCode returns the exit code for the given error. nil results in
+// ExitCodeOK, unknown errors in ExitCodeGeneral.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+
+	if apiErr := apiError(err); apiErr != nil {
+		return exitCodeForAPIError(apiErr)
+	}
+
+	switch {
+	case oidc.IsAuthorizationError(err),
+		oidc.IsRefreshError(err),
+		IsNotLoggedInError(err),
+		IsNoRefreshTokenError(err),
+		IsNotAuthorizedError(err),
+		IsInvalidCredentialsError(err),
+		IsUserAccountInactiveError(err),
+		IsSSOError(err):
+		return ExitCodeNotAuthenticated
+
+	case IsAccessForbiddenError(err):
+		return ExitCodeForbidden
+
+	case IsClusterNotFoundError(err),
+		IsNoClustersMatchSelectorError(err),
+		IsNodePoolNotFound(err),
+		IsAppNotFound(err),
+		IsReleaseNotFoundError(err),
+		IsOrganizationNotFoundError(err),
+		IsCredentialNotFoundError(err),
+		IsEndpointNotFoundError(err):
+		return ExitCodeNotFound
+
+	case IsNoUpgradeAvailableError(err),
+		IsDesiredEqualsCurrentStateError(err),
+		IsCannotScaleBelowMinimumWorkersError(err),
+		IsCannotScaleCluster(err),
+		IsClusterDoesNotSupportNodePools(err),
+		IsCredentialsAlreadySetError(err),
+		IsNoOpError(err):
+		return ExitCodeConflict
+
+	case IsInternalServerError(err),
+		IsNoResponseError(err),
+		IsUpdateCheckFailed(err):
+		return ExitCodeUnavailable
+
+	case IsCommandAbortedError(err),
+		IsMaintenanceWindowClosedError(err):
+		return ExitCodeAborted
+
+	case IsKubectlMissingError(err),
+		IsCouldNotWriteFileError(err),
+		IsTerminalRequiredError(err):
+		return ExitCodeEnvironment
+
+	case IsConflictingFlagsError(err),
+		IsConflictingWorkerFlagsUsed(err),
+		IsIncompatibleSettings(err),
+		IsRequiredFlagMissingError(err),
+		IsClusterNameOrIDMissingError(err),
+		IsNodePoolIDMissingError(err),
+		IsNodePoolIDMalformedError(err),
+		IsInvalidNodePoolIDArgument(err),
+		IsAppNameMissing(err),
+		IsInvalidAppArgument(err),
+		IsReleaseVersionMissingError(err),
+		IsInvalidReleaseError(err),
+		IsClusterOwnerMissingError(err),
+		IsOrganizationNotSpecifiedError(err),
+		IsEndpointMissingError(err),
+		IsEmptyPasswordError(err),
+		IsEmptyTokenError(err),
+		IsNoEmailArgumentGivenError(err),
+		IsTokenArgumentNotApplicableError(err),
+		IsPasswordArgumentNotApplicableError(err),
+		IsInvalidCNPrefixError(err),
+		IsInvalidDurationError(err),
+		IsDurationExceededError(err),
+		IsInvalidScheduleError(err),
+		IsOutputFormatInvalid(err),
+		IsWorkersMinMaxInvalid(err),
+		IsNotEnoughWorkerNodesError(err),
+		IsYAMLFileNotReadable(err),
+		IsYAMLNotParseable(err),
+		IsTokenFileNotReadableError(err),
+		IsProviderNotSupportedError(err),
+		IsBadRequestError(err):
+		return ExitCodeInvalidInput
+	}
+
+	return ExitCodeGeneral
+}
+
+// exitCodeForAPIError maps the HTTP status of an API error to an exit code.
+func exitCodeForAPIError(apiErr *clienterror.APIError) int {
+	if clienterror.IsOfflineError(apiErr) {
+		return ExitCodeUnavailable
+	}
+
+	switch apiErr.HTTPStatusCode {
+	case http.StatusBadRequest:
+		return ExitCodeInvalidInput
+	case http.StatusUnauthorized:
+		return ExitCodeNotAuthenticated
+	case http.StatusForbidden:
+		return ExitCodeForbidden
+	case http.StatusNotFound:
+		return ExitCodeNotFound
+	case http.StatusConflict:
+		return ExitCodeConflict
+	}
+
+	if isTemporary(apiErr) || apiErr.HTTPStatusCode >= http.StatusInternalServerError {
+		return ExitCodeUnavailable
+	}
+
+	return ExitCodeGeneral
+}
+
+// isTemporary returns true if retrying the request may succeed.
+func isTemporary(apiErr *clienterror.APIError) bool {
+	if apiErr.IsTemporary || apiErr.IsTimeout {
+		return true
+	}
+
+	switch apiErr.HTTPStatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// apiError returns the APIError wrapped in err, or nil.
+func apiError(err error) *clienterror.APIError {
+	if apiErr, ok := microerror.Cause(err).(*clienterror.APIError); ok {
+		return apiErr
+	}
+	if apiErr, ok := err.(*clienterror.APIError); ok {
+		return apiErr
+	}
+
+	return nil
+}
+
+// ErrorJSON is the error object printed instead of the human readable error
+// message when the output format is JSON.
+type ErrorJSON struct {
+	Message    string `json:"message"`
+	Details    string `json:"details,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	RequestID  string `json:"request_id,omitempty"`
+	Temporary  bool   `json:"temporary"`
+	ExitCode   int    `json:"exit_code"`
+}
+
+// NewErrorJSON returns the JSON error object for err, using the given
+// headline and subtext as message and details.
+func NewErrorJSON(err error, headline, subtext string) *ErrorJSON {
+	e := &ErrorJSON{
+		Message:  headline,
+		Details:  subtext,
+		ExitCode: ExitCode(err),
+	}
+
+	if e.Message == "" && err != nil {
+		e.Message = err.Error()
+	}
+
+	if apiErr := apiError(err); apiErr != nil {
+		e.HTTPStatus = apiErr.HTTPStatusCode
+		e.RequestID = apiErr.RequestID
+		e.Temporary = isTemporary(apiErr)
+		if e.Details == "" {
+			e.Details = apiErr.ErrorDetails
+		}
+	} else {
+		e.Temporary = ExitCode(err) == ExitCodeUnavailable
+	}
+
+	return e
+}
+
+// PrintError prints the error headline in red, followed by the subtext, if
+// given. With '--output json' a JSON error object is printed instead.
+func PrintError(err error, headline, subtext string) {
+	if flags.OutputFormat == output.FormatJSON {
+		data, jsonErr := json.MarshalIndent(NewErrorJSON(err, headline, subtext), "", "  ")
+		if jsonErr == nil {
+			fmt.Println(string(data))
+			return
+		}
+	}
+
+	fmt.Println(color.RedString(headline))
+	if subtext != "" {
+		fmt.Println(subtext)
+	}
+}
+
+// Exit terminates the program with the exit code matching err.
+func Exit(err error) {
+	os.Exit(ExitCode(err))
+}
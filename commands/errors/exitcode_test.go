@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client/clienterror"
+)
+
+// TestExitCode tests the mapping of errors to exit codes.
+func TestExitCode(t *testing.T) {
+	var testCases = []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitCodeOK},
+		{errors.New("something"), ExitCodeGeneral},
+		{microerror.Mask(ClusterNotFoundError), ExitCodeNotFound},
+		{microerror.Mask(NotLoggedInError), ExitCodeNotAuthenticated},
+		{microerror.Mask(AccessForbiddenError), ExitCodeForbidden},
+		{microerror.Mask(ConflictingFlagsError), ExitCodeInvalidInput},
+		{microerror.Mask(YAMLFileNotReadableError), ExitCodeInvalidInput},
+		{microerror.Mask(NoUpgradeAvailableError), ExitCodeConflict},
+		{microerror.Mask(NoResponseError), ExitCodeUnavailable},
+		{microerror.Mask(CommandAbortedError), ExitCodeAborted},
+		{microerror.Mask(KubectlMissingError), ExitCodeEnvironment},
+		{microerror.Mask(DifferencesFoundError), ExitCodeGeneral},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusBadRequest}, ExitCodeInvalidInput},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusUnauthorized}, ExitCodeNotAuthenticated},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusForbidden}, ExitCodeForbidden},
+		{microerror.Mask(&clienterror.APIError{HTTPStatusCode: http.StatusNotFound}), ExitCodeNotFound},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusConflict}, ExitCodeConflict},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusTooManyRequests}, ExitCodeUnavailable},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusInternalServerError}, ExitCodeUnavailable},
+		{&clienterror.APIError{IsTimeout: true}, ExitCodeUnavailable},
+		{&clienterror.APIError{HTTPStatusCode: http.StatusTeapot}, ExitCodeGeneral},
+	}
+
+	for i, tc := range testCases {
+		code := ExitCode(tc.err)
+		if code != tc.expected {
+			t.Errorf("Case %d - Expected exit code %d, got %d", i, tc.expected, code)
+		}
+	}
+}
+
+// TestNewErrorJSON tests the JSON error object.
+func TestNewErrorJSON(t *testing.T) {
+	err := &clienterror.APIError{
+		HTTPStatusCode: http.StatusServiceUnavailable,
+		ErrorMessage:   "Service unavailable",
+		ErrorDetails:   "Please try again later.",
+		RequestID:      "request-id",
+	}
+
+	e := NewErrorJSON(microerror.Mask(err), "Service unavailable", "")
+	if e.Message != "Service unavailable" {
+		t.Errorf("Unexpected message %q", e.Message)
+	}
+	if e.Details != "Please try again later." {
+		t.Errorf("Unexpected details %q", e.Details)
+	}
+	if e.HTTPStatus != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected HTTP status %d", e.HTTPStatus)
+	}
+	if e.RequestID != "request-id" {
+		t.Errorf("Unexpected request ID %q", e.RequestID)
+	}
+	if !e.Temporary {
+		t.Error("Expected temporary to be true")
+	}
+	if e.ExitCode != ExitCodeUnavailable {
+		t.Errorf("Unexpected exit code %d", e.ExitCode)
+	}
+
+	e = NewErrorJSON(microerror.Mask(ClusterNotFoundError), "", "")
+	if e.Message != ClusterNotFoundError.Error() {
+		t.Errorf("Unexpected message %q", e.Message)
+	}
+	if e.Temporary || e.HTTPStatus != 0 || e.ExitCode != ExitCodeNotFound {
+		t.Errorf("Unexpected error object %#v", e)
+	}
+}
@@ -2,15 +2,27 @@ package errors
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/oidc"
 	"github.com/giantswarm/microerror"
 
+	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/client/clienterror"
 )
 
+// HandleClientErrors handles the errors known to the client package. If the
+// error given is recognized, it prints according text for the end user and
+// exits the process. Otherwise we simply return.
+func HandleClientErrors(err error) {
+	headline, subtext := client.ErrorMessage(err)
+	if headline == "" {
+		return
+	}
+
+	PrintError(err, headline, subtext)
+	Exit(err)
+}
+
 // HandleCommonErrors is a common function to handle certain errors happening in
 // more than one command. If the error given is handled by the function, it
 // prints according text for the end user and exits the process.
@@ -79,6 +91,9 @@ func HandleCommonErrors(err error) {
 		case IsEndpointMissingError(err):
 			headline = "There is no endpoint selected."
 			subtext = "Please use the '-e|--endpoint' flag or select an endpoint using 'gsctl select endpoint'."
+		case IsNoClustersMatchSelectorError(err):
+			headline = "No clusters match the selector."
+			subtext = "Please check the selector given via --selector using 'gsctl list clusters --selector'."
 		}
 
 	}
@@ -87,9 +102,6 @@ func HandleCommonErrors(err error) {
 		return
 	}
 
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	PrintError(err, headline, subtext)
+	Exit(err)
 }
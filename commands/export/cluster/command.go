@@ -0,0 +1,256 @@
+// Package cluster implements the "export cluster" command.
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl export cluster'
+	Command = &cobra.Command{
+		Use:   "cluster <cluster-name/cluster-id>",
+		Short: "Export a cluster definition",
+		Long: `Print the definition of an existing cluster in YAML format.
+
+The output can be used with 'gsctl create cluster -f' to create a cluster
+with the same specification, or with 'gsctl diff' and 'gsctl apply' to
+manage the cluster in a declarative way.
+
+For clusters supporting node pools, a v5 definition is created, containing
+the release version, master node configuration, labels, and all node pools
+with their scaling settings, availability zones, and node specs (instance
+type, spot instance distribution, VM size). Labels containing 'giantswarm.io'
+are omitted, as they are managed by Giant Swarm.
+
+For other clusters, a v4 definition is created, containing the number of
+availability zones, scaling settings, and the worker node specification.
+
+Examples:
+
+  gsctl export cluster f01r4
+
+  gsctl export cluster "Cluster name" --output-file my-cluster.yaml
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "export-cluster"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFile, "output-file", "", "", "Path of a file to write the definition to. If not given, the definition is printed to STDOUT.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AuthToken         string
+	ClusterNameOrID   string
+	FileSystem        afero.Fs
+	OutputFile        string
+	UserProvidedToken string
+	Verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments(positionalArgs []string) Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	clusterNameOrID := ""
+	if len(positionalArgs) > 0 {
+		clusterNameOrID = strings.TrimSpace(positionalArgs[0])
+	}
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AuthToken:         token,
+		ClusterNameOrID:   clusterNameOrID,
+		FileSystem:        config.FileSystem,
+		OutputFile:        flags.OutputFile,
+		UserProvidedToken: flags.Token,
+		Verbose:           flags.Verbose,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.AuthToken == "" && args.UserProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.ClusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments(positionalArgs)
+	err := verifyPreconditions(arguments)
+
+	if err == nil {
+		return
+	}
+
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	errors.PrintError(err, err.Error(), "")
+	errors.Exit(err)
+}
+
+// exportCluster is our business function. It returns the cluster's
+// definition as YAML.
+func exportCluster(args Arguments) ([]byte, error) {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	var definition interface{}
+
+	if args.Verbose {
+		fmt.Fprintln(os.Stderr, color.WhiteString("Fetching details for cluster via v5 API endpoint."))
+	}
+	clusterV5Response, err := clientWrapper.GetClusterV5(clusterID, auxParams)
+	if err == nil {
+		if args.Verbose {
+			fmt.Fprintln(os.Stderr, color.WhiteString("Fetching node pools"))
+		}
+		nodePoolsResponse, err := clientWrapper.GetNodePools(clusterID, auxParams)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		var nodePools []*models.V5GetNodePoolsResponseItems
+		if nodePoolsResponse != nil {
+			nodePools = nodePoolsResponse.Payload
+		}
+
+		def := clusterdefinition.FromV5Cluster(clusterV5Response.Payload, nodePools)
+
+		// Labels managed by Giant Swarm can't be set on creation.
+		for key := range def.Labels {
+			if strings.Contains(key, util.LabelFilterKeySubstring) {
+				delete(def.Labels, key)
+			}
+		}
+		if len(def.Labels) == 0 {
+			def.Labels = nil
+		}
+
+		definition = def
+	} else {
+		// 404 means this is not a v5 cluster, 400 is likely "not supported
+		// on this provider". In both cases we try v4.
+		if !clienterror.IsNotFoundError(err) && !clienterror.IsBadRequestError(err) && !clienterror.IsMalformedResponse(err) {
+			return nil, microerror.Mask(err)
+		}
+
+		if args.Verbose {
+			fmt.Fprintln(os.Stderr, color.WhiteString("No usable v5 response. Fetching details for cluster via v4 API endpoint."))
+		}
+		clusterV4Response, err := clientWrapper.GetClusterV4(clusterID, auxParams)
+		if err != nil {
+			if clienterror.IsNotFoundError(err) {
+				return nil, microerror.Mask(errors.ClusterNotFoundError)
+			}
+			return nil, microerror.Mask(err)
+		}
+
+		definition = clusterdefinition.FromV4Cluster(clusterV4Response.Payload)
+	}
+
+	out, err := yaml.Marshal(definition)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return out, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	out, err := exportCluster(arguments)
+	if err == nil && arguments.OutputFile != "" {
+		err = afero.WriteFile(arguments.FileSystem, arguments.OutputFile, out, 0600)
+		if err != nil {
+			err = microerror.Maskf(errors.CouldNotWriteFileError, err.Error())
+		}
+	}
+
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		headline := ""
+		subtext := ""
+
+		switch {
+		case errors.IsClusterNotFoundError(err):
+			headline = "Cluster not found"
+			subtext = fmt.Sprintf("Either there is no cluster with ID '%s', or you have no access to it.\n", arguments.ClusterNameOrID)
+			subtext += "Please check whether the cluster is listed when executing 'gsctl list clusters'."
+		case errors.IsCouldNotWriteFileError(err):
+			headline = "Could not write definition file"
+			subtext = err.Error()
+		default:
+			headline = err.Error()
+		}
+
+		// print output
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
+	}
+
+	if arguments.OutputFile != "" {
+		fmt.Println(color.GreenString("Cluster definition written to %s", arguments.OutputFile))
+		return
+	}
+
+	fmt.Print(string(out))
+}
@@ -0,0 +1,213 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/pkg/clusterdefinition"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// configYAML is a mock configuration used by some of the tests.
+const configYAML = `last_version_check: 0001-01-01T00:00:00Z
+endpoints:
+  https://foo:
+    email: email@example.com
+    token: some-token
+    provider: aws
+selected_endpoint: https://foo
+updated: 2017-09-29T11:23:15+02:00
+`
+
+// Test_verifyPreconditions tests the checks happening before any API call.
+func Test_verifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			Arguments{APIEndpoint: "https://foo", AuthToken: "token", ClusterNameOrID: "f01r4"},
+			nil,
+		},
+		{
+			Arguments{AuthToken: "token", ClusterNameOrID: "f01r4"},
+			errors.IsEndpointMissingError,
+		},
+		{
+			Arguments{APIEndpoint: "https://foo", ClusterNameOrID: "f01r4"},
+			errors.IsNotLoggedInError,
+		},
+		{
+			Arguments{APIEndpoint: "https://foo", AuthToken: "token"},
+			errors.IsClusterNameOrIDMissingError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil {
+				if err != nil {
+					t.Errorf("Case %d - Unexpected error %#v", i, err)
+				}
+			} else if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+			}
+		})
+	}
+}
+
+// Test_exportCluster tests exporting v5 and v4 clusters from a mock API.
+func Test_exportCluster(t *testing.T) {
+	var testCases = []struct {
+		clusterID    string
+		expectedYAML string
+		errorMatcher func(error) bool
+	}{
+		// v5 cluster with HA masters, labels, and node pools.
+		{
+			clusterID: "f01r4",
+			expectedYAML: `api_version: v5
+name: My cluster
+owner: acme
+release_version: 11.0.0
+master_nodes:
+  high_availability: true
+  availability_zones:
+  - eu-west-1a
+  - eu-west-1b
+  - eu-west-1c
+nodepools:
+- name: general
+  availability_zones:
+    zones:
+    - eu-west-1a
+  scaling:
+    min: 2
+    max: 5
+  node_spec:
+    aws:
+      instance_distribution:
+        on_demand_base_capacity: 1
+        on_demand_percentage_above_base_capacity: 50
+      instance_type: m5.xlarge
+      use_alike_instance_types: true
+labels:
+  environment: testing
+`,
+		},
+		// v4 cluster.
+		{
+			clusterID: "v4c1u",
+			expectedYAML: `name: Old cluster
+owner: acme
+release_version: 8.5.0
+availability_zones: 1
+scaling:
+  min: 3
+  max: 3
+workers:
+- aws:
+    instance_type: m4.xlarge
+`,
+		},
+		// Cluster doesn't exist.
+		{
+			clusterID:    "n0n3x",
+			errorMatcher: errors.IsClusterNotFoundError,
+		},
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[
+				{"id": "f01r4", "name": "My cluster", "owner": "acme", "release_version": "11.0.0"},
+				{"id": "v4c1u", "name": "Old cluster", "owner": "acme", "release_version": "8.5.0"}
+			]`))
+		case "GET /v5/clusters/f01r4/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "f01r4", "name": "My cluster", "owner": "acme", "release_version": "11.0.0",
+				"master_nodes": {"high_availability": true, "availability_zones": ["eu-west-1a", "eu-west-1b", "eu-west-1c"]},
+				"labels": {"environment": "testing", "giantswarm.io/cluster": "f01r4"}}`))
+		case "GET /v5/clusters/f01r4/nodepools/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "a7rc4", "name": "general", "availability_zones": ["eu-west-1a"],
+				"scaling": {"min": 2, "max": 5},
+				"node_spec": {"aws": {"instance_type": "m5.xlarge", "use_alike_instance_types": true,
+					"instance_distribution": {"on_demand_base_capacity": 1, "on_demand_percentage_above_base_capacity": 50}}}}]`))
+		case "GET /v4/clusters/v4c1u/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "v4c1u", "name": "Old cluster", "owner": "acme", "release_version": "8.5.0",
+				"availability_zones": ["eu-central-1a"],
+				"scaling": {"min": 3, "max": 3},
+				"workers": [
+					{"aws": {"instance_type": "m4.xlarge"}, "cpu": {"cores": 4}, "memory": {"size_gb": 16}},
+					{"aws": {"instance_type": "m4.xlarge"}, "cpu": {"cores": 4}, "memory": {"size_gb": 16}},
+					{"aws": {"instance_type": "m4.xlarge"}, "cpu": {"cores": 4}, "memory": {"size_gb": 16}}
+				]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found"}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_, err := testutils.TempConfig(fs, configYAML)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			args := Arguments{
+				APIEndpoint:     mockServer.URL,
+				AuthToken:       "token",
+				ClusterNameOrID: tc.clusterID,
+				FileSystem:      fs,
+			}
+
+			out, err := exportCluster(args)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+
+			if diff := cmp.Diff(tc.expectedYAML, string(out)); diff != "" {
+				t.Errorf("Case %d - Output unequal. (-expected +got):\n%s", i, diff)
+			}
+
+			// The output must be accepted as a definition by 'create cluster'.
+			def, err := clusterdefinition.FromYAML(out)
+			if err != nil {
+				t.Fatalf("Case %d - Output could not be parsed as a definition: %#v", i, err)
+			}
+			switch d := def.(type) {
+			case *types.ClusterDefinitionV5:
+				if d.Name != "My cluster" || len(d.NodePools) != 1 {
+					t.Errorf("Case %d - Unexpected v5 definition %#v", i, d)
+				}
+			case *types.ClusterDefinitionV4:
+				if d.Name != "Old cluster" || len(d.Workers) != 1 {
+					t.Errorf("Case %d - Unexpected v4 definition %#v", i, d)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package export
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/export/cluster"
+)
+
+var (
+	// Command is the command to export items as definitions
+	Command = &cobra.Command{
+		Use:   "export",
+		Short: "Export clusters as definitions",
+		Long:  `Print the definition of an existing cluster, for use with 'gsctl create cluster -f'`,
+	}
+)
+
+func init() {
+	Command.AddCommand(cluster.Command)
+}
@@ -0,0 +1,298 @@
+// Package history implements the 'history' command.
+package history
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/auditlog"
+	"github.com/giantswarm/gsctl/pkg/output"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl history'
+	Command = &cobra.Command{
+		Use:   "history",
+		Short: "Show changes made using gsctl",
+		Long: `Shows the local audit log of changes made using gsctl on this machine.
+
+The audit log records every API request creating, changing or deleting
+something, e. g. clusters, node pools, key pairs, apps or credentials, with
+the time, endpoint, user, command line (without secrets), request ID, the IDs
+of the resources affected and the outcome.
+
+The audit log is disabled by default. To enable it, set the environment
+variable GSCTL_AUDIT_LOG=1. The log is stored in the file audit.jsonl in the
+configuration directory, one JSON object per line.
+
+By default, the 20 most recent entries for all endpoints are shown. Use
+--endpoint to show entries for one endpoint only.
+
+Examples:
+
+  gsctl history
+
+  gsctl history --since 24h --cluster f01r4
+
+  gsctl history --since 2020-06-01 --failed --limit 0
+
+  gsctl history --output json
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	defaultLimit = 20
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.Since, "since", "", "", "Only show entries newer than this. Either a duration like '12h' or '7d', or a date/time like '2020-06-01' or '2020-06-01T12:00:00Z'.")
+	Command.Flags().StringVarP(&flags.ClusterID, "cluster", "c", "", "Only show entries for the cluster with this ID")
+	Command.Flags().BoolVarP(&flags.Failed, "failed", "", false, "Only show requests which failed")
+	Command.Flags().IntVarP(&flags.Limit, "limit", "n", defaultLimit, "Maximum number of entries to show, starting with the most recent. 0 shows all entries.")
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint  string
+	clusterID    string
+	failed       bool
+	filePath     string
+	limit        int
+	outputFormat string
+	since        string
+}
+
+func collectArguments() Arguments {
+	endpoint := ""
+	if flags.APIEndpoint != "" {
+		endpoint = config.Config.ChooseEndpoint(flags.APIEndpoint)
+	}
+
+	return Arguments{
+		apiEndpoint:  endpoint,
+		clusterID:    flags.ClusterID,
+		failed:       flags.Failed,
+		filePath:     path.Join(config.ConfigDirPath, auditlog.FileName),
+		limit:        flags.Limit,
+		outputFormat: flags.OutputFormat,
+		since:        flags.Since,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
+	if _, err := parseSince(args.since, time.Now()); err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	errors.HandleCommonErrors(err)
+
+	headline := err.Error()
+	subtext := ""
+	if errors.IsInvalidDurationError(err) {
+		headline = "Invalid value for --since"
+		subtext = "Please use a duration like '12h' or '7d', or a date/time like '2020-06-01' or '2020-06-01T12:00:00Z'."
+	}
+
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
+}
+
+// parseSince parses the --since flag value, relative to now. An empty
+// value returns the zero time.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err == nil && days >= 0 {
+			return now.Add(-time.Duration(days) * 24 * time.Hour), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, microerror.Maskf(errors.InvalidDurationError, "'%s' is neither a duration nor a date", s)
+}
+
+// history reads the audit log and returns the selected entries, oldest
+// first.
+func history(fs afero.Fs, args Arguments) ([]auditlog.Entry, error) {
+	since, err := parseSince(args.since, time.Now())
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	entries, err := auditlog.Read(fs, args.filePath)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	filter := auditlog.Filter{
+		Since:      since,
+		Endpoint:   args.apiEndpoint,
+		ClusterID:  args.clusterID,
+		FailedOnly: args.failed,
+	}
+	entries = filter.Apply(entries)
+
+	if args.limit > 0 && len(entries) > args.limit {
+		entries = entries[len(entries)-args.limit:]
+	}
+
+	return entries, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	fs := config.FileSystem
+	if fs == nil {
+		fs = afero.NewOsFs()
+	}
+
+	entries, err := history(fs, arguments)
+	if err != nil && !auditlog.IsLogNotFound(err) {
+		errors.HandleCommonErrors(err)
+		errors.PrintError(err, "The audit log could not be read.", err.Error())
+		errors.Exit(err)
+	}
+
+	printer, _ := output.NewPrinter(arguments.outputFormat)
+	if !printer.IsTable() {
+		err = printer.Print(os.Stdout, entries, ".request_id")
+		if err != nil {
+			errors.PrintError(err, "Error while formatting output", err.Error())
+			errors.Exit(err)
+		}
+		return
+	}
+
+	if len(entries) == 0 {
+		if os.Getenv(auditlog.EnvVar) == "" {
+			fmt.Println(color.YellowString("The audit log is disabled."))
+			fmt.Printf("To record changes made using gsctl, set the environment variable %s=1.\n", auditlog.EnvVar)
+		} else {
+			fmt.Println(color.YellowString("No changes recorded."))
+		}
+		return
+	}
+
+	fmt.Println(formatTable(entries, printer.IsWide()))
+}
+
+// formatTable renders entries as a table. The wide variant adds endpoint,
+// user and request details.
+func formatTable(entries []auditlog.Entry, wide bool) string {
+	headers := []string{
+		color.CyanString("TIME"),
+		color.CyanString("COMMAND"),
+		color.CyanString("TARGET"),
+		color.CyanString("OUTCOME"),
+		color.CyanString("REQUEST ID"),
+	}
+	if wide {
+		headers = append(headers,
+			color.CyanString("ENDPOINT"),
+			color.CyanString("USER"),
+			color.CyanString("REQUEST"),
+		)
+	}
+	rows := []string{strings.Join(headers, "|")}
+
+	for _, e := range entries {
+		row := []string{
+			e.Time.Local().Format("2006 Jan 02, 15:04:05"),
+			util.Truncate(formatCommand(e.CommandLine), 60, !wide),
+			orNA(e.Targets.String()),
+			formatOutcome(e),
+			e.RequestID,
+		}
+		if wide {
+			row = append(row,
+				e.Endpoint,
+				orNA(e.Email),
+				e.Method+" "+e.Path,
+			)
+		}
+		rows = append(rows, strings.Join(row, "|"))
+	}
+
+	return columnize.SimpleFormat(rows)
+}
+
+// formatCommand replaces the path of the executable with its name.
+func formatCommand(commandLine string) string {
+	fields := strings.SplitN(commandLine, " ", 2)
+	if fields[0] == "" {
+		return "n/a"
+	}
+	fields[0] = path.Base(fields[0])
+	return strings.Join(fields, " ")
+}
+
+func formatOutcome(e auditlog.Entry) string {
+	switch e.Outcome {
+	case auditlog.OutcomeSuccess:
+		return color.GreenString(e.Outcome)
+	case auditlog.OutcomeFailure:
+		return color.RedString("%s (%d)", e.Outcome, e.StatusCode)
+	}
+	return color.RedString(e.Outcome)
+}
+
+// orNA returns 'n/a' for empty values.
+func orNA(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+	return s
+}
@@ -0,0 +1,139 @@
+package history
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/auditlog"
+)
+
+// TestParseSince tests parsing durations and dates.
+func TestParseSince(t *testing.T) {
+	now := time.Date(2020, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		since        string
+		expected     time.Time
+		errorMatcher func(error) bool
+	}{
+		{"", time.Time{}, nil},
+		{"12h", now.Add(-12 * time.Hour), nil},
+		{"7d", now.Add(-7 * 24 * time.Hour), nil},
+		{"2020-06-01T08:00:00Z", time.Date(2020, 6, 1, 8, 0, 0, 0, time.UTC), nil},
+		{"2020-06-01", time.Date(2020, 6, 1, 0, 0, 0, 0, time.Local), nil},
+		{"-1h", time.Time{}, errors.IsInvalidDurationError},
+		{"yesterday", time.Time{}, errors.IsInvalidDurationError},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			since, err := parseSince(tc.since, now)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+			if !since.Equal(tc.expected) {
+				t.Errorf("Case %d - Expected %s, got %s", i, tc.expected, since)
+			}
+		})
+	}
+}
+
+// TestVerifyPreconditions tests the validation of arguments.
+func TestVerifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{Arguments{outputFormat: "table"}, nil},
+		{Arguments{outputFormat: "json", since: "24h"}, nil},
+		{Arguments{outputFormat: "xml"}, errors.IsOutputFormatInvalid},
+		{Arguments{outputFormat: "table", since: "soon"}, errors.IsInvalidDurationError},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil && err != nil {
+				t.Errorf("Case %d - Unexpected error %#v", i, err)
+			} else if tc.errorMatcher != nil && !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+			}
+		})
+	}
+}
+
+// TestHistory tests selecting and formatting entries.
+func TestHistory(t *testing.T) {
+	color.NoColor = true
+
+	fs := afero.NewMemMapFs()
+	filePath := "/config/audit.jsonl"
+	now := time.Now().UTC()
+
+	entries := []auditlog.Entry{
+		{Time: now.Add(-72 * time.Hour), Endpoint: "https://api.a", CommandLine: "/usr/local/bin/gsctl delete cluster abc12", Method: "DELETE", Path: "/v4/clusters/abc12/", RequestID: "req1", Targets: auditlog.Targets{ClusterID: "abc12"}, StatusCode: 202, Outcome: auditlog.OutcomeSuccess},
+		{Time: now.Add(-2 * time.Hour), Endpoint: "https://api.a", CommandLine: "gsctl scale nodepool def34/a7k --nodes-min 3", Method: "PATCH", Path: "/v5/clusters/def34/nodepools/a7k/", RequestID: "req2", Targets: auditlog.Targets{ClusterID: "def34", NodePoolID: "a7k"}, StatusCode: 400, Outcome: auditlog.OutcomeFailure},
+		{Time: now.Add(-time.Hour), Endpoint: "https://api.b", CommandLine: "gsctl update organization set-credentials -o acme", Method: "POST", Path: "/v4/organizations/acme/credentials/", RequestID: "req3", Targets: auditlog.Targets{OrganizationID: "acme"}, StatusCode: 201, Outcome: auditlog.OutcomeSuccess},
+	}
+	for _, e := range entries {
+		if err := auditlog.Append(fs, filePath, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var testCases = []struct {
+		args       Arguments
+		requestIDs []string
+	}{
+		{Arguments{}, []string{"req1", "req2", "req3"}},
+		{Arguments{limit: 2}, []string{"req2", "req3"}},
+		{Arguments{since: "1d"}, []string{"req2", "req3"}},
+		{Arguments{apiEndpoint: "https://api.a"}, []string{"req1", "req2"}},
+		{Arguments{clusterID: "def34"}, []string{"req2"}},
+		{Arguments{failed: true}, []string{"req2"}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			tc.args.filePath = filePath
+			selected, err := history(fs, tc.args)
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+			var requestIDs []string
+			for _, e := range selected {
+				requestIDs = append(requestIDs, e.RequestID)
+			}
+			if strings.Join(requestIDs, ",") != strings.Join(tc.requestIDs, ",") {
+				t.Errorf("Case %d - Expected %v, got %v", i, tc.requestIDs, requestIDs)
+			}
+		})
+	}
+
+	table := formatTable(entries, false)
+	for _, expected := range []string{"gsctl delete cluster abc12", "def34/a7k", "failure (400)", "org acme"} {
+		if !strings.Contains(table, expected) {
+			t.Errorf("Expected %q in table:\n%s", expected, table)
+		}
+	}
+	if strings.Contains(table, "/usr/local/bin") || strings.Contains(table, "ENDPOINT") {
+		t.Errorf("Unexpected table:\n%s", table)
+	}
+
+	_, err := history(fs, Arguments{filePath: "/config/missing.jsonl"})
+	if !auditlog.IsLogNotFound(err) {
+		t.Errorf("Expected logNotFoundError, got %#v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/giantswarm/columnize"
 	"github.com/giantswarm/gscliauth/config"
 	clientinfo "github.com/giantswarm/gsclientgen/v2/client/info"
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/microerror"
 	"github.com/spf13/cobra"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
 )
 
 const (
@@ -35,9 +37,19 @@ var (
 	arguments Arguments
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
 // Arguments represents the arguments we can make use of in this command
 type Arguments struct {
 	apiEndpoint       string
+	outputFormat      string
 	scheme            string
 	token             string
 	userProvidedToken string
@@ -53,6 +65,7 @@ func collectArguments() Arguments {
 
 	return Arguments{
 		apiEndpoint:       endpoint,
+		outputFormat:      flags.OutputFormat,
 		scheme:            scheme,
 		token:             token,
 		userProvidedToken: flags.Token,
@@ -75,9 +88,28 @@ type infoResult struct {
 	environmentVariables map[string]string
 }
 
-// validatePreconditions simply returns nil, as the command should work under
-// all conditions.
+// infoOutput is the structured representation of infoResult used for
+// non-table output formats. The auth token is deliberately left out.
+type infoOutput struct {
+	Version              string                 `json:"version"`
+	BuildDate            string                 `json:"build_date"`
+	CommitHash           string                 `json:"commit_hash"`
+	ConfigFilePath       string                 `json:"config_path"`
+	KubeConfigPaths      []string               `json:"kubeconfig_paths"`
+	APIEndpoint          string                 `json:"api_endpoint,omitempty"`
+	APIEndpointAlias     string                 `json:"api_endpoint_alias,omitempty"`
+	Email                string                 `json:"email,omitempty"`
+	LoggedIn             bool                   `json:"logged_in"`
+	Installation         *models.V4InfoResponse `json:"installation,omitempty"`
+	EnvironmentVariables map[string]string      `json:"environment_variables,omitempty"`
+}
+
+// validatePreconditions only checks the output format, as the command
+// should work under all other conditions.
 func validatePreconditions(args Arguments) error {
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
 	return nil
 }
 
@@ -87,8 +119,11 @@ func printValidation(cmd *cobra.Command, extraArgs []string) {
 	err := validatePreconditions(arguments)
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
+
+		errors.PrintError(err, err.Error(), "")
+		errors.Exit(err)
 	}
 }
 
@@ -96,58 +131,63 @@ func printValidation(cmd *cobra.Command, extraArgs []string) {
 func printInfo(cmd *cobra.Command, args []string) {
 	result, err := info(arguments)
 
-	output := []string{}
+	if !output.IsTableFormat(arguments.outputFormat) {
+		printStructured(result, err)
+		return
+	}
+
+	rows := []string{}
 
 	if result.version != buildinfo.VersionPlaceholder && result.version != "" {
-		output = append(output, color.YellowString("%s version:", config.ProgramName)+"|"+color.CyanString(result.version)+" - https://github.com/giantswarm/gsctl/releases/tag/"+result.version)
+		rows = append(rows, color.YellowString("%s version:", config.ProgramName)+"|"+color.CyanString(result.version)+" - https://github.com/giantswarm/gsctl/releases/tag/"+result.version)
 	} else {
-		output = append(output, color.YellowString("%s version:", config.ProgramName)+"|"+color.CyanString(buildinfo.VersionPlaceholder))
+		rows = append(rows, color.YellowString("%s version:", config.ProgramName)+"|"+color.CyanString(buildinfo.VersionPlaceholder))
 	}
 
 	if result.buildDate != buildinfo.Placeholder && result.buildDate != "" {
-		output = append(output, color.YellowString("%s build:", config.ProgramName)+"|"+color.CyanString(result.buildDate))
+		rows = append(rows, color.YellowString("%s build:", config.ProgramName)+"|"+color.CyanString(result.buildDate))
 	} else {
-		output = append(output, color.YellowString("%s build:", config.ProgramName)+"|"+color.RedString(buildinfo.Placeholder))
+		rows = append(rows, color.YellowString("%s build:", config.ProgramName)+"|"+color.RedString(buildinfo.Placeholder))
 	}
 
 	if result.commitHash != buildinfo.Placeholder {
-		output = append(output, color.YellowString("%s commit hash:", config.ProgramName)+"|"+color.CyanString(result.commitHash)+" - https://github.com/giantswarm/gsctl/commit/"+result.commitHash)
+		rows = append(rows, color.YellowString("%s commit hash:", config.ProgramName)+"|"+color.CyanString(result.commitHash)+" - https://github.com/giantswarm/gsctl/commit/"+result.commitHash)
 	} else {
-		output = append(output, color.YellowString("%s commit hash:", config.ProgramName)+"|"+color.RedString(buildinfo.Placeholder))
+		rows = append(rows, color.YellowString("%s commit hash:", config.ProgramName)+"|"+color.RedString(buildinfo.Placeholder))
 	}
 
-	output = append(output, color.YellowString("Config path:")+"|"+color.CyanString(result.configFilePath))
+	rows = append(rows, color.YellowString("Config path:")+"|"+color.CyanString(result.configFilePath))
 
 	// kubectl configuration paths
-	output = append(output, color.YellowString("kubectl config path:")+"|"+color.CyanString(strings.Join(result.kubeConfigPaths, ", ")))
+	rows = append(rows, color.YellowString("kubectl config path:")+"|"+color.CyanString(strings.Join(result.kubeConfigPaths, ", ")))
 
 	if result.apiEndpoint == "" {
-		output = append(output, color.YellowString("API endpoint:")+"|n/a")
+		rows = append(rows, color.YellowString("API endpoint:")+"|n/a")
 	} else {
-		output = append(output, color.YellowString("API endpoint:")+"|"+color.CyanString(result.apiEndpoint))
+		rows = append(rows, color.YellowString("API endpoint:")+"|"+color.CyanString(result.apiEndpoint))
 	}
 
 	if result.apiEndpointAlias != "" {
-		output = append(output, color.YellowString("API endpoint alias:")+"|"+color.CyanString(result.apiEndpointAlias))
+		rows = append(rows, color.YellowString("API endpoint alias:")+"|"+color.CyanString(result.apiEndpointAlias))
 	}
 
 	if result.email == "" {
-		output = append(output, color.YellowString("Email:")+"|n/a")
+		rows = append(rows, color.YellowString("Email:")+"|n/a")
 	} else {
-		output = append(output, color.YellowString("Email:")+"|"+color.CyanString(config.Config.Email))
+		rows = append(rows, color.YellowString("Email:")+"|"+color.CyanString(config.Config.Email))
 	}
 
 	if result.token == "" {
-		output = append(output, color.YellowString("Logged in:")+"|"+color.CyanString("no"))
+		rows = append(rows, color.YellowString("Logged in:")+"|"+color.CyanString("no"))
 	} else {
-		output = append(output, color.YellowString("Logged in:")+"|"+color.CyanString("yes"))
+		rows = append(rows, color.YellowString("Logged in:")+"|"+color.CyanString("yes"))
 	}
 
 	if arguments.verbose {
 		if result.token != "" {
-			output = append(output, color.YellowString("Auth token:")+"|"+color.CyanString(result.token))
+			rows = append(rows, color.YellowString("Auth token:")+"|"+color.CyanString(result.token))
 		} else {
-			output = append(output, color.YellowString("Auth token:")+"|n/a")
+			rows = append(rows, color.YellowString("Auth token:")+"|n/a")
 		}
 	}
 
@@ -155,30 +195,30 @@ func printInfo(cmd *cobra.Command, args []string) {
 	if result.apiEndpoint != "" {
 		// Provider
 		if result.infoResponse == nil || result.infoResponse.Payload.General.Provider == "" {
-			output = append(output, color.YellowString("Provider:")+"|n/a")
+			rows = append(rows, color.YellowString("Provider:")+"|n/a")
 		} else {
-			output = append(output, color.YellowString("Provider:")+"|"+color.CyanString(result.infoResponse.Payload.General.Provider))
+			rows = append(rows, color.YellowString("Provider:")+"|"+color.CyanString(result.infoResponse.Payload.General.Provider))
 		}
 
 		if result.infoResponse != nil {
 			if result.infoResponse.Payload.General.Provider == "aws" {
-				output = append(output, color.YellowString("Worker EC2 instance type options:")+"|"+color.CyanString(strings.Join(result.infoResponse.Payload.Workers.InstanceType.Options, ", ")))
-				output = append(output, color.YellowString("Default worker EC2 instance type:")+"|"+color.CyanString(result.infoResponse.Payload.Workers.InstanceType.Default))
+				rows = append(rows, color.YellowString("Worker EC2 instance type options:")+"|"+color.CyanString(strings.Join(result.infoResponse.Payload.Workers.InstanceType.Options, ", ")))
+				rows = append(rows, color.YellowString("Default worker EC2 instance type:")+"|"+color.CyanString(result.infoResponse.Payload.Workers.InstanceType.Default))
 			} else if result.infoResponse.Payload.General.Provider == "azure" {
-				output = append(output, color.YellowString("Worker VM size options:")+"|"+color.CyanString(strings.Join(result.infoResponse.Payload.Workers.VMSize.Options, ", ")))
-				output = append(output, color.YellowString("Default worker VM size:")+"|"+color.CyanString(result.infoResponse.Payload.Workers.VMSize.Default))
+				rows = append(rows, color.YellowString("Worker VM size options:")+"|"+color.CyanString(strings.Join(result.infoResponse.Payload.Workers.VMSize.Options, ", ")))
+				rows = append(rows, color.YellowString("Default worker VM size:")+"|"+color.CyanString(result.infoResponse.Payload.Workers.VMSize.Default))
 			}
 
 			if result.infoResponse.Payload.Workers.CountPerCluster.Default != 0 {
-				output = append(output, color.YellowString("Default workers per cluster:")+"|"+color.CyanString(fmt.Sprintf("%.0f", result.infoResponse.Payload.Workers.CountPerCluster.Default)))
+				rows = append(rows, color.YellowString("Default workers per cluster:")+"|"+color.CyanString(fmt.Sprintf("%.0f", result.infoResponse.Payload.Workers.CountPerCluster.Default)))
 			}
 			if result.infoResponse.Payload.Workers.CountPerCluster.Max != 0 {
-				output = append(output, color.YellowString("Maximum workers per cluster:")+"|"+color.CyanString(fmt.Sprintf("%.0f", result.infoResponse.Payload.Workers.CountPerCluster.Max)))
+				rows = append(rows, color.YellowString("Maximum workers per cluster:")+"|"+color.CyanString(fmt.Sprintf("%.0f", result.infoResponse.Payload.Workers.CountPerCluster.Max)))
 			}
 		}
 	}
 
-	fmt.Println(columnize.SimpleFormat(output))
+	fmt.Println(columnize.SimpleFormat(rows))
 
 	if len(result.environmentVariables) > 0 {
 		envTable := []string{}
@@ -195,13 +235,49 @@ func printInfo(cmd *cobra.Command, args []string) {
 		fmt.Println()
 
 		// if this is a common error, handle it in the standard way and exit.
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		// handle non-standard errors.
-		fmt.Println(color.RedString("Some error occurred:"))
-		fmt.Println(err.Error())
-		os.Exit(1)
+		errors.PrintError(err, "Some error occurred:", err.Error())
+		errors.Exit(err)
+	}
+}
+
+// printStructured prints the info result in one of the non-table
+// output formats. Errors go to stderr to keep stdout parseable.
+func printStructured(result infoResult, infoErr error) {
+	printer, err := output.NewPrinter(arguments.outputFormat)
+	if err == nil {
+		data := infoOutput{
+			Version:              result.version,
+			BuildDate:            result.buildDate,
+			CommitHash:           result.commitHash,
+			ConfigFilePath:       result.configFilePath,
+			KubeConfigPaths:      result.kubeConfigPaths,
+			APIEndpoint:          result.apiEndpoint,
+			APIEndpointAlias:     result.apiEndpointAlias,
+			Email:                result.email,
+			LoggedIn:             result.token != "",
+			EnvironmentVariables: result.environmentVariables,
+		}
+		if result.infoResponse != nil {
+			data.Installation = result.infoResponse.Payload
+		}
+		err = printer.Print(os.Stdout, data, ".api_endpoint")
+	}
+
+	if err == nil {
+		err = infoErr
+	}
+	if err != nil {
+		if arguments.outputFormat == output.FormatJSON {
+			errors.PrintError(err, "Some error occurred:", err.Error())
+		} else {
+			fmt.Fprintln(os.Stderr, color.RedString("Some error occurred:"))
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+		errors.Exit(err)
 	}
 }
 
@@ -259,6 +335,7 @@ func info(args Arguments) (infoResult, error) {
 func getEnvironmentVariables() map[string]string {
 	// all environment variables relevant to gsctl
 	vars := []string{
+		"GSCTL_AUDIT_LOG",
 		"GSCTL_CAFILE",
 		"GSCTL_CAPATH",
 		"GSCTL_CONFIG_DIR",
@@ -0,0 +1,273 @@
+// Package kubeconfigcredential implements the 'kubeconfig-credential' command,
+// which kubectl calls as an exec credential plugin.
+package kubeconfigcredential
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/execcredential"
+	"github.com/giantswarm/gsctl/pkg/kubeconfigfile"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl kubeconfig-credential'
+	Command = &cobra.Command{
+		Use:   "kubeconfig-credential",
+		Short: "Print kubectl credentials for a cluster (called by kubectl)",
+		Long: `Prints a client certificate and key for a cluster as an ExecCredential
+object, as expected by kubectl from an exec credential plugin.
+
+This command is not meant to be called directly. 'gsctl create kubeconfig
+--exec-plugin' configures kubectl to call it whenever credentials are needed.
+
+A new key pair is created if there is no cached one, or if the cached one
+expires within the next minutes. Key pairs are cached in the "credentials"
+subfolder of the gsctl config directory.
+
+Example:
+
+  gsctl kubeconfig-credential -c f01r4 --endpoint https://api.example.com
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	// cmdTTL is the command line flag for the lifetime of new key pairs.
+	cmdTTL string
+
+	arguments Arguments
+)
+
+const (
+	activityName = "kubeconfig-credential"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.ClusterID, "cluster", "c", "", "ID of the cluster")
+	Command.Flags().StringVarP(&cmdTTL, "ttl", "", fmt.Sprintf("%dh", int(execcredential.DefaultTTL.Hours())), "Lifetime of new key pairs, e.g. 12h. Allowed units: h, d, w, m, y.")
+	Command.Flags().StringVarP(&flags.CertificateOrganizations, "certificate-organizations", "", "", "A comma separated list of organizations for the issued certificates 'O' fields.")
+	Command.Flags().StringVarP(&flags.CNPrefix, "cn-prefix", "", "", "The common name prefix for the issued certificates 'CN' field.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint       string
+	authToken         string
+	cacheDir          string
+	certOrgs          string
+	clusterID         string
+	cnPrefix          string
+	description       string
+	fileSystem        afero.Fs
+	ttl               time.Duration
+	userProvidedToken string
+}
+
+func collectArguments() (Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	ttl, err := util.ParseDuration(cmdTTL)
+	if errors.IsInvalidDurationError(err) {
+		return Arguments{}, microerror.Mask(errors.InvalidDurationError)
+	} else if errors.IsDurationExceededError(err) {
+		return Arguments{}, microerror.Mask(errors.DurationExceededError)
+	} else if err != nil {
+		return Arguments{}, microerror.Mask(err)
+	}
+
+	return Arguments{
+		apiEndpoint:       endpoint,
+		authToken:         token,
+		cacheDir:          path.Join(config.ConfigDirPath, execcredential.DirName),
+		certOrgs:          flags.CertificateOrganizations,
+		clusterID:         flags.ClusterID,
+		cnPrefix:          flags.CNPrefix,
+		description:       "Added by user " + config.Config.Email + " using 'gsctl kubeconfig-credential'",
+		fileSystem:        config.FileSystem,
+		ttl:               ttl,
+		userProvidedToken: flags.Token,
+	}, nil
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if config.Config.Token == "" && args.authToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.clusterID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+	if args.ttl < time.Hour {
+		return microerror.Maskf(errors.InvalidDurationError, "the TTL must be at least one hour")
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	var err error
+
+	arguments, err = collectArguments()
+	if err == nil {
+		err = verifyPreconditions(arguments)
+	}
+	if err == nil {
+		return
+	}
+
+	printError(err)
+	errors.Exit(err)
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	c, err := getCredential(arguments, time.Now())
+	if err == nil {
+		var data []byte
+		data, err = c.ExecCredential()
+		if err == nil {
+			fmt.Println(string(data))
+			return
+		}
+	}
+
+	printError(err)
+	errors.Exit(err)
+}
+
+// printError prints errors to STDERR, where kubectl passes them on to the
+// user. STDOUT is reserved for the credential.
+func printError(err error) {
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsEndpointMissingError(err):
+		headline = "No endpoint selected"
+		subtext = "Please use the --endpoint flag."
+	case errors.IsNotLoggedInError(err), errors.IsNotAuthorizedError(err):
+		headline = "You are not logged in"
+		subtext = fmt.Sprintf("Please log in to %s using 'gsctl login' to get kubectl credentials.", arguments.apiEndpoint)
+	case errors.IsClusterNameOrIDMissingError(err):
+		headline = "No cluster ID given"
+		subtext = "Please use the --cluster flag."
+	case errors.IsInvalidDurationError(err), errors.IsDurationExceededError(err):
+		headline = "The value passed with --ttl is invalid."
+		subtext = "Please provide a duration of at least one hour, e. g. '12h', '1d'."
+	case errors.IsAccessForbiddenError(err):
+		headline = "You are not allowed to create key pairs for this cluster"
+	case kubeconfigfile.IsLocked(err):
+		headline = "Another gsctl process is creating a key pair for this cluster"
+		subtext = err.Error()
+	case errors.IsClusterNotFoundError(err):
+		headline = fmt.Sprintf("Cluster '%s' not found", arguments.clusterID)
+		subtext = "If the cluster has been deleted, remove its kubectl settings using 'gsctl delete kubeconfig'."
+	default:
+		headline = err.Error()
+	}
+
+	fmt.Fprintln(os.Stderr, color.RedString("gsctl: "+headline))
+	if subtext != "" {
+		fmt.Fprintln(os.Stderr, subtext)
+	}
+}
+
+// getCredential is our business function. It returns the cached credential
+// for the cluster, or creates a new key pair if there is no valid one.
+func getCredential(args Arguments, now time.Time) (*execcredential.Credential, error) {
+	c, err := loadValidCredential(args, now)
+	if c != nil || err != nil {
+		return c, microerror.Mask(err)
+	}
+
+	// kubectl may run several times in parallel, e. g. in scripts. Only the
+	// first process holding the lock creates a key pair, the others find it
+	// in the cache.
+	unlock, err := execcredential.Lock(args.fileSystem, args.cacheDir, args.apiEndpoint, args.clusterID, execcredential.LockTimeout)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	defer unlock()
+
+	c, err = loadValidCredential(args, now)
+	if c != nil || err != nil {
+		return c, microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	requestBody := &models.V4AddKeyPairRequest{
+		Description:              &args.description,
+		TTLHours:                 int32(args.ttl.Hours()),
+		CnPrefix:                 args.cnPrefix,
+		CertificateOrganizations: args.certOrgs,
+	}
+
+	response, err := clientWrapper.CreateKeyPair(args.clusterID, requestBody, auxParams)
+	if err != nil {
+		switch {
+		case clienterror.IsUnauthorizedError(err):
+			return nil, microerror.Mask(errors.NotAuthorizedError)
+		case clienterror.IsAccessForbiddenError(err):
+			return nil, microerror.Mask(errors.AccessForbiddenError)
+		case clienterror.IsNotFoundError(err):
+			return nil, microerror.Mask(errors.ClusterNotFoundError)
+		}
+		return nil, microerror.Mask(err)
+	}
+
+	c = execcredential.New(args.apiEndpoint, args.clusterID, response.Payload.ID,
+		response.Payload.ClientCertificateData, response.Payload.ClientKeyData, args.ttl, now)
+
+	err = execcredential.Store(args.fileSystem, args.cacheDir, c)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return c, nil
+}
+
+// loadValidCredential returns the cached credential for the cluster, or nil
+// if there is no valid one.
+func loadValidCredential(args Arguments, now time.Time) (*execcredential.Credential, error) {
+	c, err := execcredential.Load(args.fileSystem, args.cacheDir, args.apiEndpoint, args.clusterID)
+	if execcredential.IsNotFound(err) || execcredential.IsInvalidCache(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if !c.Valid(now) {
+		return nil, nil
+	}
+
+	return c, nil
+}
@@ -0,0 +1,207 @@
+package kubeconfigcredential
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// TestGetCredential tests that key pairs are cached until shortly before
+// they expire.
+func TestGetCredential(t *testing.T) {
+	now := time.Now()
+	requests := 0
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != "POST" || r.URL.String() != "/v4/clusters/abc12/key-pairs/" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found"}`))
+			return
+		}
+
+		requests++
+		body := map[string]interface{}{
+			"id":                      "key-pair-" + strconv.Itoa(requests),
+			"client_certificate_data": testutils.CertificatePEM(t, now.Add(12*time.Hour)),
+			"client_key_data":         "key",
+			"ttl_hours":               12,
+		}
+		data, _ := json.Marshal(body)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		apiEndpoint:       mockServer.URL,
+		authToken:         "token",
+		cacheDir:          "/credentials",
+		clusterID:         "abc12",
+		fileSystem:        fs,
+		ttl:               12 * time.Hour,
+		userProvidedToken: "token",
+	}
+
+	err = verifyPreconditions(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	var testCases = []struct {
+		now               time.Time
+		expectedKeyPairID string
+	}{
+		// No cached credential.
+		{now, "key-pair-1"},
+		// Cached credential is used.
+		{now.Add(time.Hour), "key-pair-1"},
+		// Cached credential expires soon.
+		{now.Add(12*time.Hour - time.Minute), "key-pair-2"},
+	}
+
+	for i, tc := range testCases {
+		c, err := getCredential(args, tc.now)
+		if err != nil {
+			t.Fatalf("Case %d - Unexpected error %#v", i, err)
+		}
+		if c.KeyPairID != tc.expectedKeyPairID {
+			t.Errorf("Case %d - Expected key pair %s, got %s", i, tc.expectedKeyPairID, c.KeyPairID)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 key pairs to be created, got %d", requests)
+	}
+
+	args.clusterID = "unknown"
+	_, err = getCredential(args, now)
+	if !errors.IsClusterNotFoundError(err) {
+		t.Errorf("Expected ClusterNotFoundError, got %#v", err)
+	}
+}
+
+// TestGetCredentialParallel tests that kubectl processes running in parallel
+// create only one key pair.
+func TestGetCredentialParallel(t *testing.T) {
+	now := time.Now()
+	var requests int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		// Creating a key pair takes a while.
+		time.Sleep(50 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		body := map[string]interface{}{
+			"id":                      fmt.Sprintf("key-pair-%d", n),
+			"client_certificate_data": testutils.CertificatePEM(t, now.Add(12*time.Hour)),
+			"client_key_data":         "key",
+			"ttl_hours":               12,
+		}
+		data, _ := json.Marshal(body)
+		w.Write(data)
+	}))
+	defer mockServer.Close()
+
+	_, err := testutils.TempConfig(afero.NewMemMapFs(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The in-memory file system can't create files exclusively.
+	dir, err := ioutil.TempDir("", "gsctl-kubeconfig-credential")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	args := Arguments{
+		apiEndpoint:       mockServer.URL,
+		authToken:         "token",
+		cacheDir:          path.Join(dir, "credentials"),
+		clusterID:         "abc12",
+		fileSystem:        afero.NewOsFs(),
+		ttl:               12 * time.Hour,
+		userProvidedToken: "token",
+	}
+
+	var wg sync.WaitGroup
+	keyPairIDs := make([]string, 5)
+	errs := make([]error, 5)
+	for i := range keyPairIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := getCredential(args, now)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			keyPairIDs[i] = c.KeyPairID
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range keyPairIDs {
+		if errs[i] != nil {
+			t.Fatalf("Process %d - Unexpected error %#v", i, errs[i])
+		}
+		if keyPairIDs[i] != "key-pair-1" {
+			t.Errorf("Process %d - Expected key pair key-pair-1, got %s", i, keyPairIDs[i])
+		}
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("Expected 1 key pair to be created, got %d", atomic.LoadInt32(&requests))
+	}
+
+	files, _ := filepath.Glob(path.Join(dir, "credentials", "*.lock"))
+	if len(files) != 0 {
+		t.Errorf("Expected lock to be released, found %v", files)
+	}
+}
+
+// TestVerifyPreconditions tests the argument validation.
+func TestVerifyPreconditions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{Arguments{}, errors.IsEndpointMissingError},
+		{Arguments{apiEndpoint: "https://api.example.com"}, errors.IsNotLoggedInError},
+		{Arguments{apiEndpoint: "https://api.example.com", authToken: "token"}, errors.IsClusterNameOrIDMissingError},
+		{Arguments{apiEndpoint: "https://api.example.com", authToken: "token", clusterID: "abc12", ttl: time.Minute}, errors.IsInvalidDurationError},
+	}
+
+	for i, tc := range testCases {
+		err := verifyPreconditions(tc.args)
+		if !tc.errorMatcher(err) {
+			t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+		}
+	}
+}
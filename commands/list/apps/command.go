@@ -0,0 +1,255 @@
+// Package apps implements the 'list apps' sub-command.
+package apps
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command performs the "list apps" function
+	Command = &cobra.Command{
+		Use:     "apps <cluster-name/cluster-id>",
+		Aliases: []string{"app"},
+
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "List apps",
+		Long: `Prints a list of the apps installed in a cluster.
+
+The result will be a table of all apps of a specific cluster with the following details in
+columns:
+
+	NAME:          Name of the app
+	NAMESPACE:     Namespace in the cluster the app is installed into
+	CATALOG:       Name of the catalog the app is installed from
+	VERSION:       Version of the app as requested
+	APP VERSION:   Version of the upstream application, as reported by the app
+	STATUS:        Status of the app's release
+	LAST DEPLOYED: Date and time of the last deployment
+
+To see all available details for an app, use 'gsctl show app <cluster-name/cluster-id>/<app-name>'.
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	arguments Arguments
+)
+
+const activityName = "list-apps"
+
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint       string
+	authToken         string
+	clusterNameOrID   string
+	outputFormat      string
+	userProvidedToken string
+}
+
+// collectArguments creates arguments based on command line flags and config.
+func collectArguments(cmdLineArgs []string) Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		apiEndpoint:       endpoint,
+		authToken:         token,
+		clusterNameOrID:   cmdLineArgs[0],
+		outputFormat:      flags.OutputFormat,
+		userProvidedToken: flags.Token,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if config.Config.Token == "" && args.authToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.clusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments(positionalArgs)
+	err := verifyPreconditions(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+}
+
+// fetchApps fetches the apps installed in a cluster, sorted by name.
+func fetchApps(args Arguments) ([]*models.V4GetClusterAppsResponseItems, error) {
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	response, err := clientWrapper.GetApps(clusterID, auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	sort.Slice(response.Payload[:], func(i, j int) bool {
+		return appName(response.Payload[i]) < appName(response.Payload[j])
+	})
+
+	return response.Payload, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	apps, err := fetchApps(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if len(apps) == 0 && output.IsTableFormat(arguments.outputFormat) {
+		fmt.Println(color.YellowString("No apps are installed in this cluster"))
+		return
+	}
+
+	out, err := getOutput(apps, arguments.outputFormat)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(out)
+}
+
+func getOutput(apps []*models.V4GetClusterAppsResponseItems, outputFormat string) (string, error) {
+	printer, err := output.NewPrinter(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if !printer.IsTable() {
+		out, err := printer.Sprint(apps, ".metadata.name")
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return out, nil
+	}
+
+	headers := []string{
+		color.CyanString("NAME"),
+		color.CyanString("NAMESPACE"),
+		color.CyanString("CATALOG"),
+		color.CyanString("VERSION"),
+		color.CyanString("APP VERSION"),
+		color.CyanString("STATUS"),
+		color.CyanString("LAST DEPLOYED"),
+	}
+
+	table := make([]string, 0, len(apps)+1)
+	table = append(table, strings.Join(headers, "|"))
+
+	for _, app := range apps {
+		var namespace, catalog, version string
+		if app.Spec != nil {
+			namespace = app.Spec.Namespace
+			catalog = app.Spec.Catalog
+			version = app.Spec.Version
+		}
+
+		appVersion := "n/a"
+		status := "n/a"
+		lastDeployed := "n/a"
+		if app.Status != nil {
+			if app.Status.AppVersion != "" {
+				appVersion = app.Status.AppVersion
+			}
+			if app.Status.Release != nil {
+				if app.Status.Release.Status != "" {
+					status = app.Status.Release.Status
+				}
+				if app.Status.Release.LastDeployed != "" {
+					lastDeployed = util.ShortDate(util.ParseDate(app.Status.Release.LastDeployed))
+				}
+			}
+		}
+
+		table = append(table, strings.Join([]string{
+			appName(app),
+			namespace,
+			catalog,
+			version,
+			appVersion,
+			status,
+			lastDeployed,
+		}, "|"))
+	}
+
+	return columnize.SimpleFormat(table), nil
+}
+
+// appName returns the name of an app as given in its metadata.
+func appName(app *models.V4GetClusterAppsResponseItems) string {
+	if app.Metadata == nil {
+		return ""
+	}
+
+	return app.Metadata.Name
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = fmt.Sprintf("Could not find a cluster with name/ID %s. Check 'gsctl list clusters' to make sure.", arguments.clusterNameOrID)
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
@@ -0,0 +1,126 @@
+package apps
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+func Test_ListApps(t *testing.T) {
+	testCases := []struct {
+		appsResponse string
+		outputFormat string
+		output       string
+	}{
+		{
+			appsResponse: `[
+				{"metadata": {"name": "nginx-ingress-controller"}, "spec": {"catalog": "giantswarm", "name": "nginx-ingress-controller-app", "namespace": "kube-system", "version": "1.6.9"}, "status": {"app_version": "0.30.0", "release": {"last_deployed": "2020-04-21T10:43:22Z", "status": "DEPLOYED"}, "version": "1.6.9"}},
+				{"metadata": {"name": "external-dns"}, "spec": {"catalog": "giantswarm", "name": "external-dns-app", "namespace": "kube-system", "version": "1.2.0"}, "status": {}}
+			]`,
+			outputFormat: "table",
+			output: `NAME                      NAMESPACE    CATALOG     VERSION  APP VERSION  STATUS    LAST DEPLOYED
+external-dns              kube-system  giantswarm  1.2.0    n/a          n/a       n/a
+nginx-ingress-controller  kube-system  giantswarm  1.6.9    0.30.0       DEPLOYED  2020 Apr 21, 10:43 UTC`,
+		},
+		{
+			appsResponse: `[
+				{"metadata": {"name": "external-dns"}, "spec": {"catalog": "giantswarm", "name": "external-dns-app", "namespace": "kube-system", "version": "1.2.0"}}
+			]`,
+			outputFormat: "json",
+			output: `[
+  {
+    "metadata": {
+      "name": "external-dns"
+    },
+    "spec": {
+      "catalog": "giantswarm",
+      "name": "external-dns-app",
+      "namespace": "kube-system",
+      "version": "1.2.0"
+    }
+  }
+]`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch uri := r.URL.Path; uri {
+				case "/v4/clusters/cluster-id/apps/":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(tc.appsResponse))
+
+				case "/v4/clusters/":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`[{"id": "cluster-id", "name": "Name of the cluster", "owner": "acme"}]`))
+
+				default:
+					t.Errorf("Case %d: Unsupported route %s called in mock server", i, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+				}
+			}))
+			defer mockServer.Close()
+
+			// temp config
+			fs := afero.NewMemMapFs()
+			configDir := testutils.TempDir(fs)
+			config.Initialize(fs, configDir)
+
+			args := Arguments{
+				clusterNameOrID: "Name of the cluster",
+				apiEndpoint:     mockServer.URL,
+				authToken:       "my-token",
+				outputFormat:    tc.outputFormat,
+			}
+
+			err := verifyPreconditions(args)
+			if err != nil {
+				t.Errorf("Case %d: %s", i, err)
+			}
+
+			results, err := fetchApps(args)
+			if err != nil {
+				t.Errorf("Case %d: %s", i, err)
+			}
+
+			output, err := getOutput(results, args.outputFormat)
+			if err != nil {
+				t.Errorf("Case %d: %s", i, err)
+			}
+
+			if diff := cmp.Diff(tc.output, output); diff != "" {
+				t.Errorf("Case %d - Command output is incorrect. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+// Test_ListAppsInvalidOutputFormat tests that an unknown output format is rejected.
+func Test_ListAppsInvalidOutputFormat(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	args := Arguments{
+		clusterNameOrID: "cluster-id",
+		apiEndpoint:     "https://foo",
+		authToken:       "my-token",
+		outputFormat:    "xml",
+	}
+
+	err := verifyPreconditions(args)
+	if !errors.IsOutputFormatInvalid(err) {
+		t.Errorf("Expected OutputFormatInvalidError, got %#v", err)
+	}
+}
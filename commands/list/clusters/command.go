@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -16,7 +18,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/giantswarm/gsctl/clustercache"
-	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/output"
 	"github.com/giantswarm/gsctl/pkg/sortable"
 	"github.com/giantswarm/gsctl/pkg/table"
 
@@ -35,17 +37,27 @@ var (
 		Short:   "List clusters",
 		Long: `Prints a list of all clusters you have access to.
 
+With --all-endpoints, all endpoints in the configuration are queried in
+parallel, each using its own credentials, and an ENDPOINT column is added.
+Endpoints that can't be queried are reported, but don't make the command fail.
+
 Examples:
 
   gsctl list clusters
 
   gsctl list clusters --output json
 
+  gsctl list clusters --output wide
+
+  gsctl list clusters --output custom-columns=ID:.id,NAME:.name,RELEASE:.release_version
+
   gsctl list clusters --show-deleting
 
   gsctl list clusters --selector environment=testing
 
   gsctl list clusters --sort org
+
+  gsctl list clusters --all-endpoints --sort endpoint
 `,
 		PreRun: printValidation,
 		Run:    printResult,
@@ -57,6 +69,8 @@ Examples:
 
 	cmdSort string
 
+	cmdAllEndpoints bool
+
 	arguments Arguments
 )
 
@@ -69,6 +83,8 @@ const (
 	tableColOrg           = "organization"
 	tableColRelease       = "release"
 	tableColDeletingSince = "deleting-since"
+	tableColEndpoint      = "endpoint"
+	tableColLabels        = "labels"
 )
 
 var tableCols = [...]string{
@@ -78,6 +94,19 @@ var tableCols = [...]string{
 	tableColOrg,
 	tableColRelease,
 	tableColDeletingSince,
+	tableColEndpoint,
+}
+
+// jsonFieldMapping maps the table column names to the json field names
+// in the cluster data structure.
+var jsonFieldMapping = map[string]string{
+	tableColCreateDate:    "create_date",
+	tableColID:            "id",
+	tableColName:          "name",
+	tableColOrg:           "owner",
+	tableColRelease:       "release_version",
+	tableColDeletingSince: "delete_date",
+	tableColEndpoint:      "endpoint",
 }
 
 func init() {
@@ -86,21 +115,26 @@ func init() {
 
 func initFlags() {
 	Command.ResetFlags()
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON output. Defaults to human-friendly table output.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
 	Command.Flags().BoolVarP(&cmdShowDeleted, "show-deleting", "", false, "Show clusters which are currently being deleted (only with cluster release > 10.0.0).")
 	Command.Flags().StringVarP(&cmdSelector, "selector", "l", "", "Label selector query to filter clusters on.")
 	Command.Flags().StringVarP(&cmdSort, "sort", "s", "id", fmt.Sprintf("Sort by one of the fields %s", getFormattedFilterFields(tableCols[:])))
+	Command.Flags().BoolVarP(&cmdAllEndpoints, "all-endpoints", "", false, "List clusters from all endpoints in the configuration, not only the selected one.")
 }
 
 type Arguments struct {
-	apiEndpoint       string
-	authToken         string
-	outputFormat      string
-	scheme            string
-	selector          string
-	showDeleting      bool
-	sortBy            string
-	userProvidedToken string
+	allEndpoints bool
+	apiEndpoint  string
+	authToken    string
+	// endpoints is the list of endpoint URLs to query with allEndpoints.
+	endpoints            []string
+	outputFormat         string
+	scheme               string
+	selector             string
+	showDeleting         bool
+	sortBy               string
+	userProvidedEndpoint string
+	userProvidedToken    string
 }
 
 func collectArguments() Arguments {
@@ -108,20 +142,29 @@ func collectArguments() Arguments {
 	token := config.Config.ChooseToken(endpoint, flags.Token)
 	scheme := config.Config.ChooseScheme(endpoint, flags.Token)
 
+	var endpoints []string
+	if cmdAllEndpoints {
+		endpoints = config.Config.Endpoints()
+		sort.Strings(endpoints)
+	}
+
 	return Arguments{
-		apiEndpoint:       endpoint,
-		authToken:         token,
-		outputFormat:      flags.OutputFormat,
-		scheme:            scheme,
-		selector:          cmdSelector,
-		showDeleting:      cmdShowDeleted,
-		sortBy:            cmdSort,
-		userProvidedToken: flags.Token,
+		allEndpoints:         cmdAllEndpoints,
+		apiEndpoint:          endpoint,
+		authToken:            token,
+		endpoints:            endpoints,
+		outputFormat:         flags.OutputFormat,
+		scheme:               scheme,
+		selector:             cmdSelector,
+		showDeleting:         cmdShowDeleted,
+		sortBy:               cmdSort,
+		userProvidedEndpoint: flags.APIEndpoint,
+		userProvidedToken:    flags.Token,
 	}
 }
 
 func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "list clusters", "get clusters", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-clusters/"))
+	fmt.Fprint(output.NoticeWriter(flags.OutputFormat), util.GetDeprecatedNotice(config.Config.Provider, "list clusters", "get clusters", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-clusters/"))
 
 	arguments = collectArguments()
 	err := verifyListClusterPreconditions(arguments)
@@ -130,24 +173,38 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	// Display error
-	fmt.Println(color.RedString(err.Error()))
+	subtext := ""
+	if errors.IsConflictingFlagsError(err) {
+		subtext = "Use --all-endpoints to list clusters of all endpoints, or --endpoint to select one."
+	}
 
-	os.Exit(1)
+	errors.PrintError(err, err.Error(), subtext)
+	errors.Exit(err)
 }
 
 func verifyListClusterPreconditions(args Arguments) error {
-	if args.apiEndpoint == "" {
-		return microerror.Mask(errors.EndpointMissingError)
-	}
-	if config.Config.Token == "" && args.authToken == "" {
-		return microerror.Mask(errors.NotLoggedInError)
+	if args.allEndpoints {
+		if args.userProvidedEndpoint != "" {
+			return microerror.Maskf(errors.ConflictingFlagsError, "--all-endpoints cannot be combined with --endpoint")
+		}
+		// Authentication is checked per endpoint.
+		if len(args.endpoints) == 0 {
+			return microerror.Mask(errors.EndpointMissingError)
+		}
+	} else {
+		if args.apiEndpoint == "" {
+			return microerror.Mask(errors.EndpointMissingError)
+		}
+		if config.Config.Token == "" && args.authToken == "" {
+			return microerror.Mask(errors.NotLoggedInError)
+		}
 	}
-	if args.outputFormat != formatting.OutputFormatJSON && args.outputFormat != formatting.OutputFormatTable {
-		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
 	}
 
 	return nil
@@ -157,7 +214,7 @@ func verifyListClusterPreconditions(args Arguments) error {
 func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	output, err := getClustersOutput(arguments)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var (
@@ -174,6 +231,10 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 				subtext = clientErr.ErrorDetails
 			}
 
+		case IsAllEndpointsFailed(err):
+			headline = "Could not list clusters on any endpoint"
+			subtext = err.Error()
+
 		case table.IsFieldNotFoundError(err):
 			headline = fmt.Sprintf("Cannot sort by attribute '%s'.", arguments.sortBy)
 			subtext = fmt.Sprintf(
@@ -194,11 +255,8 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	if output != "" {
@@ -222,12 +280,191 @@ func getFormattedFilterFields(colNames []string) string {
 
 // getClustersOutput returns a table of clusters the user has access to
 func getClustersOutput(args Arguments) (string, error) {
-	var err error
+	if args.allEndpoints {
+		return getAllEndpointsOutput(args)
+	}
+
 	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
 	if err != nil {
 		return "", microerror.Mask(err)
 	}
 
+	clusterList, err := fetchClusters(clientWrapper, args)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	// Create the cluster list table.
+	cTable := createTable(args)
+
+	if !output.IsTableFormat(args.outputFormat) {
+		var out string
+		out, err = getStructuredOutput(filterDeleted(clusterList, args), cTable, args)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return out, nil
+	}
+
+	rows, clusterIDs, numDeletedClusters := createRows(clusterList, "", args)
+	cTable.SetRows(rows)
+
+	err = sortTable(cTable, args)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	clustercache.CacheIDs(args.apiEndpoint, clusterIDs)
+
+	return formatTable(cTable, len(rows), numDeletedClusters, args), nil
+}
+
+// endpointResult holds the clusters listed on one endpoint, or the error
+// that occurred while listing them.
+type endpointResult struct {
+	endpoint string
+	// name is the endpoint alias, or the URL if there is no alias.
+	name     string
+	clusters []*models.V4ClusterListItem
+	err      error
+}
+
+// getAllEndpointsOutput lists clusters on all configured endpoints concurrently
+// and returns them as one table (or JSON list). Errors are reported per endpoint.
+func getAllEndpointsOutput(args Arguments) (string, error) {
+	results := fetchClustersFromEndpoints(args)
+
+	var failed []endpointResult
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) == len(results) {
+		return "", microerror.Maskf(allEndpointsFailedError, "%s", formatEndpointErrors(failed))
+	}
+
+	cTable := createTable(args)
+
+	if !output.IsTableFormat(args.outputFormat) {
+		var clustersAsMapList []map[string]interface{}
+		for _, r := range results {
+			if r.err != nil {
+				continue
+			}
+
+			maps, err := clustersToMaps(filterDeleted(r.clusters, args))
+			if err != nil {
+				return "", microerror.Mask(err)
+			}
+			for _, m := range maps {
+				m["endpoint"] = r.endpoint
+			}
+			clustersAsMapList = append(clustersAsMapList, maps...)
+		}
+
+		// Errors go to STDERR, to keep STDOUT parseable.
+		if len(failed) > 0 {
+			fmt.Fprintln(os.Stderr, color.YellowString(formatEndpointErrors(failed)))
+		}
+
+		out, err := sortAndPrint(clustersAsMapList, cTable, args)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return out, nil
+	}
+
+	var rows [][]string
+	numDeletedClusters := 0
+	for _, r := range results {
+		if r.err != nil {
+			continue
+		}
+
+		endpointRows, clusterIDs, numDeleted := createRows(r.clusters, r.name, args)
+		rows = append(rows, endpointRows...)
+		numDeletedClusters += numDeleted
+
+		clustercache.CacheIDs(r.endpoint, clusterIDs)
+	}
+	cTable.SetRows(rows)
+
+	err := sortTable(cTable, args)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	output := formatTable(cTable, len(rows), numDeletedClusters, args)
+	if len(failed) > 0 {
+		output += "\n\n" + color.YellowString(formatEndpointErrors(failed))
+	}
+
+	return output, nil
+}
+
+// fetchClustersFromEndpoints calls the API of all endpoints in parallel,
+// each with the credentials stored for the endpoint. Results are returned
+// in the order of args.endpoints.
+func fetchClustersFromEndpoints(args Arguments) []endpointResult {
+	results := make([]endpointResult, len(args.endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range args.endpoints {
+		results[i] = endpointResult{endpoint: endpoint, name: endpoint}
+		if endpointConfig := config.Config.EndpointConfig(endpoint); endpointConfig != nil && endpointConfig.Alias != "" {
+			results[i].name = endpointConfig.Alias
+		}
+
+		wg.Add(1)
+		go func(r *endpointResult) {
+			defer wg.Done()
+
+			clientWrapper, err := client.NewForEndpoint(r.endpoint)
+			if err != nil {
+				r.err = microerror.Mask(err)
+				return
+			}
+
+			r.clusters, r.err = fetchClusters(clientWrapper, args)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results
+}
+
+// formatEndpointErrors returns one line per endpoint that could not be queried.
+func formatEndpointErrors(failed []endpointResult) string {
+	lines := make([]string, 0, len(failed))
+	for _, r := range failed {
+		var reason string
+
+		clientErr, isClientErr := microerror.Cause(r.err).(*clienterror.APIError)
+
+		switch {
+		case errors.IsNotAuthorizedError(r.err):
+			reason = "not authorized, please log in again"
+		case errors.IsAccessForbiddenError(r.err):
+			reason = "access forbidden"
+		case isClientErr:
+			reason = clientErr.ErrorMessage
+		default:
+			reason = r.err.Error()
+		}
+
+		lines = append(lines, fmt.Sprintf("Could not list clusters on endpoint %s: %s", r.name, reason))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// fetchClusters fetches the cluster list, using the selector if given.
+func fetchClusters(clientWrapper *client.Wrapper, args Arguments) ([]*models.V4ClusterListItem, error) {
+	var err error
+
 	auxParams := clientWrapper.DefaultAuxiliaryParams()
 	auxParams.ActivityName = listClustersActivityName
 
@@ -244,46 +481,41 @@ func getClustersOutput(args Arguments) (string, error) {
 
 	if err != nil {
 		if clienterror.IsUnauthorizedError(err) {
-			return "", microerror.Mask(errors.NotAuthorizedError)
+			return nil, microerror.Mask(errors.NotAuthorizedError)
 		}
 		if clienterror.IsAccessForbiddenError(err) {
-			return "", microerror.Mask(errors.AccessForbiddenError)
+			return nil, microerror.Mask(errors.AccessForbiddenError)
 		}
 
-		return "", microerror.Mask(err)
+		return nil, microerror.Mask(err)
 	}
 
-	// Create the cluster list table.
-	cTable := createTable(args)
-
-	if args.outputFormat == formatting.OutputFormatJSON {
-		// Filter deleted clusters if seeing them is not desired.
-		var clusterList []*models.V4ClusterListItem
-		{
-			for _, cluster := range response.Payload {
-				if cluster.DeleteDate != nil && !args.showDeleting {
-					continue
-				}
-
-				clusterList = append(clusterList, cluster)
-			}
-		}
+	return response.Payload, nil
+}
 
-		var output string
-		output, err = getJSONOutput(clusterList, cTable, arguments)
-		if err != nil {
-			return "", microerror.Mask(err)
+// filterDeleted removes deleted clusters if seeing them is not desired.
+func filterDeleted(clusterList []*models.V4ClusterListItem, args Arguments) []*models.V4ClusterListItem {
+	var filtered []*models.V4ClusterListItem
+	for _, cluster := range clusterList {
+		if cluster.DeleteDate != nil && !args.showDeleting {
+			continue
 		}
 
-		return output, nil
+		filtered = append(filtered, cluster)
 	}
 
+	return filtered
+}
+
+// createRows creates the table rows for the given clusters. If endpointName
+// is not empty, it is added as the first column. It also returns the IDs of
+// clusters not being deleted and the number of deleted clusters.
+func createRows(clusterList []*models.V4ClusterListItem, endpointName string, args Arguments) ([][]string, []string, int) {
 	numDeletedClusters := 0
-	numOtherClusters := 0
-	clusterIDs := make([]string, 0, len(response.Payload))
+	clusterIDs := make([]string, 0, len(clusterList))
 
-	rows := make([][]string, 0, len(response.Payload))
-	for _, cluster := range response.Payload {
+	rows := make([][]string, 0, len(clusterList))
+	for _, cluster := range clusterList {
 		created := util.ShortDate(util.ParseDate(cluster.CreateDate))
 		deleted := "n/a"
 
@@ -301,8 +533,6 @@ func getClustersOutput(args Arguments) (string, error) {
 			secondsSinceDelete = time.Now().Sub(deleteTime).Seconds()
 		} else {
 			clusterIDs = append(clusterIDs, cluster.ID)
-
-			numOtherClusters++
 		}
 
 		releaseVersion := cluster.ReleaseVersion
@@ -310,16 +540,23 @@ func getClustersOutput(args Arguments) (string, error) {
 			releaseVersion = "n/a"
 		}
 
-		fields := []string{
+		var fields []string
+		if endpointName != "" {
+			fields = append(fields, endpointName)
+		}
+		fields = append(fields,
 			cluster.ID,
 			cluster.Owner,
 			cluster.Name,
 			releaseVersion,
 			created,
-		}
+		)
 		if args.showDeleting {
 			fields = append(fields, color.RedString(deleted))
 		}
+		if output.IsWideFormat(args.outputFormat) {
+			fields = append(fields, formatLabels(cluster.Labels))
+		}
 
 		// Highlight row in red if old.
 		if secondsSinceDelete > 86400 {
@@ -330,20 +567,38 @@ func getClustersOutput(args Arguments) (string, error) {
 
 		rows = append(rows, fields)
 	}
-	cTable.SetRows(rows)
 
-	err = sortTable(cTable, args)
-	if err != nil {
-		return "", microerror.Mask(err)
+	return rows, clusterIDs, numDeletedClusters
+}
+
+// formatLabels returns the user-defined labels as a comma-separated list
+// of key=value pairs.
+func formatLabels(labels map[string]string) string {
+	var pairs []string
+	for key, value := range labels {
+		if strings.Contains(key, util.LabelFilterKeySubstring) {
+			continue
+		}
+		pairs = append(pairs, key+"="+value)
 	}
 
-	clustercache.CacheIDs(args.apiEndpoint, clusterIDs)
+	if len(pairs) == 0 {
+		return "n/a"
+	}
+
+	sort.Strings(pairs)
 
+	return strings.Join(pairs, ",")
+}
+
+// formatTable renders the table, or a notice if there are no rows, plus a hint
+// regarding deleted clusters.
+func formatTable(cTable *table.Table, numRows, numDeletedClusters int, args Arguments) string {
 	// This function's output string.
 	output := ""
 
 	// Only show table when there is content.
-	if len(rows) > 0 {
+	if numRows > 0 {
 		output += cTable.String()
 	} else {
 		output += color.YellowString("No clusters")
@@ -358,7 +613,7 @@ func getClustersOutput(args Arguments) (string, error) {
 		}
 	}
 
-	return output, nil
+	return output
 }
 
 func createTable(args Arguments) *table.Table {
@@ -410,6 +665,32 @@ func createTable(args Arguments) *table.Table {
 			Hidden: !args.showDeleting,
 		},
 	}
+	if output.IsWideFormat(args.outputFormat) {
+		// Hidden columns have no cells, so they must not precede the labels.
+		var shown []table.Column
+		for _, c := range headers {
+			if !c.Hidden {
+				shown = append(shown, c)
+			}
+		}
+		headers = append(shown, table.Column{
+			Name:        tableColLabels,
+			DisplayName: "LABELS",
+			Sortable: sortable.Sortable{
+				SortType: sortable.String,
+			},
+		})
+	}
+	if args.allEndpoints {
+		endpointColumn := table.Column{
+			Name:        tableColEndpoint,
+			DisplayName: "ENDPOINT",
+			Sortable: sortable.Sortable{
+				SortType: sortable.String,
+			},
+		}
+		headers = append([]table.Column{endpointColumn}, headers...)
+	}
 	t.SetColumns(headers)
 
 	return &t
@@ -435,34 +716,51 @@ func sortTable(cTable *table.Table, args Arguments) error {
 	return nil
 }
 
-func getJSONOutput(clusterList []*models.V4ClusterListItem, cTable *table.Table, args Arguments) (string, error) {
-	var (
-		err    error
-		output []byte
-	)
+// getStructuredOutput returns the cluster list in the selected non-table
+// output format, sorted like the table would be.
+func getStructuredOutput(clusterList []*models.V4ClusterListItem, cTable *table.Table, args Arguments) (string, error) {
+	// If there is nothing to sort, let's get this over with.
+	if len(clusterList) < 2 {
+		return printStructured(clusterList, args)
+	}
 
-	// take the shortest route. no need to call json.Marshal
-	if clusterList == nil || len(clusterList) == 0 {
-		return "[]", nil
+	clustersAsMapList, err := clustersToMaps(clusterList)
+	if err != nil {
+		return "", microerror.Mask(err)
 	}
 
-	// If there is nothing to sort, let's get this over with.
-	if len(clusterList) < 2 {
-		output, err = json.MarshalIndent(clusterList, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
-		if err != nil {
-			return "", microerror.Mask(err)
-		}
+	return sortAndPrint(clustersAsMapList, cTable, args)
+}
+
+// clustersToMaps converts the cluster list to maps, with the json field names
+// as keys, to be able to use the same sorting logic as in the table.
+func clustersToMaps(clusterList []*models.V4ClusterListItem) ([]map[string]interface{}, error) {
+	clustersAsMapList := []map[string]interface{}{}
 
-		return string(output), nil
+	j, err := json.Marshal(clusterList)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	err = json.Unmarshal(j, &clustersAsMapList)
+	if err != nil {
+		return nil, microerror.Mask(err)
 	}
 
+	return clustersAsMapList, nil
+}
+
+// sortAndPrint sorts the clusters according to the sort argument
+// and returns them in the selected output format.
+func sortAndPrint(clustersAsMapList []map[string]interface{}, cTable *table.Table, args Arguments) (string, error) {
+	var err error
+
 	sortByColumnName := tableColID
 	var sortByColumn table.Column
 	if args.sortBy != "" {
 		sortByColumnName = args.sortBy
 	}
 
-	if sortByColumnName != "" {
+	if sortByColumnName != "" && len(clustersAsMapList) > 1 {
 		var colName string
 
 		colName, err = cTable.GetColumnNameFromInitials(sortByColumnName)
@@ -474,39 +772,24 @@ func getJSONOutput(clusterList []*models.V4ClusterListItem, cTable *table.Table,
 		if err != nil {
 			return "", microerror.Mask(err)
 		}
-	}
 
-	// The table column names, mapped to the json field names in the cluster data structure.
-	fieldMapping := map[string]string{
-		tableColCreateDate:    "create_date",
-		tableColID:            "id",
-		tableColName:          "name",
-		tableColOrg:           "owner",
-		tableColRelease:       "release_version",
-		tableColDeletingSince: "delete_date",
+		table.SortMapSliceUsingColumnData(clustersAsMapList, sortByColumn, jsonFieldMapping)
 	}
 
-	// Convert cluster list to map, with the json field names as keys,
-	// to be able to use same sorting logic as in the table.
-	var clustersAsMapList []map[string]interface{}
-	{
-		var j []byte
-		j, err = json.Marshal(clusterList)
-		if err != nil {
-			return "", microerror.Mask(err)
-		}
-		err = json.Unmarshal(j, &clustersAsMapList)
-		if err != nil {
-			return "", microerror.Mask(err)
-		}
-	}
+	return printStructured(clustersAsMapList, args)
+}
 
-	table.SortMapSliceUsingColumnData(clustersAsMapList, sortByColumn, fieldMapping)
+// printStructured returns the data in the selected non-table output format.
+func printStructured(data interface{}, args Arguments) (string, error) {
+	printer, err := output.NewPrinter(args.outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
 
-	output, err = json.MarshalIndent(clustersAsMapList, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	out, err := printer.Sprint(data, ".id")
 	if err != nil {
 		return "", microerror.Mask(err)
 	}
 
-	return string(output), nil
+	return out, nil
 }
@@ -1,9 +1,11 @@
 package clusters
 
 import (
+	"encoding/json"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -266,3 +268,197 @@ func Test_ListClustersBySelector(t *testing.T) {
 
 	t.Log(jsonRepresentation)
 }
+
+// Test_ListClustersAllEndpoints tests listing clusters from several endpoints,
+// one of which fails.
+func Test_ListClustersAllEndpoints(t *testing.T) {
+	mockServerA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "giantswarm token-a" {
+			t.Errorf("Endpoint A called with bad authorization header %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+			{"create_date": "2020-05-01T09:45:43Z", "id": "a0001", "name": "Cluster A1", "owner": "acme", "release_version": "11.2.1"},
+			{"create_date": "2020-05-02T09:45:43Z", "id": "a0002", "name": "Cluster A2", "owner": "acme", "release_version": "11.3.0"}
+		]`))
+	}))
+	defer mockServerA.Close()
+
+	mockServerB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code": "PERMISSION_DENIED", "message": "Lorem ipsum"}`))
+	}))
+	defer mockServerB.Close()
+
+	configYAML := `endpoints:
+  ` + mockServerA.URL + `:
+    alias: alpha
+    email: email@example.com
+    token: token-a
+  ` + mockServerB.URL + `:
+    email: email@example.com
+    token: token-b
+selected_endpoint: ` + mockServerA.URL + `
+`
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		allEndpoints: true,
+		endpoints:    []string{mockServerA.URL, mockServerB.URL},
+		outputFormat: "table",
+		sortBy:       "endpoint",
+	}
+
+	err = verifyListClusterPreconditions(args)
+	if err != nil {
+		t.Error(err)
+	}
+
+	output, err := getClustersOutput(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	for _, expected := range []string{
+		"ENDPOINT",
+		"alpha      a0001",
+		"alpha      a0002",
+		"Could not list clusters on endpoint " + mockServerB.URL + ": not authorized",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	args.outputFormat = "json"
+	output, err = getClustersOutput(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	var clusterList []map[string]interface{}
+	err = json.Unmarshal([]byte(output), &clusterList)
+	if err != nil {
+		t.Fatalf("Output is not valid JSON: %#v", err)
+	}
+	if len(clusterList) != 2 {
+		t.Fatalf("Expected 2 clusters, got %d", len(clusterList))
+	}
+	for _, c := range clusterList {
+		if c["endpoint"] != mockServerA.URL {
+			t.Errorf("Expected endpoint %q, got %v", mockServerA.URL, c["endpoint"])
+		}
+	}
+
+	// Only the failing endpoint.
+	args.endpoints = []string{mockServerB.URL}
+	_, err = getClustersOutput(args)
+	if !IsAllEndpointsFailed(err) {
+		t.Errorf("Expected allEndpointsFailedError, got %#v", err)
+	}
+}
+
+// Test_verifyListClusterPreconditionsAllEndpoints tests the flag combinations for --all-endpoints.
+func Test_verifyListClusterPreconditionsAllEndpoints(t *testing.T) {
+	args := Arguments{
+		allEndpoints:         true,
+		endpoints:            []string{"https://foo"},
+		outputFormat:         "table",
+		userProvidedEndpoint: "https://foo",
+	}
+	err := verifyListClusterPreconditions(args)
+	if !errors.IsConflictingFlagsError(err) {
+		t.Errorf("Expected ConflictingFlagsError, got %#v", err)
+	}
+
+	args.userProvidedEndpoint = ""
+	args.endpoints = nil
+	err = verifyListClusterPreconditions(args)
+	if !errors.IsEndpointMissingError(err) {
+		t.Errorf("Expected EndpointMissingError, got %#v", err)
+	}
+}
+
+// Test_ListClustersStructuredFormats tests the name, custom-columns and wide output formats.
+func Test_ListClustersStructuredFormats(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[
+			{
+				"create_date": "2017-05-16T09:30:31.192170835Z",
+				"id": "fow72",
+				"name": "Production",
+				"owner": "acme",
+				"labels": {"env": "prod", "giantswarm.io/cluster": "fow72"}
+			},
+			{
+				"create_date": "2017-10-06T02:24:55.192170835Z",
+				"id": "7ste0",
+				"name": "Test",
+				"owner": "acme"
+			}
+		]`))
+	}))
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	var testCases = []struct {
+		outputFormat string
+		expected     []string
+	}{
+		{
+			outputFormat: "name",
+			expected:     []string{"7ste0\nfow72"},
+		},
+		{
+			outputFormat: "custom-columns=ID:.id,NAME:.name",
+			expected:     []string{"ID      NAME\n7ste0   Test\nfow72   Production"},
+		},
+		{
+			outputFormat: "wide",
+			expected:     []string{"LABELS", "env=prod", "n/a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.outputFormat, func(t *testing.T) {
+			args := Arguments{
+				apiEndpoint:  mockServer.URL,
+				authToken:    "testtoken",
+				outputFormat: tc.outputFormat,
+			}
+
+			err = verifyListClusterPreconditions(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := getClustersOutput(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, e := range tc.expected {
+				if !strings.Contains(out, e) {
+					t.Errorf("Expected output to contain %q, got\n%s", e, out)
+				}
+			}
+			if strings.Contains(out, "giantswarm.io/cluster") {
+				t.Errorf("Output should not contain internal labels, got\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package clusters
+
+import "github.com/giantswarm/microerror"
+
+// allEndpointsFailedError is used when clusters could not be listed
+// on any of the endpoints queried with --all-endpoints.
+var allEndpointsFailedError = &microerror.Error{
+	Kind: "allEndpointsFailedError",
+}
+
+// IsAllEndpointsFailed asserts allEndpointsFailedError.
+func IsAllEndpointsFailed(err error) bool {
+	return microerror.Cause(err) == allEndpointsFailedError
+}
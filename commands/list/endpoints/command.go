@@ -3,15 +3,19 @@ package endpoints
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/giantswarm/columnize"
 	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
 )
 
 var (
@@ -21,17 +25,38 @@ var (
 		Aliases: []string{"endpoint"},
 		Short:   "List API endpoints",
 		Long:    `Prints a list of API endpoints you have used so far`,
+		PreRun:  printValidation,
 		Run:     listEndpoints,
 	}
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
 // Arguments are the arguments we pass to the actual functions
 // listing endpoints and printing endpoints lists
 // TODO: apiEndpoint is the only argument used. The rest can be removed.
 type Arguments struct {
-	apiEndpoint string
-	scheme      string
-	token       string
+	apiEndpoint  string
+	outputFormat string
+	scheme       string
+	token        string
+}
+
+// endpointInfo is the structured representation of an endpoint
+// used for non-table output formats.
+type endpointInfo struct {
+	Alias    string `json:"alias,omitempty"`
+	URL      string `json:"url"`
+	Email    string `json:"email,omitempty"`
+	Selected bool   `json:"selected"`
+	LoggedIn bool   `json:"logged_in"`
 }
 
 // collectArguments returns Arguments
@@ -41,42 +66,74 @@ func collectArguments() Arguments {
 	token := config.Config.ChooseToken(endpoint, flags.Token)
 	scheme := config.Config.ChooseScheme(endpoint, flags.Token)
 	return Arguments{
-		apiEndpoint: endpoint,
-		token:       token,
-		scheme:      scheme,
+		apiEndpoint:  endpoint,
+		outputFormat: flags.OutputFormat,
+		token:        token,
+		scheme:       scheme,
+	}
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	if _, err := output.NewPrinter(flags.OutputFormat); err != nil {
+		fmt.Println(color.RedString("Invalid output format"))
+		fmt.Println(err.Error())
+		os.Exit(errors.ExitCodeInvalidInput)
 	}
 }
 
 // listEndpoints prints a table with all endpoint URLs the user has used
 func listEndpoints(cmd *cobra.Command, args []string) {
 	myArgs := collectArguments()
-	output := endpointsTable(myArgs)
-	if output != "" {
-		fmt.Println(output)
+
+	if !output.IsTableFormat(myArgs.outputFormat) {
+		out, err := endpointsStructured(myArgs)
+		if err != nil {
+			fmt.Println(color.RedString("Error while formatting output"))
+			fmt.Printf("Details: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Println(out)
+		return
+	}
+
+	out := endpointsTable(myArgs)
+	if out != "" {
+		fmt.Println(out)
 	}
 }
 
-// endpointsTable returns a table of clusters the user has access to
-func endpointsTable(args Arguments) string {
-	if len(config.Config.Endpoints()) == 0 {
-		return fmt.Sprintf("No endpoints configured.\n\nTo add an endpoint and authenticate for it, use\n\n\t%s\n",
-			color.YellowString("gsctl login <email> -e <endpoint>"))
+// endpointsStructured returns the endpoints in the non-table output
+// format given in the arguments.
+func endpointsStructured(args Arguments) (string, error) {
+	printer, err := output.NewPrinter(args.outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	endpoints := []endpointInfo{}
+	for _, endpoint := range sortedEndpointURLs() {
+		endpointConfig := config.Config.EndpointConfig(endpoint)
+		endpoints = append(endpoints, endpointInfo{
+			Alias:    endpointConfig.Alias,
+			URL:      endpoint,
+			Email:    endpointConfig.Email,
+			Selected: endpoint == args.apiEndpoint,
+			LoggedIn: endpointConfig.Token != "",
+		})
 	}
 
+	return printer.Sprint(endpoints, ".url")
+}
+
+// sortedEndpointURLs returns all configured endpoint URLs, sorted by
+// alias first, endpoint URL second.
+func sortedEndpointURLs() []string {
 	// get keys (URLs) and sort by them
 	endpointURLs := make([]string, 0, len(config.Config.Endpoints()))
 	for _, u := range config.Config.Endpoints() {
 		endpointURLs = append(endpointURLs, u)
 	}
 
-	// detect if we want to show the alias column
-	hasAlias := false
-	for _, endpoint := range endpointURLs {
-		if config.Config.EndpointConfig(endpoint).Alias != "" {
-			hasAlias = true
-		}
-	}
-
 	// sort by alias first, endpoint URL second
 	sort.Slice(endpointURLs, func(i, j int) bool {
 		return endpointURLs[i] < endpointURLs[j]
@@ -94,8 +151,28 @@ func endpointsTable(args Arguments) string {
 		return aliasi < aliasj
 	})
 
+	return endpointURLs
+}
+
+// endpointsTable returns a table of clusters the user has access to
+func endpointsTable(args Arguments) string {
+	if len(config.Config.Endpoints()) == 0 {
+		return fmt.Sprintf("No endpoints configured.\n\nTo add an endpoint and authenticate for it, use\n\n\t%s\n",
+			color.YellowString("gsctl login <email> -e <endpoint>"))
+	}
+
+	endpointURLs := sortedEndpointURLs()
+
+	// detect if we want to show the alias column
+	hasAlias := false
+	for _, endpoint := range endpointURLs {
+		if config.Config.EndpointConfig(endpoint).Alias != "" {
+			hasAlias = true
+		}
+	}
+
 	// table headers
-	output := []string{}
+	rows := []string{}
 	headers := []string{}
 
 	if hasAlias {
@@ -105,7 +182,7 @@ func endpointsTable(args Arguments) string {
 	headers = append(headers, color.CyanString("EMAIL"))
 	headers = append(headers, color.CyanString("SELECTED"))
 	headers = append(headers, color.CyanString("LOGGED IN"))
-	output = append(output, strings.Join(headers, "|"))
+	rows = append(rows, strings.Join(headers, "|"))
 
 	for _, endpoint := range endpointURLs {
 		endpointConfig := config.Config.EndpointConfig(endpoint)
@@ -150,8 +227,8 @@ func endpointsTable(args Arguments) string {
 			columns = append(columns, selected)
 			columns = append(columns, loggedIn)
 		}
-		output = append(output, strings.Join(columns, "|"))
+		rows = append(rows, strings.Join(columns, "|"))
 	}
 
-	return columnize.SimpleFormat(output)
+	return columnize.SimpleFormat(rows)
 }
@@ -1,6 +1,7 @@
 package endpoints
 
 import (
+	"strconv"
 	"strings"
 	"testing"
 
@@ -48,3 +49,67 @@ selected_endpoint: https://my.second.endpoint
 		t.Errorf("Table does not contain expected row '%s'", testString)
 	}
 }
+
+// Test_ListEndpointsStructured tests non-table output of endpoints.
+func Test_ListEndpointsStructured(t *testing.T) {
+	yamlText := `last_version_check: 0001-01-01T00:00:00Z
+updated: 2017-09-29T11:23:15+02:00
+endpoints:
+  https://my.first.endpoint:
+    email: email@example.com
+    token: some-token
+    alias: first
+  https://my.second.endpoint:
+    email: email@example.com
+selected_endpoint: https://my.first.endpoint
+`
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, yamlText)
+	if err != nil {
+		t.Error(err)
+	}
+
+	var testCases = []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   "name",
+			expected: "https://my.first.endpoint\nhttps://my.second.endpoint",
+		},
+		{
+			format:   "custom-columns=ALIAS:.alias,LOGGED_IN:.logged_in",
+			expected: "ALIAS   LOGGED_IN\nfirst   true\nn/a     false",
+		},
+		{
+			format: "yaml",
+			expected: `- alias: first
+  email: email@example.com
+  logged_in: true
+  selected: true
+  url: https://my.first.endpoint
+- email: email@example.com
+  logged_in: false
+  selected: false
+  url: https://my.second.endpoint`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			args := Arguments{
+				apiEndpoint:  config.Config.ChooseEndpoint(""),
+				outputFormat: tc.format,
+			}
+
+			out, err := endpointsStructured(args)
+			if err != nil {
+				t.Fatalf("unexpected error: %#v", err)
+			}
+			if out != tc.expected {
+				t.Errorf("expected\n%s\ngot\n%s", tc.expected, out)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@
 package keypairs
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -22,6 +21,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/output"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -85,7 +85,7 @@ func initFlags() {
 
 	Command.Flags().StringVarP(&flags.ClusterID, "cluster", "c", "", "Name/ID of the cluster to list key pairs for")
 	Command.Flags().BoolVarP(&flags.Full, "full", "", false, "Enables output of full, untruncated values")
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON output. Defaults to human-friendly table output.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage+" 'wide' implies --full.")
 
 	Command.MarkFlagRequired("cluster")
 }
@@ -96,11 +96,11 @@ func printValidation(cmd *cobra.Command, extraArgs []string) {
 	arguments = collectArguments()
 	err := listKeypairsValidate(&arguments)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
-		fmt.Println(color.RedString(err.Error()))
-		os.Exit(1)
+		errors.PrintError(err, err.Error(), "")
+		errors.Exit(err)
 	}
 }
 
@@ -116,8 +116,8 @@ func listKeypairsValidate(args *Arguments) error {
 	if config.Config.Token == "" && args.token == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.outputFormat != formatting.OutputFormatJSON && args.outputFormat != formatting.OutputFormatTable {
-		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is unknown", args.outputFormat))
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
 	}
 
 	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
@@ -145,7 +145,7 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 
 	// error output
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline string
@@ -159,29 +159,28 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 			headline = err.Error()
 		}
 
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
-	if arguments.outputFormat == formatting.OutputFormatJSON {
-		outputBytes, err := json.MarshalIndent(result.keypairs, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	if !output.IsTableFormat(arguments.outputFormat) {
+		printer, _ := output.NewPrinter(arguments.outputFormat)
+		out, err := printer.Sprint(result.keypairs, ".id")
 		if err != nil {
-			fmt.Println(color.RedString("Error while encoding JSON"))
+			fmt.Println(color.RedString("Error while formatting output"))
 			fmt.Printf("Details: %s", err.Error())
 			os.Exit(1)
 		}
 
-		fmt.Println(string(outputBytes))
+		fmt.Println(out)
 	} else {
 		// success output
 		if len(result.keypairs) == 0 {
 			fmt.Println(color.YellowString("No key pairs available for this cluster."))
 			fmt.Println("You can create a new key pair using the 'gsctl create kubeconfig' or 'gsctl create keypair' command.")
 		} else {
-			output := []string{}
+			full := arguments.full || output.IsWideFormat(arguments.outputFormat)
+			rows := []string{}
 
 			headers := []string{
 				color.CyanString("CREATED"),
@@ -191,7 +190,7 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 				color.CyanString("CN"),
 				color.CyanString("O"),
 			}
-			output = append(output, strings.Join(headers, "|"))
+			rows = append(rows, strings.Join(headers, "|"))
 
 			for _, keypair := range result.keypairs {
 				createdTime := util.ParseDate(keypair.CreateDate)
@@ -207,14 +206,14 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 				row := []string{
 					util.ShortDate(createdTime),
 					expires,
-					util.Truncate(formatting.CleanKeypairID(keypair.ID), 10, !arguments.full),
+					util.Truncate(formatting.CleanKeypairID(keypair.ID), 10, !full),
 					keypair.Description,
-					util.Truncate(keypair.CommonName, 24, !arguments.full),
+					util.Truncate(keypair.CommonName, 24, !full),
 					keypair.CertificateOrganizations,
 				}
-				output = append(output, strings.Join(row, "|"))
+				rows = append(rows, strings.Join(row, "|"))
 			}
-			fmt.Println(columnize.SimpleFormat(output))
+			fmt.Println(columnize.SimpleFormat(rows))
 		}
 	}
 }
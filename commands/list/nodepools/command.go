@@ -2,7 +2,6 @@
 package nodepools
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -24,6 +23,7 @@ import (
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/formatting"
 	"github.com/giantswarm/gsctl/nodespec"
+	"github.com/giantswarm/gsctl/pkg/output"
 )
 
 var (
@@ -73,7 +73,7 @@ func init() {
 }
 
 func initFlags() {
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON output. Defaults to human-friendly table output.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
 }
 
 type Arguments struct {
@@ -110,21 +110,21 @@ func verifyPreconditions(args Arguments, positionalArgs []string) error {
 	if config.Config.Token == "" && args.authToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.outputFormat != formatting.OutputFormatJSON && args.outputFormat != formatting.OutputFormatTable {
-		return microerror.Maskf(errors.OutputFormatInvalidError, "Output format '%s' is unknown", args.outputFormat)
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
 	}
 
 	return nil
 }
 
 func printValidation(cmd *cobra.Command, positionalArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "list nodepools", "get nodepools", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-nodepools/"))
+	fmt.Fprint(output.NoticeWriter(flags.OutputFormat), util.GetDeprecatedNotice(config.Config.Provider, "list nodepools", "get nodepools", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-nodepools/"))
 
 	arguments = collectArguments(positionalArgs)
 	err := verifyPreconditions(arguments, positionalArgs)
 	if err != nil {
 		handleError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -173,21 +173,21 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 	nodePools, err := fetchNodePools(arguments)
 	if err != nil {
 		handleError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
-	if len(nodePools) == 0 {
+	if len(nodePools) == 0 && output.IsTableFormat(arguments.outputFormat) {
 		fmt.Println(color.YellowString("This cluster has no node pools"))
 		return
 	}
 
-	output, err := getOutput(nodePools, arguments.outputFormat)
+	out, err := getOutput(nodePools, arguments.outputFormat)
 	if err != nil {
 		handleError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 	// Display output.
-	fmt.Println(output)
+	fmt.Println(out)
 }
 
 func formatNodesReady(nodes, nodesReady int64) string {
@@ -203,26 +203,30 @@ func getOutput(nps []*models.V5GetNodePoolsResponseItems, outputFormat string) (
 		return "", nil
 	}
 
-	if outputFormat == formatting.OutputFormatJSON {
-		outputBytes, err := json.MarshalIndent(nps, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	printer, err := output.NewPrinter(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if !printer.IsTable() {
+		out, err := printer.Sprint(nps, ".id")
 		if err != nil {
 			return "", microerror.Mask(err)
 		}
 
-		return string(outputBytes), nil
+		return out, nil
 	}
 
-	var output string
-	var err error
+	var out string
 	np := nps[0]
 
 	if np.NodeSpec.Aws != nil && np.NodeSpec.Azure == nil {
-		output, err = getOutputAWS(nps)
+		out, err = getOutputAWS(nps)
 		if err != nil {
 			return "", microerror.Mask(err)
 		}
 	} else if np.NodeSpec.Azure != nil && np.NodeSpec.Aws == nil {
-		output, err = getOutputAzure(nps)
+		out, err = getOutputAzure(nps)
 		if err != nil {
 			return "", microerror.Mask(err)
 		}
@@ -230,7 +234,7 @@ func getOutput(nps []*models.V5GetNodePoolsResponseItems, outputFormat string) (
 		return "", microerror.Mask(errors.ClusterDoesNotSupportNodePoolsError)
 	}
 
-	return output, nil
+	return out, nil
 }
 
 func getOutputAWS(nps []*models.V5GetNodePoolsResponseItems) (string, error) {
@@ -440,7 +444,7 @@ func getOutputAzure(nps []*models.V5GetNodePoolsResponseItems) (string, error) {
 }
 
 func handleError(err error) {
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	headline := ""
@@ -454,8 +458,5 @@ func handleError(err error) {
 		headline = err.Error()
 	}
 
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
+	errors.PrintError(err, headline, subtext)
 }
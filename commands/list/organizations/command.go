@@ -3,7 +3,6 @@ package organizations
 
 import (
 	"fmt"
-	"os"
 	"sort"
 
 	"github.com/fatih/color"
@@ -15,6 +14,7 @@ import (
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
 )
 
 var (
@@ -31,6 +31,15 @@ var (
 	arguments Arguments
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
 const (
 	listOrgsActivityName = "list-organizations"
 )
@@ -38,6 +47,7 @@ const (
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
+	outputFormat      string
 	scheme            string
 	userProvidedToken string
 }
@@ -51,6 +61,7 @@ func collectArguments() Arguments {
 	return Arguments{
 		apiEndpoint:       endpoint,
 		authToken:         token,
+		outputFormat:      flags.OutputFormat,
 		scheme:            scheme,
 		userProvidedToken: flags.Token,
 	}
@@ -63,8 +74,15 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
+
+	if errors.IsOutputFormatInvalid(err) {
+		errors.PrintError(err, "Invalid output format", err.Error())
+	} else {
+		errors.PrintError(err, fmt.Sprintf("Error: %s", err.Error()), "")
+	}
+	errors.Exit(err)
 }
 
 func verifyListOrgsPreconditions(args Arguments) error {
@@ -74,37 +92,42 @@ func verifyListOrgsPreconditions(args Arguments) error {
 	if config.Config.Token == "" && args.authToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
 	return nil
 }
 
 // printResult fetches a list organizations the user is member of
-// and prints it in tabular form, or prints errors of they occur.
+// and prints it in the selected output format, or prints errors of they occur.
 //
 // TODO: Refactor so that this function calls the client, receives structured
 // data which can be tested, and creates user-friendly output.
 func printResult(cmd *cobra.Command, extraArgs []string) {
-	output, err := orgsTable(arguments)
+	out, err := getOutput(arguments)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		if clientErr, ok := err.(*clienterror.APIError); ok {
-			fmt.Println(color.RedString(clientErr.ErrorMessage))
-			if clientErr.ErrorDetails != "" {
-				fmt.Println(clientErr.ErrorDetails)
-			}
+			errors.PrintError(err, clientErr.ErrorMessage, clientErr.ErrorDetails)
 		} else {
-			fmt.Println(color.RedString("Error: %s", err.Error()))
+			errors.PrintError(err, fmt.Sprintf("Error: %s", err.Error()), "")
 		}
-		os.Exit(1)
+		errors.Exit(err)
 	}
 
-	fmt.Print(output)
+	fmt.Print(out)
 }
 
-// orgsTable fetches the organizations the user is a member of
-// and returns a table in string form.
-func orgsTable(args Arguments) (string, error) {
+// getOutput fetches the organizations the user is a member of
+// and returns a table or the selected output format in string form.
+func getOutput(args Arguments) (string, error) {
+	printer, err := output.NewPrinter(args.outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
 	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
 
 	if err != nil {
@@ -126,20 +149,28 @@ func orgsTable(args Arguments) (string, error) {
 		return "", microerror.Mask(err)
 	}
 
-	var output string
+	// sort orgs by Id
+	sort.Slice(response.Payload[:], func(i, j int) bool {
+		return response.Payload[i].ID < response.Payload[j].ID
+	})
+
+	if !printer.IsTable() {
+		out, err := printer.Sprint(response.Payload, ".id")
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+		return out + "\n", nil
+	}
+
+	var out string
 	if len(response.Payload) == 0 {
-		output = color.YellowString("No organizations available\n")
+		out = color.YellowString("No organizations available\n")
 	} else {
-		// sort orgs by Id
-		sort.Slice(response.Payload[:], func(i, j int) bool {
-			return response.Payload[i].ID < response.Payload[j].ID
-		})
-
-		output = color.CyanString("ORGANIZATION") + "\n"
+		out = color.CyanString("ORGANIZATION") + "\n"
 		for _, org := range response.Payload {
-			output = output + org.ID + "\n"
+			out = out + org.ID + "\n"
 		}
 	}
 
-	return output, nil
+	return out, nil
 }
@@ -45,10 +45,36 @@ func Test_ListOrganizationsSuccess(t *testing.T) {
 			t.Errorf("Table test case %d: Unexpected error in verifyListOrgsPreconditions: %#v", i, err)
 		}
 
-		_, err = orgsTable(args)
+		_, err = getOutput(args)
 		if err != nil {
-			t.Errorf("Table test case %d: Unexpected error in orgsTable: %#v", i, err)
+			t.Errorf("Table test case %d: Unexpected error in getOutput: %#v", i, err)
 		}
 
 	}
 }
+
+// Test_ListOrganizationsName tests the 'name' output format.
+func Test_ListOrganizationsName(t *testing.T) {
+	orgsMockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id": "giantswarm"}, {"id": "acme"}]`))
+	}))
+	defer orgsMockServer.Close()
+
+	args := Arguments{
+		authToken:    "some-token",
+		apiEndpoint:  orgsMockServer.URL,
+		outputFormat: "name",
+	}
+
+	out, err := getOutput(args)
+	if err != nil {
+		t.Fatalf("Unexpected error in getOutput: %#v", err)
+	}
+
+	expected := "acme\ngiantswarm\n"
+	if out != expected {
+		t.Errorf("Expected %q, got %q", expected, out)
+	}
+}
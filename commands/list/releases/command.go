@@ -2,7 +2,6 @@
 package releases
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -21,7 +20,7 @@ import (
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
-	"github.com/giantswarm/gsctl/formatting"
+	"github.com/giantswarm/gsctl/pkg/output"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -76,7 +75,7 @@ func init() {
 func initFlags() {
 	Command.ResetFlags()
 
-	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", formatting.OutputFormatTable, fmt.Sprintf("Use '%s' for JSON output. Defaults to human-friendly table output.", formatting.OutputFormatJSON))
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
 }
 
 // Arguments are the actual arguments used to call the
@@ -108,7 +107,7 @@ func collectArguments() Arguments {
 // printValidation does our pre-checks and shows errors, in case
 // something is missing.
 func printValidation(cmd *cobra.Command, extraArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "list releases", "get releases", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-releases/"))
+	fmt.Fprint(output.NoticeWriter(flags.OutputFormat), util.GetDeprecatedNotice(config.Config.Provider, "list releases", "get releases", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-releases/"))
 
 	arguments = collectArguments()
 	err := listReleasesPreconditions(&arguments)
@@ -117,11 +116,11 @@ func printValidation(cmd *cobra.Command, extraArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
-	fmt.Println(color.RedString(err.Error()))
-	os.Exit(1)
+	errors.PrintError(err, err.Error(), "")
+	errors.Exit(err)
 }
 
 // listReleasesPreconditions validates our pre-conditions and returns an error in
@@ -133,8 +132,8 @@ func listReleasesPreconditions(args *Arguments) error {
 	if config.Config.Token == "" && args.token == "" {
 		return microerror.Mask(errors.NotLoggedInError)
 	}
-	if args.outputFormat != formatting.OutputFormatJSON && args.outputFormat != formatting.OutputFormatTable {
-		return microerror.Maskf(errors.OutputFormatInvalidError, fmt.Sprintf("Output format '%s' is unknown", args.outputFormat))
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
 	}
 
 	return nil
@@ -146,13 +145,13 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 	clientWrapper, err := client.NewWithConfig(arguments.apiEndpoint, arguments.userProvidedToken)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	releases, err := listReleases(clientWrapper, arguments)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	releaseInfoConfig := releaseinfo.Config{
@@ -161,18 +160,23 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 	releaseInfo, err := releaseinfo.New(releaseInfoConfig)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
-	if arguments.outputFormat == formatting.OutputFormatJSON {
-		outputBytes, err := json.MarshalIndent(releases, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+	if !output.IsTableFormat(arguments.outputFormat) {
+		printer, err := output.NewPrinter(arguments.outputFormat)
 		if err != nil {
-			fmt.Println(color.RedString("Error while encoding JSON"))
+			handleError(microerror.Mask(err))
+			os.Exit(errors.ExitCode(err))
+		}
+
+		err = printer.Print(os.Stdout, releases, ".version")
+		if err != nil {
+			fmt.Println(color.RedString("Error while formatting output"))
 			fmt.Printf("Details: %s", err.Error())
 			os.Exit(1)
 		}
 
-		fmt.Println(string(outputBytes))
 		return
 	}
 
@@ -184,7 +188,7 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 	}
 
 	// table headers
-	output := []string{strings.Join([]string{
+	rows := []string{strings.Join([]string{
 		color.CyanString("VERSION"),
 		color.CyanString("STATUS"),
 		color.CyanString("CREATED"),
@@ -222,7 +226,7 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 		}
 
 		if status == "active" {
-			output = append(output, strings.Join([]string{
+			rows = append(rows, strings.Join([]string{
 				color.YellowString(*release.Version),
 				color.YellowString(status),
 				color.YellowString(created),
@@ -232,7 +236,7 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 				color.YellowString(calicoVersion),
 			}, "|"))
 		} else {
-			output = append(output, strings.Join([]string{
+			rows = append(rows, strings.Join([]string{
 				*release.Version,
 				status,
 				created,
@@ -244,7 +248,7 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 		}
 	}
 
-	fmt.Println(columnize.SimpleFormat(output))
+	fmt.Println(columnize.SimpleFormat(rows))
 }
 
 // listReleases fetches releases and returns them as a structured result.
@@ -296,7 +300,7 @@ func formatKubernetesVersion(releaseInfo *releaseinfo.ReleaseInfo, version strin
 }
 
 func handleError(err error) {
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	if clientErr, ok := err.(*clienterror.APIError); ok {
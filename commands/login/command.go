@@ -16,6 +16,7 @@ import (
 	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/deviceauth"
 )
 
 const (
@@ -32,6 +33,18 @@ var (
 	// cmdSSO is the bool that triggers login via SSO.
 	cmdSSO bool
 
+	// cmdDevice is the bool that selects the device authorization flow for SSO.
+	cmdDevice bool
+
+	// cmdPasswordStdin is the bool that triggers reading the password from STDIN.
+	cmdPasswordStdin bool
+
+	// cmdTokenFile is the path of a file to read an auth token from.
+	cmdTokenFile string
+
+	// cmdToken is the auth token read from cmdTokenFile
+	cmdToken string
+
 	// Command is the "login" CLI command
 	Command = &cobra.Command{
 		Use:   "login <email> [-e|--endpoint <endpoint>]",
@@ -41,11 +54,22 @@ var (
 This will select the given endpoint for subsequent commands.
 
 The password has to be entered interactively or given as -p / --password flag.
+For non-interactive use, e. g. in CI pipelines, the password can be passed
+via standard input using --password-stdin.
+
+Alternatively, an existing auth token, e. g. one issued for a service account,
+can be read from a file using --token-file. Use '-' to read it from standard
+input. The token is checked against the API and then stored like a token
+obtained via password login.
 
 The -e or --endpoint argument can be omitted if an endpoint is already selected.`,
-		Example: "  gsctl login user@example.com --endpoint api.example.com",
-		PreRun:  loginPreRunOutput,
-		Run:     loginRunOutput,
+		Example: `  gsctl login user@example.com --endpoint api.example.com
+
+  echo "$PASSWORD" | gsctl login ci@example.com --password-stdin -e api.example.com
+
+  gsctl login ci@example.com --token-file /run/secrets/gsctl-token -e api.example.com`,
+		PreRun: loginPreRunOutput,
+		Run:    loginRunOutput,
 	}
 
 	arguments Arguments
@@ -53,18 +77,33 @@ The -e or --endpoint argument can be omitted if an endpoint is already selected.
 
 func init() {
 	Command.Flags().StringVarP(&cmdPassword, "password", "p", "", "Password. If not given, will be prompted interactively.")
+	Command.Flags().BoolVarP(&cmdPasswordStdin, "password-stdin", "", false, "Read the password from standard input.")
+	Command.Flags().StringVarP(&cmdTokenFile, "token-file", "", "", "Read an existing auth token from this file instead of creating one using a password. Use '-' for standard input.")
 	Command.Flags().BoolVarP(&cmdSSO, "sso", "", false, "Authenticate using Single Sign On through our identity provider.")
+	Command.Flags().BoolVarP(&cmdDevice, "device", "", false, "With --sso, use the device authorization flow, which needs no browser on this machine.")
 	Command.Flags().MarkHidden("sso")
+	Command.Flags().MarkHidden("device")
 }
 
 // Arguments is the argument struct for the business function.
-// Note: the absence of 'token', which is available in all other commands,
-// is by design.
+// Note: the --auth-token flag, which is available in all other commands,
+// is not accepted by design. 'token' is read via --token-file only.
 type Arguments struct {
 	apiEndpoint string
 	email       string
 	password    string
+	token       string
+	sso         bool
+	device      bool
 	verbose     bool
+
+	// ssoIssuer is the identity provider used with --sso --device, and
+	// parseIDToken verifies the ID token obtained from it. They default to
+	// deviceauth.DefaultIssuer and oidc.ParseIDToken and are only replaced
+	// in tests, as refresh tokens stored for an endpoint can only be used
+	// with the default issuer.
+	ssoIssuer    string
+	parseIDToken func(rawToken string) (*oidc.IDToken, error)
 }
 
 func collectArguments(positionalArgs []string) Arguments {
@@ -78,6 +117,10 @@ func collectArguments(positionalArgs []string) Arguments {
 		apiEndpoint: endpoint,
 		email:       cmdEmail,
 		password:    cmdPassword,
+		token:       cmdToken,
+		sso:         cmdSSO,
+		device:      cmdDevice,
+		ssoIssuer:   deviceauth.DefaultIssuer,
 		verbose:     flags.Verbose,
 	}
 }
@@ -123,6 +166,18 @@ func loginPreRunOutput(cmd *cobra.Command, positionalArgs []string) {
 		headline = "The '--auth-token' flag cannot be used with the 'gsctl login' command."
 	case errors.IsPasswordArgumentNotApplicableError(err):
 		headline = "The '--password' flag cannot be used with the 'gsctl login --sso' command."
+	case errors.IsConflictingFlagsError(err):
+		headline = "Conflicting flags used"
+		subtext = "Only one of --password, --password-stdin, --token-file and --sso can be used."
+	case errors.IsRequiredFlagMissingError(err):
+		headline = "The '--device' flag can only be used with '--sso'."
+		subtext = "Please execute the command as 'gsctl login --sso --device'."
+	case errors.IsEmptyTokenError(err):
+		headline = "The token cannot be empty."
+		subtext = fmt.Sprintf("The token read via --token-file %s is empty.", cmdTokenFile)
+	case errors.IsTokenFileNotReadableError(err):
+		headline = "The token file could not be read."
+		subtext = err.Error()
 	case errors.IsEmptyPasswordError(err):
 		headline = "The password cannot be empty."
 		subtext = "Please call the command again and enter a non-empty password. See 'gsctl login --help' for details."
@@ -130,11 +185,8 @@ func loginPreRunOutput(cmd *cobra.Command, positionalArgs []string) {
 		headline = err.Error()
 	}
 
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
 }
 
 // verifyLoginPreconditions does the pre-checks and returns an error in case something's wrong.
@@ -147,15 +199,47 @@ func verifyLoginPreconditions(positionalArgs []string) error {
 		return microerror.Mask(errors.TokenArgumentNotApplicableError)
 	}
 
-	if cmdSSO {
-		if cmdPassword != "" {
-			return microerror.Mask(errors.PasswordArgumentNotApplicableError)
+	numMethods := 0
+	for _, used := range []bool{cmdPassword != "", cmdPasswordStdin, cmdTokenFile != "", cmdSSO} {
+		if used {
+			numMethods++
 		}
-	} else {
+	}
+	if cmdSSO && cmdPassword != "" {
+		return microerror.Mask(errors.PasswordArgumentNotApplicableError)
+	}
+	if numMethods > 1 {
+		return microerror.Mask(errors.ConflictingFlagsError)
+	}
+	if cmdDevice && !cmdSSO {
+		return microerror.Maskf(errors.RequiredFlagMissingError, "--sso")
+	}
+
+	if !cmdSSO {
 		if arguments.email == "" {
 			return microerror.Mask(errors.NoEmailArgumentGivenError)
 		}
+	}
 
+	switch {
+	case cmdPasswordStdin:
+		password, err := readSecret(os.Stdin)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if password == "" {
+			return microerror.Mask(errors.EmptyPasswordError)
+		}
+		cmdPassword = password
+
+	case cmdTokenFile != "":
+		token, err := readTokenFile(config.FileSystem, os.Stdin, cmdTokenFile)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		cmdToken = token
+
+	case !cmdSSO:
 		// interactive password prompt
 		if cmdPassword == "" {
 			fmt.Printf("Password for %s on %s: ", color.CyanString(arguments.email), color.CyanString(arguments.apiEndpoint))
@@ -176,8 +260,10 @@ func verifyLoginPreconditions(positionalArgs []string) error {
 func login(loginArgs Arguments) (loginResult, error) {
 	var result loginResult
 	var err error
-	if cmdSSO {
+	if loginArgs.sso {
 		result, err = loginSSO(loginArgs)
+	} else if loginArgs.token != "" {
+		result, err = loginToken(loginArgs)
 	} else {
 		result, err = loginGiantSwarm(loginArgs)
 	}
@@ -192,7 +278,7 @@ func loginRunOutput(cmd *cobra.Command, positionalArgs []string) {
 	result, err := login(arguments)
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline = ""
@@ -202,6 +288,10 @@ func loginRunOutput(cmd *cobra.Command, positionalArgs []string) {
 			headline = "Empty password submitted"
 			subtext = "The API server complains about the password provided."
 			subtext += " Please make sure to provide a string with more than white space characters."
+		case errors.IsInvalidCredentialsError(err) && arguments.token != "":
+			headline = "Invalid token"
+			subtext = fmt.Sprintf("The token read from %s was not accepted by %s.", cmdTokenFile, color.CyanString(arguments.apiEndpoint))
+			subtext += " It may have expired or been deleted."
 		case errors.IsInvalidCredentialsError(err):
 			headline = "Bad password or email address"
 			subtext = fmt.Sprintf("Could not log you in to %s.", color.CyanString(arguments.apiEndpoint))
@@ -225,11 +315,8 @@ func loginRunOutput(cmd *cobra.Command, positionalArgs []string) {
 			headline = err.Error()
 		}
 
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	if result.loggedOutBefore && arguments.verbose {
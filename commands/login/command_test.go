@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/giantswarm/gscliauth/config"
@@ -11,7 +12,10 @@ import (
 	"github.com/spf13/afero"
 
 	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/deviceauth"
 	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeoidc"
 )
 
 // regularInfoResponse is a JSON snippet we use in several test cases
@@ -213,3 +217,108 @@ func Test_LoginInactiveAccount(t *testing.T) {
 		t.Errorf("Expected 'ACCOUNT_EXPIRED', got %#v", origErr.Payload.Code)
 	}
 }
+
+// Test_LoginSSODevice simulates an SSO login via the device authorization
+// flow against a fake identity provider.
+func Test_LoginSSODevice(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	idp, err := fakeoidc.New(fakeoidc.Config{
+		ClientID: deviceauth.DefaultClientID,
+		Email:    "sso-user@example.com",
+		Interval: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	idpServer := httptest.NewServer(idp)
+	defer idpServer.Close()
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			t.Errorf("Expected Bearer Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(regularInfoResponse)
+	}))
+	defer mockServer.Close()
+
+	args := Arguments{
+		apiEndpoint:  mockServer.URL,
+		sso:          true,
+		device:       true,
+		ssoIssuer:    idpServer.URL,
+		parseIDToken: idp.ParseIDToken,
+	}
+
+	var result loginResult
+	output := testutils.CaptureOutput(func() {
+		result, err = login(args)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if !strings.Contains(output, fakeoidc.DefaultUserCode) || !strings.Contains(output, idpServer.URL+fakeoidc.VerificationPath) {
+		t.Errorf("Expected user code and verification URL in output, got %q", output)
+	}
+	if result.email != "sso-user@example.com" {
+		t.Errorf("Expected email 'sso-user@example.com', got %q", result.email)
+	}
+	if result.alias != "codename" {
+		t.Errorf("Expected alias 'codename', got %q", result.alias)
+	}
+
+	endpointConfig := config.Config.EndpointConfig(mockServer.URL)
+	if endpointConfig.Scheme != "Bearer" {
+		t.Errorf("Expected scheme 'Bearer', got %q", endpointConfig.Scheme)
+	}
+	if endpointConfig.Token != result.token || endpointConfig.Token == "" {
+		t.Errorf("Expected access token to be stored, got %q", endpointConfig.Token)
+	}
+	if endpointConfig.RefreshToken == "" {
+		t.Error("Expected refresh token to be stored")
+	}
+	if endpointConfig.Email != "sso-user@example.com" {
+		t.Errorf("Expected email 'sso-user@example.com' in config, got %q", endpointConfig.Email)
+	}
+}
+
+// Test_LoginSSODeviceDenied simulates the user denying the device
+// authorization request.
+func Test_LoginSSODeviceDenied(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	idp, err := fakeoidc.New(fakeoidc.Config{ClientID: deviceauth.DefaultClientID, Deny: true, Interval: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	idpServer := httptest.NewServer(idp)
+	defer idpServer.Close()
+
+	args := Arguments{
+		apiEndpoint:  "https://api.example.com",
+		sso:          true,
+		device:       true,
+		ssoIssuer:    idpServer.URL,
+		parseIDToken: idp.ParseIDToken,
+	}
+
+	testutils.CaptureOutput(func() {
+		_, err = login(args)
+	})
+	if !errors.IsSSOError(err) {
+		t.Errorf("Expected SSOError, got %#v", err)
+	}
+	if config.Config.NumEndpoints() != 0 {
+		t.Errorf("Expected no endpoints to be stored, got %d", config.Config.NumEndpoints())
+	}
+}
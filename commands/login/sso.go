@@ -12,6 +12,7 @@ import (
 
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/deviceauth"
 )
 
 func init() {
@@ -19,7 +20,9 @@ func init() {
 }
 
 func loginSSO(args Arguments) (loginResult, error) {
-	numEndpointsBefore := config.Config.NumEndpoints()
+	if args.device {
+		return loginSSODevice(args)
+	}
 
 	pkceResponse, err := oidc.RunPKCE(args.apiEndpoint)
 	if err != nil {
@@ -35,8 +38,60 @@ func loginSSO(args Arguments) (loginResult, error) {
 		return loginResult{}, microerror.Mask(err)
 	}
 
+	return storeSSOAuth(args, idToken.Email, pkceResponse.AccessToken, pkceResponse.RefreshToken)
+}
+
+// loginSSODevice runs the OAuth2 Device Authorization Grant, which works
+// without a browser and local callback server on this machine.
+func loginSSODevice(args Arguments) (loginResult, error) {
+	flow, err := deviceauth.New(deviceauth.Config{
+		Issuer:   args.ssoIssuer,
+		Audience: args.apiEndpoint,
+	})
+	if err != nil {
+		return loginResult{}, microerror.Maskf(errors.SSOError, err.Error())
+	}
+
+	authorization, err := flow.Authorize()
+	if err != nil {
+		if args.verbose {
+			fmt.Println(color.WhiteString("Attempt to start the OAuth2 device authorization workflow failed."))
+		}
+		return loginResult{}, microerror.Maskf(errors.SSOError, err.Error())
+	}
+
+	fmt.Println(color.YellowString("\nTo sign in, open this URL in a browser on any device:"))
+	fmt.Printf("\n    %s\n\n", authorization.VerificationURI)
+	fmt.Printf("and enter the code %s\n\n", color.CyanString(authorization.UserCode))
+	if authorization.VerificationURIComplete != "" {
+		fmt.Printf("Alternatively, open this URL, which already contains the code:\n\n    %s\n\n", authorization.VerificationURIComplete)
+	}
+	fmt.Println("Waiting for you to complete the sign in...")
+
+	token, err := flow.Poll(authorization)
+	if err != nil {
+		return loginResult{}, microerror.Maskf(errors.SSOError, err.Error())
+	}
+
+	parseIDToken := args.parseIDToken
+	if parseIDToken == nil {
+		parseIDToken = oidc.ParseIDToken
+	}
+	idToken, err := parseIDToken(token.IDToken)
+	if err != nil {
+		return loginResult{}, microerror.Mask(err)
+	}
+
+	return storeSSOAuth(args, idToken.Email, token.AccessToken, token.RefreshToken)
+}
+
+// storeSSOAuth checks the access token obtained via SSO against the API and
+// stores it in the config file, together with the refresh token.
+func storeSSOAuth(args Arguments, email, accessToken, refreshToken string) (loginResult, error) {
+	numEndpointsBefore := config.Config.NumEndpoints()
+
 	// Check if the access token works by fetching the installation's name.
-	installationInfo, err := getInstallationInfo(args.apiEndpoint, "Bearer", pkceResponse.AccessToken)
+	installationInfo, err := getInstallationInfo(args.apiEndpoint, "Bearer", accessToken)
 	if err != nil {
 		if args.verbose {
 			fmt.Println(color.WhiteString("Attempt to use new token against the API failed."))
@@ -59,7 +114,7 @@ func loginSSO(args Arguments) (loginResult, error) {
 	}
 
 	// Store the token in the config file.
-	if err := config.Config.StoreEndpointAuth(args.apiEndpoint, installationInfo.InstallationName, installationInfo.Provider, idToken.Email, "Bearer", pkceResponse.AccessToken, pkceResponse.RefreshToken); err != nil {
+	if err := config.Config.StoreEndpointAuth(args.apiEndpoint, installationInfo.InstallationName, installationInfo.Provider, email, "Bearer", accessToken, refreshToken); err != nil {
 		if args.verbose {
 			fmt.Println(color.WhiteString("Attempt to store our authentication data with the endpoint in the configuration failed."))
 			fmt.Println(color.WhiteString("Error details: %s", err.Error()))
@@ -72,12 +127,12 @@ func loginSSO(args Arguments) (loginResult, error) {
 
 	result := loginResult{
 		apiEndpoint:        args.apiEndpoint,
-		email:              idToken.Email,
+		email:              email,
 		endpointSwitched:   (config.Config.SelectedEndpoint != args.apiEndpoint),
 		loggedOutBefore:    false,
 		alias:              installationInfo.InstallationName,
 		provider:           installationInfo.Provider,
-		token:              pkceResponse.AccessToken,
+		token:              accessToken,
 		numEndpointsBefore: numEndpointsBefore,
 		numEndpointsAfter:  config.Config.NumEndpoints(),
 	}
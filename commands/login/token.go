@@ -0,0 +1,86 @@
+package login
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+)
+
+// readSecret reads a password or token passed via standard input. Like
+// 'docker login --password-stdin', everything up to the end of input is
+// used, except for the final line break.
+func readSecret(r io.Reader) (string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// readTokenFile reads an auth token from the given file, or from stdin if the
+// path is '-'. Surrounding white space is removed.
+func readTokenFile(fs afero.Fs, stdin io.Reader, path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(stdin)
+	} else {
+		data, err = afero.ReadFile(fs, path)
+	}
+	if err != nil {
+		return "", microerror.Maskf(errors.TokenFileNotReadableError, err.Error())
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", microerror.Mask(errors.EmptyTokenError)
+	}
+
+	return token, nil
+}
+
+// loginToken stores an existing auth token, e. g. one issued for a service
+// account, after checking it against the API.
+func loginToken(args Arguments) (loginResult, error) {
+	result := loginResult{
+		apiEndpoint:        args.apiEndpoint,
+		email:              args.email,
+		endpointSwitched:   config.Config.SelectedEndpoint != args.apiEndpoint,
+		token:              args.token,
+		numEndpointsBefore: config.Config.NumEndpoints(),
+	}
+
+	if args.verbose {
+		fmt.Println(color.WhiteString("Checking the token by fetching installation details"))
+	}
+
+	installationInfo, err := getInstallationInfo(args.apiEndpoint, "giantswarm", args.token)
+	if clienterror.IsUnauthorizedError(err) {
+		return result, microerror.Mask(errors.InvalidCredentialsError)
+	} else if err != nil {
+		return result, microerror.Mask(err)
+	}
+	result.alias = installationInfo.InstallationName
+	result.provider = installationInfo.Provider
+
+	if err := config.Config.StoreEndpointAuth(args.apiEndpoint, result.alias, result.provider, args.email, "giantswarm", args.token, ""); err != nil {
+		return result, microerror.Mask(err)
+	}
+	if err := config.Config.SelectEndpoint(args.apiEndpoint); err != nil {
+		return result, microerror.Mask(err)
+	}
+
+	result.numEndpointsAfter = config.Config.NumEndpoints()
+
+	return result, nil
+}
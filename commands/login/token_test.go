@@ -0,0 +1,152 @@
+package login
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// TestReadSecret tests reading a password from standard input.
+func TestReadSecret(t *testing.T) {
+	var testCases = []struct {
+		input    string
+		expected string
+	}{
+		{"secret\n", "secret"},
+		{"secret\r\n", "secret"},
+		{"secret", "secret"},
+		{" secret with spaces \n", " secret with spaces "},
+		{"\n", ""},
+		{"", ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			secret, err := readSecret(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("Unexpected error: %#v", err)
+			}
+			if secret != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, secret)
+			}
+		})
+	}
+}
+
+// TestReadTokenFile tests reading a token from a file or standard input.
+func TestReadTokenFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/secrets/token", []byte("  my-token\n"), 0600)
+	afero.WriteFile(fs, "/secrets/empty", []byte("\n"), 0600)
+
+	var testCases = []struct {
+		path         string
+		stdin        string
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{"/secrets/token", "", "my-token", nil},
+		{"-", "stdin-token\n", "stdin-token", nil},
+		{"/secrets/empty", "", "", errors.IsEmptyTokenError},
+		{"-", "", "", errors.IsEmptyTokenError},
+		{"/secrets/missing", "", "", errors.IsTokenFileNotReadableError},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			token, err := readTokenFile(fs, strings.NewReader(tc.stdin), tc.path)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Unexpected error: %#v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %#v", err)
+			}
+			if token != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, token)
+			}
+		})
+	}
+}
+
+// Test_LoginValidToken simulates a login with an existing token.
+func Test_LoginValidToken(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "giantswarm service-account-token" {
+			t.Errorf("Unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(regularInfoResponse)
+	}))
+	defer mockServer.Close()
+
+	args := Arguments{
+		apiEndpoint: mockServer.URL,
+		email:       "ci@example.com",
+		token:       "service-account-token",
+	}
+
+	result, err := login(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if result.alias != "codename" {
+		t.Errorf("Expected alias 'codename', got %q", result.alias)
+	}
+	if result.numEndpointsAfter != 1 {
+		t.Error("Expected result.numEndpointsAfter to be 1, got", result.numEndpointsAfter)
+	}
+	if config.Config.Token != "service-account-token" {
+		t.Errorf("Expected config token 'service-account-token', got %q", config.Config.Token)
+	}
+	if config.Config.Email != "ci@example.com" {
+		t.Errorf("Expected config email 'ci@example.com', got %q", config.Config.Email)
+	}
+}
+
+// Test_LoginInvalidToken simulates a login with a token the API rejects.
+func Test_LoginInvalidToken(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code": "PERMISSION_DENIED", "message": "Lorem ipsum"}`))
+	}))
+	defer mockServer.Close()
+
+	args := Arguments{
+		apiEndpoint: mockServer.URL,
+		email:       "ci@example.com",
+		token:       "expired-token",
+	}
+
+	_, err = login(args)
+	if !errors.IsInvalidCredentialsError(err) {
+		t.Errorf("Expected InvalidCredentialsError, got %#v", err)
+	}
+	if config.Config.NumEndpoints() != 0 {
+		t.Errorf("Expected no endpoints to be stored, got %d", config.Config.NumEndpoints())
+	}
+}
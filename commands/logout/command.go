@@ -79,12 +79,12 @@ func printResult(cmd *cobra.Command, extraArgs []string) {
 			os.Exit(0)
 		}
 
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		// handle non-common errors
-		fmt.Println(color.RedString(err.Error()))
-		os.Exit(1)
+		errors.PrintError(err, err.Error(), "")
+		errors.Exit(err)
 	}
 
 	fmt.Printf("You have logged out from endpoint %s.\n", color.CyanString(logoutArgs.apiEndpoint))
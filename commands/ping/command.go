@@ -57,8 +57,8 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 	errors.HandleCommonErrors(err)
 
 	// handle non-common errors
-	fmt.Println(color.RedString(err.Error()))
-	os.Exit(1)
+	errors.PrintError(err, err.Error(), "")
+	errors.Exit(err)
 }
 
 func verifyPreconditions(args Arguments, cmdLineArgs []string) error {
@@ -79,9 +79,8 @@ func runCommand(cmd *cobra.Command, cmdLineArgs []string) {
 
 		errors.HandleCommonErrors(err)
 
-		fmt.Println(color.RedString("Could not reach API"))
-		fmt.Println(err.Error())
-		os.Exit(1)
+		errors.PrintError(err, "Could not reach API", err.Error())
+		errors.Exit(err)
 	}
 
 	fmt.Println(color.GreenString("API connection is fine"))
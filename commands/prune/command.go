@@ -0,0 +1,21 @@
+// Package prune holds the 'prune *' sub-commands.
+package prune
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/prune/kubeconfigs"
+)
+
+var (
+	// Command is the command to clean up local leftovers.
+	Command = &cobra.Command{
+		Use:   "prune",
+		Short: "Clean up local settings",
+		Long:  `Remove local settings which are no longer needed.`,
+	}
+)
+
+func init() {
+	Command.AddCommand(kubeconfigs.Command)
+}
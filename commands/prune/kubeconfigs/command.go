@@ -0,0 +1,398 @@
+// Package kubeconfigs implements the 'prune kubeconfigs' command.
+package kubeconfigs
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/execcredential"
+	"github.com/giantswarm/gsctl/pkg/kubeconfigfile"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl prune kubeconfigs'
+	Command = &cobra.Command{
+		Use:   "kubeconfigs",
+		Short: "Remove kubectl settings which are no longer usable",
+		Long: `Removes kubectl context, cluster and user entries created by
+'gsctl create kubeconfig' which can no longer be used, and deletes
+certificate and key files in the "certs" subfolder of the gsctl config
+directory which are no longer referenced, as well as credentials cached
+for 'gsctl kubeconfig-credential' for clusters without kubectl settings.
+
+Entries are removed for
+
+- clusters which don't exist on any of the endpoints you are logged in to,
+- clusters whose client certificate has expired.
+
+All endpoints you are logged in to are queried. If one of them can't be
+reached, nothing is removed. Files are considered as referenced if a kubectl
+config file given via $KUBECONFIG, or $HOME/.kube/config, references them.
+
+Examples:
+
+  gsctl prune kubeconfigs --dry-run
+
+  gsctl prune kubeconfigs --force
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "prune-kubeconfigs"
+
+	reasonClusterNotFound = "cluster not found"
+	reasonExpired         = "certificate expired"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().BoolVarP(&flags.DryRun, "dry-run", "", false, "Only list what would be removed.")
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	certsDirPath   string
+	credentialsDir string
+	dryRun         bool
+	// endpoints are the URLs of the endpoints we have a token for.
+	endpoints       []string
+	fileSystem      afero.Fs
+	force           bool
+	kubeconfigPaths []string
+}
+
+func collectArguments() Arguments {
+	var endpoints []string
+	for _, endpoint := range config.Config.Endpoints() {
+		if e := config.Config.EndpointConfig(endpoint); e != nil && e.Token != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	sort.Strings(endpoints)
+
+	return Arguments{
+		certsDirPath:    config.CertsDirPath,
+		credentialsDir:  path.Join(config.ConfigDirPath, execcredential.DirName),
+		dryRun:          flags.DryRun,
+		endpoints:       endpoints,
+		fileSystem:      config.FileSystem,
+		force:           flags.Force,
+		kubeconfigPaths: kubeconfigfile.Paths(os.Getenv("KUBECONFIG"), config.HomeDirPath),
+	}
+}
+
+// prunedCluster is a cluster whose entries are removed.
+type prunedCluster struct {
+	entries *kubeconfigfile.ClusterEntries
+	reason  string
+}
+
+// pruneResult is what gets removed.
+type pruneResult struct {
+	clusters []prunedCluster
+	// files are the credential files no longer referenced.
+	files []string
+}
+
+func verifyPreconditions(args Arguments) error {
+	if len(args.endpoints) == 0 {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	errors.Exit(err)
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	err := run(arguments)
+	if err != nil {
+		handleError(err)
+		errors.Exit(err)
+	}
+}
+
+// run finds what to prune, asks for confirmation and applies the changes.
+func run(args Arguments) error {
+	k, err := kubeconfigfile.Open(kubeconfigfile.Config{
+		FileSystem: args.fileSystem,
+		Paths:      args.kubeconfigPaths,
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	defer k.Close()
+
+	var clusterIDs map[string]bool
+	if len(kubeconfigfile.FindClusterEntries(k.Merged())) > 0 {
+		clusterIDs, err = fetchClusterIDs(args.endpoints)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	r, err := prune(k, args, clusterIDs, time.Now())
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(r.clusters) == 0 && len(r.files) == 0 {
+		fmt.Println(color.GreenString("There is nothing to prune."))
+		return nil
+	}
+
+	fmt.Println(formatResult(r))
+
+	if args.dryRun {
+		fmt.Println(color.YellowString("\nThis was a dry run, nothing has been changed."))
+		return nil
+	}
+
+	if !args.force {
+		fmt.Println()
+		if !confirm.Ask("Do you want to remove these settings and files?") {
+			fmt.Println(color.GreenString("Aborted."))
+			return nil
+		}
+	}
+
+	err = apply(k, args, r)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	fmt.Println(color.GreenString("\nThe kubectl settings have been pruned."))
+
+	return nil
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case IsEndpointsFailed(err):
+		headline = "Not all endpoints could be queried"
+		subtext = err.Error()
+		subtext += "\nTo make sure only settings for deleted clusters are removed, nothing has been changed."
+	case kubeconfigfile.IsInvalidKubeconfig(err):
+		headline = "Error: The kubectl config could not be parsed"
+		subtext = fmt.Sprintf("Details: %s\nPlease fix or remove the file.", err.Error())
+	case kubeconfigfile.IsLocked(err):
+		headline = "Error: The kubectl config is in use"
+		subtext = err.Error()
+	case kubeconfigfile.IsWrite(err):
+		headline = "Error: The kubectl config could not be written"
+		subtext = fmt.Sprintf("Details: %s", err.Error())
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
+
+// fetchClusterIDs returns the IDs of all clusters on the given endpoints,
+// queried in parallel. It fails if any endpoint can't be queried.
+func fetchClusterIDs(endpoints []string) (map[string]bool, error) {
+	type endpointResult struct {
+		ids []string
+		err error
+	}
+	results := make([]endpointResult, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint string, r *endpointResult) {
+			defer wg.Done()
+
+			clientWrapper, err := client.NewForEndpoint(endpoint)
+			if err != nil {
+				r.err = microerror.Mask(err)
+				return
+			}
+
+			auxParams := clientWrapper.DefaultAuxiliaryParams()
+			auxParams.ActivityName = activityName
+
+			response, err := clientWrapper.GetClusters(auxParams)
+			if clienterror.IsUnauthorizedError(err) {
+				r.err = microerror.Mask(errors.NotAuthorizedError)
+				return
+			} else if err != nil {
+				r.err = microerror.Mask(err)
+				return
+			}
+
+			for _, c := range response.Payload {
+				r.ids = append(r.ids, c.ID)
+			}
+		}(endpoint, &results[i])
+	}
+	wg.Wait()
+
+	clusterIDs := map[string]bool{}
+	var failed []string
+	for i, r := range results {
+		if r.err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", endpoints[i], r.err.Error()))
+			continue
+		}
+		for _, id := range r.ids {
+			clusterIDs[id] = true
+		}
+	}
+	if len(failed) > 0 {
+		return nil, microerror.Maskf(endpointsFailedError, strings.Join(failed, "\n"))
+	}
+
+	return clusterIDs, nil
+}
+
+// prune removes the entries of clusters which don't exist in clusterIDs or
+// whose client certificate expired before now, and finds the credential
+// files no longer referenced afterwards. Changes are made in memory only,
+// see apply.
+func prune(k *kubeconfigfile.Kubeconfig, args Arguments, clusterIDs map[string]bool, now time.Time) (pruneResult, error) {
+	r := pruneResult{}
+
+	for _, e := range kubeconfigfile.FindClusterEntries(k.Merged()) {
+		reason := ""
+		if !clusterIDs[e.ClusterID] {
+			reason = reasonClusterNotFound
+		} else if e.AuthInfoName != "" {
+			authInfo, f := k.AuthInfo(e.AuthInfoName)
+			// Users without a readable client certificate, e. g. using a
+			// token, are left alone.
+			cert, err := kubeconfigfile.ClientCertificate(args.fileSystem, f.Path, authInfo)
+			if err == nil && now.After(cert.NotAfter) {
+				reason = reasonExpired
+			}
+		}
+
+		if reason != "" {
+			k.DeleteClusterEntries(e)
+			r.clusters = append(r.clusters, prunedCluster{entries: e, reason: reason})
+		}
+	}
+
+	files, err := util.CredentialFiles(args.fileSystem, args.certsDirPath, "")
+	if err != nil {
+		return r, microerror.Mask(err)
+	}
+	referenced := kubeconfigfile.ReferencedFiles(k.Files())
+	for _, f := range files {
+		if !referenced[f] {
+			r.files = append(r.files, f)
+		}
+	}
+
+	// Cached credentials are kept as long as there are entries for the
+	// cluster.
+	cachedFiles, err := execcredential.Files(args.fileSystem, args.credentialsDir, "")
+	if err != nil {
+		return r, microerror.Mask(err)
+	}
+	remaining := map[string]bool{}
+	for _, e := range kubeconfigfile.FindClusterEntries(k.Merged()) {
+		remaining[e.ClusterID] = true
+	}
+	for _, f := range cachedFiles {
+		if !remaining[execcredential.FileClusterID(f)] {
+			r.files = append(r.files, f)
+		}
+	}
+
+	return r, nil
+}
+
+// apply writes the kubeconfig changes made by prune and deletes the files.
+func apply(k *kubeconfigfile.Kubeconfig, args Arguments, r pruneResult) error {
+	err := k.Save()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, f := range r.files {
+		err = args.fileSystem.Remove(f)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// formatResult lists the clusters and files to remove.
+func formatResult(r pruneResult) string {
+	var out []string
+
+	if len(r.clusters) > 0 {
+		rows := []string{strings.Join([]string{
+			color.CyanString("CLUSTER ID"),
+			color.CyanString("CONTEXTS"),
+			color.CyanString("REASON"),
+		}, "|")}
+		for _, c := range r.clusters {
+			contexts := "n/a"
+			if len(c.entries.ContextNames) > 0 {
+				contexts = strings.Join(c.entries.ContextNames, ", ")
+			}
+			rows = append(rows, strings.Join([]string{c.entries.ClusterID, contexts, c.reason}, "|"))
+		}
+		out = append(out, "kubectl settings to remove:\n", columnize.SimpleFormat(rows))
+	}
+
+	if len(r.files) > 0 {
+		if len(out) > 0 {
+			out = append(out, "")
+		}
+		out = append(out, "Files no longer referenced:\n")
+		for _, f := range r.files {
+			out = append(out, "  "+f)
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
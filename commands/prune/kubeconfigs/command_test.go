@@ -0,0 +1,211 @@
+package kubeconfigs
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/kubeconfigfile"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// TestPrune tests which entries and files are removed.
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	expired := base64.StdEncoding.EncodeToString([]byte(testutils.CertificatePEM(t, now.Add(-time.Hour))))
+
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/certs/abc12-ca.crt", []byte("ca"), 0600)
+	_ = afero.WriteFile(fs, "/certs/abc12-a1b2c3d4e5-client.crt", []byte(testutils.CertificatePEM(t, now.Add(time.Hour))), 0600)
+	_ = afero.WriteFile(fs, "/certs/abc12-a1b2c3d4e5-client.key", []byte("key"), 0600)
+	// Left over from a previous rotation.
+	_ = afero.WriteFile(fs, "/certs/abc12-0000000000-client.crt", []byte("old"), 0600)
+	_ = afero.WriteFile(fs, "/certs/gh567-ca.crt", []byte("ca"), 0600)
+	// Cached by 'gsctl kubeconfig-credential'.
+	_ = afero.WriteFile(fs, "/credentials/abc12-0a1b2c3d.json", []byte("{}"), 0600)
+	_ = afero.WriteFile(fs, "/credentials/gh567-0a1b2c3d.json", []byte("{}"), 0600)
+
+	kubeconfigYAML := `apiVersion: v1
+kind: Config
+current-context: giantswarm-gh567
+clusters:
+- name: giantswarm-abc12
+  cluster:
+    server: https://api.abc12.example.com
+    certificate-authority: /certs/abc12-ca.crt
+- name: giantswarm-def34
+  cluster:
+    server: https://api.def34.example.com
+- name: giantswarm-gh567
+  cluster:
+    server: https://api.gh567.example.com
+    certificate-authority: /certs/gh567-ca.crt
+- name: other
+  cluster:
+    server: https://other.example.com
+contexts:
+- name: giantswarm-abc12
+  context:
+    cluster: giantswarm-abc12
+    user: giantswarm-abc12-user
+- name: giantswarm-def34
+  context:
+    cluster: giantswarm-def34
+    user: giantswarm-def34-user
+- name: giantswarm-gh567
+  context:
+    cluster: giantswarm-gh567
+    user: giantswarm-gh567-user
+- name: other
+  context:
+    cluster: other
+    user: other
+users:
+- name: giantswarm-abc12-user
+  user:
+    client-certificate: /certs/abc12-a1b2c3d4e5-client.crt
+    client-key: /certs/abc12-a1b2c3d4e5-client.key
+- name: giantswarm-def34-user
+  user:
+    client-certificate-data: ` + expired + `
+    client-key-data: a2V5
+- name: giantswarm-gh567-user
+  user:
+    token: token
+- name: other
+  user:
+    token: token
+`
+	_ = afero.WriteFile(fs, "/home/.kube/config", []byte(kubeconfigYAML), 0600)
+
+	args := Arguments{
+		certsDirPath:    "/certs",
+		credentialsDir:  "/credentials",
+		fileSystem:      fs,
+		kubeconfigPaths: []string{"/home/.kube/config"},
+	}
+
+	k, err := kubeconfigfile.Open(kubeconfigfile.Config{FileSystem: fs, Paths: args.kubeconfigPaths})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer k.Close()
+
+	r, err := prune(k, args, map[string]bool{"abc12": true, "def34": true}, now)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	var pruned []string
+	for _, c := range r.clusters {
+		pruned = append(pruned, c.entries.ClusterID+": "+c.reason)
+	}
+	if strings.Join(pruned, ", ") != "def34: certificate expired, gh567: cluster not found" {
+		t.Errorf("Unexpected clusters %v", pruned)
+	}
+	if strings.Join(r.files, ",") != "/certs/abc12-0000000000-client.crt,/certs/gh567-ca.crt,/credentials/gh567-0a1b2c3d.json" {
+		t.Errorf("Unexpected files %v", r.files)
+	}
+
+	out := formatResult(r)
+	if !strings.Contains(out, "giantswarm-gh567") || !strings.Contains(out, "/certs/gh567-ca.crt") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	// Nothing changes before apply.
+	content, _ := afero.ReadFile(fs, "/home/.kube/config")
+	if string(content) != kubeconfigYAML {
+		t.Error("Expected kubeconfig not to be modified yet")
+	}
+
+	err = apply(k, args, r)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	content, _ = afero.ReadFile(fs, "/home/.kube/config")
+	for _, s := range []string{"def34", "gh567", "current-context: giantswarm"} {
+		if strings.Contains(string(content), s) {
+			t.Errorf("Expected %q to be removed:\n%s", s, content)
+		}
+	}
+	for _, s := range []string{"giantswarm-abc12-user", "name: other"} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("Expected %q to be kept:\n%s", s, content)
+		}
+	}
+	for _, f := range r.files {
+		if exists, _ := afero.Exists(fs, f); exists {
+			t.Errorf("Expected %s to be deleted", f)
+		}
+	}
+	for _, f := range []string{"/certs/abc12-a1b2c3d4e5-client.key", "/credentials/abc12-0a1b2c3d.json"} {
+		if exists, _ := afero.Exists(fs, f); !exists {
+			t.Errorf("Expected %s to be kept", f)
+		}
+	}
+}
+
+// TestFetchClusterIDs tests that clusters from all endpoints are combined
+// and that failing endpoints make the whole operation fail.
+func TestFetchClusterIDs(t *testing.T) {
+	makeServer := func(body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if body == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"code": "PERMISSION_DENIED", "message": "no"}`))
+				return
+			}
+			w.Write([]byte(body))
+		}))
+	}
+	serverA := makeServer(`[{"id": "abc12"}, {"id": "def34"}]`)
+	defer serverA.Close()
+	serverB := makeServer(`[{"id": "gh567"}]`)
+	defer serverB.Close()
+	serverC := makeServer("")
+	defer serverC.Close()
+
+	configYAML := `endpoints:
+  ` + serverA.URL + `:
+    email: email@example.com
+    token: token-a
+  ` + serverB.URL + `:
+    email: email@example.com
+    token: token-b
+  ` + serverC.URL + `:
+    email: email@example.com
+    token: token-c
+selected_endpoint: ` + serverA.URL + `
+`
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, configYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusterIDs, err := fetchClusterIDs([]string{serverA.URL, serverB.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if len(clusterIDs) != 3 || !clusterIDs["gh567"] {
+		t.Errorf("Unexpected cluster IDs %v", clusterIDs)
+	}
+
+	_, err = fetchClusterIDs([]string{serverA.URL, serverC.URL})
+	if !IsEndpointsFailed(err) || !strings.Contains(err.Error(), serverC.URL) {
+		t.Errorf("Expected endpointsFailedError, got %#v", err)
+	}
+
+	err = verifyPreconditions(Arguments{})
+	if !errors.IsNotLoggedInError(err) {
+		t.Errorf("Expected NotLoggedInError, got %#v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package kubeconfigs
+
+import "github.com/giantswarm/microerror"
+
+// endpointsFailedError is used when the clusters of at least one endpoint
+// could not be listed.
+var endpointsFailedError = &microerror.Error{
+	Kind: "endpointsFailedError",
+}
+
+// IsEndpointsFailed asserts endpointsFailedError.
+func IsEndpointsFailed(err error) bool {
+	return microerror.Cause(err) == endpointsFailedError
+}
@@ -0,0 +1,421 @@
+// Package capacity implements the 'report capacity' command.
+package capacity
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/capacity"
+	"github.com/giantswarm/gsctl/pkg/output"
+)
+
+var (
+	// Command is the cobra command for 'gsctl report capacity'
+	Command = &cobra.Command{
+		Use:   "capacity",
+		Short: "Report worker capacity and cost",
+		Long: `Report the worker node capacity of all clusters and node pools.
+
+For each node pool, or each cluster without node pools, the number of worker
+nodes, CPU cores and memory are shown. Nodes are split into on-demand and
+spot instances according to the node pool's instance distribution (AWS) or
+spot instance setting (Azure). Totals are given per organization and
+overall.
+
+Cost is calculated if a price sheet is given via --price-sheet. This is a
+YAML file with the hourly price of one node per instance type or VM size:
+
+  currency: USD
+  instance_types:
+    m5.xlarge:
+      on_demand: 0.192
+      spot: 0.07
+    Standard_D4s_v3:
+      on_demand: 0.192
+
+If no spot price is given, spot instances are priced like on-demand
+instances. Monthly cost is based on 730 hours.
+
+Examples:
+
+  gsctl report capacity
+
+  gsctl report capacity --organization acme --price-sheet prices.yaml
+
+  gsctl report capacity --output csv > capacity.csv
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "report-capacity"
+
+	// formatCSV prints one line per node pool as comma-separated values.
+	formatCSV = "csv"
+
+	outputFlagUsage = "Output format. One of: table, csv, json, yaml."
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.OrganizationID, "organization", "", "", "Only report clusters owned by this organization.")
+	Command.Flags().StringVarP(&flags.PriceSheet, "price-sheet", "", "", "Path to a YAML file with prices per instance type.")
+	Command.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, outputFlagUsage)
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint       string
+	authToken         string
+	fileSystem        afero.Fs
+	organization      string
+	outputFormat      string
+	priceSheet        string
+	userProvidedToken string
+	verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments() Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		apiEndpoint:       endpoint,
+		authToken:         token,
+		fileSystem:        config.FileSystem,
+		organization:      flags.OrganizationID,
+		outputFormat:      flags.OutputFormat,
+		priceSheet:        flags.PriceSheet,
+		userProvidedToken: flags.Token,
+		verbose:           flags.Verbose,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.authToken == "" && args.userProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.outputFormat != formatCSV {
+		if _, err := output.NewPrinter(args.outputFormat); err != nil {
+			return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	errors.Exit(err)
+}
+
+// reportCapacity walks all clusters and their node pools and computes the
+// report.
+func reportCapacity(args Arguments) (*capacity.Report, error) {
+	var prices *capacity.PriceSheet
+	if args.priceSheet != "" {
+		var err error
+		prices, err = capacity.ReadPriceSheet(args.fileSystem, args.priceSheet)
+		if err != nil {
+			return nil, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
+		}
+	}
+
+	calculator, err := capacity.New(capacity.Config{Prices: prices})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	// Informational messages must not end up in structured output.
+	notices := output.NoticeWriter(args.outputFormat)
+
+	clustersResponse, err := clientWrapper.GetClusters(auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	nodePools := []*capacity.NodePool{}
+
+	for _, cluster := range clustersResponse.Payload {
+		if cluster.DeleteDate != nil {
+			continue
+		}
+		if args.organization != "" && cluster.Owner != args.organization {
+			continue
+		}
+
+		if args.verbose {
+			fmt.Fprintln(notices, color.WhiteString("Fetching node pools of cluster %s", cluster.ID))
+		}
+		nodePoolsResponse, err := clientWrapper.GetNodePools(cluster.ID, auxParams)
+		if err == nil {
+			for _, np := range nodePoolsResponse.Payload {
+				nodePools = append(nodePools, calculator.NodePool(cluster, np))
+			}
+			continue
+		}
+
+		// Clusters without node pools respond with 400 or 404.
+		if !clienterror.IsBadRequestError(err) && !clienterror.IsNotFoundError(err) {
+			return nil, microerror.Mask(err)
+		}
+
+		if args.verbose {
+			fmt.Fprintln(notices, color.WhiteString("Fetching details of cluster %s", cluster.ID))
+		}
+		detailsResponse, err := clientWrapper.GetClusterV4(cluster.ID, auxParams)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		nodePools = append(nodePools, calculator.Cluster(cluster, detailsResponse.Payload))
+	}
+
+	return calculator.Summarize(nodePools), nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	report, err := reportCapacity(arguments)
+	if err != nil {
+		handleError(err)
+		errors.Exit(err)
+	}
+
+	switch {
+	case arguments.outputFormat == formatCSV:
+		err = printCSV(os.Stdout, report)
+	case output.IsTableFormat(arguments.outputFormat):
+		printTable(os.Stdout, report)
+	default:
+		var printer *output.Printer
+		printer, err = output.NewPrinter(arguments.outputFormat)
+		if err == nil {
+			err = printer.Print(os.Stdout, report, ".organization")
+		}
+	}
+
+	if err != nil {
+		handleError(microerror.Mask(err))
+		errors.Exit(err)
+	}
+}
+
+// printTable prints node pools and totals as tables.
+func printTable(w io.Writer, report *capacity.Report) {
+	if len(report.NodePools) == 0 {
+		fmt.Fprintln(w, color.YellowString("No clusters found."))
+		return
+	}
+
+	rows := []string{strings.Join([]string{
+		color.CyanString("ORGANIZATION"),
+		color.CyanString("CLUSTER"),
+		color.CyanString("NODE POOL"),
+		color.CyanString("INSTANCE TYPE"),
+		color.CyanString("NODES"),
+		color.CyanString("ON-DEMAND"),
+		color.CyanString("SPOT"),
+		color.CyanString("CPUS"),
+		color.CyanString("RAM (GB)"),
+		color.CyanString("COST/MONTH"),
+	}, "|")}
+
+	for _, np := range report.NodePools {
+		var monthlyCost *float64
+		if np.HourlyCost != nil {
+			cost := *np.HourlyCost * capacity.HoursPerMonth
+			monthlyCost = &cost
+		}
+
+		rows = append(rows, strings.Join([]string{
+			np.Organization,
+			np.ClusterID,
+			placeholder(np.NodePoolID),
+			placeholder(np.InstanceType),
+			strconv.FormatInt(np.Nodes, 10),
+			strconv.FormatInt(np.OnDemandNodes, 10),
+			strconv.FormatInt(np.SpotNodes, 10),
+			strconv.FormatInt(np.CPUs, 10),
+			strconv.FormatFloat(np.MemoryGB, 'f', 1, 64),
+			formatCost(monthlyCost, report.Currency),
+		}, "|"))
+	}
+
+	fmt.Fprintln(w, columnize.SimpleFormat(rows))
+	fmt.Fprintln(w)
+
+	rows = []string{strings.Join([]string{
+		color.CyanString("ORGANIZATION"),
+		color.CyanString("CLUSTERS"),
+		color.CyanString("NODES"),
+		color.CyanString("ON-DEMAND"),
+		color.CyanString("SPOT"),
+		color.CyanString("CPUS"),
+		color.CyanString("RAM (GB)"),
+		color.CyanString("COST/MONTH"),
+	}, "|")}
+
+	summaries := make([]*capacity.Summary, 0, len(report.Organizations)+1)
+	summaries = append(summaries, report.Organizations...)
+	summaries = append(summaries, report.Total)
+	for i, s := range summaries {
+		name := s.Organization
+		if i == len(summaries)-1 {
+			name = color.YellowString("TOTAL")
+		}
+
+		rows = append(rows, strings.Join([]string{
+			name,
+			strconv.Itoa(s.Clusters),
+			strconv.FormatInt(s.Nodes, 10),
+			strconv.FormatInt(s.OnDemandNodes, 10),
+			strconv.FormatInt(s.SpotNodes, 10),
+			strconv.FormatInt(s.CPUs, 10),
+			strconv.FormatFloat(s.MemoryGB, 'f', 1, 64),
+			formatCost(s.MonthlyCost, report.Currency),
+		}, "|"))
+	}
+
+	fmt.Fprintln(w, columnize.SimpleFormat(rows))
+
+	if len(report.UnpricedInstanceTypes) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, color.YellowString("Warning: the price sheet has no price for %s. Their cost is not included.", strings.Join(report.UnpricedInstanceTypes, ", ")))
+	}
+}
+
+// printCSV prints one line per node pool.
+func printCSV(w io.Writer, report *capacity.Report) error {
+	cw := csv.NewWriter(w)
+
+	records := [][]string{{
+		"organization",
+		"cluster_id",
+		"cluster_name",
+		"node_pool_id",
+		"node_pool_name",
+		"instance_type",
+		"nodes",
+		"on_demand_nodes",
+		"spot_nodes",
+		"cpus",
+		"memory_gb",
+		"hourly_cost",
+		"monthly_cost",
+		"currency",
+	}}
+
+	for _, np := range report.NodePools {
+		hourlyCost, monthlyCost := "", ""
+		if np.HourlyCost != nil {
+			hourlyCost = strconv.FormatFloat(*np.HourlyCost, 'f', -1, 64)
+			monthlyCost = strconv.FormatFloat(*np.HourlyCost*capacity.HoursPerMonth, 'f', 2, 64)
+		}
+
+		records = append(records, []string{
+			np.Organization,
+			np.ClusterID,
+			np.ClusterName,
+			np.NodePoolID,
+			np.NodePoolName,
+			np.InstanceType,
+			strconv.FormatInt(np.Nodes, 10),
+			strconv.FormatInt(np.OnDemandNodes, 10),
+			strconv.FormatInt(np.SpotNodes, 10),
+			strconv.FormatInt(np.CPUs, 10),
+			strconv.FormatFloat(np.MemoryGB, 'f', -1, 64),
+			hourlyCost,
+			monthlyCost,
+			report.Currency,
+		})
+	}
+
+	err := cw.WriteAll(records)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func formatCost(cost *float64, currency string) string {
+	if cost == nil {
+		return "n/a"
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%.2f %s", *cost, currency))
+}
+
+func placeholder(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+	return s
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	var headline string
+	var subtext string
+
+	switch {
+	case errors.IsOutputFormatInvalid(err):
+		headline = "Invalid output format"
+		subtext = "Please use one of the formats table, csv, json or yaml."
+	case errors.IsYAMLFileNotReadable(err):
+		headline = "Could not read price sheet"
+		subtext = fmt.Sprintf("Please check the file given via --price-sheet. Details: %s", err.Error())
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
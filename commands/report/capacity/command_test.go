@@ -0,0 +1,170 @@
+package capacity
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
+)
+
+// TestVerifyPreconditions tests the validation of arguments.
+func TestVerifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			Arguments{apiEndpoint: "https://foo", authToken: "token", outputFormat: "table"},
+			nil,
+		},
+		{
+			Arguments{apiEndpoint: "https://foo", authToken: "token", outputFormat: "csv"},
+			nil,
+		},
+		{
+			Arguments{authToken: "token", outputFormat: "table"},
+			errors.IsEndpointMissingError,
+		},
+		{
+			Arguments{apiEndpoint: "https://foo", outputFormat: "table"},
+			errors.IsNotLoggedInError,
+		},
+		{
+			Arguments{apiEndpoint: "https://foo", authToken: "token", outputFormat: "xls"},
+			errors.IsOutputFormatInvalid,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil && err != nil {
+				t.Errorf("Case %d - Unexpected error %#v", i, err)
+			} else if tc.errorMatcher != nil && !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+			}
+		})
+	}
+}
+
+// TestReportCapacity reports a cluster with node pools and one without
+// against the fake API.
+func TestReportCapacity(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = afero.WriteFile(fs, "prices.yaml", []byte("currency: EUR\ninstance_types:\n  m5.xlarge:\n    on_demand: 0.2\n    spot: 0.1\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := fakeapi.New(fakeapi.Config{Organizations: []string{"acme", "other"}})
+	v5ID := api.AddClusterV5(&models.V5ClusterDetailsResponse{Owner: "acme", ReleaseVersion: "12.1.0"})
+	min := int64(1)
+	api.AddNodePool(v5ID, &models.V5GetNodePoolResponse{
+		ID:      "np1",
+		Name:    "spot",
+		Scaling: &models.V5GetNodePoolResponseScaling{Min: &min, Max: 10},
+		NodeSpec: &models.V5GetNodePoolResponseNodeSpec{
+			Aws: &models.V5GetNodePoolResponseNodeSpecAws{
+				InstanceType: "m5.xlarge",
+				InstanceDistribution: &models.V5GetNodePoolResponseNodeSpecAwsInstanceDistribution{
+					OnDemandBaseCapacity:                1,
+					OnDemandPercentageAboveBaseCapacity: 0,
+				},
+			},
+		},
+		Status: &models.V5GetNodePoolResponseStatus{Nodes: 4, NodesReady: 4},
+	})
+	v4ID := api.AddClusterV4(&models.V4ClusterDetailsResponse{Owner: "other", ReleaseVersion: "9.3.0"})
+
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	args := Arguments{
+		apiEndpoint:       ts.URL,
+		authToken:         "some-token",
+		fileSystem:        fs,
+		outputFormat:      "table",
+		priceSheet:        "prices.yaml",
+		userProvidedToken: "some-token",
+	}
+
+	report, err := reportCapacity(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	if len(report.NodePools) != 2 {
+		t.Fatalf("Expected 2 node pools, got %d", len(report.NodePools))
+	}
+
+	np := report.NodePools[0]
+	if np.ClusterID != v5ID || np.NodePoolID != "np1" || np.OnDemandNodes != 1 || np.SpotNodes != 3 || np.CPUs != 16 {
+		t.Errorf("Unexpected node pool %#v", np)
+	}
+
+	// The fake API gives clusters without node pools three m5.xlarge workers.
+	cluster := report.NodePools[1]
+	if cluster.ClusterID != v4ID || cluster.NodePoolID != "" || cluster.Nodes != 3 || cluster.OnDemandNodes != 3 || cluster.CPUs != 12 {
+		t.Errorf("Unexpected cluster %#v", cluster)
+	}
+
+	if report.Total.HourlyCost == nil || *report.Total.HourlyCost < 1.099 || *report.Total.HourlyCost > 1.101 {
+		t.Errorf("Expected hourly cost 1.1, got %v", report.Total.HourlyCost)
+	}
+
+	var buf bytes.Buffer
+	err = printCSV(&buf, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 CSV lines, got %q", buf.String())
+	}
+	if want := "acme," + v5ID + ",Unnamed cluster,np1,spot,m5.xlarge,4,1,3,16,64,0.5,365.00,EUR"; lines[1] != want {
+		t.Errorf("Expected CSV line %q, got %q", want, lines[1])
+	}
+
+	// Only one organization.
+	args.organization = "other"
+	report, err = reportCapacity(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if len(report.NodePools) != 1 || len(report.Organizations) != 1 || report.Organizations[0].Organization != "other" {
+		t.Errorf("Unexpected report for organization 'other': %#v", report.NodePools)
+	}
+}
+
+// TestReportCapacityInvalidPriceSheet tests the error for a price sheet that
+// can't be read.
+func TestReportCapacityInvalidPriceSheet(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = reportCapacity(Arguments{
+		apiEndpoint:       "https://foo",
+		authToken:         "some-token",
+		fileSystem:        fs,
+		priceSheet:        "missing.yaml",
+		userProvidedToken: "some-token",
+	})
+	if !errors.IsYAMLFileNotReadable(err) {
+		t.Errorf("Expected YAML file not readable error, got %#v", err)
+	}
+}
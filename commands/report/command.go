@@ -0,0 +1,21 @@
+// Package report holds the 'report *' sub-commands.
+package report
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/report/capacity"
+)
+
+var (
+	// Command is the command to create reports.
+	Command = &cobra.Command{
+		Use:   "report",
+		Short: "Report capacity",
+		Long:  `Prints reports across all clusters you have access to.`,
+	}
+)
+
+func init() {
+	Command.AddCommand(capacity.Command)
+}
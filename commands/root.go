@@ -5,26 +5,42 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 
 	"github.com/giantswarm/gscliauth/config"
 	"github.com/giantswarm/microerror"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/apply"
+	"github.com/giantswarm/gsctl/commands/auth"
 	"github.com/giantswarm/gsctl/commands/create"
 	deletecmd "github.com/giantswarm/gsctl/commands/delete"
+	"github.com/giantswarm/gsctl/commands/dev"
+	"github.com/giantswarm/gsctl/commands/diff"
+	"github.com/giantswarm/gsctl/commands/doctor"
+	"github.com/giantswarm/gsctl/commands/export"
+	"github.com/giantswarm/gsctl/commands/history"
 	"github.com/giantswarm/gsctl/commands/info"
+	"github.com/giantswarm/gsctl/commands/kubeconfigcredential"
 	"github.com/giantswarm/gsctl/commands/list"
 	"github.com/giantswarm/gsctl/commands/login"
 	"github.com/giantswarm/gsctl/commands/logout"
 	"github.com/giantswarm/gsctl/commands/ping"
+	"github.com/giantswarm/gsctl/commands/prune"
+	"github.com/giantswarm/gsctl/commands/report"
+	"github.com/giantswarm/gsctl/commands/rotate"
 	"github.com/giantswarm/gsctl/commands/scale"
 	selectcmd "github.com/giantswarm/gsctl/commands/select"
+	"github.com/giantswarm/gsctl/commands/selfupdate"
 	"github.com/giantswarm/gsctl/commands/show"
+	"github.com/giantswarm/gsctl/commands/ui"
 	"github.com/giantswarm/gsctl/commands/update"
 	"github.com/giantswarm/gsctl/commands/upgrade"
 	"github.com/giantswarm/gsctl/commands/version"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/auditlog"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -65,21 +81,36 @@ func init() {
 	RootCommand.PersistentFlags().StringVarP(&flags.Token, "auth-token", "", tokenFromEnv, "Authorization token to use")
 	RootCommand.PersistentFlags().StringVarP(&flags.ConfigDirPath, "config-dir", "", defaultConfigDir, "Configuration directory path to use")
 	RootCommand.PersistentFlags().BoolVarP(&flags.Verbose, "verbose", "v", false, "Print more information")
+	RootCommand.PersistentFlags().BoolVarP(&flags.Offline, "offline", "", false, "Use cached API responses for read-only commands, without contacting the API")
+	RootCommand.PersistentFlags().IntVarP(&flags.Retries, "retries", "", 0, "Number of times to retry failed API requests which are safe to repeat, with exponential backoff")
 	RootCommand.PersistentFlags().BoolVarP(&flags.SilenceHTTPEndpointWarning, "silence-http-endpoint-warning", "", false, "Dont't print warnings when deliberately using an insecure HTTP endpoint")
 	RootCommand.Flags().Bool("version", false, version.Command.Short)
 
 	// add subcommands
+	RootCommand.AddCommand(apply.Command)
+	RootCommand.AddCommand(auth.Command)
 	RootCommand.AddCommand(CompletionCommand)
 	RootCommand.AddCommand(create.Command)
 	RootCommand.AddCommand(deletecmd.Command)
+	RootCommand.AddCommand(dev.Command)
+	RootCommand.AddCommand(diff.Command)
+	RootCommand.AddCommand(doctor.Command)
+	RootCommand.AddCommand(export.Command)
+	RootCommand.AddCommand(history.Command)
 	RootCommand.AddCommand(info.Command)
+	RootCommand.AddCommand(kubeconfigcredential.Command)
 	RootCommand.AddCommand(list.Command)
 	RootCommand.AddCommand(login.Command)
 	RootCommand.AddCommand(logout.Command)
 	RootCommand.AddCommand(ping.Command)
+	RootCommand.AddCommand(prune.Command)
+	RootCommand.AddCommand(report.Command)
+	RootCommand.AddCommand(rotate.Command)
 	RootCommand.AddCommand(scale.Command)
 	RootCommand.AddCommand(selectcmd.Command)
+	RootCommand.AddCommand(selfupdate.Command)
 	RootCommand.AddCommand(show.Command)
+	RootCommand.AddCommand(ui.Command)
 	RootCommand.AddCommand(update.Command)
 	RootCommand.AddCommand(upgrade.Command)
 	RootCommand.AddCommand(version.Command)
@@ -103,6 +134,9 @@ func initConfig(cmd *cobra.Command, args []string) error {
 	var configLogger io.Writer
 	if flags.SilenceHTTPEndpointWarning {
 		configLogger = ioutil.Discard
+	} else if cmd == kubeconfigcredential.Command {
+		// STDOUT is read by kubectl.
+		configLogger = os.Stderr
 	} else {
 		configLogger = os.Stdout
 	}
@@ -115,6 +149,27 @@ func initConfig(cmd *cobra.Command, args []string) error {
 		return microerror.Mask(err)
 	}
 
+	client.DefaultRetryPolicy.MaxRetries = flags.Retries
+	if flags.Verbose {
+		client.DefaultRetryLogger = os.Stderr
+	}
+
+	client.DefaultCacheConfig = client.CacheConfig{
+		Dir:        path.Join(config.ConfigDirPath, "cache"),
+		FileSystem: fs,
+		TTLs:       client.DefaultCacheTTLs,
+		Offline:    flags.Offline,
+		Logger:     os.Stderr,
+	}
+
+	if os.Getenv(auditlog.EnvVar) != "" {
+		client.DefaultAuditConfig = client.AuditConfig{
+			FilePath:   path.Join(config.ConfigDirPath, auditlog.FileName),
+			FileSystem: fs,
+			Logger:     os.Stderr,
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,21 @@
+// Package rotate holds the 'rotate *' sub-commands.
+package rotate
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/commands/rotate/kubeconfig"
+)
+
+var (
+	// Command is the command to rotate credentials.
+	Command = &cobra.Command{
+		Use:   "rotate",
+		Short: "Rotate kubeconfig credentials",
+		Long:  `Replace credentials that are about to expire with new ones.`,
+	}
+)
+
+func init() {
+	Command.AddCommand(kubeconfig.Command)
+}
@@ -0,0 +1,653 @@
+// Package kubeconfig implements the 'rotate kubeconfig' command.
+package kubeconfig
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/kubeconfigfile"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command performs the "rotate kubeconfig" function
+	Command = &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "Renew kubectl credentials before they expire",
+		Long: `Checks the client certificate used by a Giant Swarm kubectl context and
+replaces it with a new key pair if it expires soon.
+
+Without the --cluster or --all flag, the current kubectl context is checked.
+With --all, every context named 'giantswarm-*' is checked. New key pairs are
+then created via whichever endpoint you are logged in to has the cluster.
+
+The certificate is rotated if it expires within the time given via --threshold.
+Unless --ttl is given, the new key pair has the same lifetime as the old one.
+Certificate files are replaced in the "certs" subfolder of the gsctl config
+directory, embedded credentials are replaced in the kubeconfig file itself.
+Contexts created with 'gsctl create kubeconfig --exec-plugin' are skipped, as
+they get new credentials automatically.
+
+Examples:
+
+  gsctl rotate kubeconfig
+
+  gsctl rotate kubeconfig -c my0c3 --threshold 2d
+
+  gsctl rotate kubeconfig --all --dry-run
+
+  gsctl rotate kubeconfig --all --ttl 1w
+`,
+		PreRun: printValidation,
+		Run:    printResult,
+	}
+
+	// cmdAll is the command line flag to check all Giant Swarm contexts.
+	cmdAll bool
+
+	// cmdThreshold is the command line flag for the remaining lifetime
+	// below which a certificate gets rotated.
+	cmdThreshold string
+
+	// cmdTTL is the command line flag for the lifetime of new key pairs.
+	cmdTTL string
+
+	arguments Arguments
+)
+
+const (
+	activityName = "rotate-kubeconfig"
+
+	// contextPrefix is the prefix of context, cluster and user names
+	// created by 'gsctl create kubeconfig'.
+	contextPrefix = "giantswarm-"
+)
+
+// Arguments is an argument struct to pass to our business
+// function and to the validation function
+type Arguments struct {
+	all               bool
+	apiEndpoint       string
+	authToken         string
+	clusterNameOrID   string
+	description       string
+	dryRun            bool
+	fileSystem        afero.Fs
+	force             bool
+	kubeconfigPaths   []string
+	threshold         time.Duration
+	ttlHours          int32
+	userProvidedToken string
+	verbose           bool
+}
+
+// target is a context whose credentials are to be checked.
+type target struct {
+	contextName  string
+	clusterID    string
+	authInfoName string
+	// authFile is the file defining the context's user entry.
+	authFile *kubeconfigfile.File
+}
+
+// rotationResult is the outcome of checking one context.
+type rotationResult struct {
+	contextName string
+	clusterID   string
+	// expiry is the expiry date of the certificate found in the kubeconfig.
+	expiry time.Time
+	// due is true if the certificate expires within the threshold.
+	due bool
+	// rotated is true if the credentials have been replaced.
+	rotated bool
+	// execPlugin is true if credentials come from an exec plugin, which
+	// renews them itself.
+	execPlugin bool
+	keyPairID  string
+	newExpiry  time.Time
+	err        error
+}
+
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.ClusterID, "cluster", "c", "", "Name or ID of the cluster whose context should be rotated. Defaults to the current context.")
+	Command.Flags().BoolVarP(&cmdAll, "all", "", false, "Check all kubectl contexts named 'giantswarm-*'.")
+	Command.Flags().StringVarP(&cmdThreshold, "threshold", "", "12h", "Rotate certificates expiring within this period, e.g. 12h. Allowed units: h, d, w, m, y.")
+	Command.Flags().StringVarP(&cmdTTL, "ttl", "", "", "Lifetime of new key pairs, e.g. 3d. Defaults to the lifetime of the certificate being replaced.")
+	Command.Flags().StringVarP(&flags.Description, "description", "d", "", "Description for new key pairs")
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "Rotate regardless of the remaining lifetime.")
+	Command.Flags().BoolVarP(&flags.DryRun, "dry-run", "", false, "Only report expiry, don't create key pairs or modify the kubeconfig.")
+}
+
+// collectArguments gathers arguments based on command line
+// flags and config and applies defaults.
+func collectArguments() (Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	description := flags.Description
+	if description == "" {
+		description = "Added by user " + config.Config.Email + " using 'gsctl rotate kubeconfig'"
+	}
+
+	threshold, err := parseDuration(cmdThreshold)
+	if err != nil {
+		return Arguments{}, microerror.Mask(err)
+	}
+
+	var ttl time.Duration
+	if cmdTTL != "" {
+		ttl, err = parseDuration(cmdTTL)
+		if err != nil {
+			return Arguments{}, microerror.Mask(err)
+		}
+	}
+
+	return Arguments{
+		all:               cmdAll,
+		apiEndpoint:       endpoint,
+		authToken:         token,
+		clusterNameOrID:   flags.ClusterID,
+		description:       description,
+		dryRun:            flags.DryRun,
+		fileSystem:        config.FileSystem,
+		force:             flags.Force,
+		kubeconfigPaths:   config.KubeConfigPaths,
+		threshold:         threshold,
+		ttlHours:          int32(ttl.Hours()),
+		userProvidedToken: flags.Token,
+		verbose:           flags.Verbose,
+	}, nil
+}
+
+// parseDuration wraps util.ParseDuration, mapping its errors to ours.
+func parseDuration(s string) (time.Duration, error) {
+	d, err := util.ParseDuration(s)
+	if errors.IsInvalidDurationError(err) {
+		return 0, microerror.Mask(errors.InvalidDurationError)
+	} else if errors.IsDurationExceededError(err) {
+		return 0, microerror.Mask(errors.DurationExceededError)
+	} else if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	return d, nil
+}
+
+func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
+	var err error
+
+	arguments, err = collectArguments()
+	if err == nil {
+		err = verifyPreconditions(arguments)
+	}
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// verifyPreconditions checks if all preconditions are met and
+// returns nil if yes, error if not
+func verifyPreconditions(args Arguments) error {
+	if args.apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if config.Config.Token == "" && args.authToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.all && args.clusterNameOrID != "" {
+		return microerror.Maskf(errors.ConflictingFlagsError, "the flags --all and --cluster cannot be combined")
+	}
+
+	return nil
+}
+
+func printResult(cmd *cobra.Command, cmdLineArgs []string) {
+	results, err := rotateKubeconfigs(arguments)
+
+	if len(results) > 0 {
+		fmt.Println(formatResults(results, arguments))
+	}
+
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if arguments.dryRun {
+		return
+	}
+
+	for _, r := range results {
+		if r.rotated {
+			fmt.Println(color.GreenString("\nkubectl configuration has been updated."))
+			return
+		}
+	}
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	var headline string
+	var subtext string
+
+	switch {
+	case errors.IsInvalidDurationError(err):
+		headline = "The value passed with --threshold or --ttl is invalid."
+		subtext = "Please provide a number and a unit, e. g. '10h', '1d', '1w'."
+	case errors.IsDurationExceededError(err):
+		headline = "The period passed with --threshold or --ttl is too long."
+		subtext = "The maximum possible value is the equivalent of 292 years."
+	case IsContextNotFound(err):
+		headline = "No matching kubectl context found"
+		subtext = err.Error()
+		subtext += "\nPlease use 'gsctl create kubeconfig' to set up kubectl for a cluster."
+	case IsNotGiantSwarmContext(err):
+		headline = "The current kubectl context is not a Giant Swarm context"
+		subtext = "Please select a cluster via --cluster or use --all."
+	case IsRotationFailed(err):
+		headline = "Rotation failed"
+		subtext = err.Error()
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
+
+// rotateKubeconfigs is our business function. It checks the client
+// certificates of the selected contexts and replaces them if needed.
+func rotateKubeconfigs(args Arguments) ([]rotationResult, error) {
+	var files []*kubeconfigfile.File
+	var kubeconfig *kubeconfigfile.Kubeconfig
+	var err error
+
+	if args.dryRun {
+		files, err = kubeconfigfile.Load(args.fileSystem, args.kubeconfigPaths)
+	} else if len(args.kubeconfigPaths) > 0 {
+		kubeconfig, err = kubeconfigfile.Open(kubeconfigfile.Config{
+			FileSystem: args.fileSystem,
+			Paths:      args.kubeconfigPaths,
+		})
+		if err == nil {
+			defer kubeconfig.Close()
+			files = kubeconfig.Files()
+		}
+	}
+	if kubeconfigfile.IsInvalidKubeconfig(err) {
+		return nil, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterID := ""
+	if args.clusterNameOrID != "" {
+		clusterID, err = clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	targets, err := findTargets(files, clusterID, args.all)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clientFor := clientForCluster(args, clientWrapper)
+
+	results := []rotationResult{}
+	numFailed := 0
+	for _, t := range targets {
+		r := rotateTarget(clientFor, args, t)
+		if r.err != nil {
+			numFailed++
+		}
+		if r.rotated {
+			kubeconfig.MarkChanged(t.authFile)
+		}
+		results = append(results, r)
+	}
+
+	if kubeconfig != nil {
+		err = kubeconfig.Save()
+		if err != nil {
+			return results, microerror.Maskf(errors.CouldNotWriteFileError, err.Error())
+		}
+	}
+
+	if numFailed > 0 {
+		return results, microerror.Maskf(rotationFailedError, "%d of %d contexts could not be rotated", numFailed, len(results))
+	}
+
+	return results, nil
+}
+
+// clientForCluster returns a function that finds the client for the
+// endpoint a cluster belongs to. Without --all, the contexts to check belong
+// to the selected endpoint. With --all, they may belong to any endpoint the
+// user is logged in to, so the cluster lists of these endpoints are fetched
+// on first use.
+func clientForCluster(args Arguments, selected *client.Wrapper) func(clusterID string) (*client.Wrapper, error) {
+	if !args.all {
+		return func(string) (*client.Wrapper, error) {
+			return selected, nil
+		}
+	}
+
+	var clients map[string]*client.Wrapper
+	var unavailable []string
+
+	return func(clusterID string) (*client.Wrapper, error) {
+		if clients == nil {
+			clients, unavailable = clientsByCluster(args, selected)
+		}
+
+		if c, ok := clients[clusterID]; ok {
+			return c, nil
+		}
+
+		if len(unavailable) > 0 {
+			return nil, microerror.Maskf(errors.ClusterNotFoundError, "cluster not found, endpoints %s could not be queried", strings.Join(unavailable, ", "))
+		}
+		return nil, microerror.Maskf(errors.ClusterNotFoundError, "cluster not found on any endpoint you are logged in to")
+	}
+}
+
+// clientsByCluster maps the IDs of all clusters on the selected endpoint and
+// on all other endpoints the user is logged in to to the client for their
+// endpoint. It also returns the endpoints which could not be queried.
+func clientsByCluster(args Arguments, selected *client.Wrapper) (map[string]*client.Wrapper, []string) {
+	endpoints := []string{args.apiEndpoint}
+	var others []string
+	for _, endpoint := range config.Config.Endpoints() {
+		if e := config.Config.EndpointConfig(endpoint); endpoint != args.apiEndpoint && e != nil && e.Token != "" {
+			others = append(others, endpoint)
+		}
+	}
+	sort.Strings(others)
+	endpoints = append(endpoints, others...)
+
+	clients := map[string]*client.Wrapper{}
+	var unavailable []string
+
+	for _, endpoint := range endpoints {
+		clientWrapper := selected
+		if endpoint != args.apiEndpoint {
+			var err error
+			clientWrapper, err = client.NewForEndpoint(endpoint)
+			if err != nil {
+				unavailable = append(unavailable, endpoint)
+				continue
+			}
+		}
+
+		auxParams := clientWrapper.DefaultAuxiliaryParams()
+		auxParams.ActivityName = activityName
+
+		response, err := clientWrapper.GetClusters(auxParams)
+		if err != nil {
+			if args.verbose {
+				fmt.Println(color.WhiteString("Could not list clusters on endpoint %s: %s", endpoint, err.Error()))
+			}
+			unavailable = append(unavailable, endpoint)
+			continue
+		}
+
+		for _, c := range response.Payload {
+			// The selected endpoint comes first and takes precedence.
+			if _, ok := clients[c.ID]; !ok {
+				clients[c.ID] = clientWrapper
+			}
+		}
+	}
+
+	return clients, unavailable
+}
+
+// findTargets returns the contexts to check. If all is true, these are all
+// contexts named 'giantswarm-*'. If a cluster ID is given, it's the context
+// for that cluster. Otherwise it's the current context.
+func findTargets(files []*kubeconfigfile.File, clusterID string, all bool) ([]target, error) {
+	// Contexts defined in several files are taken from the first file,
+	// as kubectl does.
+	contexts := map[string]*clientcmdapi.Context{}
+	currentContext := ""
+	for _, f := range files {
+		if currentContext == "" {
+			currentContext = f.Config.CurrentContext
+		}
+		for name, c := range f.Config.Contexts {
+			if _, ok := contexts[name]; !ok {
+				contexts[name] = c
+			}
+		}
+	}
+
+	var names []string
+	switch {
+	case all:
+		for name := range contexts {
+			if strings.HasPrefix(name, contextPrefix) {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return nil, microerror.Maskf(contextNotFoundError, "There is no context named '%s*'.", contextPrefix)
+		}
+		sort.Strings(names)
+
+	case clusterID != "":
+		if _, ok := contexts[contextPrefix+clusterID]; ok {
+			names = []string{contextPrefix + clusterID}
+		} else {
+			// Contexts created with a custom name via --context.
+			for name, c := range contexts {
+				if c.Cluster == contextPrefix+clusterID {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+		}
+		if len(names) == 0 {
+			return nil, microerror.Maskf(contextNotFoundError, "There is no context for cluster '%s'.", clusterID)
+		}
+
+	default:
+		if currentContext == "" || contexts[currentContext] == nil {
+			return nil, microerror.Maskf(contextNotFoundError, "There is no current context.")
+		}
+		if !strings.HasPrefix(contexts[currentContext].Cluster, contextPrefix) {
+			return nil, microerror.Mask(notGiantSwarmContextError)
+		}
+		names = []string{currentContext}
+	}
+
+	targets := []target{}
+	for _, name := range names {
+		c := contexts[name]
+		t := target{
+			contextName:  name,
+			clusterID:    strings.TrimPrefix(c.Cluster, contextPrefix),
+			authInfoName: c.AuthInfo,
+		}
+		for _, f := range files {
+			if _, ok := f.Config.AuthInfos[c.AuthInfo]; ok {
+				t.authFile = f
+				break
+			}
+		}
+		targets = append(targets, t)
+	}
+
+	return targets, nil
+}
+
+// rotateTarget checks the client certificate of one context and replaces
+// the credentials if the certificate expires within the threshold.
+func rotateTarget(clientFor func(clusterID string) (*client.Wrapper, error), args Arguments, t target) rotationResult {
+	result := rotationResult{
+		contextName: t.contextName,
+		clusterID:   t.clusterID,
+	}
+
+	if t.authFile == nil {
+		result.err = microerror.Maskf(clientCertificateMissingError, "user '%s' not found", t.authInfoName)
+		return result
+	}
+	authInfo := t.authFile.Config.AuthInfos[t.authInfoName]
+
+	if authInfo.Exec != nil {
+		result.execPlugin = true
+		return result
+	}
+
+	cert, err := kubeconfigfile.ClientCertificate(args.fileSystem, t.authFile.Path, authInfo)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	result.expiry = cert.NotAfter
+	result.due = time.Until(cert.NotAfter) < args.threshold
+	if !result.due && !args.force {
+		return result
+	}
+	if args.dryRun {
+		return result
+	}
+
+	ttlHours := args.ttlHours
+	if ttlHours == 0 {
+		ttlHours = int32(math.Round(cert.NotAfter.Sub(cert.NotBefore).Hours()))
+		if ttlHours < 1 {
+			ttlHours = 1
+		}
+	}
+
+	clientWrapper, err := clientFor(t.clusterID)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	requestBody := &models.V4AddKeyPairRequest{
+		Description: &args.description,
+		TTLHours:    ttlHours,
+	}
+	if len(cert.Subject.Organization) > 0 {
+		requestBody.CertificateOrganizations = strings.Join(cert.Subject.Organization, ",")
+	}
+
+	response, err := clientWrapper.CreateKeyPair(t.clusterID, requestBody, auxParams)
+	if err != nil {
+		if clienterror.IsAccessForbiddenError(err) {
+			result.err = microerror.Mask(errors.AccessForbiddenError)
+		} else if clienterror.IsNotFoundError(err) {
+			result.err = microerror.Mask(errors.ClusterNotFoundError)
+		} else if clienterror.IsBadRequestError(err) {
+			result.err = microerror.Maskf(errors.BadRequestError, err.Error())
+		} else {
+			result.err = microerror.Mask(err)
+		}
+		return result
+	}
+
+	if authInfo.ClientCertificate != "" {
+		// Credentials are referenced as files, so we write new files.
+		authInfo.ClientCertificate = util.StoreClientCertificate(args.fileSystem, config.CertsDirPath,
+			t.clusterID, response.Payload.ID, response.Payload.ClientCertificateData)
+		authInfo.ClientKey = util.StoreClientKey(args.fileSystem, config.CertsDirPath,
+			t.clusterID, response.Payload.ID, response.Payload.ClientKeyData)
+		authInfo.ClientCertificateData = nil
+		authInfo.ClientKeyData = nil
+	} else {
+		authInfo.ClientCertificateData = []byte(response.Payload.ClientCertificateData)
+		authInfo.ClientKeyData = []byte(response.Payload.ClientKeyData)
+	}
+
+	result.rotated = true
+	result.keyPairID = response.Payload.ID
+	result.newExpiry = time.Now().Add(time.Duration(response.Payload.TTLHours) * time.Hour)
+	if newCert, err := kubeconfigfile.ParseCertificate([]byte(response.Payload.ClientCertificateData)); err == nil {
+		result.newExpiry = newCert.NotAfter
+	}
+
+	return result
+}
+
+// formatResults returns a table with one row per checked context.
+func formatResults(results []rotationResult, args Arguments) string {
+	rows := []string{strings.Join([]string{
+		color.CyanString("CONTEXT"),
+		color.CyanString("CLUSTER ID"),
+		color.CyanString("EXPIRES"),
+		color.CyanString("STATUS"),
+	}, "|")}
+
+	for _, r := range results {
+		expires := "n/a"
+		if !r.expiry.IsZero() {
+			expires = util.ShortDate(r.expiry.UTC())
+			if time.Now().After(r.expiry) {
+				expires = color.RedString(expires)
+			} else if r.due {
+				expires = color.YellowString(expires)
+			}
+		}
+
+		var status string
+		switch {
+		case r.err != nil:
+			status = color.RedString("failed: %s", r.err.Error())
+		case r.execPlugin:
+			status = "renewed by exec plugin"
+		case r.rotated:
+			status = color.GreenString("rotated, new expiry %s", util.ShortDate(r.newExpiry.UTC()))
+		case r.due && args.dryRun:
+			status = color.YellowString("rotation due")
+		case args.dryRun && args.force:
+			status = "would be rotated"
+		default:
+			status = "valid"
+		}
+
+		rows = append(rows, strings.Join([]string{r.contextName, r.clusterID, expires, status}, "|"))
+	}
+
+	return columnize.SimpleFormat(rows)
+}
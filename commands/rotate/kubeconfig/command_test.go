@@ -0,0 +1,459 @@
+package kubeconfig
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/spf13/afero"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/kubeconfigfile"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// makeMockServer returns a mock API server listing the given clusters and
+// creating key pairs. The request bodies received are appended to the given
+// slice.
+func makeMockServer(t *testing.T, requests *[]map[string]interface{}, clusterIDs ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == "GET" && r.URL.Path == "/v4/clusters/" {
+			clusters := []map[string]string{}
+			for _, id := range clusterIDs {
+				clusters = append(clusters, map[string]string{"id": id, "name": id, "owner": "acme"})
+			}
+			response, _ := json.Marshal(clusters)
+			w.WriteHeader(http.StatusOK)
+			w.Write(response)
+			return
+		}
+
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/key-pairs/") {
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			*requests = append(*requests, body)
+
+			ttl := time.Duration(body["ttl_hours"].(float64)) * time.Hour
+			certPEM, keyPEM := testutils.ClientCertificate(t, time.Now(), time.Now().Add(ttl))
+			response, _ := json.Marshal(map[string]interface{}{
+				"id":                         "ab:cd:ef:01:23:45:67:89:ab:cd:ef:01",
+				"ttl_hours":                  body["ttl_hours"],
+				"client_certificate_data":    certPEM,
+				"client_key_data":            keyPEM,
+				"certificate_authority_data": "ca",
+				"create_date":                "2020-01-01T12:00:00.000000Z",
+			})
+			w.WriteHeader(http.StatusOK)
+			w.Write(response)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found"}`))
+	}))
+}
+
+// writeKubeconfig writes a kubeconfig file and sets it as the only
+// kubeconfig path.
+func writeKubeconfig(t *testing.T, fs afero.Fs, yamlText string) string {
+	kubeconfigPath := path.Join(testutils.TempDir(fs), "kubeconfig")
+	err := afero.WriteFile(fs, kubeconfigPath, []byte(yamlText), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kubeconfigPath
+}
+
+// Test_RotateCurrentContextFiles tests rotation of a certificate
+// referenced as a file, for the current context.
+func Test_RotateCurrentContextFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	certPEM, _ := testutils.ClientCertificate(t, now.Add(-22*time.Hour), now.Add(2*time.Hour))
+	certPath := path.Join(config.CertsDirPath, "abc12-old-client.crt")
+	err = afero.WriteFile(fs, certPath, []byte(certPEM), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubeconfigPath := writeKubeconfig(t, fs, `apiVersion: v1
+kind: Config
+current-context: giantswarm-abc12
+clusters:
+- name: giantswarm-abc12
+  cluster:
+    server: https://api.abc12.example.com
+contexts:
+- name: giantswarm-abc12
+  context:
+    cluster: giantswarm-abc12
+    user: giantswarm-abc12-user
+users:
+- name: giantswarm-abc12-user
+  user:
+    client-certificate: `+certPath+`
+    client-key: /some/old.key
+`)
+
+	var requests []map[string]interface{}
+	mockServer := makeMockServer(t, &requests)
+	defer mockServer.Close()
+
+	args := Arguments{
+		apiEndpoint:     mockServer.URL,
+		authToken:       "token",
+		description:     "test",
+		fileSystem:      fs,
+		kubeconfigPaths: []string{kubeconfigPath},
+		threshold:       12 * time.Hour,
+	}
+
+	err = verifyPreconditions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := rotateKubeconfigs(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	if len(results) != 1 || !results[0].rotated || results[0].clusterID != "abc12" {
+		t.Fatalf("Expected one rotated result for abc12, got %#v", results)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("Expected one key pair request, got %d", len(requests))
+	}
+	if requests[0]["ttl_hours"].(float64) != 24 {
+		t.Errorf("Expected new key pair to keep the lifetime of 24 hours, got %v", requests[0]["ttl_hours"])
+	}
+	if requests[0]["certificate_organizations"] != "system:masters" {
+		t.Errorf("Expected certificate organizations to be kept, got %v", requests[0]["certificate_organizations"])
+	}
+
+	data, err := afero.ReadFile(fs, kubeconfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := clientcmd.Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := c.AuthInfos["giantswarm-abc12-user"]
+	if user.ClientCertificate == certPath || user.ClientKey == "/some/old.key" {
+		t.Errorf("Expected credential file paths to be replaced, got %q and %q", user.ClientCertificate, user.ClientKey)
+	}
+
+	cert, err := kubeconfigfile.ClientCertificate(fs, kubeconfigPath, user)
+	if err != nil {
+		t.Fatalf("New certificate not readable: %#v", err)
+	}
+	if time.Until(cert.NotAfter) < 23*time.Hour {
+		t.Errorf("Expected new certificate to expire in about 24 hours, got %v", cert.NotAfter)
+	}
+}
+
+// Test_RotateAllEmbedded tests rotation of all Giant Swarm contexts with
+// embedded credentials, including dry runs.
+func Test_RotateAllEmbedded(t *testing.T) {
+	now := time.Now()
+	expiredPEM, _ := testutils.ClientCertificate(t, now.Add(-48*time.Hour), now.Add(-1*time.Hour))
+	validPEM, _ := testutils.ClientCertificate(t, now.Add(-1*time.Hour), now.Add(30*24*time.Hour))
+
+	kubeconfigYAML := `apiVersion: v1
+kind: Config
+current-context: other
+clusters:
+- name: giantswarm-abc12
+  cluster:
+    server: https://api.abc12.example.com
+- name: giantswarm-def34
+  cluster:
+    server: https://api.def34.example.com
+- name: giantswarm-gh567
+  cluster:
+    server: https://api.gh567.example.com
+contexts:
+- name: giantswarm-abc12
+  context:
+    cluster: giantswarm-abc12
+    user: giantswarm-abc12-user
+- name: giantswarm-def34
+  context:
+    cluster: giantswarm-def34
+    user: giantswarm-def34-user
+- name: giantswarm-gh567
+  context:
+    cluster: giantswarm-gh567
+    user: giantswarm-gh567-user
+- name: other
+  context:
+    cluster: other
+    user: other
+users:
+- name: giantswarm-abc12-user
+  user:
+    client-certificate-data: ` + toBase64(expiredPEM) + `
+    client-key-data: ` + toBase64("old-key") + `
+- name: giantswarm-def34-user
+  user:
+    client-certificate-data: ` + toBase64(validPEM) + `
+    client-key-data: ` + toBase64("valid-key") + `
+- name: giantswarm-gh567-user
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: gsctl
+      args: ["kubeconfig-credential", "--cluster", "gh567"]
+`
+
+	var testCases = []struct {
+		name            string
+		dryRun          bool
+		expectedRotated []bool
+		expectedChanged bool
+	}{
+		{
+			name:            "dry run",
+			dryRun:          true,
+			expectedRotated: []bool{false, false, false},
+			expectedChanged: false,
+		},
+		{
+			name:            "rotation",
+			expectedRotated: []bool{true, false, false},
+			expectedChanged: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_, err := testutils.TempConfig(fs, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			kubeconfigPath := writeKubeconfig(t, fs, kubeconfigYAML)
+
+			var requests []map[string]interface{}
+			mockServer := makeMockServer(t, &requests, "abc12", "def34", "gh567")
+			defer mockServer.Close()
+
+			args := Arguments{
+				all:             true,
+				apiEndpoint:     mockServer.URL,
+				authToken:       "token",
+				dryRun:          tc.dryRun,
+				fileSystem:      fs,
+				kubeconfigPaths: []string{kubeconfigPath},
+				threshold:       24 * time.Hour,
+				ttlHours:        72,
+			}
+
+			results, err := rotateKubeconfigs(args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %#v", err)
+			}
+			if len(results) != 3 {
+				t.Fatalf("Expected 3 results, got %d", len(results))
+			}
+			if !results[2].execPlugin || results[2].err != nil {
+				t.Errorf("Expected the exec plugin user to be skipped, got %#v", results[2])
+			}
+			if !results[0].due || results[1].due {
+				t.Errorf("Expected only the first context to be due, got %v and %v", results[0].due, results[1].due)
+			}
+			for i := range results {
+				if results[i].rotated != tc.expectedRotated[i] {
+					t.Errorf("Result %d: expected rotated=%v, got %v", i, tc.expectedRotated[i], results[i].rotated)
+				}
+			}
+
+			data, err := afero.ReadFile(fs, kubeconfigPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := clientcmd.Load(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			changed := string(c.AuthInfos["giantswarm-abc12-user"].ClientKeyData) != "old-key"
+			if changed != tc.expectedChanged {
+				t.Errorf("Expected changed=%v, got %v", tc.expectedChanged, changed)
+			}
+			if string(c.AuthInfos["giantswarm-def34-user"].ClientKeyData) != "valid-key" {
+				t.Error("Valid credentials must not be changed")
+			}
+			if tc.expectedChanged && requests[0]["ttl_hours"].(float64) != 72 {
+				t.Errorf("Expected TTL 72, got %v", requests[0]["ttl_hours"])
+			}
+		})
+	}
+}
+
+// Test_RotateAllEndpoints tests that with --all, key pairs are created via
+// the endpoint each cluster belongs to.
+func Test_RotateAllEndpoints(t *testing.T) {
+	now := time.Now()
+	expiredPEM, _ := testutils.ClientCertificate(t, now.Add(-48*time.Hour), now.Add(-1*time.Hour))
+
+	var selectedRequests, otherRequests []map[string]interface{}
+	selectedServer := makeMockServer(t, &selectedRequests, "abc12")
+	defer selectedServer.Close()
+	otherServer := makeMockServer(t, &otherRequests, "xyz89")
+	defer otherServer.Close()
+
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, `endpoints:
+  `+selectedServer.URL+`:
+    email: email@example.com
+    token: some-token
+  `+otherServer.URL+`:
+    email: email@example.com
+    token: other-token
+selected_endpoint: `+selectedServer.URL+`
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubeconfigYAML := `apiVersion: v1
+kind: Config
+clusters:
+- name: giantswarm-abc12
+  cluster:
+    server: https://api.abc12.example.com
+- name: giantswarm-xyz89
+  cluster:
+    server: https://api.xyz89.example.org
+- name: giantswarm-gone1
+  cluster:
+    server: https://api.gone1.example.com
+contexts:
+- name: giantswarm-abc12
+  context:
+    cluster: giantswarm-abc12
+    user: giantswarm-abc12-user
+- name: giantswarm-gone1
+  context:
+    cluster: giantswarm-gone1
+    user: giantswarm-gone1-user
+- name: giantswarm-xyz89
+  context:
+    cluster: giantswarm-xyz89
+    user: giantswarm-xyz89-user
+users:
+- name: giantswarm-abc12-user
+  user:
+    client-certificate-data: ` + toBase64(expiredPEM) + `
+    client-key-data: ` + toBase64("old-key") + `
+- name: giantswarm-gone1-user
+  user:
+    client-certificate-data: ` + toBase64(expiredPEM) + `
+    client-key-data: ` + toBase64("old-key") + `
+- name: giantswarm-xyz89-user
+  user:
+    client-certificate-data: ` + toBase64(expiredPEM) + `
+    client-key-data: ` + toBase64("old-key") + `
+`
+	kubeconfigPath := writeKubeconfig(t, fs, kubeconfigYAML)
+
+	args := Arguments{
+		all:             true,
+		apiEndpoint:     selectedServer.URL,
+		authToken:       "some-token",
+		fileSystem:      fs,
+		kubeconfigPaths: []string{kubeconfigPath},
+		threshold:       24 * time.Hour,
+	}
+
+	results, err := rotateKubeconfigs(args)
+	if !IsRotationFailed(err) {
+		t.Errorf("Expected rotationFailedError, got %#v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if !results[0].rotated || !results[2].rotated {
+		t.Errorf("Expected abc12 and xyz89 to be rotated, got %#v", results)
+	}
+	if !errors.IsClusterNotFoundError(results[1].err) {
+		t.Errorf("Expected gone1 to fail with ClusterNotFoundError, got %#v", results[1].err)
+	}
+	if len(selectedRequests) != 1 || len(otherRequests) != 1 {
+		t.Errorf("Expected one key pair request per endpoint, got %d and %d", len(selectedRequests), len(otherRequests))
+	}
+}
+
+// Test_FindTargetsErrors tests errors when selecting contexts.
+func Test_FindTargetsErrors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	kubeconfigPath := writeKubeconfig(t, fs, `apiVersion: v1
+kind: Config
+current-context: other
+contexts:
+- name: other
+  context:
+    cluster: other
+    user: other
+`)
+
+	files, err := kubeconfigfile.Load(fs, []string{kubeconfigPath, "/does/not/exist"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = findTargets(files, "", false)
+	if !IsNotGiantSwarmContext(err) {
+		t.Errorf("Expected notGiantSwarmContextError, got %#v", err)
+	}
+
+	_, err = findTargets(files, "abc12", false)
+	if !IsContextNotFound(err) {
+		t.Errorf("Expected contextNotFoundError, got %#v", err)
+	}
+
+	_, err = findTargets(files, "", true)
+	if !IsContextNotFound(err) {
+		t.Errorf("Expected contextNotFoundError, got %#v", err)
+	}
+}
+
+// Test_VerifyPreconditions tests the flag combination check.
+func Test_VerifyPreconditions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Arguments{
+		all:             true,
+		apiEndpoint:     "https://api.example.com",
+		authToken:       "token",
+		clusterNameOrID: "abc12",
+	}
+
+	err = verifyPreconditions(args)
+	if !errors.IsConflictingFlagsError(err) {
+		t.Errorf("Expected ConflictingFlagsError, got %#v", err)
+	}
+}
+
+func toBase64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
@@ -0,0 +1,46 @@
+package kubeconfig
+
+import "github.com/giantswarm/microerror"
+
+// contextNotFoundError is used when no kubeconfig context for the
+// given cluster, or no current context, could be found.
+var contextNotFoundError = &microerror.Error{
+	Kind: "contextNotFoundError",
+}
+
+// IsContextNotFound asserts contextNotFoundError.
+func IsContextNotFound(err error) bool {
+	return microerror.Cause(err) == contextNotFoundError
+}
+
+// notGiantSwarmContextError is used when the selected context does not
+// point to a Giant Swarm workload cluster.
+var notGiantSwarmContextError = &microerror.Error{
+	Kind: "notGiantSwarmContextError",
+}
+
+// IsNotGiantSwarmContext asserts notGiantSwarmContextError.
+func IsNotGiantSwarmContext(err error) bool {
+	return microerror.Cause(err) == notGiantSwarmContextError
+}
+
+// clientCertificateMissingError is used when a context's user entry
+// does not use a client certificate.
+var clientCertificateMissingError = &microerror.Error{
+	Kind: "clientCertificateMissingError",
+}
+
+// IsClientCertificateMissing asserts clientCertificateMissingError.
+func IsClientCertificateMissing(err error) bool {
+	return microerror.Cause(err) == clientCertificateMissingError
+}
+
+// rotationFailedError is used when rotation failed for at least one context.
+var rotationFailedError = &microerror.Error{
+	Kind: "rotationFailedError",
+}
+
+// IsRotationFailed asserts rotationFailedError.
+func IsRotationFailed(err error) bool {
+	return microerror.Cause(err) == rotationFailedError
+}
@@ -4,6 +4,7 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/config"
@@ -19,6 +20,7 @@ import (
 	"github.com/giantswarm/gsctl/confirm"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/limits"
+	"github.com/giantswarm/gsctl/pkg/wait"
 )
 
 var (
@@ -45,6 +47,10 @@ Examples:
   gsctl scale cluster c7t2o --num-workers 3
 
   gsctl scale cluster "Cluster name" --num-workers 3
+
+  To wait until the number of worker nodes is within the new limits:
+
+  gsctl scale cluster c7t2o --num-workers 3 --wait --timeout 20m
 `,
 
 		PreRun: printValidation,
@@ -76,6 +82,8 @@ func initFlags() {
 	Command.Flags().Int64VarP(&flags.WorkersMax, cmdWorkersMaxName, "", 0, "Maximum number of worker nodes to have after scaling.")
 	Command.Flags().Int64VarP(&flags.WorkersMin, cmdWorkersMinName, "", 0, "Minimum number of worker nodes to have after scaling.")
 	Command.Flags().IntVarP(&flags.NumWorkers, cmdWorkersNumName, "w", 0, "Shorthand to set --workers-min and --workers-max to the same value.")
+	Command.Flags().BoolVarP(&flags.Wait, "wait", "", false, "Wait until the cluster has been scaled.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "timeout", "", wait.DefaultTimeout, "Maximum time to wait when using --wait.")
 }
 
 // Arguments contains all arguments that influence the business function.
@@ -88,6 +96,8 @@ type Arguments struct {
 	Scheme              string
 	UserProvidedToken   string
 	Verbose             bool
+	Wait                bool
+	WaitTimeout         time.Duration
 	WorkersMax          int64
 	WorkersMaxSet       bool
 	WorkersMin          int64
@@ -140,6 +150,8 @@ func collectArguments(cmd *cobra.Command, positionalArgs []string) (Arguments, e
 		Scheme:              scheme,
 		UserProvidedToken:   flags.Token,
 		Verbose:             flags.Verbose,
+		Wait:                flags.Wait,
+		WaitTimeout:         flags.WaitTimeout,
 		WorkersMax:          flags.WorkersMax,
 		WorkersMin:          flags.WorkersMin,
 		Workers:             flags.NumWorkers,
@@ -243,8 +255,8 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	arguments, err = collectArguments(cmd, positionalArgs)
 
 	if err != nil {
-		fmt.Println(color.RedString(err.Error()))
-		os.Exit(1)
+		errors.PrintError(err, err.Error(), "")
+		errors.Exit(err)
 	}
 
 	clientWrapper, err := client.NewWithConfig(arguments.APIEndpoint, arguments.UserProvidedToken)
@@ -262,7 +274,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	var headline string
@@ -289,11 +301,8 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	// handle non-common errors
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
 }
 
 // scaleCluster is the actual function submitting the API call and handling the response.
@@ -404,17 +413,21 @@ func printResult(cmd *cobra.Command, commandLineArgs []string) {
 	var err error
 	arguments, err = collectArguments(cmd, commandLineArgs)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
-		fmt.Println(color.RedString(err.Error()))
-		os.Exit(1)
+		errors.PrintError(err, err.Error(), "")
+		errors.Exit(err)
 	}
 
 	// Actually make the scaling request to the API.
 	result, err := scaleCluster(arguments)
+	if err == nil && arguments.Wait {
+		fmt.Println(color.GreenString("The cluster is being scaled"))
+		err = waitForScaling(arguments, result)
+	}
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline string
@@ -433,18 +446,40 @@ func printResult(cmd *cobra.Command, commandLineArgs []string) {
 		case errors.IsCommandAbortedError(err):
 			headline = "Cancelled"
 			subtext = "Scaling settings of this cluster stay as they are."
+		case wait.IsTimeout(err):
+			headline = "Timeout"
+			subtext = fmt.Sprintf("The number of worker nodes did not reach min=%d and max=%d in time.", result.ScalingMinAfter, result.ScalingMaxAfter)
 		default:
 			headline = err.Error()
 		}
 
 		// Print error output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
-	fmt.Println(color.GreenString("The cluster is being scaled"))
+	if arguments.Wait {
+		fmt.Println(color.GreenString("The cluster has been scaled"))
+	} else {
+		fmt.Println(color.GreenString("The cluster is being scaled"))
+	}
 	fmt.Printf("The cluster limits have been changed from min=%d and max=%d to min=%d and max=%d workers.\n", result.ScalingMinBefore, result.ScalingMaxBefore, result.ScalingMinAfter, result.ScalingMaxAfter)
 }
+
+// waitForScaling polls the API until the number of worker nodes is within the new limits.
+func waitForScaling(args Arguments, result *Result) error {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	w, err := wait.New(wait.Config{
+		ClientWrapper: clientWrapper,
+		Timeout:       args.WaitTimeout,
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return w.ClusterScaled(args.ClusterNameOrID, int64(result.ScalingMinAfter), int64(result.ScalingMaxAfter))
+}
@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/gabs"
 	"github.com/giantswarm/gscliauth/config"
@@ -20,6 +21,7 @@ import (
 	"github.com/giantswarm/gsctl/client"
 
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/wait"
 	"github.com/giantswarm/gsctl/testutils"
 )
 
@@ -47,6 +49,7 @@ func TestCollectArguments(t *testing.T) {
 				AuthToken:       "some-token",
 				ClusterNameOrID: "clusterid",
 				Scheme:          "giantswarm",
+				WaitTimeout:     wait.DefaultTimeout,
 			},
 		},
 		{
@@ -56,6 +59,7 @@ func TestCollectArguments(t *testing.T) {
 				AuthToken:       "some-token",
 				ClusterNameOrID: "clusterid",
 				Scheme:          "giantswarm",
+				WaitTimeout:     wait.DefaultTimeout,
 				WorkersMax:      5,
 				WorkersMaxSet:   false,
 				WorkersMin:      5,
@@ -71,6 +75,7 @@ func TestCollectArguments(t *testing.T) {
 				AuthToken:       "some-token",
 				ClusterNameOrID: "clusterid",
 				Scheme:          "giantswarm",
+				WaitTimeout:     wait.DefaultTimeout,
 				WorkersMaxSet:   false,
 				WorkersMin:      12,
 				WorkersMinSet:   true,
@@ -84,6 +89,7 @@ func TestCollectArguments(t *testing.T) {
 				AuthToken:       "some-token",
 				ClusterNameOrID: "clusterid",
 				Scheme:          "giantswarm",
+				WaitTimeout:     wait.DefaultTimeout,
 				WorkersMaxSet:   true,
 				WorkersMax:      12,
 				WorkersMinSet:   false,
@@ -97,6 +103,7 @@ func TestCollectArguments(t *testing.T) {
 				AuthToken:       "some-token",
 				ClusterNameOrID: "clusterid",
 				Scheme:          "giantswarm",
+				WaitTimeout:     wait.DefaultTimeout,
 				WorkersMax:      5,
 				WorkersMaxSet:   true,
 				WorkersMin:      5,
@@ -105,6 +112,21 @@ func TestCollectArguments(t *testing.T) {
 				WorkersSet:      true,
 			},
 		},
+		{
+			[]string{"clusterid", "--num-workers=3", "--wait", "--timeout=20m"},
+			Arguments{
+				APIEndpoint:     "https://foo",
+				AuthToken:       "some-token",
+				ClusterNameOrID: "clusterid",
+				Scheme:          "giantswarm",
+				Wait:            true,
+				WaitTimeout:     20 * time.Minute,
+				WorkersMax:      3,
+				WorkersMin:      3,
+				Workers:         3,
+				WorkersSet:      true,
+			},
+		},
 	}
 
 	fs := afero.NewMemMapFs()
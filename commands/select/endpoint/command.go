@@ -65,11 +65,8 @@ func selectEndpointPreRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	}
 
 	// print output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
 }
 
 func verifySelectEndpointPreconditions(cmdLineArgs []string) error {
@@ -87,7 +84,7 @@ func selectEndpointRunOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		if config.IsEndpointNotDefinedError(err) {
 			fmt.Println(color.RedString("The endpoint given is not defined."))
 			fmt.Println("Please use 'gsctl login <email> -e <endpoint>' to add a new endpoint first.")
-			os.Exit(1)
+			os.Exit(errors.ExitCodeNotFound)
 		}
 		fmt.Println(color.RedString("Error: " + err.Error()))
 	} else {
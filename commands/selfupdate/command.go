@@ -0,0 +1,262 @@
+// Package selfupdate implements the 'self-update' command.
+package selfupdate
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/buildinfo"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	updater "github.com/giantswarm/gsctl/pkg/selfupdate"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// Command is the cobra command for 'gsctl self-update'
+	Command = &cobra.Command{
+		Use:   "self-update",
+		Short: "Update gsctl to the latest version",
+		Long: `Replace this gsctl binary with the latest release.
+
+The release archive for the current operating system and architecture is
+downloaded and verified against the checksums file published with the
+release. Then the binary is replaced. The previous binary is kept next to
+the new one, so that the update can be reverted using --rollback.
+
+Examples:
+
+  gsctl self-update
+
+  gsctl self-update --version 0.24.0
+
+  gsctl self-update --rollback
+`,
+
+		// PreRun checks a few general things, like flag combinations.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	actionUpdated    = "updated"
+	actionUpToDate   = "up-to-date"
+	actionRolledBack = "rolled-back"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.Version, "version", "", "", "Version to install instead of the latest one, e. g. '0.24.0'.")
+	Command.Flags().BoolVarP(&flags.Rollback, "rollback", "", false, "Restore the binary replaced by the last update.")
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "Install the version even if it is already installed.")
+	Command.Flags().StringVarP(&flags.ReleaseBaseURL, "release-base-url", "", updater.DefaultBaseURL, "URL to download releases from.")
+	Command.Flags().MarkHidden("release-base-url")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	baseURL        string
+	currentVersion string
+	// executable is the binary to replace. Empty means the running one.
+	executable string
+	force      bool
+	rollback   bool
+	verbose    bool
+	version    string
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments() Arguments {
+	return Arguments{
+		baseURL:        flags.ReleaseBaseURL,
+		currentVersion: currentVersion(),
+		force:          flags.Force,
+		rollback:       flags.Rollback,
+		verbose:        flags.Verbose,
+		version:        strings.TrimPrefix(flags.Version, "v"),
+	}
+}
+
+// currentVersion returns the version of this binary, or "0.0.0" if it was
+// not built as a release.
+func currentVersion() string {
+	if buildinfo.Version == buildinfo.VersionPlaceholder || buildinfo.Version == "" {
+		return "0.0.0"
+	}
+
+	return strings.Replace(buildinfo.Version, "+git", "", 1)
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.rollback && (args.version != "" || args.force) {
+		return microerror.Maskf(errors.ConflictingFlagsError, "the flag --rollback cannot be combined with --version or --force")
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	errors.Exit(err)
+}
+
+type selfUpdateResult struct {
+	action      string
+	fromVersion string
+	toVersion   string
+	executable  string
+	backup      string
+}
+
+// selfUpdate installs the requested version or rolls back.
+func selfUpdate(args Arguments) (*selfUpdateResult, error) {
+	u, err := updater.New(updater.Config{
+		BaseURL:    args.baseURL,
+		Executable: args.executable,
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	result := &selfUpdateResult{
+		fromVersion: args.currentVersion,
+		executable:  u.Executable(),
+		backup:      u.BackupPath(),
+	}
+
+	if args.rollback {
+		err = u.Rollback()
+		if updater.IsNoBackup(err) {
+			return nil, microerror.Mask(err)
+		} else if os.IsPermission(microerror.Cause(err)) {
+			return nil, microerror.Maskf(errors.CouldNotWriteFileError, err.Error())
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		result.action = actionRolledBack
+		return result, nil
+	}
+
+	result.toVersion = args.version
+	if result.toVersion == "" {
+		result.toVersion, err = u.LatestVersion()
+		if err != nil {
+			return nil, microerror.Maskf(errors.UpdateCheckFailed, err.Error())
+		}
+		result.toVersion = strings.TrimPrefix(result.toVersion, "v")
+	}
+
+	if !args.force && result.toVersion == result.fromVersion {
+		result.action = actionUpToDate
+		return result, nil
+	}
+
+	if args.version == "" && !args.force {
+		// Don't downgrade accidentally, e. g. when a release was withdrawn.
+		comp, err := util.CompareVersions(result.toVersion, result.fromVersion)
+		if err == nil && comp < 0 {
+			result.action = actionUpToDate
+			return result, nil
+		}
+	}
+
+	fmt.Printf("Downloading %s %s for %s/%s\n", config.ProgramName, result.toVersion, runtime.GOOS, runtime.GOARCH)
+
+	err = u.Update(result.toVersion)
+	if updater.IsReleaseNotFound(err) {
+		return nil, microerror.Maskf(errors.ReleaseNotFoundError, err.Error())
+	} else if os.IsPermission(microerror.Cause(err)) {
+		return nil, microerror.Maskf(errors.CouldNotWriteFileError, err.Error())
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	result.action = actionUpdated
+	return result, nil
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	result, err := selfUpdate(arguments)
+	if err != nil {
+		handleError(err)
+		errors.Exit(err)
+	}
+
+	switch result.action {
+	case actionUpToDate:
+		fmt.Println(color.GreenString("%s %s is up to date.", config.ProgramName, result.fromVersion))
+		if arguments.version == "" {
+			fmt.Printf("The latest release is %s.\n", result.toVersion)
+		}
+	case actionUpdated:
+		fmt.Println(color.GreenString("Updated %s from %s to %s.", config.ProgramName, result.fromVersion, result.toVersion))
+		fmt.Printf("The previous binary has been kept as %s.\n", result.backup)
+		fmt.Printf("To revert the update, execute '%s self-update --rollback'.\n", config.ProgramName)
+	case actionRolledBack:
+		fmt.Println(color.GreenString("Restored the previous %s binary.", config.ProgramName))
+		fmt.Printf("The replaced binary has been kept as %s. Rolling back again restores it.\n", result.backup)
+	}
+}
+
+func handleError(err error) {
+	errors.HandleCommonErrors(err)
+
+	var headline string
+	var subtext string
+
+	switch {
+	case errors.IsConflictingFlagsError(err):
+		headline = "Conflicting flags used"
+		subtext = "The flag --rollback cannot be combined with --version or --force."
+	case errors.IsUpdateCheckFailed(err):
+		headline = "Could not determine the latest version"
+		subtext = "Please try again later or give the version to install using --version."
+	case errors.IsReleaseNotFoundError(err):
+		headline = "Release not found"
+		subtext = fmt.Sprintf("There is no release artifact of this version for %s/%s. Please check https://github.com/giantswarm/gsctl/releases.", runtime.GOOS, runtime.GOARCH)
+	case updater.IsChecksumMismatch(err):
+		headline = "Checksum mismatch"
+		subtext = "The downloaded archive does not match the published checksum. The binary has not been replaced. Please try again."
+	case updater.IsChecksumMissing(err):
+		headline = "Checksum missing"
+		subtext = "The release does not contain a checksum for this platform, so the download cannot be verified. The binary has not been replaced."
+	case updater.IsBinaryNotFound(err):
+		headline = "Invalid release archive"
+		subtext = "The downloaded archive does not contain a gsctl binary. The binary has not been replaced."
+	case updater.IsNoBackup(err):
+		headline = "No previous binary found"
+		subtext = "There is nothing to roll back to, as no update has been installed via 'gsctl self-update'."
+	case errors.IsCouldNotWriteFileError(err):
+		headline = "Could not replace the binary"
+		subtext = "Please make sure you have permission to write to the directory containing gsctl, or install it manually."
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
@@ -0,0 +1,180 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	updater "github.com/giantswarm/gsctl/pkg/selfupdate"
+)
+
+// releaseServer serves a release 1.2.3 for the current platform, with 1.2.3
+// being the latest release.
+func releaseServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("release archives for windows are zip files")
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	content := []byte("new")
+	dir := fmt.Sprintf("gsctl-1.2.3-%s-%s", runtime.GOOS, runtime.GOARCH)
+	err := tw.WriteHeader(&tar.Header{Name: dir + "/gsctl", Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw.Write(content)
+	tw.Close()
+	gz.Close()
+
+	archive := buf.Bytes()
+	sum := sha256.Sum256(archive)
+	files := map[string][]byte{
+		dir + ".tar.gz":             archive,
+		"gsctl-1.2.3-checksums.txt": []byte(hex.EncodeToString(sum[:]) + "  " + dir + ".tar.gz\n"),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/tag/1.2.3")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[filepath.Base(r.URL.Path)]
+		if !ok || filepath.Base(filepath.Dir(r.URL.Path)) != "1.2.3" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func tempExecutable(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "gsctl-self-update")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "gsctl")
+	err = ioutil.WriteFile(path, []byte("old"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// TestVerifyPreconditions tests flag combinations.
+func TestVerifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args       Arguments
+		errorMatch func(error) bool
+	}{
+		{Arguments{}, nil},
+		{Arguments{version: "1.2.3", force: true}, nil},
+		{Arguments{rollback: true}, nil},
+		{Arguments{rollback: true, version: "1.2.3"}, errors.IsConflictingFlagsError},
+		{Arguments{rollback: true, force: true}, errors.IsConflictingFlagsError},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatch == nil {
+				if err != nil {
+					t.Errorf("Case %d - Unexpected error %#v", i, err)
+				}
+			} else if !tc.errorMatch(err) {
+				t.Errorf("Case %d - Error did not match expectation: %#v", i, err)
+			}
+		})
+	}
+}
+
+// TestSelfUpdate tests updating to the latest version and rolling back.
+func TestSelfUpdate(t *testing.T) {
+	ts := releaseServer(t)
+	defer ts.Close()
+
+	executable := tempExecutable(t)
+	args := Arguments{
+		baseURL:        ts.URL,
+		currentVersion: "1.0.0",
+		executable:     executable,
+	}
+
+	result, err := selfUpdate(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if result.action != actionUpdated || result.toVersion != "1.2.3" {
+		t.Errorf("Unexpected result %#v", result)
+	}
+	content, _ := ioutil.ReadFile(executable)
+	if string(content) != "new" {
+		t.Errorf("Expected the new binary, got %q", content)
+	}
+
+	// The new version is the latest one.
+	args.currentVersion = "1.2.3"
+	result, err = selfUpdate(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if result.action != actionUpToDate {
+		t.Errorf("Expected to be up to date, got %#v", result)
+	}
+
+	args.rollback = true
+	result, err = selfUpdate(args)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if result.action != actionRolledBack {
+		t.Errorf("Unexpected result %#v", result)
+	}
+	content, _ = ioutil.ReadFile(executable)
+	if string(content) != "old" {
+		t.Errorf("Expected the old binary after rollback, got %q", content)
+	}
+}
+
+// TestSelfUpdateErrors tests pinning an unknown version and rolling back
+// without a previous binary.
+func TestSelfUpdateErrors(t *testing.T) {
+	ts := releaseServer(t)
+	defer ts.Close()
+
+	_, err := selfUpdate(Arguments{baseURL: ts.URL, currentVersion: "1.0.0", executable: tempExecutable(t), version: "9.9.9"})
+	if !errors.IsReleaseNotFoundError(err) {
+		t.Errorf("Expected release not found error, got %#v", err)
+	}
+	if errors.ExitCode(err) != errors.ExitCodeNotFound {
+		t.Errorf("Expected exit code %d, got %d", errors.ExitCodeNotFound, errors.ExitCode(err))
+	}
+
+	_, err = selfUpdate(Arguments{baseURL: ts.URL, executable: tempExecutable(t), rollback: true})
+	if !updater.IsNoBackup(err) {
+		t.Errorf("Expected no backup error, got %#v", err)
+	}
+}
@@ -0,0 +1,265 @@
+// Package app implements the 'show app' command.
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
+	"github.com/giantswarm/gsctl/util"
+)
+
+var (
+	// ShowAppCommand is the cobra command for 'gsctl show app'
+	ShowAppCommand = &cobra.Command{
+		Use: "app <cluster-name/cluster-id>/<app-name>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Show app details",
+		Long: `Display details of an app installed in a cluster.
+
+Examples:
+
+  gsctl show app f01r4/nginx-ingress-controller
+  gsctl show app "Cluster name"/nginx-ingress-controller
+  gsctl show app f01r4/nginx-ingress-controller --output json
+  gsctl show app f01r4/nginx-ingress-controller --output jsonpath='{.status.version}'
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments *Arguments
+)
+
+const (
+	activityName = "show-app"
+)
+
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	ShowAppCommand.ResetFlags()
+	ShowAppCommand.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint       string
+	appName           string
+	authToken         string
+	clusterNameOrID   string
+	outputFormat      string
+	userProvidedToken string
+}
+
+func collectArguments(positionalArgs []string) (*Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	parts := strings.Split(positionalArgs[0], "/")
+
+	if len(parts) < 2 {
+		return nil, microerror.Maskf(errors.InvalidAppArgumentError, "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details.")
+	}
+
+	return &Arguments{
+		apiEndpoint:       endpoint,
+		appName:           parts[1],
+		authToken:         token,
+		clusterNameOrID:   parts[0],
+		outputFormat:      flags.OutputFormat,
+		userProvidedToken: flags.Token,
+	}, nil
+}
+
+func verifyPreconditions(args *Arguments) error {
+	if args.apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if config.Config.Token == "" && args.authToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.clusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+	if args.appName == "" {
+		return microerror.Mask(errors.AppNameMissingError)
+	}
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	var err error
+	arguments, err = collectArguments(positionalArgs)
+	if err == nil {
+		err = verifyPreconditions(arguments)
+		if err == nil {
+			return
+		}
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	app, err := fetchApp(arguments)
+	if err != nil {
+		handleError(microerror.Mask(err))
+		os.Exit(errors.ExitCode(err))
+	}
+
+	out, err := getOutput(app, arguments.outputFormat)
+	if err != nil {
+		handleError(microerror.Mask(err))
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(out)
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	var (
+		headline string
+		subtext  string
+	)
+	{
+		switch {
+		case errors.IsInvalidAppArgument(err):
+			headline = "Invalid argument syntax"
+			subtext = "Please give the cluster name or ID, followed by /, followed by the app name."
+
+		case errors.IsAppNameMissing(err):
+			headline = "No app name specified."
+			subtext = "Please specify an app name. Use --help for details."
+
+		case errors.IsAppNotFound(err):
+			headline = "App not found"
+			subtext = fmt.Sprintf("Could not find an app named '%s' in this cluster. Check 'gsctl list apps %s' to make sure.", arguments.appName, arguments.clusterNameOrID)
+
+		case errors.IsClusterNotFoundError(err):
+			headline = "Cluster not found"
+			subtext = "Could not find a cluster with this name/ID. Check 'gsctl list clusters' to make sure."
+
+		default:
+			headline = err.Error()
+		}
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
+
+// fetchApp fetches the details of one app installed in a cluster.
+func fetchApp(args *Arguments) (*models.V4GetClusterAppsResponseItems, error) {
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	app, err := clientWrapper.GetApp(clusterID, args.appName, auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if app == nil {
+		return nil, microerror.Mask(errors.AppNotFoundError)
+	}
+
+	return app, nil
+}
+
+func getOutput(app *models.V4GetClusterAppsResponseItems, outputFormat string) (string, error) {
+	printer, err := output.NewPrinter(outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if !printer.IsTable() {
+		out, err := printer.Sprint(app, ".metadata.name")
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return out, nil
+	}
+
+	spec := app.Spec
+	if spec == nil {
+		spec = &models.V4GetClusterAppsResponseItemsSpec{}
+	}
+	status := app.Status
+	if status == nil {
+		status = &models.V4GetClusterAppsResponseItemsStatus{}
+	}
+
+	var table []string
+	{
+		table = append(table, color.YellowString("Name:")+"|"+app.Metadata.Name)
+		table = append(table, color.YellowString("Chart name:")+"|"+spec.Name)
+		table = append(table, color.YellowString("Catalog:")+"|"+spec.Catalog)
+		table = append(table, color.YellowString("Namespace:")+"|"+spec.Namespace)
+		table = append(table, color.YellowString("Version:")+"|"+spec.Version)
+		table = append(table, color.YellowString("Installed version:")+"|"+formatOptional(status.Version))
+		table = append(table, color.YellowString("App version:")+"|"+formatOptional(status.AppVersion))
+
+		releaseStatus := "n/a"
+		lastDeployed := "n/a"
+		if status.Release != nil {
+			releaseStatus = formatOptional(status.Release.Status)
+			if status.Release.LastDeployed != "" {
+				lastDeployed = util.ShortDate(util.ParseDate(status.Release.LastDeployed))
+			}
+		}
+		table = append(table, color.YellowString("Status:")+"|"+releaseStatus)
+		table = append(table, color.YellowString("Last deployed:")+"|"+lastDeployed)
+
+		userConfig := "none"
+		if spec.UserConfig != nil && spec.UserConfig.Configmap != nil && spec.UserConfig.Configmap.Name != "" {
+			userConfig = fmt.Sprintf("%s/%s", spec.UserConfig.Configmap.Namespace, spec.UserConfig.Configmap.Name)
+		}
+		table = append(table, color.YellowString("User values config map:")+"|"+userConfig)
+	}
+
+	return columnize.SimpleFormat(table), nil
+}
+
+func formatOptional(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+
+	return s
+}
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+const appsResponse = `[
+	{"metadata": {"name": "nginx-ingress-controller"}, "spec": {"catalog": "giantswarm", "name": "nginx-ingress-controller-app", "namespace": "kube-system", "version": "1.6.9", "user_config": {"configmap": {"name": "nginx-ingress-controller-user-values", "namespace": "cluster-id"}}}, "status": {"app_version": "0.30.0", "release": {"last_deployed": "2020-04-21T10:43:22Z", "status": "DEPLOYED"}, "version": "1.6.9"}}
+]`
+
+func newMockServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch uri := r.URL.Path; uri {
+		case "/v4/clusters/cluster-id/apps/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(appsResponse))
+
+		case "/v4/clusters/":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"id": "cluster-id", "name": "Name of the cluster", "owner": "acme"}]`))
+
+		default:
+			t.Errorf("Unsupported route %s called in mock server", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "Not found."}`))
+		}
+	}))
+}
+
+// Test_ShowApp tests the table output for an existing app.
+func Test_ShowApp(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	args, err := collectArguments([]string{"cluster-id/nginx-ingress-controller"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	args.apiEndpoint = mockServer.URL
+	args.authToken = "my-token"
+	args.outputFormat = "table"
+
+	err = verifyPreconditions(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	app, err := fetchApp(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	output, err := getOutput(app, args.outputFormat)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+
+	expected := `Name:                    nginx-ingress-controller
+Chart name:              nginx-ingress-controller-app
+Catalog:                 giantswarm
+Namespace:               kube-system
+Version:                 1.6.9
+Installed version:       1.6.9
+App version:             0.30.0
+Status:                  DEPLOYED
+Last deployed:           2020 Apr 21, 10:43 UTC
+User values config map:  cluster-id/nginx-ingress-controller-user-values`
+
+	if diff := cmp.Diff(expected, output); diff != "" {
+		t.Errorf("Output is incorrect (-expected +got):\n%s", diff)
+	}
+}
+
+// Test_ShowAppNotFound tests showing an app that is not installed.
+func Test_ShowAppNotFound(t *testing.T) {
+	mockServer := newMockServer(t)
+	defer mockServer.Close()
+
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	args := &Arguments{
+		apiEndpoint:     mockServer.URL,
+		appName:         "non-existing",
+		authToken:       "my-token",
+		clusterNameOrID: "Name of the cluster",
+		outputFormat:    "table",
+	}
+
+	_, err := fetchApp(args)
+	if !errors.IsAppNotFound(err) {
+		t.Errorf("Expected AppNotFoundError, got %#v", err)
+	}
+}
+
+// Test_ShowAppInvalidArgument tests argument parsing failures.
+func Test_ShowAppInvalidArgument(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	_, err := collectArguments([]string{"cluster-id"})
+	if !errors.IsInvalidAppArgument(err) {
+		t.Errorf("Expected InvalidAppArgumentError, got %#v", err)
+	}
+
+	args, err := collectArguments([]string{"cluster-id/"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	args.authToken = "my-token"
+	args.apiEndpoint = "https://foo"
+	args.outputFormat = "table"
+	err = verifyPreconditions(args)
+	if !errors.IsAppNameMissing(err) {
+		t.Errorf("Expected AppNameMissingError, got %#v", err)
+	}
+}
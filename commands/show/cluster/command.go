@@ -24,6 +24,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/nodespec"
+	"github.com/giantswarm/gsctl/pkg/output"
 	"github.com/giantswarm/gsctl/util"
 	"github.com/giantswarm/gsctl/webui"
 )
@@ -40,6 +41,8 @@ Examples:
 
   gsctl show cluster c7t2o
   gsctl show cluster "Cluster name"
+  gsctl show cluster c7t2o --output yaml
+  gsctl show cluster c7t2o --output jsonpath='{.release_version}'
 `,
 
 		// PreRun checks a few general things, like authentication.
@@ -62,16 +65,40 @@ const (
 	naString = "n/a"
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	ShowClusterCommand.ResetFlags()
+	ShowClusterCommand.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
 // Arguments specifies all the arguments to be used for our business function.
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
 	scheme            string
 	clusterNameOrID   string
+	outputFormat      string
 	userProvidedToken string
 	verbose           bool
 }
 
+// clusterV4Output is the structured output for a v4 cluster.
+type clusterV4Output struct {
+	*models.V4ClusterDetailsResponse
+	Credential *models.V4GetCredentialResponse `json:"credential,omitempty"`
+}
+
+// clusterV5Output is the structured output for a v5 cluster,
+// including its node pools.
+type clusterV5Output struct {
+	*models.V5ClusterDetailsResponse
+	Credential *models.V4GetCredentialResponse `json:"credential,omitempty"`
+	NodePools  models.V5GetNodePoolsResponse   `json:"node_pools"`
+}
+
 // collectArguments fills arguments from user input, config, and environment.
 func collectArguments() Arguments {
 	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
@@ -83,13 +110,14 @@ func collectArguments() Arguments {
 		authToken:         token,
 		scheme:            scheme,
 		clusterNameOrID:   "",
+		outputFormat:      flags.OutputFormat,
 		userProvidedToken: flags.Token,
 		verbose:           flags.Verbose,
 	}
 }
 
 func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "show cluster", "get clusters", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-clusters/"))
+	fmt.Fprint(output.NoticeWriter(flags.OutputFormat), util.GetDeprecatedNotice(config.Config.Provider, "show cluster", "get clusters", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-clusters/"))
 
 	arguments = collectArguments()
 	err := verifyPreconditions(arguments, cmdLineArgs)
@@ -98,12 +126,12 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	// handle non-common errors
-	fmt.Println(color.RedString(err.Error()))
-	os.Exit(1)
+	errors.PrintError(err, err.Error(), "")
+	errors.Exit(err)
 }
 
 func verifyPreconditions(args Arguments, cmdLineArgs []string) error {
@@ -116,6 +144,9 @@ func verifyPreconditions(args Arguments, cmdLineArgs []string) error {
 	if len(cmdLineArgs) == 0 {
 		return microerror.Mask(errors.ClusterNameOrIDMissingError)
 	}
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
 	return nil
 }
 
@@ -232,6 +263,9 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 	var clusterStatus *client.ClusterStatus
 	var nodePools *models.V5GetNodePoolsResponse
 
+	// Informational messages must not end up in structured output.
+	notices := output.NoticeWriter(args.outputFormat)
+
 	var err error
 	args.clusterNameOrID, err = clustercache.GetID(args.apiEndpoint, args.clusterNameOrID, clientWrapper)
 	if err != nil {
@@ -240,7 +274,7 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 
 	// first try v5
 	if args.verbose {
-		fmt.Println(color.WhiteString("Fetching details for cluster via v5 API endpoint."))
+		fmt.Fprintln(notices, color.WhiteString("Fetching details for cluster via v5 API endpoint."))
 	}
 	clusterDetailsV5, v5Err := getClusterDetailsV5(args)
 	if v5Err == nil {
@@ -265,7 +299,7 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 
 		// Fall back to v4.
 		if args.verbose {
-			fmt.Println(color.WhiteString("No usable v5 response. Fetching details for cluster via v4 API endpoint."))
+			fmt.Fprintln(notices, color.WhiteString("No usable v5 response. Fetching details for cluster via v4 API endpoint."))
 		}
 
 		var clusterDetailsV4Err error
@@ -277,7 +311,7 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 		}
 
 		if args.verbose {
-			fmt.Println(color.WhiteString("Fetching status for v4 cluster."))
+			fmt.Fprintln(notices, color.WhiteString("Fetching status for v4 cluster."))
 		}
 		auxParams := clientWrapper.DefaultAuxiliaryParams()
 		auxParams.ActivityName = activityName
@@ -310,18 +344,18 @@ func getClusterDetails(clientWrapper *client.Wrapper, args Arguments) (
 
 		if credentialID != "" {
 			if args.verbose {
-				fmt.Println(color.WhiteString("Fetching credential details for organization %s", clusterOwner))
+				fmt.Fprintln(notices, color.WhiteString("Fetching credential details for organization %s", clusterOwner))
 			}
 
 			var credentialDetailsErr error
 			credentialDetails, credentialDetailsErr = getOrgCredentials(clusterOwner, credentialID, args)
 			if credentialDetailsErr != nil {
 				if time.Since(created) < clusterCreationExpectedDuration {
-					fmt.Println("This is expected for clusters which are most likely still in creation.")
+					fmt.Fprintln(notices, "This is expected for clusters which are most likely still in creation.")
 				}
 				// Print any error occurring here, but don't return, as this is non-critical.
-				fmt.Printf(color.YellowString("Warning: credential details for org %s (credential ID %s) could not be fetched.\n", clusterOwner, credentialID))
-				fmt.Printf("Error details: %s\n", credentialDetailsErr)
+				fmt.Fprintf(notices, color.YellowString("Warning: credential details for org %s (credential ID %s) could not be fetched.\n", clusterOwner, credentialID))
+				fmt.Fprintf(notices, "Error details: %s\n", credentialDetailsErr)
 			}
 		}
 	}
@@ -359,7 +393,7 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	clientWrapper, err := client.NewWithConfig(arguments.apiEndpoint, arguments.userProvidedToken)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	clusterDetailsV4, clusterDetailsV5, nodePools, clusterStatus, credentialDetails, err := getClusterDetails(clientWrapper, arguments)
@@ -370,7 +404,7 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	}
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	releaseInfoConfig := releaseinfo.Config{
@@ -379,7 +413,17 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	releaseInfo, err := releaseinfo.New(releaseInfoConfig)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if !output.IsTableFormat(arguments.outputFormat) {
+		out, err := getStructuredOutput(arguments, clusterDetailsV4, clusterDetailsV5, nodePools, credentialDetails)
+		if err != nil {
+			handleError(microerror.Mask(err))
+			os.Exit(errors.ExitCode(err))
+		}
+		fmt.Println(out)
+		return
 	}
 
 	if clusterDetailsV4 != nil {
@@ -389,6 +433,46 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	}
 }
 
+// getStructuredOutput returns the cluster details in one of the
+// non-table output formats.
+func getStructuredOutput(
+	args Arguments,
+	clusterDetailsV4 *models.V4ClusterDetailsResponse,
+	clusterDetailsV5 *models.V5ClusterDetailsResponse,
+	nodePools *models.V5GetNodePoolsResponse,
+	credentialDetails *models.V4GetCredentialResponse,
+) (string, error) {
+	printer, err := output.NewPrinter(args.outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	var data interface{}
+	if clusterDetailsV4 != nil {
+		data = clusterV4Output{
+			V4ClusterDetailsResponse: clusterDetailsV4,
+			Credential:               credentialDetails,
+		}
+	} else {
+		o := clusterV5Output{
+			V5ClusterDetailsResponse: clusterDetailsV5,
+			Credential:               credentialDetails,
+			NodePools:                models.V5GetNodePoolsResponse{},
+		}
+		if nodePools != nil {
+			o.NodePools = *nodePools
+		}
+		data = o
+	}
+
+	out, err := printer.Sprint(data, ".id")
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return out, nil
+}
+
 // printV4Result prints the detils for a V4 cluster.
 func printV4Result(
 	args Arguments,
@@ -418,36 +502,36 @@ func printV4Result(
 	webUIURL, _ := webui.ClusterDetailsURL(args.apiEndpoint, clusterDetails.ID, clusterDetails.Owner)
 
 	// print table
-	output := []string{}
+	rows := []string{}
 
-	output = append(output, color.YellowString("ID:")+"|"+clusterDetails.ID)
+	rows = append(rows, color.YellowString("ID:")+"|"+clusterDetails.ID)
 
-	output = append(output, color.YellowString("Name:")+"|"+stringOrPlaceholder(clusterDetails.Name))
-	output = append(output, color.YellowString("Created:")+"|"+formatDate(clusterDetails.CreateDate))
-	output = append(output, color.YellowString("Organization:")+"|"+clusterDetails.Owner)
-	output = append(output, color.YellowString("Kubernetes API endpoint:")+"|"+clusterDetails.APIEndpoint)
-	output = append(output, color.YellowString("Workload cluster release:")+"|"+stringOrPlaceholder(clusterDetails.ReleaseVersion))
+	rows = append(rows, color.YellowString("Name:")+"|"+stringOrPlaceholder(clusterDetails.Name))
+	rows = append(rows, color.YellowString("Created:")+"|"+formatDate(clusterDetails.CreateDate))
+	rows = append(rows, color.YellowString("Organization:")+"|"+clusterDetails.Owner)
+	rows = append(rows, color.YellowString("Kubernetes API endpoint:")+"|"+clusterDetails.APIEndpoint)
+	rows = append(rows, color.YellowString("Workload cluster release:")+"|"+stringOrPlaceholder(clusterDetails.ReleaseVersion))
 
 	{
 		kubernetesVersion := formatKubernetesVersion(releaseInfo, clusterDetails.ReleaseVersion)
-		output = append(output, fmt.Sprintf("%s|%s", color.YellowString("Kubernetes version:"), kubernetesVersion))
+		rows = append(rows, fmt.Sprintf("%s|%s", color.YellowString("Kubernetes version:"), kubernetesVersion))
 	}
 
 	// BYOC credentials.
 	if credentialDetails != nil && credentialDetails.ID != "" {
-		output = append(output, formatCredentialDetails(credentialDetails)...)
+		rows = append(rows, formatCredentialDetails(credentialDetails)...)
 	}
 
 	if len(clusterDetails.AvailabilityZones) > 0 {
 		sort.Strings(clusterDetails.AvailabilityZones)
-		output = append(output, color.YellowString("Availability Zones:")+"|"+strings.Join(clusterDetails.AvailabilityZones, ", "))
+		rows = append(rows, color.YellowString("Availability Zones:")+"|"+strings.Join(clusterDetails.AvailabilityZones, ", "))
 	}
 
 	// Instance type / VM size
 	if clusterDetails.Workers[0].Aws != nil && clusterDetails.Workers[0].Aws.InstanceType != "" {
-		output = append(output, color.YellowString("Worker EC2 instance type:")+"|"+clusterDetails.Workers[0].Aws.InstanceType)
+		rows = append(rows, color.YellowString("Worker EC2 instance type:")+"|"+clusterDetails.Workers[0].Aws.InstanceType)
 	} else if clusterDetails.Workers[0].Azure != nil && clusterDetails.Workers[0].Azure.VMSize != "" {
-		output = append(output, color.YellowString("Worker VM size:")+"|"+clusterDetails.Workers[0].Azure.VMSize)
+		rows = append(rows, color.YellowString("Worker VM size:")+"|"+clusterDetails.Workers[0].Azure.VMSize)
 	}
 
 	// scaling info
@@ -463,35 +547,35 @@ func printV4Result(
 			scalingInfo = fmt.Sprintf("autoscaling between %d and %d", minScale, clusterDetails.Scaling.Max)
 		}
 	}
-	output = append(output, color.YellowString("Worker node scaling:")+"|"+stringOrPlaceholder(scalingInfo))
+	rows = append(rows, color.YellowString("Worker node scaling:")+"|"+stringOrPlaceholder(scalingInfo))
 
 	// what the autoscaler tries to reach as a target (only interesting if not pinned)
 	if clusterStatus != nil && clusterStatus.Cluster != nil && clusterDetails.Scaling != nil && *clusterDetails.Scaling.Min != clusterDetails.Scaling.Max {
-		output = append(output, color.YellowString("Desired worker node count:")+"|"+fmt.Sprintf("%d", clusterStatus.Cluster.Scaling.DesiredCapacity))
+		rows = append(rows, color.YellowString("Desired worker node count:")+"|"+fmt.Sprintf("%d", clusterStatus.Cluster.Scaling.DesiredCapacity))
 	}
 
 	// current number of workers
-	output = append(output, color.YellowString("Worker nodes running:")+"|"+fmt.Sprintf("%d", numWorkers))
+	rows = append(rows, color.YellowString("Worker nodes running:")+"|"+fmt.Sprintf("%d", numWorkers))
 
-	output = append(output, color.YellowString("CPU cores in workers:")+"|"+fmt.Sprintf("%d", sumWorkerCPUs(numWorkers, clusterDetails.Workers)))
-	output = append(output, color.YellowString("RAM in worker nodes (GB):")+"|"+fmt.Sprintf("%.2f", sumWorkerMemory(numWorkers, clusterDetails.Workers)))
+	rows = append(rows, color.YellowString("CPU cores in workers:")+"|"+fmt.Sprintf("%d", sumWorkerCPUs(numWorkers, clusterDetails.Workers)))
+	rows = append(rows, color.YellowString("RAM in worker nodes (GB):")+"|"+fmt.Sprintf("%.2f", sumWorkerMemory(numWorkers, clusterDetails.Workers)))
 
 	if clusterDetails.Kvm != nil {
-		output = append(output, color.YellowString("Storage in worker nodes (GB):")+"|"+fmt.Sprintf("%.2f", sumWorkerStorage(numWorkers, clusterDetails.Workers)))
+		rows = append(rows, color.YellowString("Storage in worker nodes (GB):")+"|"+fmt.Sprintf("%.2f", sumWorkerStorage(numWorkers, clusterDetails.Workers)))
 	}
 
 	// KVM ingress port mappings
 	if clusterDetails.Kvm != nil && len(clusterDetails.Kvm.PortMappings) > 0 {
 		for _, portMapping := range clusterDetails.Kvm.PortMappings {
-			output = append(output, color.YellowString(fmt.Sprintf("Ingress port for %s:", portMapping.Protocol))+"|"+fmt.Sprintf("%d", portMapping.Port))
+			rows = append(rows, color.YellowString(fmt.Sprintf("Ingress port for %s:", portMapping.Protocol))+"|"+fmt.Sprintf("%d", portMapping.Port))
 		}
 	}
 
 	if webUIURL != "" {
-		output = append(output, color.YellowString("Web UI:")+"|"+webUIURL)
+		rows = append(rows, color.YellowString("Web UI:")+"|"+webUIURL)
 	}
 
-	fmt.Println(columnize.SimpleFormat(output))
+	fmt.Println(columnize.SimpleFormat(rows))
 }
 
 // printV5Result prints details for a v5 clsuter.
@@ -768,7 +852,7 @@ func guessProviderInfo(fromNodePools *models.V5GetNodePoolsResponse) (interface{
 }
 
 func handleError(err error) {
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	headline := ""
@@ -784,10 +868,7 @@ func handleError(err error) {
 		subtext = "Please contact the Giant Swarm support team and share details about the command you just executed."
 	}
 
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
+	errors.PrintError(err, headline, subtext)
 }
 
 func formatKubernetesVersion(releaseInfo *releaseinfo.ReleaseInfo, releaseVersion string) string {
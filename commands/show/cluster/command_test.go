@@ -901,3 +901,54 @@ func toInt8Ptr(i int) *int8 {
 
 	return &c
 }
+
+// TestGetStructuredOutput tests non-table output for v4 and v5 clusters.
+func TestGetStructuredOutput(t *testing.T) {
+	var testCases = []struct {
+		outputFormat string
+		detailsV4    *models.V4ClusterDetailsResponse
+		detailsV5    *models.V5ClusterDetailsResponse
+		nodePools    *models.V5GetNodePoolsResponse
+		expected     string
+	}{
+		{
+			outputFormat: "jsonpath={.release_version}",
+			detailsV4: &models.V4ClusterDetailsResponse{
+				ID:             "v4cl1",
+				ReleaseVersion: "9.0.0",
+			},
+			expected: "9.0.0",
+		},
+		{
+			outputFormat: "name",
+			detailsV5: &models.V5ClusterDetailsResponse{
+				ID: "v5cl1",
+			},
+			expected: "v5cl1",
+		},
+		{
+			outputFormat: "template={{range .node_pools}}{{.id}} {{end}}",
+			detailsV5: &models.V5ClusterDetailsResponse{
+				ID: "v5cl1",
+			},
+			nodePools: &models.V5GetNodePoolsResponse{
+				&models.V5GetNodePoolsResponseItems{ID: "a7k"},
+				&models.V5GetNodePoolsResponseItems{ID: "b9x"},
+			},
+			expected: "a7k b9x ",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			args := Arguments{outputFormat: tc.outputFormat}
+			out, err := getStructuredOutput(args, tc.detailsV4, tc.detailsV5, tc.nodePools, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %#v", err)
+			}
+			if out != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, out)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/fatih/color"
 	"github.com/giantswarm/gscliauth/config"
 	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/giantswarm/microerror"
@@ -19,6 +18,7 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
 	"github.com/giantswarm/gsctl/nodespec"
+	"github.com/giantswarm/gsctl/pkg/output"
 )
 
 var (
@@ -36,6 +36,7 @@ Examples:
 
   gsctl show nodepool f01r4/75rh1
   gsctl show nodepool "Cluster name"/75rh1
+  gsctl show nodepool f01r4/75rh1 --output json
 `,
 
 		// PreRun checks a few general things, like authentication.
@@ -50,11 +51,21 @@ const (
 	activityName = "show-nodepool"
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	ShowNodepoolCommand.ResetFlags()
+	ShowNodepoolCommand.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
 	clusterNameOrID   string
 	nodePoolID        string
+	outputFormat      string
 	userProvidedToken string
 }
 
@@ -83,6 +94,7 @@ func collectArguments(positionalArgs []string) (*Arguments, error) {
 		authToken:         token,
 		clusterNameOrID:   parts[0],
 		nodePoolID:        parts[1],
+		outputFormat:      flags.OutputFormat,
 		userProvidedToken: flags.Token,
 	}, nil
 }
@@ -101,12 +113,15 @@ func verifyPreconditions(args *Arguments) error {
 	if args.nodePoolID == "" {
 		return microerror.Mask(errors.NodePoolIDMissingError)
 	}
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
 
 	return nil
 }
 
 func printValidation(cmd *cobra.Command, positionalArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "show nodepool", "get nodepools", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-nodepools/"))
+	fmt.Fprint(output.NoticeWriter(flags.OutputFormat), util.GetDeprecatedNotice(config.Config.Provider, "show nodepool", "get nodepools", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-nodepools/"))
 
 	args, err := collectArguments(positionalArgs)
 	if err == nil {
@@ -117,21 +132,21 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	handleError(err)
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func printResult(cmd *cobra.Command, positionalArgs []string) {
-	output, err := getOutput(positionalArgs)
+	out, err := getOutput(positionalArgs)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
-	fmt.Println(output)
+	fmt.Println(out)
 }
 
 func handleError(err error) {
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	var (
@@ -153,10 +168,7 @@ func handleError(err error) {
 		}
 	}
 
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
+	errors.PrintError(err, headline, subtext)
 }
 
 // fetchNodePool collects all information we would want to display
@@ -194,17 +206,30 @@ func getOutput(positionalArgs []string) (string, error) {
 		return "", microerror.Mask(err)
 	}
 
-	var output string
+	printer, err := output.NewPrinter(args.outputFormat)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if !printer.IsTable() {
+		out, err := printer.Sprint(nodePool, ".id")
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+		return out, nil
+	}
+
+	var out string
 	{
 		switch {
 		case nodePool.NodeSpec.Aws != nil:
-			output, err = getOutputAWS(nodePool)
+			out, err = getOutputAWS(nodePool)
 			if err != nil {
 				return "", microerror.Mask(err)
 			}
 
 		case nodePool.NodeSpec.Azure != nil:
-			output, err = getOutputAzure(nodePool)
+			out, err = getOutputAzure(nodePool)
 			if err != nil {
 				return "", microerror.Mask(err)
 			}
@@ -214,5 +239,5 @@ func getOutput(positionalArgs []string) (string, error) {
 		}
 	}
 
-	return output, nil
+	return out, nil
 }
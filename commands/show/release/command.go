@@ -16,6 +16,7 @@ import (
 	"github.com/giantswarm/gsctl/client/clienterror"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/output"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -29,6 +30,7 @@ var (
 Examples:
 
   gsctl show release 14.0.0
+  gsctl show release 14.0.0 --output json
 `,
 
 		// PreRun checks a few general things, like authentication.
@@ -45,9 +47,19 @@ const (
 	showReleaseActivityName = "show-release"
 )
 
+func init() {
+	initFlags()
+}
+
+func initFlags() {
+	ShowReleaseCommand.ResetFlags()
+	ShowReleaseCommand.Flags().StringVarP(&flags.OutputFormat, "output", "o", output.FormatTable, output.FlagUsage)
+}
+
 type Arguments struct {
 	apiEndpoint       string
 	authToken         string
+	outputFormat      string
 	releaseVersion    string
 	scheme            string
 	userProvidedToken string
@@ -62,6 +74,7 @@ func collectArguments() Arguments {
 	return Arguments{
 		apiEndpoint:       endpoint,
 		authToken:         token,
+		outputFormat:      flags.OutputFormat,
 		scheme:            scheme,
 		releaseVersion:    "",
 		userProvidedToken: flags.Token,
@@ -70,7 +83,7 @@ func collectArguments() Arguments {
 }
 
 func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
-	fmt.Print(util.GetDeprecatedNotice(config.Config.Provider, "show release", "get releases", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-releases/"))
+	fmt.Fprint(output.NoticeWriter(flags.OutputFormat), util.GetDeprecatedNotice(config.Config.Provider, "show release", "get releases", "https://docs.giantswarm.io/ui-api/kubectl-gs/get-releases/"))
 
 	arguments = collectArguments()
 	err := verifyShowReleasePreconditions(arguments, cmdLineArgs)
@@ -79,12 +92,12 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	// handle non-common errors
-	fmt.Println(color.RedString(err.Error()))
-	os.Exit(1)
+	errors.PrintError(err, err.Error(), "")
+	errors.Exit(err)
 }
 
 func verifyShowReleasePreconditions(args Arguments, cmdLineArgs []string) error {
@@ -97,6 +110,9 @@ func verifyShowReleasePreconditions(args Arguments, cmdLineArgs []string) error
 	if len(cmdLineArgs) == 0 {
 		return microerror.Mask(errors.ReleaseVersionMissingError)
 	}
+	if _, err := output.NewPrinter(args.outputFormat); err != nil {
+		return microerror.Maskf(errors.OutputFormatInvalidError, err.Error())
+	}
 	return nil
 }
 
@@ -135,19 +151,35 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	clientWrapper, err := client.NewWithConfig(arguments.apiEndpoint, arguments.userProvidedToken)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	release, err := getReleaseDetails(clientWrapper, arguments)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	if !output.IsTableFormat(arguments.outputFormat) {
+		printer, err := output.NewPrinter(arguments.outputFormat)
+		if err != nil {
+			handleError(microerror.Mask(err))
+			os.Exit(errors.ExitCode(err))
+		}
+
+		err = printer.Print(os.Stdout, release, ".version")
+		if err != nil {
+			handleError(microerror.Mask(err))
+			os.Exit(errors.ExitCode(err))
+		}
+
+		return
 	}
 
 	releaseData, err := getReleaseData(clientWrapper, *release.Version)
 	if err != nil {
 		handleError(microerror.Mask(err))
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	// success output
@@ -177,7 +209,7 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 }
 
 func handleError(err error) {
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	var headline = ""
@@ -192,10 +224,7 @@ func handleError(err error) {
 	}
 
 	// Print error output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
+	errors.PrintError(err, headline, subtext)
 }
 
 func formatComponentVersion(releaseData releaseinfo.ReleaseData, component, version string) string {
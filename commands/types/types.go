@@ -79,6 +79,13 @@ type ScalingDefinition struct {
 	Max int64 `yaml:"max,omitempty"`
 }
 
+// NodePoolScalingDefinition defines how a node pool can scale. Min is a
+// pointer so that an omitted value can be told apart from zero.
+type NodePoolScalingDefinition struct {
+	Min *int64 `yaml:"min,omitempty"`
+	Max int64  `yaml:"max,omitempty"`
+}
+
 // MasterDefinition defines a master in cluster creation, as introduced by the V5 API.
 type MasterDefinition struct {
 	AvailabilityZone string `yaml:"availability_zone,omitempty"`
@@ -111,6 +118,6 @@ type NodeSpec struct {
 type NodePoolDefinition struct {
 	Name              string                       `yaml:"name,omitempty"`
 	AvailabilityZones *AvailabilityZonesDefinition `yaml:"availability_zones,omitempty"`
-	Scaling           *ScalingDefinition           `yaml:"scaling,omitempty"`
+	Scaling           *NodePoolScalingDefinition   `yaml:"scaling,omitempty"`
 	NodeSpec          *NodeSpec                    `yaml:"node_spec,omitempty"`
 }
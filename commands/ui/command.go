@@ -0,0 +1,411 @@
+// Package ui implements the 'ui' command, a full-screen terminal UI for
+// browsing clusters and node pools.
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/skratchdot/open-golang/open"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/webui"
+)
+
+var (
+	// Command is the cobra command for 'gsctl ui'
+	Command = &cobra.Command{
+		Use:   "ui",
+		Short: "Browse clusters and node pools interactively",
+		Long: `Start a full-screen terminal UI to browse clusters and node pools.
+
+The cluster list can be filtered by name or ID, by organization using
+'org:<organization>' and by label using 'label:<key>=<value>'. Select a
+cluster and press enter to see its details and node pools, as printed by
+'gsctl show cluster' and 'gsctl list nodepools'.
+
+Keyboard actions create a kubeconfig for the selected cluster, scale a
+node pool and open the cluster in the web UI. Press '?' for all keys.
+
+The cluster list is refreshed in the background.
+
+Examples:
+
+  gsctl ui
+
+  gsctl ui --filter "org:acme label:environment=production"
+
+  gsctl ui --refresh-interval 1m
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	cmdFilter string
+
+	cmdRefreshInterval time.Duration
+
+	arguments Arguments
+
+	// openURL opens a URL in the browser. Replaced in tests.
+	openURL = open.Start
+)
+
+const (
+	activityName = "ui"
+
+	defaultRefreshInterval = 30 * time.Second
+	minRefreshInterval     = 5 * time.Second
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&cmdFilter, "filter", "", "", "Initial filter for the cluster list.")
+	Command.Flags().DurationVarP(&cmdRefreshInterval, "refresh-interval", "", defaultRefreshInterval, "Time between refreshes of the cluster list.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	apiEndpoint       string
+	authToken         string
+	configDirPath     string
+	filter            string
+	offline           bool
+	refreshInterval   time.Duration
+	userProvidedToken string
+	verbose           bool
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments() Arguments {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	return Arguments{
+		apiEndpoint:       endpoint,
+		authToken:         token,
+		configDirPath:     flags.ConfigDirPath,
+		filter:            cmdFilter,
+		offline:           flags.Offline,
+		refreshInterval:   cmdRefreshInterval,
+		userProvidedToken: flags.Token,
+		verbose:           flags.Verbose,
+	}
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.apiEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if args.authToken == "" && args.userProvidedToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.refreshInterval < minRefreshInterval {
+		return microerror.Maskf(errors.InvalidDurationError, "the refresh interval must be at least %s", minRefreshInterval)
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	arguments = collectArguments()
+	err := verifyPreconditions(arguments)
+	if err == nil {
+		err = checkTerminal()
+	}
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	errors.Exit(err)
+}
+
+// checkTerminal makes sure we can take over the screen.
+func checkTerminal() error {
+	if !isTerminal() {
+		return microerror.Maskf(errors.TerminalRequiredError, "STDIN and STDOUT must be a terminal")
+	}
+	return nil
+}
+
+// ui holds everything the main loop needs.
+type ui struct {
+	args          Arguments
+	clientWrapper *client.Wrapper
+	model         *model
+	// run executes gsctl with the given arguments and returns its output.
+	run func(args ...string) (string, error)
+}
+
+type refreshResult struct {
+	clusters []*models.V4ClusterListItem
+	err      error
+}
+
+func newUI(args Arguments) (*ui, error) {
+	clientWrapper, err := client.NewWithConfig(args.apiEndpoint, args.userProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	u := &ui{
+		args:          args,
+		clientWrapper: clientWrapper,
+		model:         &model{filter: args.filter},
+	}
+	u.run = u.runGsctl
+
+	return u, nil
+}
+
+// fetchClusters fetches the cluster list.
+func (u *ui) fetchClusters() refreshResult {
+	auxParams := u.clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	response, err := u.clientWrapper.GetClusters(auxParams)
+	if err != nil {
+		return refreshResult{err: microerror.Mask(err)}
+	}
+
+	return refreshResult{clusters: response.Payload}
+}
+
+// loop runs the UI until the user quits.
+func (u *ui) loop(t *terminal) error {
+	keys := make(chan key)
+	go t.readKeys(keys)
+
+	refreshes := make(chan refreshResult, 1)
+	refreshing := false
+	refresh := func() {
+		if refreshing {
+			return
+		}
+		refreshing = true
+		go func() { refreshes <- u.fetchClusters() }()
+	}
+
+	ticker := time.NewTicker(u.args.refreshInterval)
+	defer ticker.Stop()
+
+	// The first fetch is synchronous, so that errors like an expired token
+	// are reported right away.
+	result := u.fetchClusters()
+	if result.err != nil {
+		return microerror.Mask(result.err)
+	}
+	u.model.setClusters(result.clusters, time.Now())
+
+	for {
+		t.draw(u.model.render(t.size()))
+
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			a := u.model.handleKey(k)
+			if a.kind == actionQuit {
+				return nil
+			}
+			if a.kind == actionRefresh {
+				u.model.status = "Refreshing..."
+				refresh()
+				continue
+			}
+			if a.kind != actionNone {
+				u.model.status = "Working..."
+				t.draw(u.model.render(t.size()))
+				if u.perform(a) {
+					refresh()
+				}
+			}
+		case result := <-refreshes:
+			refreshing = false
+			if result.err != nil {
+				u.model.status = "Refresh failed: " + result.err.Error()
+				continue
+			}
+			u.model.setClusters(result.clusters, time.Now())
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// perform executes an action and updates the model with the outcome. It
+// returns true if the cluster list should be refreshed.
+func (u *ui) perform(a action) bool {
+	c := a.cluster
+	if c == nil {
+		return false
+	}
+
+	switch a.kind {
+	case actionDetails:
+		out, err := u.run("show", "cluster", c.ID)
+		lines := splitLines(out)
+		if err == nil {
+			nodePools, npErr := u.run("list", "nodepools", c.ID)
+			// Clusters without node pools are expected to fail here.
+			if npErr == nil {
+				lines = append(lines, "", "Node pools:", "")
+				lines = append(lines, splitLines(nodePools)...)
+			}
+		}
+		u.model.showDetails(c, fmt.Sprintf("Cluster %s (%s)", c.ID, c.Name), lines)
+		if err != nil {
+			u.model.status = "Could not fetch cluster details."
+		} else {
+			u.model.status = ""
+		}
+	case actionKubeconfig:
+		out, err := u.run("create", "kubeconfig", "--cluster", c.ID)
+		u.model.showDetails(c, fmt.Sprintf("Kubeconfig for cluster %s (%s)", c.ID, c.Name), splitLines(out))
+		if err != nil {
+			u.model.status = "Could not create kubeconfig."
+		} else {
+			u.model.status = "Kubeconfig created."
+		}
+	case actionScale:
+		out, err := u.run("update", "nodepool", c.ID+"/"+a.nodePool,
+			"--nodes-min", strconv.FormatInt(a.scalingMin, 10),
+			"--nodes-max", strconv.FormatInt(a.scalingMax, 10))
+		u.model.showDetails(c, fmt.Sprintf("Scaling node pool %s/%s", c.ID, a.nodePool), splitLines(out))
+		if err != nil {
+			u.model.status = "Could not scale node pool."
+			return false
+		}
+		u.model.status = fmt.Sprintf("Node pool %s scales between %d and %d nodes.", a.nodePool, a.scalingMin, a.scalingMax)
+		return true
+	case actionWebUI:
+		url, err := webui.ClusterDetailsURL(u.args.apiEndpoint, c.ID, c.Owner)
+		if err != nil {
+			u.model.status = "No web UI URL known for this installation."
+			return false
+		}
+		err = openURL(url)
+		if err != nil {
+			u.model.status = "Could not open a browser. Please visit " + url
+		} else {
+			u.model.status = "Opened " + url
+		}
+	}
+
+	return false
+}
+
+// runGsctl executes this binary with the given sub-command, so that the
+// output is exactly what the command prints when used directly.
+func (u *ui) runGsctl(args ...string) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	args = append(args, "--endpoint", u.args.apiEndpoint)
+	if u.args.configDirPath != "" {
+		args = append(args, "--config-dir", u.args.configDirPath)
+	}
+	if u.args.offline {
+		args = append(args, "--offline")
+	}
+
+	// No STDIN, so that commands fail instead of asking for confirmation.
+	cmd := exec.Command(executable, args...)
+	cmd.Env = childEnv(os.Environ(), u.args.userProvidedToken)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), microerror.Mask(err)
+	}
+
+	return string(out), nil
+}
+
+// childEnv returns the environment for gsctl child processes. The auth token
+// is passed as GSCTL_AUTH_TOKEN instead of a command line argument, as the
+// latter can be read by other users of the machine via ps.
+func childEnv(environ []string, token string) []string {
+	env := []string{}
+	for _, e := range environ {
+		if !strings.HasPrefix(e, "GSCTL_AUTH_TOKEN=") {
+			env = append(env, e)
+		}
+	}
+	if token != "" {
+		env = append(env, "GSCTL_AUTH_TOKEN="+token)
+	}
+
+	return env
+}
+
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	return strings.Split(strings.TrimRight(s, "\n"), "\n")
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	u, err := newUI(arguments)
+	if err != nil {
+		handleError(err)
+		errors.Exit(err)
+	}
+
+	t, err := openTerminal()
+	if err != nil {
+		err = microerror.Maskf(errors.TerminalRequiredError, err.Error())
+		handleError(err)
+		errors.Exit(err)
+	}
+
+	err = u.loop(t)
+	t.close()
+
+	if err != nil {
+		handleError(err)
+		errors.Exit(err)
+	}
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	var headline string
+	var subtext string
+
+	switch {
+	case errors.IsTerminalRequiredError(err):
+		headline = "Terminal required"
+		subtext = "'gsctl ui' needs an interactive terminal. To use gsctl in scripts, please use commands like 'gsctl list clusters' instead."
+	case errors.IsInvalidDurationError(err):
+		headline = "Invalid refresh interval"
+		subtext = fmt.Sprintf("Please use a refresh interval of at least %s.", minRefreshInterval)
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
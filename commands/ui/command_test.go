@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/go-openapi/strfmt"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
+)
+
+func testClusters() []*models.V4ClusterListItem {
+	deleted := strfmt.DateTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	return []*models.V4ClusterListItem{
+		{ID: "c1", Name: "Production", Owner: "acme", ReleaseVersion: "12.1.0", Labels: map[string]string{"environment": "production", "giantswarm.io/cluster": "c1"}},
+		{ID: "c2", Name: "Staging", Owner: "acme", ReleaseVersion: "12.0.0", Labels: map[string]string{"environment": "staging"}},
+		{ID: "c3", Name: "Argon", Owner: "other", ReleaseVersion: "11.0.0"},
+		{ID: "c4", Name: "Deleted", Owner: "acme", DeleteDate: &deleted},
+	}
+}
+
+// TestVerifyPreconditions tests the validation of arguments.
+func TestVerifyPreconditions(t *testing.T) {
+	var testCases = []struct {
+		args         Arguments
+		errorMatcher func(error) bool
+	}{
+		{
+			Arguments{apiEndpoint: "https://foo", authToken: "token", refreshInterval: time.Minute},
+			nil,
+		},
+		{
+			Arguments{authToken: "token", refreshInterval: time.Minute},
+			errors.IsEndpointMissingError,
+		},
+		{
+			Arguments{apiEndpoint: "https://foo", refreshInterval: time.Minute},
+			errors.IsNotLoggedInError,
+		},
+		{
+			Arguments{apiEndpoint: "https://foo", authToken: "token", refreshInterval: time.Second},
+			errors.IsInvalidDurationError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			err := verifyPreconditions(tc.args)
+			if tc.errorMatcher == nil && err != nil {
+				t.Errorf("Case %d - Unexpected error %#v", i, err)
+			} else if tc.errorMatcher != nil && !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+			}
+		})
+	}
+}
+
+// TestFilter tests filtering the cluster list.
+func TestFilter(t *testing.T) {
+	var testCases = []struct {
+		filter      string
+		expectedIDs []string
+	}{
+		{"", []string{"c1", "c2", "c3"}},
+		{"prod", []string{"c1"}},
+		{"C2", []string{"c2"}},
+		{"org:acme", []string{"c1", "c2"}},
+		{"org:ac", []string{}},
+		{"label:environment", []string{"c1", "c2"}},
+		{"label:environment=staging", []string{"c2"}},
+		{"org:acme label:environment=production", []string{"c1"}},
+		{"org:other prod", []string{}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			m := &model{filter: tc.filter}
+			m.setClusters(testClusters(), time.Now())
+
+			ids := []string{}
+			for _, c := range m.visible {
+				ids = append(ids, c.ID)
+			}
+			if diff := cmp.Diff(tc.expectedIDs, ids); diff != "" {
+				t.Errorf("Case %d - Results unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+// TestParseKeys tests decoding terminal input.
+func TestParseKeys(t *testing.T) {
+	var testCases = []struct {
+		input    string
+		expected []key
+	}{
+		{"q", []key{{r: 'q'}}},
+		{"\x1b[A\x1b[B", []key{{code: keyUp}, {code: keyDown}}},
+		{"\x1bOA", []key{{code: keyUp}}},
+		{"\x1b[5~\x1b[6~", []key{{code: keyPageUp}, {code: keyPageDown}}},
+		{"\x1b", []key{{code: keyEsc}}},
+		{"\x1b[Cx", []key{{r: 'x'}}},
+		{"\r\x7f\x03", []key{{code: keyEnter}, {code: keyBackspace}, {code: keyCtrlC}}},
+		{"ä\x01", []key{{r: 'ä'}}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			keys := parseKeys([]byte(tc.input))
+			if diff := cmp.Diff(tc.expected, keys, cmp.AllowUnexported(key{})); diff != "" {
+				t.Errorf("Case %d - Results unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+func typeKeys(m *model, s string) action {
+	var a action
+	for _, r := range s {
+		a = m.handleKey(key{r: r})
+	}
+	return a
+}
+
+// TestHandleKey tests navigation, filtering and scaling input.
+func TestHandleKey(t *testing.T) {
+	m := &model{}
+	m.setClusters(testClusters(), time.Now())
+
+	m.handleKey(key{code: keyDown})
+	m.handleKey(key{code: keyDown})
+	m.handleKey(key{code: keyDown})
+	if c := m.selectedCluster(); c.ID != "c3" {
+		t.Errorf("Expected c3 to be selected, got %s", c.ID)
+	}
+	m.handleKey(key{r: 'k'})
+	if c := m.selectedCluster(); c.ID != "c2" {
+		t.Errorf("Expected c2 to be selected, got %s", c.ID)
+	}
+
+	// The selection is kept on refresh.
+	m.setClusters(testClusters(), time.Now())
+	if c := m.selectedCluster(); c.ID != "c2" {
+		t.Errorf("Expected c2 to be selected after refresh, got %s", c.ID)
+	}
+
+	// Filter while typing.
+	typeKeys(m, "/arg")
+	if m.inputMode != inputFilter || len(m.visible) != 1 || m.visible[0].ID != "c3" {
+		t.Errorf("Expected filtered list with c3, got %d clusters", len(m.visible))
+	}
+	m.handleKey(key{code: keyBackspace})
+	if m.filter != "ar" {
+		t.Errorf("Expected filter 'ar', got %q", m.filter)
+	}
+	m.handleKey(key{code: keyEnter})
+	if m.inputMode != inputNone || m.filter != "ar" {
+		t.Errorf("Expected filter 'ar' to be applied, got %q", m.filter)
+	}
+	m.handleKey(key{code: keyEsc})
+	if m.filter != "" || len(m.visible) != 3 {
+		t.Errorf("Expected filter to be cleared, got %q", m.filter)
+	}
+
+	a := m.handleKey(key{code: keyEnter})
+	if a.kind != actionDetails || a.cluster.ID != "c1" {
+		t.Fatalf("Expected details action for c1, got %#v", a)
+	}
+	m.showDetails(a.cluster, "Cluster c1", []string{"ID: c1"})
+
+	// Invalid scaling input is reported in the status line.
+	typeKeys(m, "sa7k2p 5 3")
+	a = m.handleKey(key{code: keyEnter})
+	if a.kind != actionNone || !strings.Contains(m.status, "maximum") {
+		t.Errorf("Expected problem with the maximum, got %#v, status %q", a, m.status)
+	}
+
+	typeKeys(m, "sa7k2p 3 10")
+	a = m.handleKey(key{code: keyEnter})
+	if a.kind != actionScale || a.cluster.ID != "c1" || a.nodePool != "a7k2p" || a.scalingMin != 3 || a.scalingMax != 10 {
+		t.Errorf("Unexpected scale action %#v", a)
+	}
+
+	m.handleKey(key{r: 'q'})
+	if m.view != viewList {
+		t.Errorf("Expected list view")
+	}
+	if a := m.handleKey(key{r: 'q'}); a.kind != actionQuit {
+		t.Errorf("Expected quit action, got %#v", a)
+	}
+}
+
+// TestRender tests that the screen content fits the terminal size.
+func TestRender(t *testing.T) {
+	m := &model{}
+	m.setClusters(testClusters(), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	lines := m.render(30, 6)
+	if len(lines) != 6 {
+		t.Fatalf("Expected 6 lines, got %d", len(lines))
+	}
+	for i, l := range lines {
+		if len(l.text) > 30 {
+			t.Errorf("Line %d is longer than 30 characters: %q", i, l.text)
+		}
+	}
+	if lines[0].kind != lineHeader || !strings.HasPrefix(lines[0].text, "gsctl ui - 3 clusters") {
+		t.Errorf("Unexpected header %#v", lines[0])
+	}
+	if lines[2].kind != lineSelected || !strings.HasPrefix(lines[2].text, "c1") {
+		t.Errorf("Expected c1 to be selected, got %#v", lines[2])
+	}
+
+	// Scroll down to the last cluster.
+	m.handleKey(key{code: keyPageDown})
+	lines = m.render(100, 6)
+	if lines[3].kind != lineSelected || !strings.HasPrefix(lines[3].text, "c3") {
+		t.Errorf("Expected c3 to be selected, got %#v", lines[3])
+	}
+	if !strings.Contains(lines[2].text, "environment=staging") || strings.Contains(lines[2].text, "giantswarm.io") {
+		t.Errorf("Unexpected labels in %q", lines[2].text)
+	}
+}
+
+// TestPerform tests executing actions with a fake runner and browser.
+func TestPerform(t *testing.T) {
+	var calls []string
+	u := &ui{
+		args:  Arguments{apiEndpoint: "https://api.g8s.example.com"},
+		model: &model{},
+		run: func(args ...string) (string, error) {
+			calls = append(calls, strings.Join(args, " "))
+			if args[0] == "list" {
+				return "", microerror.Mask(errors.ClusterNotFoundError)
+			}
+			return "ID:\tc1\n", nil
+		},
+	}
+	u.model.setClusters(testClusters(), time.Now())
+	c := u.model.selectedCluster()
+
+	var opened string
+	defaultOpenURL := openURL
+	defer func() { openURL = defaultOpenURL }()
+	openURL = func(url string) error {
+		opened = url
+		return nil
+	}
+
+	u.perform(action{kind: actionDetails, cluster: c})
+	if u.model.view != viewDetails || len(u.model.details) != 1 || u.model.details[0] != "ID:    c1" {
+		t.Errorf("Unexpected details %q", u.model.details)
+	}
+
+	refresh := u.perform(action{kind: actionScale, cluster: c, nodePool: "a7k2p", scalingMin: 3, scalingMax: 10})
+	if !refresh {
+		t.Errorf("Expected a refresh after scaling")
+	}
+
+	u.perform(action{kind: actionKubeconfig, cluster: c})
+
+	u.perform(action{kind: actionWebUI, cluster: c})
+	if opened != "https://happa.g8s.example.com/organizations/acme/clusters/c1" {
+		t.Errorf("Unexpected URL opened: %q", opened)
+	}
+
+	expected := []string{
+		"show cluster c1",
+		"list nodepools c1",
+		"update nodepool c1/a7k2p --nodes-min 3 --nodes-max 10",
+		"create kubeconfig --cluster c1",
+	}
+	if diff := cmp.Diff(expected, calls); diff != "" {
+		t.Errorf("Commands unequal. (-expected +got):\n%s", diff)
+	}
+}
+
+// TestFetchClusters tests fetching the cluster list from the fake API.
+func TestFetchClusters(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := fakeapi.New(fakeapi.Config{Organizations: []string{"acme"}})
+	id := api.AddClusterV5(&models.V5ClusterDetailsResponse{Owner: "acme", ReleaseVersion: "12.1.0"})
+
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+
+	u, err := newUI(Arguments{apiEndpoint: ts.URL, authToken: "some-token", userProvidedToken: "some-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := u.fetchClusters()
+	if result.err != nil {
+		t.Fatalf("Unexpected error %#v", result.err)
+	}
+	if len(result.clusters) != 1 || result.clusters[0].ID != id {
+		t.Errorf("Unexpected clusters %#v", result.clusters)
+	}
+}
+
+// TestChildEnv tests that the auth token is passed to child processes via
+// the environment only.
+func TestChildEnv(t *testing.T) {
+	environ := []string{"HOME=/home/user", "GSCTL_AUTH_TOKEN=old-token"}
+
+	env := childEnv(environ, "new-token")
+	if diff := cmp.Diff([]string{"HOME=/home/user", "GSCTL_AUTH_TOKEN=new-token"}, env); diff != "" {
+		t.Errorf("Environment not as expected (-want +got):\n%s", diff)
+	}
+
+	env = childEnv(environ, "")
+	if diff := cmp.Diff([]string{"HOME=/home/user"}, env); diff != "" {
+		t.Errorf("Environment not as expected (-want +got):\n%s", diff)
+	}
+}
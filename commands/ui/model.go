@@ -0,0 +1,508 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+)
+
+// view is the screen currently shown.
+type view int
+
+const (
+	viewList view = iota
+	viewDetails
+	viewHelp
+)
+
+// inputMode is set while the user types into the prompt line.
+type inputMode int
+
+const (
+	inputNone inputMode = iota
+	inputFilter
+	inputScale
+)
+
+// action is what the main loop has to do after a key press.
+type action struct {
+	kind       actionKind
+	cluster    *models.V4ClusterListItem
+	nodePool   string
+	scalingMin int64
+	scalingMax int64
+}
+
+type actionKind int
+
+const (
+	actionNone actionKind = iota
+	actionQuit
+	actionRefresh
+	actionDetails
+	actionKubeconfig
+	actionScale
+	actionWebUI
+)
+
+// model is the state of the UI. It is changed by key presses and API
+// results only, so that it can be tested without a terminal.
+type model struct {
+	clusters []*models.V4ClusterListItem
+	// visible are the clusters matching the filter, sorted.
+	visible  []*models.V4ClusterListItem
+	filter   string
+	selected int
+	offset   int
+
+	view view
+	// details are the lines shown in the details view.
+	details      []string
+	detailsTitle string
+	scroll       int
+	// detailsCluster is the cluster the details view belongs to.
+	detailsCluster *models.V4ClusterListItem
+
+	input     string
+	inputMode inputMode
+
+	status      string
+	lastRefresh time.Time
+}
+
+const helpText = `Keys in the cluster list:
+
+  up/down, j/k     Select a cluster
+  enter            Show cluster details and node pools
+  /                Filter by name or ID, 'org:<organization>' or
+                   'label:<key>=<value>'. Terms are combined with AND.
+  esc              Clear the filter
+  c                Create a kubeconfig for the selected cluster
+  w                Open the cluster in the web UI
+  r                Refresh now
+  ?                Show this help
+  q                Quit
+
+Keys in the details view:
+
+  up/down, j/k     Scroll
+  s                Scale a node pool, e. g. 'a7k2p 3 10' for node pool
+                   a7k2p with 3 to 10 nodes
+  c                Create a kubeconfig
+  w                Open the cluster in the web UI
+  esc, q           Back to the cluster list
+`
+
+// setClusters replaces the cluster list, keeping the selection if possible.
+func (m *model) setClusters(clusters []*models.V4ClusterListItem, now time.Time) {
+	var selectedID string
+	if c := m.selectedCluster(); c != nil {
+		selectedID = c.ID
+	}
+
+	m.clusters = clusters
+	m.lastRefresh = now
+	m.applyFilter()
+
+	for i, c := range m.visible {
+		if c.ID == selectedID {
+			m.selected = i
+		}
+	}
+}
+
+// applyFilter updates the visible clusters.
+func (m *model) applyFilter() {
+	m.visible = m.visible[:0]
+	for _, c := range m.clusters {
+		if c.DeleteDate == nil && matchesFilter(c, m.filter) {
+			m.visible = append(m.visible, c)
+		}
+	}
+
+	sort.SliceStable(m.visible, func(i, j int) bool {
+		a, b := m.visible[i], m.visible[j]
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	if m.selected >= len(m.visible) {
+		m.selected = len(m.visible) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+}
+
+// matchesFilter returns true if the cluster matches all terms of the filter.
+// Terms are 'org:<organization>', 'label:<key>=<value>' or a substring of
+// the cluster name or ID.
+func matchesFilter(c *models.V4ClusterListItem, filter string) bool {
+	for _, term := range strings.Fields(strings.ToLower(filter)) {
+		switch {
+		case strings.HasPrefix(term, "org:"):
+			if strings.ToLower(c.Owner) != strings.TrimPrefix(term, "org:") {
+				return false
+			}
+		case strings.HasPrefix(term, "label:"):
+			parts := strings.SplitN(strings.TrimPrefix(term, "label:"), "=", 2)
+			found := false
+			for k, v := range c.Labels {
+				if strings.ToLower(k) == parts[0] && (len(parts) == 1 || strings.ToLower(v) == parts[1]) {
+					found = true
+				}
+			}
+			if !found {
+				return false
+			}
+		default:
+			if !strings.Contains(strings.ToLower(c.Name), term) && !strings.Contains(strings.ToLower(c.ID), term) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (m *model) selectedCluster() *models.V4ClusterListItem {
+	if m.selected < 0 || m.selected >= len(m.visible) {
+		return nil
+	}
+	return m.visible[m.selected]
+}
+
+// showDetails switches to the details view.
+func (m *model) showDetails(c *models.V4ClusterListItem, title string, lines []string) {
+	m.view = viewDetails
+	m.detailsCluster = c
+	m.detailsTitle = title
+	m.details = lines
+	m.scroll = 0
+}
+
+// handleKey changes the state according to a key press and returns what
+// the main loop has to do.
+func (m *model) handleKey(k key) action {
+	if m.inputMode != inputNone {
+		return m.handleInputKey(k)
+	}
+
+	m.status = ""
+
+	switch m.view {
+	case viewHelp:
+		m.view = viewList
+		return action{}
+	case viewDetails:
+		return m.handleDetailsKey(k)
+	}
+
+	switch {
+	case k.code == keyUp || k.r == 'k':
+		if m.selected > 0 {
+			m.selected--
+		}
+	case k.code == keyDown || k.r == 'j':
+		if m.selected < len(m.visible)-1 {
+			m.selected++
+		}
+	case k.code == keyPageUp:
+		m.selected = 0
+	case k.code == keyPageDown:
+		m.selected = len(m.visible) - 1
+	case k.code == keyEsc:
+		m.filter = ""
+		m.applyFilter()
+	case k.code == keyEnter:
+		if c := m.selectedCluster(); c != nil {
+			return action{kind: actionDetails, cluster: c}
+		}
+	case k.code == keyCtrlC || k.r == 'q':
+		return action{kind: actionQuit}
+	case k.r == '/':
+		m.inputMode = inputFilter
+		m.input = m.filter
+	case k.r == 'r':
+		return action{kind: actionRefresh}
+	case k.r == '?':
+		m.view = viewHelp
+	case k.r == 'c':
+		if c := m.selectedCluster(); c != nil {
+			return action{kind: actionKubeconfig, cluster: c}
+		}
+	case k.r == 'w':
+		if c := m.selectedCluster(); c != nil {
+			return action{kind: actionWebUI, cluster: c}
+		}
+	}
+
+	return action{}
+}
+
+func (m *model) handleDetailsKey(k key) action {
+	switch {
+	case k.code == keyUp || k.r == 'k':
+		if m.scroll > 0 {
+			m.scroll--
+		}
+	case k.code == keyDown || k.r == 'j':
+		if m.scroll < len(m.details)-1 {
+			m.scroll++
+		}
+	case k.code == keyPageUp:
+		m.scroll = 0
+	case k.code == keyEsc || k.r == 'q':
+		m.view = viewList
+	case k.code == keyCtrlC:
+		return action{kind: actionQuit}
+	case k.r == 's':
+		m.inputMode = inputScale
+		m.input = ""
+	case k.r == 'c':
+		return action{kind: actionKubeconfig, cluster: m.detailsCluster}
+	case k.r == 'w':
+		return action{kind: actionWebUI, cluster: m.detailsCluster}
+	}
+
+	return action{}
+}
+
+func (m *model) handleInputKey(k key) action {
+	switch {
+	case k.code == keyCtrlC:
+		return action{kind: actionQuit}
+	case k.code == keyEsc:
+		if m.inputMode == inputFilter {
+			m.filter = ""
+			m.applyFilter()
+		}
+		m.inputMode = inputNone
+		m.input = ""
+	case k.code == keyBackspace:
+		if len(m.input) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.input)
+			m.input = m.input[:len(m.input)-size]
+		}
+		if m.inputMode == inputFilter {
+			m.filter = m.input
+			m.applyFilter()
+		}
+	case k.code == keyEnter:
+		mode := m.inputMode
+		m.inputMode = inputNone
+
+		if mode == inputFilter {
+			m.filter = m.input
+			m.selected = 0
+			m.applyFilter()
+			return action{}
+		}
+
+		a, problem := parseScaleInput(m.input)
+		if problem != "" {
+			m.status = problem
+			return action{}
+		}
+		a.cluster = m.detailsCluster
+		return a
+	case k.r != 0:
+		m.input += string(k.r)
+		if m.inputMode == inputFilter {
+			// Filter while typing.
+			m.filter = m.input
+			m.selected = 0
+			m.applyFilter()
+		}
+	}
+
+	return action{}
+}
+
+// parseScaleInput parses '<node-pool-id> <min> <max>'. If the input is
+// invalid, the problem is returned for display.
+func parseScaleInput(input string) (action, string) {
+	fields := strings.Fields(input)
+	if len(fields) != 3 {
+		return action{}, "Please enter the node pool ID, the minimum and the maximum number of nodes, e. g. 'a7k2p 3 10'."
+	}
+
+	min, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || min < 0 {
+		return action{}, fmt.Sprintf("The minimum number of nodes must be a number, got '%s'.", fields[1])
+	}
+	max, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil || max < min {
+		return action{}, fmt.Sprintf("The maximum number of nodes must be a number not smaller than the minimum, got '%s'.", fields[2])
+	}
+
+	return action{kind: actionScale, nodePool: fields[0], scalingMin: min, scalingMax: max}, ""
+}
+
+// render returns the lines to show on a screen of the given size. Styling
+// is applied by the terminal, based on the line kinds.
+func (m *model) render(width, height int) []line {
+	lines := []line{{text: m.header(), kind: lineHeader}}
+	bodyHeight := height - 3
+	if bodyHeight < 1 {
+		bodyHeight = 1
+	}
+
+	var body []line
+	switch m.view {
+	case viewHelp:
+		for _, l := range strings.Split(helpText, "\n") {
+			body = append(body, line{text: l})
+		}
+	case viewDetails:
+		body = append(body, line{text: m.detailsTitle, kind: lineTitle})
+		end := m.scroll + bodyHeight - 1
+		if end > len(m.details) {
+			end = len(m.details)
+		}
+		for _, l := range m.details[m.scroll:end] {
+			body = append(body, line{text: l})
+		}
+	default:
+		body = m.renderList(bodyHeight)
+	}
+
+	if len(body) > bodyHeight {
+		body = body[:bodyHeight]
+	}
+	lines = append(lines, body...)
+	for len(lines) < height-2 {
+		lines = append(lines, line{})
+	}
+
+	lines = append(lines, line{text: m.statusLine(), kind: lineStatus})
+	lines = append(lines, line{text: m.footer(), kind: lineFooter})
+
+	for i := range lines {
+		lines[i].text = truncate(lines[i].text, width)
+	}
+
+	return lines
+}
+
+func (m *model) header() string {
+	h := fmt.Sprintf("gsctl ui - %d clusters", len(m.visible))
+	if m.filter != "" {
+		h += fmt.Sprintf(" matching '%s'", m.filter)
+	}
+	if !m.lastRefresh.IsZero() {
+		h += fmt.Sprintf(" - updated %s", m.lastRefresh.Format("15:04:05"))
+	}
+	return h
+}
+
+func (m *model) statusLine() string {
+	switch m.inputMode {
+	case inputFilter:
+		return "Filter: " + m.input + "_"
+	case inputScale:
+		return "Scale node pool (<node-pool-id> <min> <max>): " + m.input + "_"
+	}
+	return m.status
+}
+
+func (m *model) footer() string {
+	switch m.view {
+	case viewDetails:
+		return "s scale node pool  c kubeconfig  w web UI  esc back"
+	case viewHelp:
+		return "Press any key to return"
+	}
+	return "enter details  / filter  c kubeconfig  w web UI  r refresh  ? help  q quit"
+}
+
+func (m *model) renderList(height int) []line {
+	rows := [][]string{{"ID", "NAME", "ORGANIZATION", "RELEASE", "LABELS"}}
+	for _, c := range m.visible {
+		rows = append(rows, []string{c.ID, c.Name, c.Owner, c.ReleaseVersion, formatLabels(c.Labels)})
+	}
+	texts := alignColumns(rows)
+
+	lines := []line{{text: texts[0], kind: lineTitle}}
+	if len(m.visible) == 0 {
+		return append(lines, line{text: "No clusters found."})
+	}
+
+	// Scroll so that the selected cluster is visible.
+	rowsHeight := height - 1
+	if m.selected < m.offset {
+		m.offset = m.selected
+	} else if rowsHeight > 0 && m.selected >= m.offset+rowsHeight {
+		m.offset = m.selected - rowsHeight + 1
+	}
+
+	for i := m.offset; i < len(m.visible) && i < m.offset+rowsHeight; i++ {
+		l := line{text: texts[i+1]}
+		if i == m.selected {
+			l.kind = lineSelected
+		}
+		lines = append(lines, l)
+	}
+
+	return lines
+}
+
+// formatLabels returns the labels sorted by key, without the ones set by
+// Giant Swarm.
+func formatLabels(labels map[string]string) string {
+	pairs := []string{}
+	for k, v := range labels {
+		if strings.Contains(k, "giantswarm.io") {
+			continue
+		}
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// alignColumns pads the cells so that columns are aligned.
+func alignColumns(rows [][]string) []string {
+	widths := []int{}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	result := make([]string, 0, len(rows))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if i < len(row)-1 {
+				cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			}
+			cells[i] = cell
+		}
+		result = append(result, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+
+	return result
+}
+
+// truncate shortens s to the given number of runes.
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width])
+}
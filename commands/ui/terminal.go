@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/giantswarm/microerror"
+	"golang.org/x/term"
+)
+
+// key is a key press. Either code or r is set.
+type key struct {
+	code keyCode
+	r    rune
+}
+
+type keyCode int
+
+const (
+	keyNone keyCode = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyEsc
+	keyBackspace
+	keyCtrlC
+)
+
+// lineKind selects the style of a line.
+type lineKind int
+
+const (
+	lineNormal lineKind = iota
+	lineHeader
+	lineTitle
+	lineSelected
+	lineStatus
+	lineFooter
+)
+
+type line struct {
+	text string
+	kind lineKind
+}
+
+const (
+	escAltScreenOn  = "\x1b[?1049h"
+	escAltScreenOff = "\x1b[?1049l"
+	escCursorHide   = "\x1b[?25l"
+	escCursorShow   = "\x1b[?25h"
+	escHome         = "\x1b[H"
+	escClearLine    = "\x1b[K"
+	escClearBelow   = "\x1b[J"
+	escReset        = "\x1b[0m"
+	escBold         = "\x1b[1m"
+	escReverse      = "\x1b[7m"
+	escCyan         = "\x1b[36m"
+	escYellow       = "\x1b[33m"
+	escDim          = "\x1b[2m"
+)
+
+// terminal is a full-screen terminal in raw mode.
+type terminal struct {
+	in       *os.File
+	out      io.Writer
+	oldState *term.State
+}
+
+// isTerminal returns true if both STDIN and STDOUT are terminals.
+func isTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// openTerminal switches the terminal to raw mode and the alternate screen.
+func openTerminal() (*terminal, error) {
+	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	t := &terminal{
+		in:       os.Stdin,
+		out:      os.Stdout,
+		oldState: oldState,
+	}
+	io.WriteString(t.out, escAltScreenOn+escCursorHide)
+
+	return t, nil
+}
+
+// close restores the terminal.
+func (t *terminal) close() {
+	io.WriteString(t.out, escReset+escCursorShow+escAltScreenOff)
+	term.Restore(int(t.in.Fd()), t.oldState)
+}
+
+// size returns the width and height of the terminal.
+func (t *terminal) size() (int, int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// draw replaces the screen content with the given lines.
+func (t *terminal) draw(lines []line) {
+	var buf bytes.Buffer
+	buf.WriteString(escHome)
+
+	for i, l := range lines {
+		if i > 0 {
+			buf.WriteString("\r\n")
+		}
+		switch l.kind {
+		case lineHeader:
+			buf.WriteString(escBold + escYellow)
+		case lineTitle:
+			buf.WriteString(escBold + escCyan)
+		case lineSelected:
+			buf.WriteString(escReverse)
+		case lineFooter:
+			buf.WriteString(escDim)
+		}
+		buf.WriteString(l.text)
+		buf.WriteString(escClearLine + escReset)
+	}
+	buf.WriteString(escClearBelow)
+
+	t.out.Write(buf.Bytes())
+}
+
+// readKeys sends key presses to the channel until reading fails.
+func (t *terminal) readKeys(keys chan<- key) {
+	buf := make([]byte, 64)
+	for {
+		n, err := t.in.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+// parseKeys decodes the bytes read from a terminal in raw mode.
+func parseKeys(data []byte) []key {
+	var keys []key
+
+	for len(data) > 0 {
+		switch {
+		case bytes.HasPrefix(data, []byte("\x1b[A")), bytes.HasPrefix(data, []byte("\x1bOA")):
+			keys = append(keys, key{code: keyUp})
+			data = data[3:]
+		case bytes.HasPrefix(data, []byte("\x1b[B")), bytes.HasPrefix(data, []byte("\x1bOB")):
+			keys = append(keys, key{code: keyDown})
+			data = data[3:]
+		case bytes.HasPrefix(data, []byte("\x1b[5~")):
+			keys = append(keys, key{code: keyPageUp})
+			data = data[4:]
+		case bytes.HasPrefix(data, []byte("\x1b[6~")):
+			keys = append(keys, key{code: keyPageDown})
+			data = data[4:]
+		case bytes.HasPrefix(data, []byte("\x1b[")):
+			// Ignore other escape sequences, e. g. left and right.
+			i := 2
+			for i < len(data) && (data[i] < 0x40 || data[i] > 0x7e) {
+				i++
+			}
+			data = data[min(i+1, len(data)):]
+		case data[0] == 0x1b:
+			keys = append(keys, key{code: keyEsc})
+			data = data[1:]
+		case data[0] == '\r' || data[0] == '\n':
+			keys = append(keys, key{code: keyEnter})
+			data = data[1:]
+		case data[0] == 0x7f || data[0] == 0x08:
+			keys = append(keys, key{code: keyBackspace})
+			data = data[1:]
+		case data[0] == 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+			data = data[1:]
+		case data[0] < 0x20:
+			// Ignore other control characters.
+			data = data[1:]
+		default:
+			r, size := utf8.DecodeRune(data)
+			keys = append(keys, key{r: r})
+			data = data[size:]
+		}
+	}
+
+	return keys
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,275 @@
+// Package app implements the "update app" command.
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/appvalues"
+)
+
+var (
+	// Command is the cobra command for 'gsctl update app'
+	Command = &cobra.Command{
+		Use: "app <cluster-name/cluster-id>/<app-name>",
+		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
+		Args:  cobra.ExactArgs(1),
+		Short: "Modify an app",
+		Long: `Change the version or the user values of an app installed in a cluster.
+
+User values given via --values-file are merged into the user values already
+configured for the app. If the app has no user values yet, they are created.
+
+Examples:
+
+  gsctl update app f01r4/nginx-ingress-controller --version 1.7.0
+
+  gsctl update app "Cluster name"/external-dns --values-file ./values.yaml
+`,
+
+		// PreRun checks a few general things, like authentication.
+		PreRun: printValidation,
+
+		// Run calls the business function and prints results and errors.
+		Run: printResult,
+	}
+
+	arguments Arguments
+)
+
+const (
+	activityName = "update-app"
+)
+
+func init() {
+	initFlags()
+}
+
+// initFlags initializes flags in a re-usable way, so we can call it from multiple tests.
+func initFlags() {
+	Command.ResetFlags()
+	Command.Flags().StringVarP(&flags.AppVersion, "version", "", "", "Version of the app to upgrade or downgrade to.")
+	Command.Flags().StringVarP(&flags.AppValuesFile, "values-file", "", "", "Path to a YAML file with user values for the app.")
+}
+
+// Arguments defines the arguments this command can take into consideration.
+type Arguments struct {
+	APIEndpoint       string
+	AppName           string
+	AuthToken         string
+	ClusterNameOrID   string
+	UserProvidedToken string
+	Values            map[string]interface{}
+	Verbose           bool
+	Version           string
+}
+
+// collectArguments populates an arguments struct with values both from command flags,
+// from config, and potentially from built-in defaults.
+func collectArguments(fs afero.Fs, positionalArgs []string) (Arguments, error) {
+	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
+	token := config.Config.ChooseToken(endpoint, flags.Token)
+
+	parts := strings.Split(positionalArgs[0], "/")
+	if len(parts) < 2 {
+		return Arguments{}, microerror.Maskf(errors.InvalidAppArgumentError, "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details.")
+	}
+
+	var values map[string]interface{}
+	if flags.AppValuesFile != "" {
+		var err error
+		values, err = appvalues.ReadFile(fs, flags.AppValuesFile)
+		if appvalues.IsInvalidValues(err) {
+			return Arguments{}, microerror.Maskf(errors.YAMLNotParseableError, err.Error())
+		} else if err != nil {
+			return Arguments{}, microerror.Maskf(errors.YAMLFileNotReadableError, err.Error())
+		}
+	}
+
+	return Arguments{
+		APIEndpoint:       endpoint,
+		AppName:           parts[1],
+		AuthToken:         token,
+		ClusterNameOrID:   parts[0],
+		UserProvidedToken: flags.Token,
+		Values:            values,
+		Verbose:           flags.Verbose,
+		Version:           flags.AppVersion,
+	}, nil
+}
+
+func verifyPreconditions(args Arguments) error {
+	if args.APIEndpoint == "" {
+		return microerror.Mask(errors.EndpointMissingError)
+	}
+	if config.Config.Token == "" && args.AuthToken == "" {
+		return microerror.Mask(errors.NotLoggedInError)
+	}
+	if args.ClusterNameOrID == "" {
+		return microerror.Mask(errors.ClusterNameOrIDMissingError)
+	}
+	if args.AppName == "" {
+		return microerror.Mask(errors.AppNameMissingError)
+	}
+	if args.Version == "" && args.Values == nil {
+		return microerror.Maskf(errors.NoOpError, "Nothing to update.")
+	}
+
+	return nil
+}
+
+func printValidation(cmd *cobra.Command, positionalArgs []string) {
+	var err error
+
+	arguments, err = collectArguments(afero.NewOsFs(), positionalArgs)
+	if err == nil {
+		err = verifyPreconditions(arguments)
+	}
+
+	if err == nil {
+		return
+	}
+
+	handleError(err)
+	os.Exit(errors.ExitCode(err))
+}
+
+// updateApp applies the version and user values changes to the app.
+func updateApp(args Arguments) error {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	clusterID, err := clustercache.GetID(args.APIEndpoint, args.ClusterNameOrID, clientWrapper)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	app, err := clientWrapper.GetApp(clusterID, args.AppName, auxParams)
+	if err != nil {
+		return microerror.Mask(handleAPIError(err, errors.ClusterNotFoundError))
+	}
+	if app == nil {
+		return microerror.Mask(errors.AppNotFoundError)
+	}
+
+	if args.Values != nil {
+		hasConfig := app.Spec != nil && app.Spec.UserConfig != nil && app.Spec.UserConfig.Configmap != nil && app.Spec.UserConfig.Configmap.Name != ""
+
+		if hasConfig {
+			if args.Verbose {
+				fmt.Println(color.WhiteString("Modifying user values of app '%s'", args.AppName))
+			}
+			_, err = clientWrapper.ModifyAppConfig(clusterID, args.AppName, args.Values, auxParams)
+		} else {
+			if args.Verbose {
+				fmt.Println(color.WhiteString("Creating user values for app '%s'", args.AppName))
+			}
+			_, err = clientWrapper.CreateAppConfig(clusterID, args.AppName, args.Values, auxParams)
+		}
+		if err != nil {
+			return microerror.Mask(handleAPIError(err, errors.AppNotFoundError))
+		}
+	}
+
+	if args.Version != "" {
+		if args.Verbose {
+			fmt.Println(color.WhiteString("Setting version of app '%s' to %s", args.AppName, args.Version))
+		}
+
+		requestBody := &models.V4ModifyAppRequest{
+			Spec: &models.V4ModifyAppRequestSpec{
+				Version: args.Version,
+			},
+		}
+
+		_, err = clientWrapper.ModifyApp(clusterID, args.AppName, requestBody, auxParams)
+		if err != nil {
+			return microerror.Mask(handleAPIError(err, errors.AppNotFoundError))
+		}
+	}
+
+	return nil
+}
+
+// handleAPIError maps client errors to the errors of this command. A 404
+// response is mapped to notFoundErr, as its meaning depends on the request.
+func handleAPIError(err error, notFoundErr *microerror.Error) error {
+	switch {
+	case clienterror.IsAccessForbiddenError(err):
+		return microerror.Mask(errors.AccessForbiddenError)
+	case clienterror.IsNotFoundError(err):
+		return microerror.Mask(notFoundErr)
+	case clienterror.IsBadRequestError(err):
+		return microerror.Maskf(errors.BadRequestError, err.Error())
+	}
+
+	return err
+}
+
+func printResult(cmd *cobra.Command, positionalArgs []string) {
+	err := updateApp(arguments)
+	if err != nil {
+		handleError(err)
+		os.Exit(errors.ExitCode(err))
+	}
+
+	fmt.Println(color.GreenString("App '%s' in cluster '%s' has been modified.", arguments.AppName, arguments.ClusterNameOrID))
+	fmt.Printf("Use 'gsctl show app %s/%s' to check the status.\n", arguments.ClusterNameOrID, arguments.AppName)
+}
+
+func handleError(err error) {
+	errors.HandleClientErrors(err)
+	errors.HandleCommonErrors(err)
+
+	headline := ""
+	subtext := ""
+
+	switch {
+	case errors.IsInvalidAppArgument(err):
+		headline = "Invalid argument syntax"
+		subtext = "Please specify the app as <cluster-name/cluster-id>/<app-name>. Use --help for details."
+	case errors.IsAppNameMissing(err):
+		headline = "No app name specified."
+		subtext = "Please specify an app name. Use --help for details."
+	case errors.IsNoOpError(err):
+		headline = microerror.Pretty(err, false)
+		subtext = "Please use --version and/or --values-file. Use --help for details."
+	case errors.IsYAMLFileNotReadable(err):
+		headline = "Could not read values file"
+		subtext = err.Error()
+	case errors.IsYAMLNotParseable(err):
+		headline = "Could not parse values file"
+		subtext = "The values file must contain a YAML object. " + err.Error()
+	case errors.IsAppNotFound(err):
+		headline = "App not found"
+		subtext = fmt.Sprintf("Could not find an app named '%s' in this cluster. Check 'gsctl list apps %s' to make sure.", arguments.AppName, arguments.ClusterNameOrID)
+	case errors.IsClusterNotFoundError(err):
+		headline = "Cluster not found"
+		subtext = "Could not find a cluster with this name/ID. Check 'gsctl list clusters' to make sure."
+	case errors.IsBadRequestError(err):
+		headline = "Bad request"
+		subtext = err.Error()
+	default:
+		headline = err.Error()
+	}
+
+	errors.PrintError(err, headline, subtext)
+}
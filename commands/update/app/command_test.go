@@ -0,0 +1,149 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gscliauth/config"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// Test_VerifyPreconditions tests that at least one change is requested.
+func Test_VerifyPreconditions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	initFlags()
+	Command.ParseFlags([]string{})
+	args, err := collectArguments(fs, []string{"f01r4/external-dns"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	args.APIEndpoint = "https://foo"
+	args.AuthToken = "token"
+
+	err = verifyPreconditions(args)
+	if !errors.IsNoOpError(err) {
+		t.Errorf("Expected NoOpError, got %#v", err)
+	}
+
+	_, err = collectArguments(fs, []string{"external-dns"})
+	if !errors.IsInvalidAppArgument(err) {
+		t.Errorf("Expected InvalidAppArgumentError, got %#v", err)
+	}
+}
+
+// Test_UpdateApp tests which requests are sent for the different kinds of changes.
+func Test_UpdateApp(t *testing.T) {
+	var testCases = []struct {
+		appResponse      string
+		modifyStatus     int
+		args             Arguments
+		expectedRequests []string
+		errorMatcher     func(error) bool
+	}{
+		{
+			appResponse: `[{"metadata": {"name": "external-dns"}, "spec": {"version": "1.2.0"}}]`,
+			args:        Arguments{AppName: "external-dns", Version: "1.3.0"},
+			expectedRequests: []string{
+				"PATCH /v4/clusters/f01r4/apps/external-dns/ {\"spec\":{\"version\":\"1.3.0\"}}",
+			},
+		},
+		{
+			appResponse: `[{"metadata": {"name": "external-dns"}, "spec": {"version": "1.2.0"}}]`,
+			args:        Arguments{AppName: "external-dns", Values: map[string]interface{}{"foo": "bar"}},
+			expectedRequests: []string{
+				"PUT /v4/clusters/f01r4/apps/external-dns/config/ {\"foo\":\"bar\"}",
+			},
+		},
+		{
+			appResponse: `[{"metadata": {"name": "external-dns"}, "spec": {"version": "1.2.0", "user_config": {"configmap": {"name": "external-dns-user-values", "namespace": "f01r4"}}}}]`,
+			args:        Arguments{AppName: "external-dns", Version: "1.3.0", Values: map[string]interface{}{"foo": "bar"}},
+			expectedRequests: []string{
+				"PATCH /v4/clusters/f01r4/apps/external-dns/config/ {\"foo\":\"bar\"}",
+				"PATCH /v4/clusters/f01r4/apps/external-dns/ {\"spec\":{\"version\":\"1.3.0\"}}",
+			},
+		},
+		{
+			appResponse:  `[]`,
+			args:         Arguments{AppName: "external-dns", Version: "1.3.0"},
+			errorMatcher: errors.IsAppNotFound,
+		},
+		{
+			appResponse:  `[{"metadata": {"name": "external-dns"}, "spec": {"version": "1.2.0"}}]`,
+			modifyStatus: http.StatusNotFound,
+			args:         Arguments{AppName: "external-dns", Version: "1.3.0"},
+			errorMatcher: errors.IsAppNotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			var requests []string
+
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/v4/clusters/":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`[{"id": "f01r4", "name": "Name of the cluster", "owner": "acme"}]`))
+				case r.Method == http.MethodGet && r.URL.Path == "/v4/clusters/f01r4/apps/":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(tc.appResponse))
+				default:
+					body, _ := ioutil.ReadAll(r.Body)
+					var compact interface{}
+					json.Unmarshal(body, &compact)
+					compactBody, _ := json.Marshal(compact)
+					requests = append(requests, r.Method+" "+r.URL.Path+" "+string(compactBody))
+					if tc.modifyStatus != 0 {
+						w.WriteHeader(tc.modifyStatus)
+						w.Write([]byte(`{"code": "RESOURCE_NOT_FOUND", "message": "The app could not be found."}`))
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{}`))
+				}
+			}))
+			defer mockServer.Close()
+
+			fs := afero.NewMemMapFs()
+			configDir := testutils.TempDir(fs)
+			config.Initialize(fs, configDir)
+
+			args := tc.args
+			args.APIEndpoint = mockServer.URL
+			args.AuthToken = "token"
+			args.UserProvidedToken = "token"
+			args.ClusterNameOrID = "f01r4"
+
+			err := updateApp(args)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Unexpected error: %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error: %#v", i, err)
+			}
+
+			if len(requests) != len(tc.expectedRequests) {
+				t.Fatalf("Case %d - Expected requests %v, got %v", i, tc.expectedRequests, requests)
+			}
+			for j := range requests {
+				if requests[j] != tc.expectedRequests[j] {
+					t.Errorf("Case %d - Expected request %q, got %q", i, tc.expectedRequests[j], requests[j])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/pkg/batch"
+)
+
+// updateClustersLabels applies the label changes to all clusters matching
+// the selector, after showing a summary and asking for confirmation once.
+func updateClustersLabels(args Arguments) ([]batch.Result, error) {
+	if len(args.Labels) == 0 {
+		return nil, microerror.Mask(errors.NoOpError)
+	}
+
+	// Validate label changes before touching any cluster.
+	_, err := modifyClusterLabelsRequestFromArguments(args.Labels)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusters, err := batch.Resolve(clientWrapper, args.APIEndpoint, args.Selector, activityName)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	fmt.Printf("The labels of the following %d clusters will be modified (%s):\n\n", len(clusters), strings.Join(args.Labels, ", "))
+	fmt.Println(batch.SummaryTable(clusters))
+	fmt.Println("")
+
+	if !args.Force {
+		confirmed := confirm.Ask(fmt.Sprintf("Do you want to modify the labels of %d clusters?", len(clusters)))
+		if !confirmed {
+			return nil, microerror.Mask(errors.CommandAbortedError)
+		}
+	}
+
+	results := batch.Run(clusters, args.Parallel, func(c batch.Cluster) (string, error) {
+		clusterArgs := args
+		clusterArgs.ClusterNameOrID = c.ID
+		clusterArgs.Verbose = false
+
+		_, err := updateLabels(clusterArgs)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return "Labels modified", nil
+	})
+
+	return results, nil
+}
+
+// printBatchResult modifies the clusters matching the selector and prints
+// the report. The exit code reflects all failed modifications.
+func printBatchResult(args Arguments) {
+	results, err := updateClustersLabels(args)
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		var headline string
+		var subtext string
+
+		switch {
+		case errors.IsNoOpError(err) && len(args.Labels) == 0:
+			headline = "No label changes specified"
+			subtext = "Please specify the label changes to apply via --label."
+		case errors.IsCommandAbortedError(err):
+			headline = "Not modifying."
+		default:
+			headline = err.Error()
+		}
+
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
+	}
+
+	fmt.Println("")
+	fmt.Println(batch.Report(results))
+
+	if code := batch.ExitCode(results); code != errors.ExitCodeOK {
+		fmt.Println(color.YellowString("Please check the failed modifications listed above."))
+		os.Exit(code)
+	}
+}
@@ -3,7 +3,6 @@ package cluster
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/fatih/color"
@@ -19,14 +18,15 @@ import (
 	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/batch"
 )
 
 var (
 	// Command is the cobra command for 'gsctl update cluster'
 	Command = &cobra.Command{
 		Use: "cluster <cluster-name/cluster-id>",
-		// Args: cobra.ExactArgs(1) guarantees that cobra will fail if no positional argument is given.
-		Args:  cobra.ExactArgs(1),
+		// Args: cobra.MaximumNArgs(1) allows to omit the cluster when using --selector.
+		Args:  cobra.MaximumNArgs(1),
 		Short: "Modify cluster details",
 		Long: `Change the details of a cluster
 
@@ -37,6 +37,11 @@ Examples:
 
   gsctl update cluster f01r4 --label environment=testing --label labeltodelete=
   gsctl update cluster f01r4 --master-ha=true
+
+Labels can be changed on all clusters matching a label selector at once, with
+at most --parallel updates running at the same time:
+
+  gsctl update cluster --selector environment=testing --label owner=team-a
 `,
 
 		// PreRun checks a few general things, like authentication.
@@ -63,6 +68,9 @@ func initFlags() {
 	Command.Flags().StringVarP(&flags.Name, "name", "n", "", "new cluster name")
 	Command.Flags().BoolVar(&flags.MasterHA, "master-ha", false, "switch to high-availability master (AWS only)")
 	Command.Flags().StringSliceVar(&flags.Label, "label", nil, "modification of a label in form of 'key=value'. Can be specified multiple times. To delete a label set to 'key='")
+	Command.Flags().StringVarP(&flags.Selector, "selector", "l", "", "label selector query. Modifies the labels of all matching clusters instead of a single one")
+	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "if set, no interactive confirmation will be required when using --selector")
+	Command.Flags().IntVarP(&flags.Parallel, "parallel", "", batch.DefaultParallel, "maximum number of clusters to modify at the same time when using --selector")
 }
 
 // Arguments represents all the ways the user can influence the command.
@@ -70,9 +78,12 @@ type Arguments struct {
 	APIEndpoint       string
 	AuthToken         string
 	ClusterNameOrID   string
+	Force             bool
 	MasterHA          bool
 	Labels            []string
 	Name              string
+	Parallel          int
+	Selector          string
 	UserProvidedToken string
 	Verbose           bool
 }
@@ -81,13 +92,21 @@ func collectArguments(positionalArgs []string) Arguments {
 	endpoint := config.Config.ChooseEndpoint(flags.APIEndpoint)
 	token := config.Config.ChooseToken(endpoint, flags.Token)
 
+	clusterNameOrID := ""
+	if len(positionalArgs) > 0 {
+		clusterNameOrID = strings.TrimSpace(positionalArgs[0])
+	}
+
 	return Arguments{
 		APIEndpoint:       endpoint,
 		AuthToken:         token,
-		ClusterNameOrID:   strings.TrimSpace(positionalArgs[0]),
+		ClusterNameOrID:   clusterNameOrID,
+		Force:             flags.Force,
 		MasterHA:          flags.MasterHA,
 		Labels:            flags.Label,
 		Name:              flags.Name,
+		Parallel:          flags.Parallel,
+		Selector:          flags.Selector,
 		UserProvidedToken: flags.Token,
 		Verbose:           flags.Verbose,
 	}
@@ -105,7 +124,9 @@ func verifyPreconditions(cmd *cobra.Command, args Arguments) error {
 		return microerror.Mask(errors.EndpointMissingError)
 	} else if args.AuthToken == "" && args.UserProvidedToken == "" {
 		return microerror.Mask(errors.NotLoggedInError)
-	} else if args.ClusterNameOrID == "" {
+	} else if args.Selector != "" && (args.ClusterNameOrID != "" || args.Name != "" || args.MasterHA) {
+		return microerror.Mask(errors.ConflictingFlagsError)
+	} else if args.ClusterNameOrID == "" && args.Selector == "" {
 		return microerror.Mask(errors.ClusterNameOrIDMissingError)
 	} else if cmd.Flag("master-ha").Changed && !args.MasterHA {
 		return microerror.Mask(revertHAMasterNotAllowedError)
@@ -124,7 +145,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	headline := ""
@@ -136,6 +157,10 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 		headline = "Operation not permitted"
 		subtext = "It is not possible to change from multiple master nodes to a single master."
 
+	case errors.IsConflictingFlagsError(err) && arguments.Selector != "":
+		headline = "Conflicting flags used"
+		subtext = "--selector can only be combined with --label, not with a cluster name or ID."
+
 	case errors.IsConflictingFlagsError(err):
 		headline = "Conflicting flags used"
 		subtext = "--name/-n and --label are exclusive."
@@ -145,12 +170,9 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	// print output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
+	errors.PrintError(err, headline, subtext)
 
-	os.Exit(1)
+	errors.Exit(err)
 }
 
 func updateCluster(args Arguments) (*result, error) {
@@ -316,9 +338,14 @@ func updateLabels(args Arguments) (*result, error) {
 }
 
 func printResult(cmd *cobra.Command, positionalArgs []string) {
+	if arguments.Selector != "" {
+		printBatchResult(arguments)
+		return
+	}
+
 	result, err := updateCluster(arguments)
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		headline := ""
@@ -354,11 +381,8 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	fmt.Println(color.GreenString("Cluster '%s' has been modified.", arguments.ClusterNameOrID))
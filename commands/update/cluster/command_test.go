@@ -6,11 +6,15 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
 
+	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/batch"
 	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
 )
 
 // configYAML is a mock configuration used by some of the tests.
@@ -44,6 +48,7 @@ func TestCollectArgs(t *testing.T) {
 				APIEndpoint:     "https://foo",
 				AuthToken:       "some-token",
 				ClusterNameOrID: "clusterid",
+				Parallel:        batch.DefaultParallel,
 			},
 		},
 		{
@@ -57,6 +62,22 @@ func TestCollectArgs(t *testing.T) {
 				AuthToken:       "some-token",
 				ClusterNameOrID: "clusterid",
 				Name:            "NewName",
+				Parallel:        batch.DefaultParallel,
+			},
+		},
+		{
+			[]string{},
+			func() {
+				initFlags()
+				Command.ParseFlags([]string{"--selector=environment=testing", "--label=owner=team-a", "--force"})
+			},
+			Arguments{
+				APIEndpoint: "https://foo",
+				AuthToken:   "some-token",
+				Force:       true,
+				Labels:      []string{"owner=team-a"},
+				Parallel:    batch.DefaultParallel,
+				Selector:    "environment=testing",
 			},
 		},
 	}
@@ -141,6 +162,36 @@ func Test_verifyPreconditions(t *testing.T) {
 			},
 			nil,
 		},
+		// Selector instead of cluster ID.
+		{
+			Arguments{
+				AuthToken:   "token",
+				APIEndpoint: "https://mock-url",
+				Selector:    "environment=testing",
+				Labels:      []string{"owner=team-a"},
+			},
+			nil,
+		},
+		// Selector and cluster ID given at the same time.
+		{
+			Arguments{
+				AuthToken:       "token",
+				APIEndpoint:     "https://mock-url",
+				ClusterNameOrID: "cluster-id",
+				Selector:        "environment=testing",
+			},
+			errors.IsConflictingFlagsError,
+		},
+		// Selector and name given at the same time.
+		{
+			Arguments{
+				AuthToken:   "token",
+				APIEndpoint: "https://mock-url",
+				Selector:    "environment=testing",
+				Name:        "newname",
+			},
+			errors.IsConflictingFlagsError,
+		},
 	}
 
 	fs := afero.NewMemMapFs()
@@ -455,3 +506,62 @@ func Test_modifyClusterLabelsRequestFromArguments(t *testing.T) {
 	}
 
 }
+
+// TestUpdateClustersLabels modifies labels of all clusters matching a selector.
+func TestUpdateClustersLabels(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := fakeapi.New(fakeapi.Config{})
+	for _, id := range []string{"a1111", "b2222"} {
+		server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: id, Owner: fakeapi.DefaultOrganization, Labels: map[string]string{"environment": "testing"}})
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	args := Arguments{
+		APIEndpoint:       ts.URL,
+		AuthToken:         "some-token",
+		Force:             true,
+		Labels:            []string{"owner=team-a"},
+		Parallel:          2,
+		Selector:          "environment=testing",
+		UserProvidedToken: "some-token",
+	}
+
+	var results []batch.Result
+	testutils.CaptureOutput(func() {
+		results, err = updateClustersLabels(args)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("Unexpected error for cluster %s: %#v", r.Cluster.ID, r.Err)
+		}
+	}
+
+	args.Selector = "owner=team-a"
+	args.Labels = nil
+	_, err = updateClustersLabels(args)
+	if !errors.IsNoOpError(err) {
+		t.Errorf("Expected NoOpError without label changes, got %#v", err)
+	}
+
+	// Both clusters now carry the new label.
+	clientWrapper, err := client.NewWithConfig(ts.URL, "some-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusters, err := batch.Resolve(clientWrapper, ts.URL, "owner=team-a", activityName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 2 {
+		t.Errorf("Expected 2 clusters with the new label, got %d", len(clusters))
+	}
+}
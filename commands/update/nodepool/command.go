@@ -158,7 +158,7 @@ func printValidation(cmd *cobra.Command, positionalArgs []string) {
 	}
 
 	handleError(err)
-	os.Exit(1)
+	os.Exit(errors.ExitCode(err))
 }
 
 func updateNodePool(args Arguments) (*result, error) {
@@ -220,7 +220,7 @@ func printResult(cmd *cobra.Command, positionalArgs []string) {
 	r, err := updateNodePool(arguments)
 	if err != nil {
 		handleError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 
 	fmt.Println(color.GreenString("Node pool '%s' (ID '%s') in cluster '%s' has been modified.", r.NodePool.Name, r.NodePool.ID, arguments.ClusterNameOrID))
@@ -241,7 +241,7 @@ func getInstallationInfo(endpoint, userProvidedToken string) (*models.V4InfoResp
 }
 
 func handleError(err error) {
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	headline := ""
@@ -258,8 +258,5 @@ func handleError(err error) {
 	}
 
 	// print output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
+	errors.PrintError(err, headline, subtext)
 }
@@ -2,7 +2,6 @@ package setcredentials
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/fatih/color"
@@ -133,7 +132,7 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 		return
 	}
 
-	client.HandleErrors(err)
+	errors.HandleClientErrors(err)
 	errors.HandleCommonErrors(err)
 
 	// From here on we handle errors that can only occur in this command
@@ -162,11 +161,8 @@ func printValidation(cmd *cobra.Command, cmdLineArgs []string) {
 	}
 
 	// print output
-	fmt.Println(color.RedString(headline))
-	if subtext != "" {
-		fmt.Println(subtext)
-	}
-	os.Exit(1)
+	errors.PrintError(err, headline, subtext)
+	errors.Exit(err)
 }
 
 func verifyPreconditions(args Arguments) error {
@@ -284,7 +280,7 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 	result, err := setOrgCredentials(arguments)
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		// From here on we handle errors that can only occur in this command
@@ -300,11 +296,8 @@ func printResult(cmd *cobra.Command, cmdLineArgs []string) {
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 
 	// success
@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/confirm"
+	"github.com/giantswarm/gsctl/pkg/batch"
+	"github.com/giantswarm/gsctl/pkg/wait"
+)
+
+// upgradeClusters upgrades all clusters matching the selector, after showing
+// a summary and asking for confirmation once. As only clusters with node
+// pools have labels, the v5 API is used throughout.
+func upgradeClusters(args Arguments) ([]batch.Result, error) {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	clusters, err := batch.Resolve(clientWrapper, args.APIEndpoint, args.Selector, upgradeClusterActivityName)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = upgradeClusterActivityName
+
+	releasesResponse, err := clientWrapper.GetReleases(auxParams)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var releaseVersions []string
+	releaseExists := false
+	for _, r := range releasesResponse.Payload {
+		if *r.Version == args.Release {
+			releaseExists = true
+		}
+
+		if !r.Active || !isVersionProductionReady(*r.Version) {
+			continue
+		}
+
+		releaseVersions = append(releaseVersions, *r.Version)
+	}
+
+	if args.Release != "" && !releaseExists {
+		return nil, microerror.Maskf(errors.InvalidReleaseError, fmt.Sprintf("Can't upgrade to non existing release %s", args.Release))
+	}
+
+	// Determine the target version per cluster. An empty string means there
+	// is no newer release.
+	targetVersions := map[string]string{}
+	for _, c := range clusters {
+		if args.Release != "" {
+			targetVersions[c.ID] = args.Release
+		} else {
+			targetVersions[c.ID] = successorReleaseVersion(c.ReleaseVersion, releaseVersions)
+		}
+	}
+
+	fmt.Printf("The following %d clusters will be upgraded:\n\n", len(clusters))
+	fmt.Println(batch.SummaryTable(clusters, batch.Column{
+		Title: "TARGET RELEASE",
+		Value: func(c batch.Cluster) string {
+			if targetVersions[c.ID] == "" {
+				return "n/a"
+			}
+			return targetVersions[c.ID]
+		},
+	}))
+
+	fmt.Println("")
+	fmt.Println("NOTE: Upgrading may impact your running workloads and will make the clusters'")
+	fmt.Println("Kubernetes API unavailable temporarily. Before upgrading, please acknowledge the")
+	fmt.Println("details described in")
+	fmt.Println("")
+	fmt.Printf("    %s\n", upgradeDocsURL)
+	fmt.Println("")
+
+	if !args.Force {
+		confirmed := confirm.Ask(fmt.Sprintf("Do you want to start the upgrade of %d clusters now?", len(clusters)))
+		if !confirmed {
+			return nil, microerror.Mask(errors.CommandAbortedError)
+		}
+	}
+
+	results := batch.Run(clusters, args.Parallel, func(c batch.Cluster) (string, error) {
+		targetVersion := targetVersions[c.ID]
+		if targetVersion == "" {
+			return "", microerror.Mask(errors.NoUpgradeAvailableError)
+		}
+
+		details, err := clientWrapper.GetClusterV5(c.ID, auxParams)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+		lastUpdated := wait.LastUpdated(details.Payload)
+
+		_, err = clientWrapper.ModifyClusterV5(c.ID, &models.V5ModifyClusterRequest{ReleaseVersion: targetVersion}, auxParams)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		if !args.Wait {
+			return fmt.Sprintf("Upgrade to %s started", targetVersion), nil
+		}
+
+		w, err := wait.New(wait.Config{
+			ClientWrapper: clientWrapper,
+			Output:        ioutil.Discard,
+			Timeout:       args.WaitTimeout,
+		})
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		err = w.ClusterUpgraded(c.ID, targetVersion, lastUpdated)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		return fmt.Sprintf("Upgraded to %s", targetVersion), nil
+	})
+
+	return results, nil
+}
+
+// printBatchResult upgrades the clusters matching the selector and prints
+// the report. The exit code reflects all failed upgrades.
+func printBatchResult(args Arguments) {
+	results, err := upgradeClusters(args)
+	if err != nil {
+		errors.HandleClientErrors(err)
+		errors.HandleCommonErrors(err)
+
+		var headline string
+		var subtext string
+
+		switch {
+		case errors.IsCommandAbortedError(err):
+			headline = "Not upgrading."
+		case errors.IsInvalidReleaseError(err):
+			headline = "Invalid release"
+			subtext = err.Error()
+		default:
+			headline = err.Error()
+		}
+
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
+	}
+
+	fmt.Println("")
+	fmt.Println(batch.Report(results))
+
+	if code := batch.ExitCode(results); code != errors.ExitCodeOK {
+		fmt.Println(color.YellowString("Please check the failed upgrades listed above."))
+		os.Exit(code)
+	}
+}
@@ -2,8 +2,9 @@ package cluster
 
 import (
 	"fmt"
-	"os"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/fatih/color"
@@ -19,6 +20,10 @@ import (
 	"github.com/giantswarm/gsctl/commands/errors"
 	"github.com/giantswarm/gsctl/confirm"
 	"github.com/giantswarm/gsctl/flags"
+	"github.com/giantswarm/gsctl/pkg/batch"
+	"github.com/giantswarm/gsctl/pkg/releaseinfo"
+	"github.com/giantswarm/gsctl/pkg/schedule"
+	"github.com/giantswarm/gsctl/pkg/wait"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -56,6 +61,40 @@ Example:
   gsctl upgrade cluster 6iec4
   gsctl upgrade cluster "Cluster name"
   gsctl upgrade cluster "Cluster name" --release "13.0.0"
+
+To upgrade all clusters matching a label selector, use --selector instead of
+a cluster name or ID. The matching clusters are listed for confirmation and
+upgraded with at most --parallel upgrades running at the same time:
+
+  gsctl upgrade cluster --selector environment=testing --parallel 2
+
+To block until the cluster runs the new release, use --wait. The command exits
+with a non-zero exit code if the upgrade does not complete within the time
+given via --timeout:
+
+  gsctl upgrade cluster 6iec4 --force --wait --timeout 2h
+
+To see what would change without upgrading, use --plan. This compares the
+components of the running and the target release, shows the Kubernetes
+versions and their end of life dates, and aggregates the changelogs of all
+releases in between. If skipping releases is not advisable, the releases to
+upgrade through are listed:
+
+  gsctl upgrade cluster 6iec4 --plan
+  gsctl upgrade cluster 6iec4 --release 13.0.0 --plan
+
+To upgrade at a given time or within a maintenance window, use --at or
+--window. The target release is checked and the confirmation is asked for
+right away. Then the command waits in the foreground until the time has come,
+starts the upgrade and waits until the cluster runs the new release, like
+--wait. If the window has closed before the upgrade could be started, the
+command aborts without upgrading.
+
+Windows are given as '[<weekday>] <HH:MM>-<HH:MM> [<time zone>]'. Without a
+weekday, the window is open every day. The time zone defaults to UTC.
+
+  gsctl upgrade cluster 6iec4 --at 2020-09-19T02:00:00Z
+  gsctl upgrade cluster 6iec4 --window "Sat 02:00-04:00 UTC" --timeout 2h
 `),
 
 		// We use PreRun for general input validation, authentication etc.
@@ -74,12 +113,19 @@ Example:
 // to the validation function.
 type Arguments struct {
 	APIEndpoint       string
+	At                string
 	AuthToken         string
 	ClusterNameOrID   string
 	Force             bool
+	Parallel          int
+	Plan              bool
 	Release           string
+	Selector          string
 	UserProvidedToken string
 	Verbose           bool
+	Wait              bool
+	WaitTimeout       time.Duration
+	Window            string
 }
 
 // function to create arguments based on command line flags and config
@@ -93,12 +139,19 @@ func collectArguments(positionalArgs []string) Arguments {
 
 	return Arguments{
 		APIEndpoint:       endpoint,
+		At:                flags.StartAt,
 		AuthToken:         token,
 		ClusterNameOrID:   clusterID,
 		Force:             flags.Force,
+		Parallel:          flags.Parallel,
+		Plan:              flags.Plan,
 		Release:           flags.Release,
+		Selector:          flags.Selector,
 		UserProvidedToken: flags.Token,
 		Verbose:           flags.Verbose,
+		Wait:              flags.Wait,
+		WaitTimeout:       flags.WaitTimeout,
+		Window:            flags.MaintenanceWindow,
 	}
 }
 
@@ -106,6 +159,11 @@ type upgradeClusterResult struct {
 	clusterID     string
 	versionBefore string
 	versionAfter  string
+	// plan is only set when using --plan.
+	plan *releaseinfo.ReleaseDiff
+	// lastUpdated is the time of the cluster's latest Updated condition
+	// before the upgrade was requested, see wait.LastUpdated.
+	lastUpdated time.Time
 }
 
 func init() {
@@ -116,7 +174,14 @@ func initFlags() {
 	Command.ResetFlags()
 
 	Command.Flags().BoolVarP(&flags.Force, "force", "", false, "If set, no interactive confirmation will be required (risky!).")
+	Command.Flags().BoolVarP(&flags.Plan, "plan", "", false, "Only show what would change, without upgrading.")
+	Command.Flags().BoolVarP(&flags.Wait, "wait", "", false, "Wait until the cluster has been upgraded.")
+	Command.Flags().DurationVarP(&flags.WaitTimeout, "timeout", "", wait.DefaultTimeout, "Maximum time to wait when using --wait.")
 	Command.Flags().StringVarP(&flags.Release, "release", "", "", "The target release version for the upgrade. If no version is specified, the first version following the running one is selected..")
+	Command.Flags().StringVarP(&flags.Selector, "selector", "l", "", "Label selector query. Upgrades all matching clusters instead of a single one.")
+	Command.Flags().IntVarP(&flags.Parallel, "parallel", "", batch.DefaultParallel, "Maximum number of clusters to upgrade at the same time when using --selector.")
+	Command.Flags().StringVarP(&flags.StartAt, "at", "", "", "Start the upgrade at this time, given in RFC3339 format, e. g. '2020-09-19T02:00:00Z'.")
+	Command.Flags().StringVarP(&flags.MaintenanceWindow, "window", "", "", "Start the upgrade within this maintenance window, e. g. 'Sat 02:00-04:00 UTC'.")
 }
 
 // Prints results of our pre-validation
@@ -129,7 +194,7 @@ func upgradeClusterValidationOutput(cmd *cobra.Command, cmdLineArgs []string) {
 	err := validateUpgradeClusterPreconditions(arguments, cmdLineArgs)
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		switch {
@@ -140,17 +205,32 @@ func upgradeClusterValidationOutput(cmd *cobra.Command, cmdLineArgs []string) {
 			subtext = fmt.Sprintf("Use '%s login' to login or '--auth-token' to pass a valid auth token.", config.ProgramName)
 		case errors.IsClusterNameOrIDMissingError(err):
 			headline = "No cluster name or ID specified."
-			subtext = "Please specify which cluster to upgrade by using the cluster name or ID as an argument."
+			subtext = "Please specify which cluster to upgrade by using the cluster name or ID as an argument, or use --selector."
+		case errors.IsConflictingFlagsError(err) && arguments.At != "" && arguments.Window != "":
+			headline = "Conflicting flags/arguments"
+			subtext = "Please use either --at or --window, not both."
+		case errors.IsConflictingFlagsError(err) && (arguments.At != "" || arguments.Window != ""):
+			headline = "Conflicting flags/arguments"
+			subtext = "--at and --window work for a single cluster and can't be combined with --selector or --plan."
+		case errors.IsInvalidScheduleError(err) && arguments.At != "":
+			headline = "Invalid value for --at"
+			subtext = "Please give a time in the future in RFC3339 format, e. g. '2020-09-19T02:00:00Z'."
+		case errors.IsInvalidScheduleError(err):
+			headline = "Invalid maintenance window"
+			subtext = "Please give the window as '[<weekday>] <HH:MM>-<HH:MM> [<time zone>]', e. g. 'Sat 02:00-04:00 UTC'."
+		case errors.IsConflictingFlagsError(err) && arguments.Plan:
+			headline = "Conflicting flags/arguments"
+			subtext = "--plan works for a single cluster and can't be combined with --selector or --wait."
+		case errors.IsConflictingFlagsError(err):
+			headline = "Conflicting flags/arguments"
+			subtext = "Please specify either a cluster name or ID or a label selector via --selector, not both."
 		default:
 			headline = err.Error()
 		}
 
 		// print output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
 	}
 }
 
@@ -165,10 +245,27 @@ func validateUpgradeClusterPreconditions(args Arguments, cmdLineArgs []string) e
 		return microerror.Mask(errors.NotLoggedInError)
 	}
 
-	// cluster ID is present
-	if args.ClusterNameOrID == "" {
+	// cluster ID or selector is present
+	if args.ClusterNameOrID == "" && args.Selector == "" {
 		return microerror.Mask(errors.ClusterNameOrIDMissingError)
 	}
+	if args.ClusterNameOrID != "" && args.Selector != "" {
+		return microerror.Mask(errors.ConflictingFlagsError)
+	}
+	if args.Plan && (args.Selector != "" || args.Wait) {
+		return microerror.Mask(errors.ConflictingFlagsError)
+	}
+
+	if args.At != "" || args.Window != "" {
+		if (args.At != "" && args.Window != "") || args.Selector != "" || args.Plan {
+			return microerror.Mask(errors.ConflictingFlagsError)
+		}
+
+		_, err := schedulePeriod(args, time.Now())
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
 
 	return nil
 }
@@ -176,10 +273,29 @@ func validateUpgradeClusterPreconditions(args Arguments, cmdLineArgs []string) e
 // upgradeClusterExecutionOutput executes our business function and displays the result,
 // both in case of success or error
 func upgradeClusterExecutionOutput(cmd *cobra.Command, cmdLineArgs []string) {
+	if arguments.Selector != "" {
+		printBatchResult(arguments)
+		return
+	}
+
 	result, err := upgradeCluster(arguments)
+	if err == nil && arguments.Plan {
+		printPlan(arguments, result)
+		return
+	}
+
+	// Scheduled upgrades are always monitored, as nobody may be watching.
+	monitor := arguments.Wait || isScheduled(arguments)
+	if err == nil && monitor {
+		fmt.Println(color.GreenString("Starting to upgrade cluster '%s' to release version %s",
+			result.clusterID,
+			result.versionAfter))
+
+		err = waitForUpgrade(arguments, result)
+	}
 
 	if err != nil {
-		client.HandleErrors(err)
+		errors.HandleClientErrors(err)
 		errors.HandleCommonErrors(err)
 
 		var headline = ""
@@ -199,16 +315,26 @@ func upgradeClusterExecutionOutput(cmd *cobra.Command, cmdLineArgs []string) {
 			subtext = fmt.Sprintf("We couldn't find a cluster '%s' via API endpoint %s.", arguments.ClusterNameOrID, arguments.APIEndpoint)
 		case errors.IsCommandAbortedError(err):
 			headline = "Not upgrading."
+		case errors.IsMaintenanceWindowClosedError(err):
+			headline = "Not upgrading."
+			subtext = fmt.Sprintf("The maintenance window %s closed before the upgrade could be started.", arguments.Window)
+		case wait.IsTimeout(err):
+			headline = "Timeout"
+			subtext = fmt.Sprintf("The upgrade of cluster '%s' to release version %s did not complete in time.", result.clusterID, result.versionAfter)
 		default:
 			headline = err.Error()
 		}
 
 		// Print error output
-		fmt.Println(color.RedString(headline))
-		if subtext != "" {
-			fmt.Println(subtext)
-		}
-		os.Exit(1)
+		errors.PrintError(err, headline, subtext)
+		errors.Exit(err)
+	}
+
+	if monitor {
+		fmt.Println(color.GreenString("Cluster '%s' has been upgraded to release version %s",
+			result.clusterID,
+			result.versionAfter))
+		return
 	}
 
 	fmt.Println(color.GreenString("Starting to upgrade cluster '%s' to release version %s",
@@ -216,6 +342,24 @@ func upgradeClusterExecutionOutput(cmd *cobra.Command, cmdLineArgs []string) {
 		result.versionAfter))
 }
 
+// waitForUpgrade polls the API until the cluster runs the target release version.
+func waitForUpgrade(args Arguments, result *upgradeClusterResult) error {
+	clientWrapper, err := client.NewWithConfig(args.APIEndpoint, args.UserProvidedToken)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	w, err := wait.New(wait.Config{
+		ClientWrapper: clientWrapper,
+		Timeout:       args.WaitTimeout,
+	})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return w.ClusterUpgraded(result.clusterID, result.versionAfter, result.lastUpdated)
+}
+
 // upgradeCluster performs our actual function. It usually creates an API client,
 // configures it, configures an API request and performs it.
 func upgradeCluster(args Arguments) (*upgradeClusterResult, error) {
@@ -309,6 +453,25 @@ func upgradeCluster(args Arguments) (*upgradeClusterResult, error) {
 		return nil, microerror.Maskf(errors.InvalidReleaseError, fmt.Sprintf("Can't upgrade to non existing release %s", targetVersion))
 	}
 
+	if args.Plan {
+		result.plan, err = planUpgrade(clientWrapper, result.versionBefore, targetVersion)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		return result, nil
+	}
+
+	upgradePath := releaseinfo.UpgradePath(releasesResponse.Payload, result.versionBefore, targetVersion)
+
+	var period schedule.Period
+	if isScheduled(args) {
+		period, err = schedulePeriod(args, scheduler.Now())
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	// Show some details independent of confirmation
 	if !targetRelease.Active {
 		fmt.Printf("Cluster '%s' will be upgraded from version %s to %s, which is not an active release.\n",
@@ -332,6 +495,14 @@ func upgradeCluster(args Arguments) (*upgradeClusterResult, error) {
 		fmt.Printf("    - %s: %s\n", change.Component, change.Description)
 	}
 
+	if len(upgradePath) > 1 {
+		fmt.Println("")
+		fmt.Println(color.YellowString("WARNING: Skipping releases is not advisable for this upgrade. Please consider"))
+		fmt.Println(color.YellowString("upgrading through these releases one at a time instead:"))
+		fmt.Println("")
+		fmt.Printf("    %s\n", strings.Join(append([]string{result.versionBefore}, upgradePath...), " -> "))
+	}
+
 	fmt.Println("")
 	fmt.Println("NOTE: Upgrading may impact your running workloads and will make the cluster's")
 	fmt.Println("Kubernetes API unavailable temporarily. Before upgrading, please acknowledge the")
@@ -340,15 +511,43 @@ func upgradeCluster(args Arguments) (*upgradeClusterResult, error) {
 	fmt.Printf("    %s\n", upgradeDocsURL)
 	fmt.Println("")
 
+	if isScheduled(args) {
+		fmt.Printf("The upgrade will be started at %s.\n", color.CyanString(period.String()))
+		fmt.Println("This command will keep running until the upgrade has completed.")
+		fmt.Println("")
+	}
+
 	// Confirmation
 	if !args.Force {
-		confirmed := confirm.Ask("Do you want to start the upgrade now?")
+		question := "Do you want to start the upgrade now?"
+		if isScheduled(args) {
+			question = "Do you want to start the upgrade at the scheduled time?"
+		}
+
+		confirmed := confirm.Ask(question)
 		if !confirmed {
 			return nil, microerror.Mask(errors.CommandAbortedError)
 		}
 	}
 
+	if isScheduled(args) {
+		err = scheduler.WaitForStart(period)
+		if schedule.IsWindowClosed(err) {
+			return nil, microerror.Mask(errors.MaintenanceWindowClosedError)
+		} else if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	if detailsV5 != nil {
+		// The cluster may have changed while waiting for confirmation or
+		// the maintenance window.
+		responseV5, err := clientWrapper.GetClusterV5(result.clusterID, auxParams)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		result.lastUpdated = wait.LastUpdated(responseV5.Payload)
+
 		if args.Verbose {
 			fmt.Println(color.WhiteString("Submitting cluster modification request to v5 endpoint."))
 		}
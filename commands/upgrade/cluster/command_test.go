@@ -4,15 +4,23 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/gabs"
 	"github.com/giantswarm/gscliauth/config"
+	"github.com/giantswarm/gsclientgen/v2/models"
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
 
+	"github.com/giantswarm/gsctl/client"
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/batch"
+	"github.com/giantswarm/gsctl/pkg/schedule"
+	"github.com/giantswarm/gsctl/pkg/wait"
 	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
 )
 
 func Test_successorReleaseVersion(t *testing.T) {
@@ -182,7 +190,9 @@ func Test_collectArguments(t *testing.T) {
 			},
 			resultingArgs: Arguments{
 				ClusterNameOrID: "clusterid",
+				WaitTimeout:     wait.DefaultTimeout,
 				Force:           true,
+				Parallel:        batch.DefaultParallel,
 				Release:         "",
 			},
 		},
@@ -198,10 +208,46 @@ func Test_collectArguments(t *testing.T) {
 			},
 			resultingArgs: Arguments{
 				ClusterNameOrID: "clusterid",
+				WaitTimeout:     wait.DefaultTimeout,
+				Parallel:        batch.DefaultParallel,
 				Release:         "1.2.3",
 				Force:           false,
 			},
 		},
+		{
+			name:                "Test 3: Wait with timeout",
+			positionalArguments: []string{"clusterid"},
+			commandExecution: func() {
+				initFlags()
+				Command.ParseFlags([]string{
+					"clusterid",
+					"--wait",
+					"--timeout=2h",
+				})
+			},
+			resultingArgs: Arguments{
+				ClusterNameOrID: "clusterid",
+				Parallel:        batch.DefaultParallel,
+				Wait:            true,
+				WaitTimeout:     2 * time.Hour,
+			},
+		},
+		{
+			name:                "Test 4: Selector",
+			positionalArguments: []string{},
+			commandExecution: func() {
+				initFlags()
+				Command.ParseFlags([]string{
+					"--selector=environment=testing",
+					"--parallel=2",
+				})
+			},
+			resultingArgs: Arguments{
+				Parallel:    2,
+				Selector:    "environment=testing",
+				WaitTimeout: wait.DefaultTimeout,
+			},
+		},
 	}
 
 	for index, tt := range tests {
@@ -266,6 +312,137 @@ func Test_validateUpgradeClusterPreconditions(t *testing.T) {
 			},
 			wantErr: errors.IsClusterNameOrIDMissingError,
 		},
+		{
+			name: "Selector instead of cluster ID",
+			args: args{
+				Arguments{
+					APIEndpoint: "https://some-endpoint.com",
+					AuthToken:   "token",
+					Selector:    "environment=testing",
+				},
+				[]string{},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Selector and cluster ID",
+			args: args{
+				Arguments{
+					APIEndpoint:     "https://some-endpoint.com",
+					AuthToken:       "token",
+					ClusterNameOrID: "clusterid",
+					Selector:        "environment=testing",
+				},
+				[]string{},
+			},
+			wantErr: errors.IsConflictingFlagsError,
+		},
+		{
+			name: "Plan and wait",
+			args: args{
+				Arguments{
+					APIEndpoint:     "https://some-endpoint.com",
+					AuthToken:       "token",
+					ClusterNameOrID: "clusterid",
+					Plan:            true,
+					Wait:            true,
+				},
+				[]string{},
+			},
+			wantErr: errors.IsConflictingFlagsError,
+		},
+		{
+			name: "At and window",
+			args: args{
+				Arguments{
+					APIEndpoint:     "https://some-endpoint.com",
+					At:              "2099-01-01T02:00:00Z",
+					AuthToken:       "token",
+					ClusterNameOrID: "clusterid",
+					Window:          "Sat 02:00-04:00 UTC",
+				},
+				[]string{},
+			},
+			wantErr: errors.IsConflictingFlagsError,
+		},
+		{
+			name: "Window and selector",
+			args: args{
+				Arguments{
+					APIEndpoint: "https://some-endpoint.com",
+					AuthToken:   "token",
+					Selector:    "environment=testing",
+					Window:      "Sat 02:00-04:00 UTC",
+				},
+				[]string{},
+			},
+			wantErr: errors.IsConflictingFlagsError,
+		},
+		{
+			name: "At in the future",
+			args: args{
+				Arguments{
+					APIEndpoint:     "https://some-endpoint.com",
+					At:              "2099-01-01T02:00:00Z",
+					AuthToken:       "token",
+					ClusterNameOrID: "clusterid",
+				},
+				[]string{},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "At in the past",
+			args: args{
+				Arguments{
+					APIEndpoint:     "https://some-endpoint.com",
+					At:              "2001-01-01T02:00:00Z",
+					AuthToken:       "token",
+					ClusterNameOrID: "clusterid",
+				},
+				[]string{},
+			},
+			wantErr: errors.IsInvalidScheduleError,
+		},
+		{
+			name: "At not RFC3339",
+			args: args{
+				Arguments{
+					APIEndpoint:     "https://some-endpoint.com",
+					At:              "tomorrow",
+					AuthToken:       "token",
+					ClusterNameOrID: "clusterid",
+				},
+				[]string{},
+			},
+			wantErr: errors.IsInvalidScheduleError,
+		},
+		{
+			name: "Invalid window",
+			args: args{
+				Arguments{
+					APIEndpoint:     "https://some-endpoint.com",
+					AuthToken:       "token",
+					ClusterNameOrID: "clusterid",
+					Window:          "Caturday 02:00-04:00",
+				},
+				[]string{},
+			},
+			wantErr: errors.IsInvalidScheduleError,
+		},
+		{
+			name: "Plan and selector",
+			args: args{
+				Arguments{
+					APIEndpoint: "https://some-endpoint.com",
+					AuthToken:   "token",
+					Plan:        true,
+					Selector:    "environment=testing",
+				},
+				[]string{},
+			},
+			wantErr: errors.IsConflictingFlagsError,
+		},
 	}
 	for index, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -283,3 +460,193 @@ func Test_validateUpgradeClusterPreconditions(t *testing.T) {
 		})
 	}
 }
+
+// TestUpgradeClustersBySelector upgrades all clusters matching a selector.
+func TestUpgradeClustersBySelector(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	server := fakeapi.New(fakeapi.Config{})
+	server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: "a1111", Owner: fakeapi.DefaultOrganization, ReleaseVersion: "11.5.0", Labels: map[string]string{"environment": "testing"}})
+	server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: "b2222", Owner: fakeapi.DefaultOrganization, ReleaseVersion: "12.1.0", Labels: map[string]string{"environment": "testing"}})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	args := Arguments{
+		APIEndpoint:       ts.URL,
+		AuthToken:         "some-token",
+		Force:             true,
+		Parallel:          2,
+		Selector:          "environment=testing",
+		UserProvidedToken: "some-token",
+		Wait:              true,
+		WaitTimeout:       5 * time.Second,
+	}
+
+	var results []batch.Result
+	var err error
+	testutils.CaptureOutput(func() {
+		results, err = upgradeClusters(args)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Message != "Upgraded to 12.1.0" {
+		t.Errorf("Unexpected result %#v", results[0])
+	}
+	// The second cluster already runs the latest release.
+	if !errors.IsNoUpgradeAvailableError(results[1].Err) {
+		t.Errorf("Expected NoUpgradeAvailableError, got %#v", results[1].Err)
+	}
+	if batch.ExitCode(results) != errors.ExitCodeConflict {
+		t.Errorf("Expected exit code %d, got %d", errors.ExitCodeConflict, batch.ExitCode(results))
+	}
+}
+
+// TestUpgradeClusterPlan shows the upgrade plan without upgrading.
+func TestUpgradeClusterPlan(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	server := fakeapi.New(fakeapi.Config{})
+	clusterID := server.AddClusterV4(&models.V4ClusterDetailsResponse{Owner: fakeapi.DefaultOrganization, ReleaseVersion: "9.3.0"})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	args := Arguments{
+		APIEndpoint:       ts.URL,
+		AuthToken:         "some-token",
+		ClusterNameOrID:   clusterID,
+		Plan:              true,
+		Release:           "12.1.0",
+		UserProvidedToken: "some-token",
+	}
+
+	var result *upgradeClusterResult
+	var err error
+	testutils.CaptureOutput(func() {
+		result, err = upgradeCluster(args)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.plan == nil {
+		t.Fatal("Expected a plan")
+	}
+	if result.plan.From.K8sVersion != "1.15.5" || result.plan.To.K8sVersion != "1.17.9" {
+		t.Errorf("Unexpected Kubernetes versions %#v, %#v", result.plan.From, result.plan.To)
+	}
+	if len(result.plan.UpgradePath) != 2 || result.plan.UpgradePath[0] != "11.5.0" {
+		t.Errorf("Unexpected upgrade path %#v", result.plan.UpgradePath)
+	}
+
+	output := testutils.CaptureOutput(func() {
+		printPlan(args, result)
+	})
+	if !strings.Contains(output, "--release 11.5.0") {
+		t.Errorf("Expected the first hop to be suggested, got %q", output)
+	}
+
+	// The cluster has not been upgraded.
+	clientWrapper, err := client.NewWithConfig(ts.URL, "some-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	details, err := clientWrapper.GetClusterV4(clusterID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Payload.ReleaseVersion != "9.3.0" {
+		t.Errorf("Expected release version 9.3.0, got %s", details.Payload.ReleaseVersion)
+	}
+}
+
+// TestUpgradeClusterScheduled starts an upgrade within a maintenance window,
+// using a fake clock.
+func TestUpgradeClusterScheduled(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	configDir := testutils.TempDir(fs)
+	config.Initialize(fs, configDir)
+
+	server := fakeapi.New(fakeapi.Config{})
+	openID := server.AddClusterV5(&models.V5ClusterDetailsResponse{Owner: fakeapi.DefaultOrganization, ReleaseVersion: "11.5.0"})
+	closedID := server.AddClusterV5(&models.V5ClusterDetailsResponse{Owner: fakeapi.DefaultOrganization, ReleaseVersion: "11.5.0"})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	clientWrapper, err := client.NewWithConfig(ts.URL, "some-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(s *schedule.Waiter) { scheduler = s }(scheduler)
+
+	// Wednesday noon, the window opens on Saturday.
+	now := time.Date(2020, 9, 16, 12, 0, 0, 0, time.UTC)
+	var slept time.Duration
+	scheduler = schedule.New(schedule.Config{
+		Output: ioutil.Discard,
+		Now:    func() time.Time { return now },
+		Sleep: func(d time.Duration) {
+			slept += d
+			now = now.Add(d)
+		},
+	})
+
+	args := Arguments{
+		APIEndpoint:       ts.URL,
+		AuthToken:         "some-token",
+		ClusterNameOrID:   openID,
+		Force:             true,
+		UserProvidedToken: "some-token",
+		Window:            "Sat 02:00-04:00 UTC",
+	}
+
+	var result *upgradeClusterResult
+	testutils.CaptureOutput(func() {
+		result, err = upgradeCluster(args)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if slept != 62*time.Hour {
+		t.Errorf("Expected to wait 62h, waited %s", slept)
+	}
+	details, err := clientWrapper.GetClusterV5(openID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Payload.ReleaseVersion != result.versionAfter {
+		t.Errorf("Expected release version %s, got %s", result.versionAfter, details.Payload.ReleaseVersion)
+	}
+
+	// The machine sleeps through the window.
+	now = time.Date(2020, 9, 16, 12, 0, 0, 0, time.UTC)
+	scheduler = schedule.New(schedule.Config{
+		Output: ioutil.Discard,
+		Now:    func() time.Time { return now },
+		Sleep:  func(d time.Duration) { now = now.Add(72 * time.Hour) },
+	})
+	args.ClusterNameOrID = closedID
+
+	testutils.CaptureOutput(func() {
+		_, err = upgradeCluster(args)
+	})
+	if !errors.IsMaintenanceWindowClosedError(err) {
+		t.Errorf("Expected MaintenanceWindowClosedError, got %#v", err)
+	}
+	details, err = clientWrapper.GetClusterV5(closedID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Payload.ReleaseVersion != "11.5.0" {
+		t.Errorf("Expected release version 11.5.0, got %s", details.Payload.ReleaseVersion)
+	}
+}
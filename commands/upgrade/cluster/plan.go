@@ -0,0 +1,56 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/releaseinfo"
+)
+
+// planUpgrade compares the running release with the target release.
+func planUpgrade(clientWrapper *client.Wrapper, versionBefore, versionAfter string) (*releaseinfo.ReleaseDiff, error) {
+	releaseInfo, err := releaseinfo.New(releaseinfo.Config{ClientWrapper: clientWrapper})
+	if releaseinfo.IsNotAuthorized(err) {
+		return nil, microerror.Mask(errors.NotAuthorizedError)
+	} else if releaseinfo.IsInternalServerError(err) {
+		return nil, microerror.Maskf(errors.InternalServerError, err.Error())
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	d, err := releaseInfo.Diff(versionBefore, versionAfter)
+	if releaseinfo.IsVersionNotFound(err) {
+		return nil, microerror.Maskf(errors.InvalidReleaseError, "release %s of the cluster is unknown", versionBefore)
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return d, nil
+}
+
+// printPlan prints what an upgrade would change.
+func printPlan(args Arguments, result *upgradeClusterResult) {
+	fmt.Printf("Cluster '%s' would be upgraded from version %s to %s.\n",
+		color.CyanString(args.ClusterNameOrID),
+		color.CyanString(result.versionBefore),
+		color.CyanString(result.versionAfter))
+	fmt.Println("")
+
+	result.plan.Print(os.Stdout)
+
+	next := result.versionAfter
+	if len(result.plan.UpgradePath) > 0 {
+		next = result.plan.UpgradePath[0]
+	}
+
+	fmt.Println("")
+	fmt.Println("No changes have been made. To start the upgrade, use")
+	fmt.Println("")
+	fmt.Printf("    gsctl upgrade cluster %s --release %s\n", args.ClusterNameOrID, next)
+	fmt.Println("")
+}
@@ -0,0 +1,43 @@
+package cluster
+
+import (
+	"time"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/schedule"
+)
+
+// scheduler waits for scheduled upgrades to start. Tests replace it to
+// avoid waiting.
+var scheduler = schedule.New(schedule.Config{})
+
+// isScheduled returns true if the upgrade should start at a given time
+// or within a maintenance window.
+func isScheduled(args Arguments) bool {
+	return args.At != "" || args.Window != ""
+}
+
+// schedulePeriod returns the period in which the upgrade may start,
+// based on --at or --window.
+func schedulePeriod(args Arguments, now time.Time) (schedule.Period, error) {
+	if args.At != "" {
+		at, err := time.Parse(time.RFC3339, args.At)
+		if err != nil {
+			return schedule.Period{}, microerror.Maskf(errors.InvalidScheduleError, "--at %s is not in RFC3339 format", args.At)
+		}
+		if at.Before(now) {
+			return schedule.Period{}, microerror.Maskf(errors.InvalidScheduleError, "--at %s is in the past", args.At)
+		}
+
+		return schedule.Period{Start: at}, nil
+	}
+
+	window, err := schedule.ParseWindow(args.Window)
+	if err != nil {
+		return schedule.Period{}, microerror.Maskf(errors.InvalidScheduleError, "--window %s: %s", args.Window, err.Error())
+	}
+
+	return window.Next(now), nil
+}
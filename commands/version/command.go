@@ -2,7 +2,6 @@ package version
 
 import (
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
@@ -14,6 +13,7 @@ import (
 
 	"github.com/giantswarm/gsctl/buildinfo"
 	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/selfupdate"
 	"github.com/giantswarm/gsctl/util"
 )
 
@@ -86,28 +86,15 @@ func printVersion(cmd *cobra.Command, args []string) {
 
 // latestVersion returns the latest available version as string
 func latestVersion(url string) (string, error) {
-	// an HTTP client that doesn't follow redirects and timeouts quickly
-	// in order to not let the user wait too long
-	client := &http.Client{
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-		Timeout: time.Duration(updateCheckTimeout),
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return "", microerror.Mask(err)
-	}
-	defer resp.Body.Close()
-
-	location := resp.Header.Get("Location")
-	if location == "" {
+	// timeout quickly in order to not let the user wait too long
+	version, err := selfupdate.LatestVersion(url, updateCheckTimeout)
+	if selfupdate.IsLatestVersionUnknown(err) {
 		return "", microerror.Mask(errors.UpdateCheckFailed)
+	} else if err != nil {
+		return "", microerror.Mask(err)
 	}
 
-	parts := strings.Split(location, "/")
-	return parts[len(parts)-1], nil
+	return version, nil
 }
 
 // currentVersion returns the current gsctl version as string.
@@ -162,5 +149,6 @@ func versionCheckDue() bool {
 func formatUpdateInfo(info updateAvailabilityInfo) string {
 	output := color.YellowString(fmt.Sprintf("Good news: an update for %s is available.\n", config.ProgramName))
 	output += fmt.Sprintf("Please visit https://github.com/giantswarm/gsctl/releases/tag/%s for details.\n", info.latestVersion)
+	output += fmt.Sprintf("To update, execute '%s self-update'.\n", config.ProgramName)
 	return output
 }
@@ -1,5 +1,7 @@
 package flags
 
+import "time"
+
 var (
 	// APIEndpoint represents the API endpoint URL flag.
 	APIEndpoint string
@@ -35,6 +37,18 @@ var (
 	// Verbose represents the verbosity switch passed as a flag.
 	Verbose bool
 
+	// AppCatalog is the name of the catalog an app is installed from.
+	AppCatalog string
+
+	// AppNamespace is the namespace in the workload cluster an app is installed into.
+	AppNamespace string
+
+	// AppVersion is the version of an app to install.
+	AppVersion string
+
+	// AppValuesFile is the path to a YAML file containing user values for an app.
+	AppValuesFile string
+
 	// CertificateOrganizations represents the O value for key pairs passed as a flag.
 	CertificateOrganizations string
 
@@ -54,9 +68,15 @@ var (
 	// Description represents the description passed as a flag.
 	Description string
 
+	// DryRun makes a command only print what it would do, without making changes.
+	DryRun bool
+
 	// Use spot instances for a node pool
 	EnableSpotInstances bool
 
+	// Failed restricts output to operations which failed.
+	Failed bool
+
 	// Force represents the value of the force flag, passed as a flag.
 	// If true, all warnings should be suppressed.
 	Force bool
@@ -70,6 +90,13 @@ var (
 	// UseKubie is used to set the context with Kubie
 	UseKubie bool
 
+	// Limit is the maximum number of items to show.
+	Limit int
+
+	// MaintenanceWindow is a recurring time window in which an operation may
+	// start, e. g. 'Sat 02:00-04:00 UTC'.
+	MaintenanceWindow string
+
 	// Label contains label changes passed as multiple flags.
 	Label []string
 
@@ -79,18 +106,52 @@ var (
 	// NumWorkers is the number of workers required via flag on execution.
 	NumWorkers int
 
+	// Offline serves read-only API requests from the response cache.
+	Offline bool
+
 	// OrganizationID represents an organization ID, passed as a flag.
 	OrganizationID string
 
+	// OutputFile is the path of a file to write output to, instead of STDOUT.
+	OutputFile string
+
 	// OutputFormat is the output format (table or json) of a commands output, passed as a flag.
 	OutputFormat string
 
 	// Owner is the owner organization of the cluster as set via flag on execution.
 	Owner string
 
+	// Parallel is the maximum number of operations run at the same time
+	// when acting on several clusters.
+	Parallel int
+
+	// Plan makes a command only show what would change, without making changes.
+	Plan bool
+
+	// PriceSheet is the path of a YAML file with prices per instance type.
+	PriceSheet string
+
 	// Release sets a release to use, provided as a command line flag.
 	Release string
 
+	// ReleaseBaseURL is the URL gsctl releases are downloaded from.
+	ReleaseBaseURL string
+
+	// Retries is the number of times failed idempotent API requests are retried.
+	Retries int
+
+	// Rollback restores the previous state instead of applying a change.
+	Rollback bool
+
+	// Selector is a label selector query choosing the clusters to act on.
+	Selector string
+
+	// StartAt is the time (RFC3339) at which an operation should start.
+	StartAt string
+
+	// Since is a duration or point in time before which items are ignored.
+	Since string
+
 	// SilenceHTTPEndpointWarning represents
 	SilenceHTTPEndpointWarning bool
 
@@ -107,6 +168,15 @@ var (
 	// TTL represents a TTL (time to live) value passed as a flag.
 	TTL string
 
+	// Version is the gsctl version to install.
+	Version string
+
+	// Wait makes a command wait for an asynchronous operation to complete.
+	Wait bool
+
+	// WaitTimeout is the maximum time to wait when Wait is set.
+	WaitTimeout time.Duration
+
 	// WorkerAwsEc2InstanceType is the instance type name for nodes in AWS.
 	WorkerAwsEc2InstanceType string
 
@@ -0,0 +1,58 @@
+// Package appvalues reads user values for apps from YAML files.
+package appvalues
+
+import (
+	"fmt"
+
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ReadFile reads user values for an app from the YAML file at the given path.
+func ReadFile(fs afero.Fs, path string) (map[string]interface{}, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return Parse(data)
+}
+
+// Parse parses user values for an app from YAML data. The result can be
+// serialized as JSON, so nested maps are converted to have string keys.
+func Parse(yamlBytes []byte) (map[string]interface{}, error) {
+	raw := map[string]interface{}{}
+
+	err := yaml.Unmarshal(yamlBytes, &raw)
+	if err != nil {
+		return nil, microerror.Maskf(invalidValuesError, err.Error())
+	}
+
+	values := map[string]interface{}{}
+	for k, v := range raw {
+		values[k] = convert(v)
+	}
+
+	return values, nil
+}
+
+// convert recursively turns map[interface{}]interface{} values, as produced
+// by the YAML parser, into map[string]interface{}.
+func convert(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, val := range typed {
+			m[fmt.Sprintf("%v", k)] = convert(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range typed {
+			typed[i] = convert(val)
+		}
+		return typed
+	default:
+		return v
+	}
+}
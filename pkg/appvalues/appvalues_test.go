@@ -0,0 +1,80 @@
+package appvalues
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+// TestParse tests parsing user values into a JSON serializable structure.
+func TestParse(t *testing.T) {
+	var testCases = []struct {
+		yaml         string
+		expectedJSON string
+		errorMatcher func(error) bool
+	}{
+		{
+			yaml:         "",
+			expectedJSON: `{}`,
+		},
+		{
+			yaml: `replicas: 3
+ingress:
+  enabled: true
+  hosts:
+  - name: example.com
+    port: 443
+`,
+			expectedJSON: `{"ingress":{"enabled":true,"hosts":[{"name":"example.com","port":443}]},"replicas":3}`,
+		},
+		{
+			yaml:         "- just\n- a list\n",
+			errorMatcher: IsInvalidValues,
+		},
+	}
+
+	for i, tc := range testCases {
+		values, err := Parse([]byte(tc.yaml))
+		if tc.errorMatcher != nil {
+			if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Unexpected error: %#v", i, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Case %d - Unexpected error: %#v", i, err)
+			continue
+		}
+
+		got, err := json.Marshal(values)
+		if err != nil {
+			t.Errorf("Case %d - Could not marshal values: %#v", i, err)
+			continue
+		}
+
+		if diff := cmp.Diff(tc.expectedJSON, string(got)); diff != "" {
+			t.Errorf("Case %d - Result did not match (-expected +got):\n%s", i, diff)
+		}
+	}
+}
+
+// TestReadFile tests reading values from a file.
+func TestReadFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/values.yaml", []byte("foo: bar\n"), 0644)
+
+	values, err := ReadFile(fs, "/values.yaml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if values["foo"] != "bar" {
+		t.Errorf("Expected foo=bar, got %#v", values)
+	}
+
+	_, err = ReadFile(fs, "/missing.yaml")
+	if err == nil {
+		t.Error("Expected error for missing file, got nil")
+	}
+}
@@ -0,0 +1,229 @@
+// Package auditlog reads and writes the local audit log, a JSON lines file
+// recording API requests which change something, like creating, scaling or
+// deleting clusters.
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+)
+
+const (
+	// FileName is the name of the audit log file in the config directory.
+	FileName = "audit.jsonl"
+
+	// EnvVar is the environment variable enabling the audit log.
+	EnvVar = "GSCTL_AUDIT_LOG"
+)
+
+// Outcomes of a request.
+const (
+	// OutcomeSuccess means the API accepted the request.
+	OutcomeSuccess = "success"
+	// OutcomeFailure means the API responded with an error status.
+	OutcomeFailure = "failure"
+	// OutcomeError means there was no response, e. g. due to a network problem.
+	OutcomeError = "error"
+)
+
+// Entry is a single request in the audit log.
+type Entry struct {
+	Time        time.Time `json:"time"`
+	Endpoint    string    `json:"endpoint"`
+	Email       string    `json:"email,omitempty"`
+	CommandLine string    `json:"command_line"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	RequestID   string    `json:"request_id,omitempty"`
+	Targets
+
+	StatusCode int    `json:"status_code,omitempty"`
+	Outcome    string `json:"outcome"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Targets are the IDs of the resources a request acts on.
+type Targets struct {
+	ClusterID      string `json:"cluster_id,omitempty"`
+	NodePoolID     string `json:"node_pool_id,omitempty"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	AppName        string `json:"app_name,omitempty"`
+}
+
+// ParseTargets extracts resource IDs from an API path like
+// /v5/clusters/abc12/nodepools/a7k/.
+func ParseTargets(requestPath string) Targets {
+	t := Targets{}
+
+	parts := strings.Split(strings.Trim(requestPath, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		value := parts[i+1]
+		switch parts[i] {
+		case "clusters":
+			t.ClusterID = value
+		case "nodepools":
+			t.NodePoolID = value
+		case "organizations":
+			t.OrganizationID = value
+		case "apps":
+			t.AppName = value
+		default:
+			continue
+		}
+		i++
+	}
+
+	return t
+}
+
+// Merge fills IDs missing in t with the ones from other. This is used to
+// add the ID of a created resource, taken from the Location header.
+func (t Targets) Merge(other Targets) Targets {
+	if t.ClusterID == "" {
+		t.ClusterID = other.ClusterID
+	}
+	if t.NodePoolID == "" {
+		t.NodePoolID = other.NodePoolID
+	}
+	if t.OrganizationID == "" {
+		t.OrganizationID = other.OrganizationID
+	}
+	if t.AppName == "" {
+		t.AppName = other.AppName
+	}
+	return t
+}
+
+// String returns the targets in a human readable form, e. g. 'abc12/a7k'.
+func (t Targets) String() string {
+	var parts []string
+	if t.OrganizationID != "" {
+		parts = append(parts, "org "+t.OrganizationID)
+	}
+	if t.ClusterID != "" {
+		id := t.ClusterID
+		if t.NodePoolID != "" {
+			id += "/" + t.NodePoolID
+		}
+		if t.AppName != "" {
+			id += " app " + t.AppName
+		}
+		parts = append(parts, id)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Append adds an entry to the log file, creating it if necessary. The file
+// is only readable by the user, as it reveals what they did.
+func Append(fs afero.Fs, filePath string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = fs.MkdirAll(path.Dir(filePath), 0700)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	f, err := fs.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	defer f.Close()
+
+	// A single write per line keeps lines intact when several gsctl
+	// processes append at the same time.
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// Read returns all entries of the log file, oldest first. Lines which
+// cannot be parsed are skipped.
+func Read(fs afero.Fs, filePath string) ([]Entry, error) {
+	f, err := fs.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, microerror.Maskf(logNotFoundError, "%s does not exist", filePath)
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		entry := Entry{}
+		err = json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return entries, nil
+}
+
+// Filter selects entries. Zero values match everything.
+type Filter struct {
+	// Since excludes entries before this time.
+	Since time.Time
+
+	// Endpoint is the API endpoint URL.
+	Endpoint string
+
+	// ClusterID is the ID of the cluster acted on.
+	ClusterID string
+
+	// FailedOnly excludes successful requests.
+	FailedOnly bool
+}
+
+// Matches returns true if the entry is selected by the filter.
+func (f Filter) Matches(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.Endpoint != "" && strings.TrimRight(e.Endpoint, "/") != strings.TrimRight(f.Endpoint, "/") {
+		return false
+	}
+	if f.ClusterID != "" && e.ClusterID != f.ClusterID {
+		return false
+	}
+	if f.FailedOnly && e.Outcome == OutcomeSuccess {
+		return false
+	}
+	return true
+}
+
+// Apply returns the entries matching the filter.
+func (f Filter) Apply(entries []Entry) []Entry {
+	var selected []Entry
+	for _, e := range entries {
+		if f.Matches(e) {
+			selected = append(selected, e)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,94 @@
+package auditlog
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+// TestParseTargets tests extracting resource IDs from API paths.
+func TestParseTargets(t *testing.T) {
+	var testCases = []struct {
+		path     string
+		expected Targets
+	}{
+		{"/v5/clusters/", Targets{}},
+		{"/v5/clusters/abc12/", Targets{ClusterID: "abc12"}},
+		{"/v5/clusters/abc12/nodepools/a7k/", Targets{ClusterID: "abc12", NodePoolID: "a7k"}},
+		{"/v4/clusters/abc12/key-pairs/", Targets{ClusterID: "abc12"}},
+		{"/v4/clusters/abc12/apps/nginx/config/", Targets{ClusterID: "abc12", AppName: "nginx"}},
+		{"/v4/organizations/acme/credentials/", Targets{OrganizationID: "acme"}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			targets := ParseTargets(tc.path)
+			if diff := cmp.Diff(tc.expected, targets); diff != "" {
+				t.Errorf("Case %d - Targets not as expected: (-want +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+// TestAppendRead tests writing and reading the log, including filters.
+func TestAppendRead(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	filePath := "/config/audit.jsonl"
+
+	_, err := Read(fs, filePath)
+	if !IsLogNotFound(err) {
+		t.Errorf("Expected logNotFoundError, got %#v", err)
+	}
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: now.Add(-48 * time.Hour), Endpoint: "https://api.a", Method: "DELETE", Path: "/v4/clusters/abc12/", Targets: Targets{ClusterID: "abc12"}, Outcome: OutcomeSuccess},
+		{Time: now.Add(-time.Hour), Endpoint: "https://api.a/", Method: "PATCH", Path: "/v5/clusters/def34/", Targets: Targets{ClusterID: "def34"}, StatusCode: 400, Outcome: OutcomeFailure},
+		{Time: now, Endpoint: "https://api.b", Method: "POST", Path: "/v5/clusters/", Targets: Targets{ClusterID: "def34"}, Outcome: OutcomeError, Error: "timeout"},
+	}
+	for _, e := range entries {
+		err = Append(fs, filePath, e)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+	}
+
+	// Broken lines don't prevent reading the others.
+	f, _ := fs.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0600)
+	_, _ = f.Write([]byte("{broken\n"))
+	f.Close()
+
+	read, err := Read(fs, filePath)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if diff := cmp.Diff(entries, read); diff != "" {
+		t.Errorf("Entries not as expected: (-want +got):\n%s", diff)
+	}
+
+	info, _ := fs.Stat(filePath)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %s", info.Mode().Perm())
+	}
+
+	var filterCases = []struct {
+		filter   Filter
+		expected int
+	}{
+		{Filter{}, 3},
+		{Filter{Since: now.Add(-24 * time.Hour)}, 2},
+		{Filter{Endpoint: "https://api.a"}, 2},
+		{Filter{ClusterID: "def34"}, 2},
+		{Filter{FailedOnly: true}, 2},
+		{Filter{Endpoint: "https://api.a", FailedOnly: true}, 1},
+	}
+	for i, tc := range filterCases {
+		if n := len(tc.filter.Apply(read)); n != tc.expected {
+			t.Errorf("Filter case %d - Expected %d entries, got %d", i, tc.expected, n)
+		}
+	}
+}
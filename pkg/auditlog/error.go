@@ -0,0 +1,12 @@
+package auditlog
+
+import "github.com/giantswarm/microerror"
+
+var logNotFoundError = &microerror.Error{
+	Kind: "logNotFoundError",
+}
+
+// IsLogNotFound asserts logNotFoundError.
+func IsLogNotFound(err error) bool {
+	return microerror.Cause(err) == logNotFoundError
+}
@@ -0,0 +1,49 @@
+// Package authtoken inspects the auth tokens stored in the gsctl config.
+//
+// Tokens obtained via SSO are JWTs using the "Bearer" scheme. Their expiry
+// can be read from the token itself. Tokens obtained via email and password
+// use the "giantswarm" scheme and are opaque.
+package authtoken
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// SchemeBearer is the auth scheme of tokens obtained via SSO.
+	SchemeBearer = "Bearer"
+
+	// SchemeGiantSwarm is the auth scheme of tokens obtained via email
+	// and password.
+	SchemeGiantSwarm = "giantswarm"
+)
+
+// claims are the JWT claims we are interested in.
+type claims struct {
+	Expiry int64 `json:"exp"`
+}
+
+// Expiry returns the expiry time of a JWT. The signature is not verified.
+// ok is false if the token is not a JWT or has no expiry.
+func Expiry(token string) (expiry time.Time, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	c := claims{}
+	err = json.Unmarshal(data, &c)
+	if err != nil || c.Expiry == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(c.Expiry, 0), true
+}
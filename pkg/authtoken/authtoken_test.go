@@ -0,0 +1,37 @@
+package authtoken
+
+import (
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestExpiry tests reading the expiry from tokens.
+func TestExpiry(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	header := encode(`{"alg":"RS256","typ":"JWT"}`)
+
+	var testCases = []struct {
+		token          string
+		expectedOK     bool
+		expectedExpiry time.Time
+	}{
+		{header + "." + encode(`{"email":"user@example.com","exp":1593561600}`) + ".sig", true, time.Unix(1593561600, 0)},
+		{header + "." + encode(`{"email":"user@example.com"}`) + ".sig", false, time.Time{}},
+		{header + ".not-base64!.sig", false, time.Time{}},
+		{"a1b2c3d4-e5f6", false, time.Time{}},
+		{"", false, time.Time{}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			expiry, ok := Expiry(tc.token)
+			if ok != tc.expectedOK || !expiry.Equal(tc.expectedExpiry) {
+				t.Errorf("Case %d - Expected %v, %s, got %v, %s", i, tc.expectedOK, tc.expectedExpiry, ok, expiry)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Package batch runs an operation on all clusters matching a label selector,
+// with bounded concurrency, and reports the outcome per cluster.
+package batch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/clustercache"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/pkg/wait"
+)
+
+// DefaultParallel is the default number of operations running at the same time.
+const DefaultParallel = 4
+
+// Cluster is a cluster an operation is applied to.
+type Cluster struct {
+	ID             string
+	Name           string
+	Owner          string
+	ReleaseVersion string
+}
+
+// Column is an additional column shown in the summary table.
+type Column struct {
+	Title string
+	Value func(Cluster) string
+}
+
+// Operation is applied to a single cluster. It returns a short message
+// describing the outcome on success.
+type Operation func(Cluster) (string, error)
+
+// Result is the outcome of an operation for a single cluster.
+type Result struct {
+	Cluster Cluster
+	Message string
+	Err     error
+}
+
+// Resolve returns the clusters matching the label selector, sorted by
+// name. Clusters being deleted are skipped. The IDs are added to the cluster
+// cache, so that operations can look them up without further requests.
+func Resolve(clientWrapper *client.Wrapper, endpoint, selector, activityName string) ([]Cluster, error) {
+	auxParams := clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	response, err := clientWrapper.GetClustersByLabel(&models.V5ListClustersByLabelRequest{Labels: &selector}, auxParams)
+	if err != nil {
+		if clienterror.IsUnauthorizedError(err) {
+			return nil, microerror.Mask(errors.NotAuthorizedError)
+		}
+		if clienterror.IsAccessForbiddenError(err) {
+			return nil, microerror.Mask(errors.AccessForbiddenError)
+		}
+
+		return nil, microerror.Mask(err)
+	}
+
+	var clusters []Cluster
+	var ids []string
+	for _, item := range response.Payload {
+		if item.DeleteDate != nil {
+			continue
+		}
+
+		clusters = append(clusters, Cluster{
+			ID:             item.ID,
+			Name:           item.Name,
+			Owner:          item.Owner,
+			ReleaseVersion: item.ReleaseVersion,
+		})
+		ids = append(ids, item.ID)
+	}
+
+	if len(clusters) == 0 {
+		return nil, microerror.Maskf(errors.NoClustersMatchSelectorError, "selector '%s'", selector)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	clustercache.CacheIDs(endpoint, ids)
+
+	return clusters, nil
+}
+
+// SummaryTable returns a table of the clusters an operation is going to be
+// applied to, with the given additional columns.
+func SummaryTable(clusters []Cluster, columns ...Column) string {
+	headers := []string{
+		color.CyanString("ID"),
+		color.CyanString("NAME"),
+		color.CyanString("ORGANIZATION"),
+		color.CyanString("RELEASE"),
+	}
+	for _, column := range columns {
+		headers = append(headers, color.CyanString(column.Title))
+	}
+
+	rows := []string{strings.Join(headers, "|")}
+	for _, c := range clusters {
+		row := []string{c.ID, c.Name, c.Owner, c.ReleaseVersion}
+		for _, column := range columns {
+			row = append(row, column.Value(c))
+		}
+		rows = append(rows, strings.Join(row, "|"))
+	}
+
+	return columnize.SimpleFormat(rows)
+}
+
+// Run applies the operation to all clusters, running at most parallel
+// operations at the same time. Values below 1 are treated as 1. Results are
+// returned in the order of clusters.
+func Run(clusters []Cluster, parallel int, op Operation) []Result {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]Result, len(clusters))
+	slots := make(chan struct{}, parallel)
+
+	var wg sync.WaitGroup
+	for i := range clusters {
+		results[i].Cluster = clusters[i]
+
+		wg.Add(1)
+		go func(r *Result) {
+			defer wg.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			r.Message, r.Err = op(r.Cluster)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Report returns a table with the outcome per cluster, followed by a line
+// stating how many operations succeeded.
+func Report(results []Result) string {
+	headers := []string{
+		color.CyanString("ID"),
+		color.CyanString("NAME"),
+		color.CyanString("RESULT"),
+		color.CyanString("DETAILS"),
+	}
+	rows := []string{strings.Join(headers, "|")}
+
+	succeeded := 0
+	for _, r := range results {
+		status := color.GreenString("OK")
+		details := r.Message
+		if r.Err != nil {
+			status = color.RedString("FAILED")
+			details = reason(r.Err)
+		} else {
+			succeeded++
+		}
+
+		rows = append(rows, strings.Join([]string{r.Cluster.ID, r.Cluster.Name, status, details}, "|"))
+	}
+
+	summary := fmt.Sprintf("%d of %d operations succeeded.", succeeded, len(results))
+	if succeeded == len(results) {
+		summary = color.GreenString(summary)
+	} else {
+		summary = color.RedString(summary)
+	}
+
+	return columnize.SimpleFormat(rows) + "\n\n" + summary
+}
+
+// ExitCode returns the exit code for the results. It is errors.ExitCodeOK if
+// all operations succeeded. If all failed operations have the same exit
+// code, that one is returned, otherwise errors.ExitCodeGeneral.
+func ExitCode(results []Result) int {
+	code := errors.ExitCodeOK
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+
+		c := errors.ExitCode(r.Err)
+		if code != errors.ExitCodeOK && code != c {
+			return errors.ExitCodeGeneral
+		}
+		code = c
+	}
+
+	return code
+}
+
+// reason returns a single line describing why an operation failed.
+func reason(err error) string {
+	if apiErr, ok := microerror.Cause(err).(*clienterror.APIError); ok {
+		return apiErr.ErrorMessage
+	}
+	if apiErr, ok := err.(*clienterror.APIError); ok {
+		return apiErr.ErrorMessage
+	}
+
+	switch {
+	case errors.IsNotAuthorizedError(err):
+		return "not authorized, please log in again"
+	case errors.IsAccessForbiddenError(err):
+		return "access forbidden"
+	case errors.IsClusterNotFoundError(err):
+		return "cluster not found"
+	case errors.IsNoUpgradeAvailableError(err):
+		return "no newer release available"
+	case wait.IsTimeout(err):
+		return "did not complete in time"
+	}
+
+	return strings.Replace(err.Error(), "\n", " ", -1)
+}
@@ -0,0 +1,141 @@
+package batch
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/commands/errors"
+	"github.com/giantswarm/gsctl/testutils"
+	"github.com/giantswarm/gsctl/testutils/fakeapi"
+)
+
+// TestResolve tests resolving clusters by label selector.
+func TestResolve(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_, err := testutils.TempConfig(fs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := fakeapi.New(fakeapi.Config{})
+	server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: "b2222", Name: "beta", Owner: fakeapi.DefaultOrganization, Labels: map[string]string{"environment": "testing"}})
+	server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: "a1111", Name: "alpha", Owner: fakeapi.DefaultOrganization, Labels: map[string]string{"environment": "testing"}})
+	server.AddClusterV5(&models.V5ClusterDetailsResponse{ID: "c3333", Name: "gamma", Owner: fakeapi.DefaultOrganization, Labels: map[string]string{"environment": "production"}})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	clientWrapper, err := client.New(&client.Configuration{
+		Endpoint: ts.URL,
+		AuthHeaderGetter: func() (string, error) {
+			return "giantswarm test-token", nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusters, err := Resolve(clientWrapper, ts.URL, "environment=testing", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 2 || clusters[0].ID != "a1111" || clusters[1].ID != "b2222" {
+		t.Errorf("Unexpected clusters %#v", clusters)
+	}
+
+	_, err = Resolve(clientWrapper, ts.URL, "environment=staging", "test")
+	if !errors.IsNoClustersMatchSelectorError(err) {
+		t.Errorf("Expected NoClustersMatchSelectorError, got %#v", err)
+	}
+}
+
+// TestRun checks that results keep their order and concurrency is bounded.
+func TestRun(t *testing.T) {
+	var clusters []Cluster
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		clusters = append(clusters, Cluster{ID: id})
+	}
+
+	var mutex sync.Mutex
+	running := 0
+	maxRunning := 0
+
+	results := Run(clusters, 3, func(c Cluster) (string, error) {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+
+		if c.ID == "c" {
+			return "", microerror.Mask(errors.ClusterNotFoundError)
+		}
+		return "done " + c.ID, nil
+	})
+
+	if maxRunning > 3 {
+		t.Errorf("Expected at most 3 operations at a time, got %d", maxRunning)
+	}
+	for i, r := range results {
+		if r.Cluster.ID != clusters[i].ID {
+			t.Errorf("Result %d - expected cluster %s, got %s", i, clusters[i].ID, r.Cluster.ID)
+		}
+		if r.Cluster.ID == "c" {
+			if r.Err == nil {
+				t.Errorf("Result %d - expected error", i)
+			}
+		} else if r.Message != "done "+r.Cluster.ID {
+			t.Errorf("Result %d - unexpected message %q", i, r.Message)
+		}
+	}
+
+	report := Report(results)
+	if !strings.Contains(report, "6 of 7 operations succeeded.") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+	if !strings.Contains(report, "cluster not found") {
+		t.Errorf("Expected failure reason in report:\n%s", report)
+	}
+}
+
+// TestExitCode tests the aggregate exit code.
+func TestExitCode(t *testing.T) {
+	notFound := microerror.Mask(errors.ClusterNotFoundError)
+	conflict := microerror.Mask(errors.NoUpgradeAvailableError)
+
+	var testCases = []struct {
+		errs     []error
+		expected int
+	}{
+		{[]error{nil, nil}, errors.ExitCodeOK},
+		{[]error{nil, notFound}, errors.ExitCodeNotFound},
+		{[]error{notFound, notFound}, errors.ExitCodeNotFound},
+		{[]error{notFound, nil, conflict}, errors.ExitCodeGeneral},
+	}
+
+	for i, tc := range testCases {
+		var results []Result
+		for _, err := range tc.errs {
+			results = append(results, Result{Err: err})
+		}
+
+		code := ExitCode(results)
+		if code != tc.expected {
+			t.Errorf("Case %d - expected exit code %d, got %d", i, tc.expected, code)
+		}
+	}
+}
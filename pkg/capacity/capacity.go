@@ -0,0 +1,267 @@
+// Package capacity computes the worker node capacity of clusters and node
+// pools and, based on a price sheet, their cost.
+package capacity
+
+import (
+	"math"
+	"sort"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/nodespec"
+)
+
+// HoursPerMonth is the number of hours used to calculate monthly cost.
+const HoursPerMonth = 730
+
+// NodePool is the capacity of a node pool. Clusters without node pools are
+// represented as one node pool without ID.
+type NodePool struct {
+	Organization  string `json:"organization"`
+	ClusterID     string `json:"cluster_id"`
+	ClusterName   string `json:"cluster_name"`
+	NodePoolID    string `json:"node_pool_id,omitempty"`
+	NodePoolName  string `json:"node_pool_name,omitempty"`
+	InstanceType  string `json:"instance_type,omitempty"`
+	Nodes         int64  `json:"nodes"`
+	OnDemandNodes int64  `json:"on_demand_nodes"`
+	SpotNodes     int64  `json:"spot_nodes"`
+	// CPUs and MemoryGB are zero if the instance type is unknown.
+	CPUs     int64   `json:"cpus"`
+	MemoryGB float64 `json:"memory_gb"`
+	// HourlyCost is nil if the price sheet has no price for the instance type.
+	HourlyCost *float64 `json:"hourly_cost,omitempty"`
+}
+
+// Summary is the capacity of several node pools.
+type Summary struct {
+	Organization  string   `json:"organization,omitempty"`
+	Clusters      int      `json:"clusters"`
+	NodePools     int      `json:"node_pools"`
+	Nodes         int64    `json:"nodes"`
+	OnDemandNodes int64    `json:"on_demand_nodes"`
+	SpotNodes     int64    `json:"spot_nodes"`
+	CPUs          int64    `json:"cpus"`
+	MemoryGB      float64  `json:"memory_gb"`
+	HourlyCost    *float64 `json:"hourly_cost,omitempty"`
+	MonthlyCost   *float64 `json:"monthly_cost,omitempty"`
+}
+
+// Report is the capacity of all node pools, summarized per organization.
+type Report struct {
+	Currency      string      `json:"currency,omitempty"`
+	NodePools     []*NodePool `json:"node_pools"`
+	Organizations []*Summary  `json:"organizations"`
+	Total         *Summary    `json:"total"`
+	// UnpricedInstanceTypes are instance types in use which the price sheet
+	// has no price for. Their cost is not included.
+	UnpricedInstanceTypes []string `json:"unpriced_instance_types,omitempty"`
+}
+
+// Calculator computes the capacity of node pools.
+type Calculator struct {
+	aws    *nodespec.ProviderAWS
+	azure  *nodespec.ProviderAzure
+	prices *PriceSheet
+}
+
+// Config is the configuration for a Calculator.
+type Config struct {
+	// Prices is optional. Without it, no cost is calculated.
+	Prices *PriceSheet
+}
+
+// New creates a new Calculator.
+func New(config Config) (*Calculator, error) {
+	aws, err := nodespec.NewAWS()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	azure, err := nodespec.NewAzureProvider()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	c := &Calculator{
+		aws:    aws,
+		azure:  azure,
+		prices: config.Prices,
+	}
+
+	return c, nil
+}
+
+// NodePool computes the capacity of a node pool of a cluster.
+func (c *Calculator) NodePool(cluster *models.V4ClusterListItem, np *models.V5GetNodePoolsResponseItems) *NodePool {
+	result := &NodePool{
+		Organization: cluster.Owner,
+		ClusterID:    cluster.ID,
+		ClusterName:  cluster.Name,
+		NodePoolID:   np.ID,
+		NodePoolName: np.Name,
+	}
+	if np.Status != nil {
+		result.Nodes = np.Status.Nodes
+	}
+	result.OnDemandNodes = result.Nodes
+
+	if np.NodeSpec != nil && np.NodeSpec.Aws != nil {
+		result.InstanceType = np.NodeSpec.Aws.InstanceType
+		if d := np.NodeSpec.Aws.InstanceDistribution; d != nil {
+			result.OnDemandNodes, result.SpotNodes = SplitNodes(result.Nodes, d.OnDemandBaseCapacity, d.OnDemandPercentageAboveBaseCapacity)
+		}
+	} else if np.NodeSpec != nil && np.NodeSpec.Azure != nil {
+		result.InstanceType = np.NodeSpec.Azure.VMSize
+		if np.NodeSpec.Azure.SpotInstances != nil && np.NodeSpec.Azure.SpotInstances.Enabled {
+			result.OnDemandNodes, result.SpotNodes = 0, result.Nodes
+		}
+	}
+
+	c.addResources(result)
+
+	return result
+}
+
+// Cluster computes the capacity of the workers of a cluster without node
+// pools. All of them are on-demand instances.
+func (c *Calculator) Cluster(cluster *models.V4ClusterListItem, details *models.V4ClusterDetailsResponse) *NodePool {
+	result := &NodePool{
+		Organization:  cluster.Owner,
+		ClusterID:     cluster.ID,
+		ClusterName:   cluster.Name,
+		Nodes:         int64(len(details.Workers)),
+		OnDemandNodes: int64(len(details.Workers)),
+	}
+	if len(details.Workers) == 0 {
+		return result
+	}
+
+	worker := details.Workers[0]
+	switch {
+	case worker.Aws != nil && worker.Aws.InstanceType != "":
+		result.InstanceType = worker.Aws.InstanceType
+		c.addResources(result)
+	case worker.Azure != nil && worker.Azure.VMSize != "":
+		result.InstanceType = worker.Azure.VMSize
+		c.addResources(result)
+	case worker.CPU != nil && worker.Memory != nil:
+		// KVM workers have no instance type.
+		result.CPUs = result.Nodes * worker.CPU.Cores
+		result.MemoryGB = float64(result.Nodes) * worker.Memory.SizeGb
+	}
+
+	return result
+}
+
+// addResources sets CPUs, memory and cost based on the instance type.
+func (c *Calculator) addResources(np *NodePool) {
+	if it, err := c.aws.GetInstanceTypeDetails(np.InstanceType); err == nil {
+		np.CPUs = np.Nodes * int64(it.CPUCores)
+		np.MemoryGB = float64(np.Nodes) * float64(it.MemorySizeGB)
+	} else if vmSize, err := c.azure.GetVMSizeDetails(np.InstanceType); err == nil {
+		np.CPUs = np.Nodes * vmSize.NumberOfCores
+		np.MemoryGB = float64(np.Nodes) * vmSize.MemoryInMB / 1000
+	}
+
+	if c.prices == nil {
+		return
+	}
+	if price, ok := c.prices.Price(np.InstanceType); ok {
+		cost := float64(np.OnDemandNodes)*price.OnDemand + float64(np.SpotNodes)*price.spot()
+		np.HourlyCost = &cost
+	}
+}
+
+// SplitNodes returns how many of the given nodes are on-demand and spot
+// instances, according to an AWS instance distribution. The on-demand
+// share above the base capacity is rounded up, as AWS does.
+func SplitNodes(nodes, onDemandBase, onDemandPercentage int64) (onDemand, spot int64) {
+	if nodes <= onDemandBase {
+		return nodes, 0
+	}
+
+	above := nodes - onDemandBase
+	onDemand = onDemandBase + int64(math.Ceil(float64(above*onDemandPercentage)/100))
+
+	return onDemand, nodes - onDemand
+}
+
+// Summarize creates a report with totals per organization and overall.
+func (c *Calculator) Summarize(nodePools []*NodePool) *Report {
+	report := &Report{
+		NodePools: nodePools,
+		Total:     &Summary{},
+	}
+	if c.prices != nil {
+		report.Currency = c.prices.Currency
+	}
+
+	sort.SliceStable(report.NodePools, func(i, j int) bool {
+		a, b := report.NodePools[i], report.NodePools[j]
+		if a.Organization != b.Organization {
+			return a.Organization < b.Organization
+		}
+		if a.ClusterID != b.ClusterID {
+			return a.ClusterID < b.ClusterID
+		}
+		return a.NodePoolID < b.NodePoolID
+	})
+
+	organizations := map[string]*Summary{}
+	clusters := map[string]bool{}
+	unpriced := map[string]bool{}
+
+	for _, np := range report.NodePools {
+		org, ok := organizations[np.Organization]
+		if !ok {
+			org = &Summary{Organization: np.Organization}
+			organizations[np.Organization] = org
+			report.Organizations = append(report.Organizations, org)
+		}
+
+		newCluster := !clusters[np.ClusterID]
+		clusters[np.ClusterID] = true
+
+		for _, s := range []*Summary{org, report.Total} {
+			if newCluster {
+				s.Clusters++
+			}
+			if np.NodePoolID != "" {
+				s.NodePools++
+			}
+			s.add(np)
+		}
+
+		if c.prices != nil && np.HourlyCost == nil && np.Nodes > 0 {
+			name := np.InstanceType
+			if name == "" {
+				name = "n/a"
+			}
+			unpriced[name] = true
+		}
+	}
+
+	for name := range unpriced {
+		report.UnpricedInstanceTypes = append(report.UnpricedInstanceTypes, name)
+	}
+	sort.Strings(report.UnpricedInstanceTypes)
+
+	return report
+}
+
+func (s *Summary) add(np *NodePool) {
+	s.Nodes += np.Nodes
+	s.OnDemandNodes += np.OnDemandNodes
+	s.SpotNodes += np.SpotNodes
+	s.CPUs += np.CPUs
+	s.MemoryGB += np.MemoryGB
+
+	if np.HourlyCost != nil {
+		if s.HourlyCost == nil {
+			s.HourlyCost, s.MonthlyCost = new(float64), new(float64)
+		}
+		*s.HourlyCost += *np.HourlyCost
+		*s.MonthlyCost = *s.HourlyCost * HoursPerMonth
+	}
+}
@@ -0,0 +1,153 @@
+package capacity
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSplitNodes tests the on-demand/spot split of AWS node pools.
+func TestSplitNodes(t *testing.T) {
+	var testCases = []struct {
+		nodes, base, percentage int64
+		onDemand, spot          int64
+	}{
+		{0, 0, 100, 0, 0},
+		{5, 0, 100, 5, 0},
+		{5, 0, 0, 0, 5},
+		{5, 2, 0, 2, 3},
+		{5, 10, 0, 5, 0},
+		{10, 2, 50, 6, 4},
+		// The on-demand share is rounded up.
+		{3, 0, 50, 2, 1},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			onDemand, spot := SplitNodes(tc.nodes, tc.base, tc.percentage)
+			if onDemand != tc.onDemand || spot != tc.spot {
+				t.Errorf("Case %d - Expected %d/%d, got %d/%d", i, tc.onDemand, tc.spot, onDemand, spot)
+			}
+		})
+	}
+}
+
+// TestReport tests capacity and cost of node pools and clusters without
+// node pools.
+func TestReport(t *testing.T) {
+	prices, err := ParsePriceSheet([]byte(`currency: USD
+instance_types:
+  m5.xlarge:
+    on_demand: 0.2
+    spot: 0.1
+  Standard_D4s_v3:
+    on_demand: 0.3
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(Config{Prices: prices})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	awsCluster := &models.V4ClusterListItem{ID: "a1", Name: "AWS", Owner: "acme"}
+	azureCluster := &models.V4ClusterListItem{ID: "b1", Name: "Azure", Owner: "acme"}
+	kvmCluster := &models.V4ClusterListItem{ID: "c1", Name: "KVM", Owner: "other"}
+
+	nodePools := []*NodePool{
+		c.NodePool(awsCluster, &models.V5GetNodePoolsResponseItems{
+			ID: "np1",
+			NodeSpec: &models.V5GetNodePoolsResponseItemsNodeSpec{
+				Aws: &models.V5GetNodePoolsResponseItemsNodeSpecAws{
+					InstanceType: "m5.xlarge",
+					InstanceDistribution: &models.V5GetNodePoolsResponseItemsNodeSpecAwsInstanceDistribution{
+						OnDemandBaseCapacity:                1,
+						OnDemandPercentageAboveBaseCapacity: 0,
+					},
+				},
+			},
+			Status: &models.V5GetNodePoolsResponseItemsStatus{Nodes: 3},
+		}),
+		c.NodePool(azureCluster, &models.V5GetNodePoolsResponseItems{
+			ID: "np2",
+			NodeSpec: &models.V5GetNodePoolsResponseItemsNodeSpec{
+				Azure: &models.V5GetNodePoolsResponseItemsNodeSpecAzure{
+					VMSize:        "Standard_D4s_v3",
+					SpotInstances: &models.V5GetNodePoolsResponseItemsNodeSpecAzureSpotInstances{Enabled: true},
+				},
+			},
+			Status: &models.V5GetNodePoolsResponseItemsStatus{Nodes: 2},
+		}),
+		c.Cluster(kvmCluster, &models.V4ClusterDetailsResponse{
+			Workers: []*models.V4ClusterDetailsResponseWorkersItems{
+				{CPU: &models.V4ClusterDetailsResponseWorkersItemsCPU{Cores: 4}, Memory: &models.V4ClusterDetailsResponseWorkersItemsMemory{SizeGb: 8}},
+				{CPU: &models.V4ClusterDetailsResponseWorkersItemsCPU{Cores: 4}, Memory: &models.V4ClusterDetailsResponseWorkersItemsMemory{SizeGb: 8}},
+			},
+		}),
+	}
+
+	report := c.Summarize(nodePools)
+
+	aws := report.NodePools[0]
+	if aws.OnDemandNodes != 1 || aws.SpotNodes != 2 || aws.CPUs != 12 || aws.MemoryGB != 48 {
+		t.Errorf("Unexpected AWS node pool %#v", aws)
+	}
+	if aws.HourlyCost == nil || *aws.HourlyCost != 0.4 {
+		t.Errorf("Expected AWS hourly cost 0.4, got %v", aws.HourlyCost)
+	}
+
+	azure := report.NodePools[1]
+	if azure.OnDemandNodes != 0 || azure.SpotNodes != 2 || azure.CPUs != 8 {
+		t.Errorf("Unexpected Azure node pool %#v", azure)
+	}
+	// Without a spot price, spot instances are priced as on-demand.
+	if azure.HourlyCost == nil || *azure.HourlyCost != 0.6 {
+		t.Errorf("Expected Azure hourly cost 0.6, got %v", azure.HourlyCost)
+	}
+
+	kvm := report.NodePools[2]
+	if kvm.NodePoolID != "" || kvm.Nodes != 2 || kvm.CPUs != 8 || kvm.MemoryGB != 16 || kvm.HourlyCost != nil {
+		t.Errorf("Unexpected KVM cluster %#v", kvm)
+	}
+
+	if len(report.Organizations) != 2 || report.Organizations[0].Organization != "acme" {
+		t.Fatalf("Unexpected organizations %#v", report.Organizations)
+	}
+	acme := report.Organizations[0]
+	if acme.Clusters != 2 || acme.NodePools != 2 || acme.Nodes != 5 || acme.SpotNodes != 4 || acme.CPUs != 20 {
+		t.Errorf("Unexpected organization summary %#v", acme)
+	}
+	if acme.MonthlyCost == nil || *acme.MonthlyCost != 1.0*HoursPerMonth {
+		t.Errorf("Expected monthly cost %v, got %v", 1.0*HoursPerMonth, acme.MonthlyCost)
+	}
+
+	if report.Total.Clusters != 3 || report.Total.Nodes != 7 || report.Total.CPUs != 28 {
+		t.Errorf("Unexpected total %#v", report.Total)
+	}
+	if report.Currency != "USD" {
+		t.Errorf("Expected currency USD, got %q", report.Currency)
+	}
+	if diff := cmp.Diff([]string{"n/a"}, report.UnpricedInstanceTypes); diff != "" {
+		t.Errorf("Unexpected unpriced instance types (-want +got):\n%s", diff)
+	}
+}
+
+// TestParsePriceSheet tests rejecting invalid price sheets.
+func TestParsePriceSheet(t *testing.T) {
+	var testCases = []string{
+		"instance_types: [m5.xlarge]",
+		"instance_types:\n  m5.xlarge:\n    on_demand: -1",
+		"instance_types:\n  m5.xlarge:\n    reserved: 0.1",
+	}
+
+	for i, tc := range testCases {
+		_, err := ParsePriceSheet([]byte(tc))
+		if !IsInvalidPriceSheet(err) {
+			t.Errorf("Case %d - Expected invalid price sheet error, got %#v", i, err)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package capacity
+
+import "github.com/giantswarm/microerror"
+
+var invalidPriceSheetError = &microerror.Error{
+	Kind: "invalidPriceSheetError",
+}
+
+// IsInvalidPriceSheet asserts invalidPriceSheetError.
+func IsInvalidPriceSheet(err error) bool {
+	return microerror.Cause(err) == invalidPriceSheetError
+}
@@ -0,0 +1,77 @@
+package capacity
+
+import (
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// PriceSheet holds hourly prices per node by instance type or VM size.
+//
+// Example:
+//
+//	currency: USD
+//	instance_types:
+//	  m5.xlarge:
+//	    on_demand: 0.192
+//	    spot: 0.07
+//	  Standard_D4s_v3:
+//	    on_demand: 0.192
+type PriceSheet struct {
+	Currency      string           `yaml:"currency"`
+	InstanceTypes map[string]Price `yaml:"instance_types"`
+}
+
+// Price is the hourly price of one node.
+type Price struct {
+	OnDemand float64 `yaml:"on_demand"`
+	// Spot is optional. If not set, spot instances are priced like
+	// on-demand instances.
+	Spot float64 `yaml:"spot"`
+}
+
+// ParsePriceSheet parses a price sheet from YAML.
+func ParsePriceSheet(data []byte) (*PriceSheet, error) {
+	p := &PriceSheet{}
+
+	err := yaml.UnmarshalStrict(data, p)
+	if err != nil {
+		return nil, microerror.Maskf(invalidPriceSheetError, err.Error())
+	}
+
+	for name, price := range p.InstanceTypes {
+		if price.OnDemand < 0 || price.Spot < 0 {
+			return nil, microerror.Maskf(invalidPriceSheetError, "negative price for %s", name)
+		}
+	}
+
+	return p, nil
+}
+
+// ReadPriceSheet reads a price sheet from a YAML file.
+func ReadPriceSheet(fs afero.Fs, path string) (*PriceSheet, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	p, err := ParsePriceSheet(data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return p, nil
+}
+
+// Price returns the price for the given instance type or VM size.
+func (p *PriceSheet) Price(instanceType string) (Price, bool) {
+	price, ok := p.InstanceTypes[instanceType]
+	return price, ok
+}
+
+func (p Price) spot() float64 {
+	if p.Spot == 0 {
+		return p.OnDemand
+	}
+	return p.Spot
+}
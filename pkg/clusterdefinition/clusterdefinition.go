@@ -0,0 +1,107 @@
+// Package clusterdefinition provides functions to read cluster definitions
+// (as used by 'gsctl create cluster -f'), to translate them into API
+// request bodies, and to plan the changes needed to bring an existing
+// cluster in line with a definition.
+package clusterdefinition
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+// FromYAML reads a cluster definition from YAML data. The result is
+// either a *types.ClusterDefinitionV4 or a *types.ClusterDefinitionV5.
+func FromYAML(yamlBytes []byte) (interface{}, error) {
+	// First unmarshal into a map so we can detect v4 or v5 schema.
+	rawMap := map[string]interface{}{}
+
+	err := yaml.Unmarshal(yamlBytes, rawMap)
+	if err != nil {
+		return nil, microerror.Maskf(unmashalToMapFailedError, err.Error())
+	}
+
+	// Detecting v5 purely based on the existence of the 'api_version' key.
+	if _, apiVersionOK := rawMap["api_version"]; apiVersionOK {
+		// v5
+		def := &types.ClusterDefinitionV5{}
+		err := yaml.UnmarshalStrict(yamlBytes, def)
+		if err != nil {
+			return nil, microerror.Maskf(invalidV5DefinitionYAMLError, err.Error())
+		}
+
+		return def, nil
+	}
+
+	// v4 (default/fall back)
+	def := &types.ClusterDefinitionV4{}
+	err = yaml.UnmarshalStrict(yamlBytes, def)
+	if err != nil {
+		return nil, microerror.Maskf(invalidDefinitionYAMLError, err.Error())
+	}
+
+	return def, nil
+}
+
+// FromFile reads a cluster definition from a YAML file.
+func FromFile(fs afero.Fs, path string) (interface{}, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return FromYAML(data)
+}
+
+// FromReader reads a cluster definition from a reader, e. g. standard input.
+func FromReader(r io.Reader) (interface{}, error) {
+	yamlString := ""
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		yamlString += scanner.Text() + "\n"
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return FromYAML([]byte(yamlString))
+}
+
+// ReadV5 reads a v5 cluster definition from the given file, or from standard
+// input if path is "-". The definition must contain the cluster name and
+// owner, as these identify an existing cluster.
+func ReadV5(fs afero.Fs, path string) (*types.ClusterDefinitionV5, error) {
+	var definitionInterface interface{}
+	var err error
+
+	if path == "-" {
+		definitionInterface, err = FromReader(os.Stdin)
+	} else {
+		definitionInterface, err = FromFile(fs, path)
+	}
+	if err != nil {
+		return nil, microerror.Maskf(definitionNotReadableError, err.Error())
+	}
+
+	def, ok := definitionInterface.(*types.ClusterDefinitionV5)
+	if !ok {
+		return nil, microerror.Mask(notV5DefinitionError)
+	}
+
+	if def.Name == "" {
+		return nil, microerror.Maskf(clusterNameMissingError, "the definition must contain a cluster name")
+	}
+	if def.Owner == "" {
+		return nil, microerror.Mask(clusterOwnerMissingError)
+	}
+
+	return def, nil
+}
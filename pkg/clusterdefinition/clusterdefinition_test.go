@@ -0,0 +1,141 @@
+package clusterdefinition
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+func TestFromYAML(t *testing.T) {
+	testCases := []struct {
+		input        string
+		expected     interface{}
+		errorMatcher func(error) bool
+	}{
+		{
+			input:    "owner: acme\nname: v4 cluster\n",
+			expected: &types.ClusterDefinitionV4{Owner: "acme", Name: "v4 cluster"},
+		},
+		{
+			input:    "api_version: v5\nowner: acme\nname: v5 cluster\nnodepools:\n- name: general\n",
+			expected: &types.ClusterDefinitionV5{APIVersion: "v5", Owner: "acme", Name: "v5 cluster", NodePools: []*types.NodePoolDefinition{{Name: "general"}}},
+		},
+		{
+			input:        "api_version: v5\nunknown_key: foo\n",
+			errorMatcher: IsInvalidV5DefinitionYAML,
+		},
+		{
+			input:        "owner: acme\nunknown_key: foo\n",
+			errorMatcher: IsInvalidDefinitionYAML,
+		},
+		{
+			input:        "- a list\n- is not a map\n",
+			errorMatcher: IsUnmashalToMapFailed,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			def, err := FromReader(strings.NewReader(tc.input))
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+			if diff := cmp.Diff(tc.expected, def); diff != "" {
+				t.Errorf("Case %d - Definition unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+func TestReadV5(t *testing.T) {
+	testCases := []struct {
+		input        string
+		expected     *types.ClusterDefinitionV5
+		errorMatcher func(error) bool
+	}{
+		{
+			input:    "api_version: v5\nowner: acme\nname: v5 cluster\n",
+			expected: &types.ClusterDefinitionV5{APIVersion: "v5", Owner: "acme", Name: "v5 cluster"},
+		},
+		{
+			input:        "owner: acme\nname: v4 cluster\n",
+			errorMatcher: IsNotV5Definition,
+		},
+		{
+			input:        "api_version: v5\nowner: acme\n",
+			errorMatcher: IsClusterNameMissing,
+		},
+		{
+			input:        "api_version: v5\nname: v5 cluster\n",
+			errorMatcher: IsClusterOwnerMissing,
+		},
+		{
+			input:        "api_version: v5\nunknown_key: foo\n",
+			errorMatcher: IsDefinitionNotReadable,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, "/cluster.yaml", []byte(tc.input), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			def, err := ReadV5(fs, "/cluster.yaml")
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+			if diff := cmp.Diff(tc.expected, def); diff != "" {
+				t.Errorf("Case %d - Definition unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+
+	_, err := ReadV5(afero.NewMemMapFs(), "/missing.yaml")
+	if !IsDefinitionNotReadable(err) {
+		t.Errorf("Expected definitionNotReadableError, got %#v", err)
+	}
+}
+
+func TestAddNodePoolRequest(t *testing.T) {
+	def := &types.NodePoolDefinition{
+		Name:              "general",
+		AvailabilityZones: &types.AvailabilityZonesDefinition{Number: 2},
+		Scaling:           &types.NodePoolScalingDefinition{Min: toInt64Ptr(2), Max: 5},
+		NodeSpec:          &types.NodeSpec{AWS: &types.AWSSpecificDefinition{InstanceType: "m5.xlarge"}},
+	}
+
+	b := AddNodePoolRequest(def)
+
+	if b.Name != "general" {
+		t.Errorf("Expected name 'general', got %q", b.Name)
+	}
+	if b.AvailabilityZones.Number != 2 {
+		t.Errorf("Expected 2 availability zones, got %d", b.AvailabilityZones.Number)
+	}
+	if *b.Scaling.Min != 2 || b.Scaling.Max != 5 {
+		t.Errorf("Expected scaling 2/5, got %d/%d", *b.Scaling.Min, b.Scaling.Max)
+	}
+	if b.NodeSpec.Aws.InstanceType != "m5.xlarge" {
+		t.Errorf("Expected instance type 'm5.xlarge', got %q", b.NodeSpec.Aws.InstanceType)
+	}
+}
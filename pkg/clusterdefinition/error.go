@@ -0,0 +1,140 @@
+package clusterdefinition
+
+import "github.com/giantswarm/microerror"
+
+// unmashalToMapFailedError is used when a YAML cluster definition can't be unmarshalled into map[string]interface{}.
+var unmashalToMapFailedError = &microerror.Error{
+	Kind: "unmashalToMapFailedError",
+	Desc: "Could not unmarshal YAML into a map[string]interface{} structure. Seems like the YAML is invalid.",
+}
+
+// IsUnmashalToMapFailed asserts unmashalToMapFailedError.
+func IsUnmashalToMapFailed(err error) bool {
+	return microerror.Cause(err) == unmashalToMapFailedError
+}
+
+// invalidV5DefinitionYAMLError is used when the YAML definition can't be parsed as valid v5.
+var invalidV5DefinitionYAMLError = &microerror.Error{
+	Kind: "invalidV5DefinitionYAMLError",
+}
+
+// IsInvalidV5DefinitionYAML asserts invalidV5DefinitionYAMLError.
+func IsInvalidV5DefinitionYAML(err error) bool {
+	return microerror.Cause(err) == invalidV5DefinitionYAMLError
+}
+
+// invalidDefinitionYAMLError is used when the YAML definition can't be parsed as any valid cluster definition.
+var invalidDefinitionYAMLError = &microerror.Error{
+	Kind: "invalidDefinitionYAMLError",
+}
+
+// IsInvalidDefinitionYAML asserts invalidDefinitionYAMLError.
+func IsInvalidDefinitionYAML(err error) bool {
+	return microerror.Cause(err) == invalidDefinitionYAMLError
+}
+
+// definitionNotReadableError is used when a cluster definition file can't be
+// read or parsed.
+var definitionNotReadableError = &microerror.Error{
+	Kind: "definitionNotReadableError",
+}
+
+// IsDefinitionNotReadable asserts definitionNotReadableError.
+func IsDefinitionNotReadable(err error) bool {
+	return microerror.Cause(err) == definitionNotReadableError
+}
+
+// notV5DefinitionError is used when the definition given is not a v5 cluster definition.
+var notV5DefinitionError = &microerror.Error{
+	Kind: "notV5DefinitionError",
+}
+
+// IsNotV5Definition asserts notV5DefinitionError.
+func IsNotV5Definition(err error) bool {
+	return microerror.Cause(err) == notV5DefinitionError
+}
+
+// clusterNameMissingError is used when a v5 definition lacks the cluster name.
+var clusterNameMissingError = &microerror.Error{
+	Kind: "clusterNameMissingError",
+}
+
+// IsClusterNameMissing asserts clusterNameMissingError.
+func IsClusterNameMissing(err error) bool {
+	return microerror.Cause(err) == clusterNameMissingError
+}
+
+// clusterOwnerMissingError is used when a v5 definition lacks the owner.
+var clusterOwnerMissingError = &microerror.Error{
+	Kind: "clusterOwnerMissingError",
+}
+
+// IsClusterOwnerMissing asserts clusterOwnerMissingError.
+func IsClusterOwnerMissing(err error) bool {
+	return microerror.Cause(err) == clusterOwnerMissingError
+}
+
+// clusterNotFoundError is used when no cluster matches the name and owner
+// given in the definition.
+var clusterNotFoundError = &microerror.Error{
+	Kind: "clusterNotFoundError",
+}
+
+// IsClusterNotFound asserts clusterNotFoundError.
+func IsClusterNotFound(err error) bool {
+	return microerror.Cause(err) == clusterNotFoundError
+}
+
+// clusterNotV5Error is used when the cluster matching the definition is not a v5 cluster.
+var clusterNotV5Error = &microerror.Error{
+	Kind: "clusterNotV5Error",
+}
+
+// IsClusterNotV5 asserts clusterNotV5Error.
+func IsClusterNotV5(err error) bool {
+	return microerror.Cause(err) == clusterNotV5Error
+}
+
+// multipleClustersFoundError is used when more than one cluster matches
+// the name and owner given in the definition.
+var multipleClustersFoundError = &microerror.Error{
+	Kind: "multipleClustersFoundError",
+}
+
+// IsMultipleClustersFound asserts multipleClustersFoundError.
+func IsMultipleClustersFound(err error) bool {
+	return microerror.Cause(err) == multipleClustersFoundError
+}
+
+// nodePoolNameMissingError is used when a node pool in the definition has no name,
+// so it cannot be matched with an existing node pool.
+var nodePoolNameMissingError = &microerror.Error{
+	Kind: "nodePoolNameMissingError",
+}
+
+// IsNodePoolNameMissing asserts nodePoolNameMissingError.
+func IsNodePoolNameMissing(err error) bool {
+	return microerror.Cause(err) == nodePoolNameMissingError
+}
+
+// duplicateNodePoolNameError is used when a node pool name appears more than once,
+// either in the definition or in the cluster.
+var duplicateNodePoolNameError = &microerror.Error{
+	Kind: "duplicateNodePoolNameError",
+}
+
+// IsDuplicateNodePoolName asserts duplicateNodePoolNameError.
+func IsDuplicateNodePoolName(err error) bool {
+	return microerror.Cause(err) == duplicateNodePoolNameError
+}
+
+// revertHAMasterNotAllowedError is used when the definition asks for a single
+// master while the cluster has high-availability masters.
+var revertHAMasterNotAllowedError = &microerror.Error{
+	Kind: "revertHAMasterNotAllowedError",
+}
+
+// IsRevertHAMasterNotAllowed asserts revertHAMasterNotAllowedError.
+func IsRevertHAMasterNotAllowed(err error) bool {
+	return microerror.Cause(err) == revertHAMasterNotAllowedError
+}
@@ -0,0 +1,72 @@
+package clusterdefinition
+
+import (
+	"github.com/giantswarm/gsclientgen/v2/models"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+// AddNodePoolRequest creates the request body for adding the node pool
+// described by the given definition.
+func AddNodePoolRequest(def *types.NodePoolDefinition) *models.V5AddNodePoolRequest {
+	b := &models.V5AddNodePoolRequest{
+		Name:              def.Name,
+		AvailabilityZones: &models.V5AddNodePoolRequestAvailabilityZones{},
+		Scaling:           &models.V5AddNodePoolRequestScaling{},
+		NodeSpec:          &models.V5AddNodePoolRequestNodeSpec{},
+	}
+
+	if def.AvailabilityZones != nil {
+		if def.AvailabilityZones.Number != 0 {
+			b.AvailabilityZones.Number = def.AvailabilityZones.Number
+		}
+		if len(def.AvailabilityZones.Zones) != 0 {
+			b.AvailabilityZones.Zones = def.AvailabilityZones.Zones
+		}
+	}
+
+	if def.Scaling != nil {
+		var min int64
+		if def.Scaling.Min != nil {
+			min = *def.Scaling.Min
+		}
+		b.Scaling.Min = &min
+		if def.Scaling.Max != 0 {
+			b.Scaling.Max = def.Scaling.Max
+		}
+	}
+
+	if def.NodeSpec != nil {
+		if def.NodeSpec.AWS != nil {
+			b.NodeSpec.Aws = &models.V5AddNodePoolRequestNodeSpecAws{}
+
+			if def.NodeSpec.AWS.InstanceDistribution != nil {
+				b.NodeSpec.Aws.InstanceDistribution = &models.V5AddNodePoolRequestNodeSpecAwsInstanceDistribution{
+					OnDemandBaseCapacity:                &def.NodeSpec.AWS.InstanceDistribution.OnDemandBaseCapacity,
+					OnDemandPercentageAboveBaseCapacity: &def.NodeSpec.AWS.InstanceDistribution.OnDemandPercentageAboveBaseCapacity,
+				}
+			}
+
+			if def.NodeSpec.AWS.InstanceType != "" {
+				b.NodeSpec.Aws.InstanceType = def.NodeSpec.AWS.InstanceType
+			}
+
+			b.NodeSpec.Aws.UseAlikeInstanceTypes = &def.NodeSpec.AWS.UseAlikeInstanceTypes
+		}
+
+		if def.NodeSpec.Azure != nil {
+			b.NodeSpec.Azure = &models.V5AddNodePoolRequestNodeSpecAzure{}
+			if def.NodeSpec.Azure.VMSize != "" {
+				b.NodeSpec.Azure.VMSize = def.NodeSpec.Azure.VMSize
+			}
+			if def.NodeSpec.Azure.AzureSpotInstances != nil {
+				b.NodeSpec.Azure.SpotInstances = &models.V5AddNodePoolRequestNodeSpecAzureSpotInstances{
+					Enabled:  &def.NodeSpec.Azure.AzureSpotInstances.Enabled,
+					MaxPrice: &def.NodeSpec.Azure.AzureSpotInstances.MaxPrice,
+				}
+			}
+		}
+	}
+
+	return b
+}
@@ -0,0 +1,371 @@
+package clusterdefinition
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/commands/types"
+	"github.com/giantswarm/gsctl/util"
+)
+
+// Plan describes the changes needed to bring a cluster in line with a definition.
+type Plan struct {
+	ClusterID   string
+	ClusterName string
+
+	// ReleaseFrom and ReleaseTo are set if the cluster has to be upgraded.
+	ReleaseFrom string
+	ReleaseTo   string
+
+	// EnableHAMasters is true if the cluster has to be switched to high-availability masters.
+	EnableHAMasters bool
+
+	LabelChanges      []LabelChange
+	NodePoolsToCreate []*types.NodePoolDefinition
+	NodePoolsToModify []NodePoolChange
+	NodePoolsToDelete []NodePoolRef
+
+	// Warnings are differences that can't be reconciled via the API.
+	Warnings []string
+}
+
+// LabelChange is a single label to be added, changed, or removed.
+// From is nil for new labels, To is nil for labels to be removed.
+type LabelChange struct {
+	Key  string
+	From *string
+	To   *string
+}
+
+// NodePoolRef identifies an existing node pool.
+type NodePoolRef struct {
+	ID   string
+	Name string
+}
+
+// NodePoolChange is a scaling change to an existing node pool.
+type NodePoolChange struct {
+	ID      string
+	Name    string
+	MinFrom int64
+	MinTo   int64
+	MaxFrom int64
+	MaxTo   int64
+}
+
+// IsEmpty returns true if the plan contains no changes. Warnings are not
+// considered changes.
+func (p *Plan) IsEmpty() bool {
+	return p.ReleaseTo == "" &&
+		!p.EnableHAMasters &&
+		len(p.LabelChanges) == 0 &&
+		len(p.NodePoolsToCreate) == 0 &&
+		len(p.NodePoolsToModify) == 0 &&
+		len(p.NodePoolsToDelete) == 0
+}
+
+// LookupCluster finds the one cluster matching the definition's name and
+// owner and returns its details and node pools.
+func LookupCluster(def *types.ClusterDefinitionV5, clientWrapper *client.Wrapper, auxParams *client.AuxiliaryParams) (*models.V5ClusterDetailsResponse, []*models.V5GetNodePoolsResponseItems, error) {
+	response, err := clientWrapper.GetClusters(auxParams)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	var ids []string
+	for _, c := range response.Payload {
+		if c.DeleteDate != nil {
+			continue
+		}
+		if c.Name == def.Name && c.Owner == def.Owner {
+			ids = append(ids, c.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return nil, nil, microerror.Maskf(clusterNotFoundError, "no cluster named '%s' owned by '%s'", def.Name, def.Owner)
+	case 1:
+	default:
+		return nil, nil, microerror.Maskf(multipleClustersFoundError, "clusters %s are all named '%s' and owned by '%s'", strings.Join(ids, ", "), def.Name, def.Owner)
+	}
+
+	clusterResponse, err := clientWrapper.GetClusterV5(ids[0], auxParams)
+	if clienterror.IsNotFoundError(err) || clienterror.IsBadRequestError(err) {
+		return nil, nil, microerror.Mask(clusterNotV5Error)
+	} else if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	nodePoolsResponse, err := clientWrapper.GetNodePools(ids[0], auxParams)
+	if err != nil {
+		return nil, nil, microerror.Mask(err)
+	}
+
+	var nodePools []*models.V5GetNodePoolsResponseItems
+	if nodePoolsResponse != nil {
+		nodePools = nodePoolsResponse.Payload
+	}
+
+	return clusterResponse.Payload, nodePools, nil
+}
+
+// CreatePlan compares the definition with the cluster's current state and
+// returns the changes required. Sections omitted from the definition
+// (release version, master nodes, labels, node pools) are left untouched.
+func CreatePlan(def *types.ClusterDefinitionV5, cluster *models.V5ClusterDetailsResponse, nodePools []*models.V5GetNodePoolsResponseItems) (*Plan, error) {
+	p := &Plan{
+		ClusterID:   cluster.ID,
+		ClusterName: cluster.Name,
+	}
+
+	// Release version
+	if def.ReleaseVersion != "" {
+		desired := strings.TrimPrefix(def.ReleaseVersion, "v")
+		if desired != cluster.ReleaseVersion {
+			p.ReleaseFrom = cluster.ReleaseVersion
+			p.ReleaseTo = desired
+		}
+	}
+
+	// Master nodes
+	if def.MasterNodes != nil && def.MasterNodes.HighAvailability != nil {
+		current := cluster.MasterNodes != nil && cluster.MasterNodes.HighAvailability
+		desired := *def.MasterNodes.HighAvailability
+		if desired && !current {
+			p.EnableHAMasters = true
+		} else if !desired && current {
+			return nil, microerror.Mask(revertHAMasterNotAllowedError)
+		}
+	}
+
+	// Labels
+	if def.Labels != nil {
+		p.LabelChanges = diffLabels(def.Labels, cluster.Labels)
+	}
+
+	// Node pools
+	if def.NodePools != nil {
+		err := planNodePools(p, def.NodePools, nodePools)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return p, nil
+}
+
+// diffLabels returns the label changes needed to get from the current to the
+// desired labels. Labels managed by Giant Swarm are ignored unless they
+// are mentioned in the definition explicitly.
+func diffLabels(desired map[string]*string, current map[string]string) []LabelChange {
+	changes := []LabelChange{}
+
+	for key, value := range desired {
+		currentValue, exists := current[key]
+		switch {
+		case value == nil && exists:
+			changes = append(changes, LabelChange{Key: key, From: toStringPtr(currentValue)})
+		case value != nil && !exists:
+			changes = append(changes, LabelChange{Key: key, To: toStringPtr(*value)})
+		case value != nil && exists && *value != currentValue:
+			changes = append(changes, LabelChange{Key: key, From: toStringPtr(currentValue), To: toStringPtr(*value)})
+		}
+	}
+
+	for key, value := range current {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if strings.Contains(key, util.LabelFilterKeySubstring) {
+			continue
+		}
+		changes = append(changes, LabelChange{Key: key, From: toStringPtr(value)})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// planNodePools matches node pools by name and adds the resulting
+// creations, modifications and deletions to the plan.
+func planNodePools(p *Plan, desired []*types.NodePoolDefinition, current []*models.V5GetNodePoolsResponseItems) error {
+	currentByName := map[string]*models.V5GetNodePoolsResponseItems{}
+	for _, np := range current {
+		if _, exists := currentByName[np.Name]; exists {
+			return microerror.Maskf(duplicateNodePoolNameError, "the cluster has more than one node pool named '%s'", np.Name)
+		}
+		currentByName[np.Name] = np
+	}
+
+	desiredNames := map[string]bool{}
+	for _, d := range desired {
+		if d.Name == "" {
+			return microerror.Mask(nodePoolNameMissingError)
+		}
+		if desiredNames[d.Name] {
+			return microerror.Maskf(duplicateNodePoolNameError, "the definition has more than one node pool named '%s'", d.Name)
+		}
+		desiredNames[d.Name] = true
+
+		np, exists := currentByName[d.Name]
+		if !exists {
+			p.NodePoolsToCreate = append(p.NodePoolsToCreate, d)
+			continue
+		}
+
+		if d.Scaling != nil && np.Scaling != nil {
+			change := NodePoolChange{
+				ID:      np.ID,
+				Name:    np.Name,
+				MaxFrom: np.Scaling.Max,
+				MaxTo:   np.Scaling.Max,
+			}
+			if np.Scaling.Min != nil {
+				change.MinFrom = *np.Scaling.Min
+			}
+			change.MinTo = change.MinFrom
+
+			if d.Scaling.Min != nil {
+				change.MinTo = *d.Scaling.Min
+			}
+			if d.Scaling.Max != 0 {
+				change.MaxTo = d.Scaling.Max
+			}
+
+			if change.MinFrom != change.MinTo || change.MaxFrom != change.MaxTo {
+				p.NodePoolsToModify = append(p.NodePoolsToModify, change)
+			}
+		}
+
+		p.Warnings = append(p.Warnings, nodePoolWarnings(d, np)...)
+	}
+
+	for _, np := range current {
+		if !desiredNames[np.Name] {
+			p.NodePoolsToDelete = append(p.NodePoolsToDelete, NodePoolRef{ID: np.ID, Name: np.Name})
+		}
+	}
+
+	return nil
+}
+
+// nodePoolWarnings lists differences in node pool attributes that can't be
+// changed for an existing node pool.
+func nodePoolWarnings(d *types.NodePoolDefinition, np *models.V5GetNodePoolsResponseItems) []string {
+	var warnings []string
+
+	warn := func(attribute, from, to string) {
+		if from != to {
+			warnings = append(warnings, fmt.Sprintf("Node pool '%s' (%s): %s can't be changed from %s to %s.", np.Name, np.ID, attribute, from, to))
+		}
+	}
+
+	if d.AvailabilityZones != nil {
+		if d.AvailabilityZones.Number > 0 {
+			warn("number of availability zones", strconv.Itoa(len(np.AvailabilityZones)), strconv.FormatInt(d.AvailabilityZones.Number, 10))
+		}
+		if len(d.AvailabilityZones.Zones) > 0 {
+			warn("availability zones", strings.Join(np.AvailabilityZones, ","), strings.Join(d.AvailabilityZones.Zones, ","))
+		}
+	}
+
+	if d.NodeSpec == nil || np.NodeSpec == nil {
+		return warnings
+	}
+
+	if d.NodeSpec.AWS != nil && np.NodeSpec.Aws != nil {
+		if d.NodeSpec.AWS.InstanceType != "" {
+			warn("instance type", np.NodeSpec.Aws.InstanceType, d.NodeSpec.AWS.InstanceType)
+		}
+		warn("use of alike instance types", strconv.FormatBool(np.NodeSpec.Aws.UseAlikeInstanceTypes), strconv.FormatBool(d.NodeSpec.AWS.UseAlikeInstanceTypes))
+
+		if d.NodeSpec.AWS.InstanceDistribution != nil {
+			current := np.NodeSpec.Aws.InstanceDistribution
+			if current == nil {
+				current = &models.V5GetNodePoolsResponseItemsNodeSpecAwsInstanceDistribution{}
+			}
+			warn("on-demand base capacity",
+				strconv.FormatInt(current.OnDemandBaseCapacity, 10),
+				strconv.FormatInt(d.NodeSpec.AWS.InstanceDistribution.OnDemandBaseCapacity, 10))
+			warn("on-demand percentage above base capacity",
+				strconv.FormatInt(current.OnDemandPercentageAboveBaseCapacity, 10),
+				strconv.FormatInt(d.NodeSpec.AWS.InstanceDistribution.OnDemandPercentageAboveBaseCapacity, 10))
+		}
+	}
+
+	if d.NodeSpec.Azure != nil && np.NodeSpec.Azure != nil {
+		if d.NodeSpec.Azure.VMSize != "" {
+			warn("VM size", np.NodeSpec.Azure.VMSize, d.NodeSpec.Azure.VMSize)
+		}
+
+		if d.NodeSpec.Azure.AzureSpotInstances != nil {
+			current := np.NodeSpec.Azure.SpotInstances
+			if current == nil {
+				current = &models.V5GetNodePoolsResponseItemsNodeSpecAzureSpotInstances{}
+			}
+			warn("use of spot instances", strconv.FormatBool(current.Enabled), strconv.FormatBool(d.NodeSpec.Azure.AzureSpotInstances.Enabled))
+			if d.NodeSpec.Azure.AzureSpotInstances.MaxPrice != 0 {
+				warn("spot instance maximum price",
+					strconv.FormatFloat(current.MaxPrice, 'f', -1, 64),
+					strconv.FormatFloat(d.NodeSpec.Azure.AzureSpotInstances.MaxPrice, 'f', -1, 64))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// FormatPlan renders the plan in a human-readable way, including warnings.
+func FormatPlan(p *Plan) string {
+	var lines []string
+
+	if p.ReleaseTo != "" {
+		lines = append(lines, color.YellowString("~ release version: %s -> %s", p.ReleaseFrom, p.ReleaseTo))
+	}
+	if p.EnableHAMasters {
+		lines = append(lines, color.YellowString("~ master nodes high availability: false -> true"))
+	}
+
+	for _, l := range p.LabelChanges {
+		switch {
+		case l.From == nil:
+			lines = append(lines, color.GreenString("+ label %s=%s", l.Key, *l.To))
+		case l.To == nil:
+			lines = append(lines, color.RedString("- label %s=%s", l.Key, *l.From))
+		default:
+			lines = append(lines, color.YellowString("~ label %s: %s -> %s", l.Key, *l.From, *l.To))
+		}
+	}
+
+	for _, np := range p.NodePoolsToCreate {
+		lines = append(lines, color.GreenString("+ node pool '%s'", np.Name))
+	}
+	for _, np := range p.NodePoolsToModify {
+		lines = append(lines, color.YellowString("~ node pool '%s' (%s) scaling: min %d -> %d, max %d -> %d", np.Name, np.ID, np.MinFrom, np.MinTo, np.MaxFrom, np.MaxTo))
+	}
+	for _, np := range p.NodePoolsToDelete {
+		lines = append(lines, color.RedString("- node pool '%s' (%s)", np.Name, np.ID))
+	}
+
+	for _, w := range p.Warnings {
+		lines = append(lines, color.MagentaString("! %s", w))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func toStringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,252 @@
+package clusterdefinition
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+// TestCreatePlan tests the comparison of definition and cluster state.
+func TestCreatePlan(t *testing.T) {
+	haTrue := true
+	haFalse := false
+	min1 := int64(1)
+	min3 := int64(3)
+	prod := "production"
+
+	cluster := &models.V5ClusterDetailsResponse{
+		ID:             "f01r4",
+		Name:           "My cluster",
+		Owner:          "acme",
+		ReleaseVersion: "11.0.0",
+		MasterNodes:    &models.V5ClusterDetailsResponseMasterNodes{HighAvailability: false},
+		Labels: map[string]string{
+			"environment":                    "testing",
+			"team":                           "blue",
+			"giantswarm.io/cluster":          "f01r4",
+			"release.giantswarm.io/version":  "11.0.0",
+			"cluster-operator.giantswarm.io": "1.0.0",
+		},
+	}
+
+	nodePools := []*models.V5GetNodePoolsResponseItems{
+		{
+			ID:       "a7rc4",
+			Name:     "general",
+			Scaling:  &models.V5GetNodePoolsResponseItemsScaling{Min: &min1, Max: 3},
+			NodeSpec: &models.V5GetNodePoolsResponseItemsNodeSpec{Aws: &models.V5GetNodePoolsResponseItemsNodeSpecAws{InstanceType: "m5.xlarge"}},
+		},
+		{
+			ID:      "6feel",
+			Name:    "batch",
+			Scaling: &models.V5GetNodePoolsResponseItemsScaling{Min: &min3, Max: 10},
+		},
+	}
+
+	var testCases = []struct {
+		definition   *types.ClusterDefinitionV5
+		expected     *Plan
+		errorMatcher func(error) bool
+	}{
+		// Only name and owner: nothing to do.
+		{
+			definition: &types.ClusterDefinitionV5{Name: "My cluster", Owner: "acme"},
+			expected:   &Plan{ClusterID: "f01r4", ClusterName: "My cluster"},
+		},
+		// Same release, HA masters, upgrade.
+		{
+			definition: &types.ClusterDefinitionV5{
+				Name:           "My cluster",
+				Owner:          "acme",
+				ReleaseVersion: "v11.1.0",
+				MasterNodes:    &types.MasterNodes{HighAvailability: &haTrue},
+			},
+			expected: &Plan{
+				ClusterID:       "f01r4",
+				ClusterName:     "My cluster",
+				ReleaseFrom:     "11.0.0",
+				ReleaseTo:       "11.1.0",
+				EnableHAMasters: true,
+			},
+		},
+		// Labels added, changed, removed; giantswarm.io labels are kept.
+		{
+			definition: &types.ClusterDefinitionV5{
+				Name:   "My cluster",
+				Owner:  "acme",
+				Labels: map[string]*string{"environment": &prod, "cost-center": &prod},
+			},
+			expected: &Plan{
+				ClusterID:   "f01r4",
+				ClusterName: "My cluster",
+				LabelChanges: []LabelChange{
+					{Key: "cost-center", To: &prod},
+					{Key: "environment", From: toStringPtr("testing"), To: &prod},
+					{Key: "team", From: toStringPtr("blue")},
+				},
+			},
+		},
+		// Node pools created, scaled, deleted.
+		{
+			definition: &types.ClusterDefinitionV5{
+				Name:  "My cluster",
+				Owner: "acme",
+				NodePools: []*types.NodePoolDefinition{
+					{
+						Name:     "general",
+						Scaling:  &types.NodePoolScalingDefinition{Min: toInt64Ptr(2), Max: 5},
+						NodeSpec: &types.NodeSpec{AWS: &types.AWSSpecificDefinition{InstanceType: "m5.2xlarge"}},
+					},
+					{
+						Name: "gpu",
+					},
+				},
+			},
+			expected: &Plan{
+				ClusterID:         "f01r4",
+				ClusterName:       "My cluster",
+				NodePoolsToCreate: []*types.NodePoolDefinition{{Name: "gpu"}},
+				NodePoolsToModify: []NodePoolChange{
+					{ID: "a7rc4", Name: "general", MinFrom: 1, MinTo: 2, MaxFrom: 3, MaxTo: 5},
+				},
+				NodePoolsToDelete: []NodePoolRef{{ID: "6feel", Name: "batch"}},
+				Warnings:          []string{"Node pool 'general' (a7rc4): instance type can't be changed from m5.xlarge to m5.2xlarge."},
+			},
+		},
+		// Scaling without min keeps the current min.
+		{
+			definition: &types.ClusterDefinitionV5{
+				Name:  "My cluster",
+				Owner: "acme",
+				NodePools: []*types.NodePoolDefinition{
+					{Name: "general", Scaling: &types.NodePoolScalingDefinition{Max: 5}},
+					{Name: "batch", Scaling: &types.NodePoolScalingDefinition{Max: 10}},
+				},
+			},
+			expected: &Plan{
+				ClusterID:   "f01r4",
+				ClusterName: "My cluster",
+				NodePoolsToModify: []NodePoolChange{
+					{ID: "a7rc4", Name: "general", MinFrom: 1, MinTo: 1, MaxFrom: 3, MaxTo: 5},
+				},
+			},
+		},
+		// Node pool without name.
+		{
+			definition: &types.ClusterDefinitionV5{
+				Name:      "My cluster",
+				Owner:     "acme",
+				NodePools: []*types.NodePoolDefinition{{Scaling: &types.NodePoolScalingDefinition{Max: 5}}},
+			},
+			errorMatcher: IsNodePoolNameMissing,
+		},
+		// Duplicate node pool name.
+		{
+			definition: &types.ClusterDefinitionV5{
+				Name:      "My cluster",
+				Owner:     "acme",
+				NodePools: []*types.NodePoolDefinition{{Name: "general"}, {Name: "general"}},
+			},
+			errorMatcher: IsDuplicateNodePoolName,
+		},
+		// Single master stays single master.
+		{
+			definition: &types.ClusterDefinitionV5{
+				Name:        "My cluster",
+				Owner:       "acme",
+				MasterNodes: &types.MasterNodes{HighAvailability: &haFalse},
+			},
+			expected: &Plan{ClusterID: "f01r4", ClusterName: "My cluster"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			p, err := CreatePlan(tc.definition, cluster, nodePools)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+
+			if diff := cmp.Diff(tc.expected, p); diff != "" {
+				t.Errorf("Case %d - Plan unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+// TestCreatePlanRevertHAMaster tests that HA masters can't be switched off.
+func TestCreatePlanRevertHAMaster(t *testing.T) {
+	haFalse := false
+	cluster := &models.V5ClusterDetailsResponse{
+		ID:          "f01r4",
+		MasterNodes: &models.V5ClusterDetailsResponseMasterNodes{HighAvailability: true},
+	}
+	def := &types.ClusterDefinitionV5{
+		MasterNodes: &types.MasterNodes{HighAvailability: &haFalse},
+	}
+
+	_, err := CreatePlan(def, cluster, nil)
+	if !IsRevertHAMasterNotAllowed(err) {
+		t.Errorf("Expected revertHAMasterNotAllowedError, got %#v", err)
+	}
+}
+
+// TestCreatePlanWarnings tests that node pool attributes which can't be
+// changed are reported as warnings.
+func TestCreatePlanWarnings(t *testing.T) {
+	cluster := &models.V5ClusterDetailsResponse{ID: "f01r4"}
+	nodePools := []*models.V5GetNodePoolsResponseItems{
+		{
+			ID:                "a7rc4",
+			Name:              "general",
+			AvailabilityZones: []string{"westeurope-1"},
+			NodeSpec: &models.V5GetNodePoolsResponseItemsNodeSpec{
+				Azure: &models.V5GetNodePoolsResponseItemsNodeSpecAzure{VMSize: "Standard_D4s_v3"},
+			},
+		},
+	}
+	def := &types.ClusterDefinitionV5{
+		NodePools: []*types.NodePoolDefinition{
+			{
+				Name:              "general",
+				AvailabilityZones: &types.AvailabilityZonesDefinition{Number: 2},
+				NodeSpec: &types.NodeSpec{
+					Azure: &types.AzureSpecificDefinition{
+						VMSize:             "Standard_D4s_v3",
+						AzureSpotInstances: &types.AzureSpotInstances{Enabled: true},
+					},
+				},
+			},
+		},
+	}
+
+	p, err := CreatePlan(def, cluster, nodePools)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	expected := []string{
+		"Node pool 'general' (a7rc4): number of availability zones can't be changed from 1 to 2.",
+		"Node pool 'general' (a7rc4): use of spot instances can't be changed from false to true.",
+	}
+	if diff := cmp.Diff(expected, p.Warnings); diff != "" {
+		t.Errorf("Warnings unequal. (-expected +got):\n%s", diff)
+	}
+	if !p.IsEmpty() {
+		t.Errorf("Expected no changes, got %#v", p)
+	}
+}
+
+func toInt64Ptr(i int64) *int64 {
+	return &i
+}
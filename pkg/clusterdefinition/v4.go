@@ -0,0 +1,65 @@
+package clusterdefinition
+
+import (
+	"github.com/giantswarm/gsclientgen/v2/models"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+// FromV4Cluster creates a definition describing the current state of a v4 cluster.
+//
+// Only one worker item is returned, as the number of workers is determined
+// by the scaling settings. Depending on the provider, the worker is described
+// by the AWS instance type, the Azure VM size, or memory, CPU and storage.
+func FromV4Cluster(cluster *models.V4ClusterDetailsResponse) *types.ClusterDefinitionV4 {
+	def := &types.ClusterDefinitionV4{
+		Name:              cluster.Name,
+		Owner:             cluster.Owner,
+		ReleaseVersion:    cluster.ReleaseVersion,
+		AvailabilityZones: len(cluster.AvailabilityZones),
+	}
+
+	if cluster.Scaling != nil {
+		def.Scaling.Max = cluster.Scaling.Max
+		if cluster.Scaling.Min != nil {
+			def.Scaling.Min = *cluster.Scaling.Min
+		}
+	}
+
+	if len(cluster.Workers) == 0 || cluster.Workers[0] == nil {
+		return def
+	}
+
+	w := cluster.Workers[0]
+	worker := types.NodeDefinition{}
+
+	switch {
+	case w.Aws != nil && w.Aws.InstanceType != "":
+		worker.AWS.InstanceType = w.Aws.InstanceType
+	case w.Azure != nil && w.Azure.VMSize != "":
+		worker.Azure.VMSize = w.Azure.VMSize
+	default:
+		if w.CPU != nil {
+			worker.CPU.Cores = int(w.CPU.Cores)
+		}
+		if w.Memory != nil {
+			worker.Memory.SizeGB = float32(w.Memory.SizeGb)
+		}
+		if w.Storage != nil {
+			worker.Storage.SizeGB = float32(w.Storage.SizeGb)
+		}
+	}
+
+	if labels, ok := w.Labels.(map[string]interface{}); ok && len(labels) > 0 {
+		worker.Labels = map[string]string{}
+		for key, value := range labels {
+			if s, ok := value.(string); ok {
+				worker.Labels[key] = s
+			}
+		}
+	}
+
+	def.Workers = []types.NodeDefinition{worker}
+
+	return def
+}
@@ -0,0 +1,94 @@
+package clusterdefinition
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+func TestFromV4Cluster(t *testing.T) {
+	min := int64(3)
+
+	var testCases = []struct {
+		cluster  *models.V4ClusterDetailsResponse
+		expected *types.ClusterDefinitionV4
+	}{
+		// AWS cluster, described by instance type.
+		{
+			&models.V4ClusterDetailsResponse{
+				ID:                "f01r4",
+				Name:              "My cluster",
+				Owner:             "acme",
+				ReleaseVersion:    "8.5.0",
+				AvailabilityZones: []string{"eu-central-1a", "eu-central-1b"},
+				Scaling:           &models.V4ClusterDetailsResponseScaling{Min: &min, Max: 6},
+				Workers: []*models.V4ClusterDetailsResponseWorkersItems{
+					{
+						Aws:    &models.V4ClusterDetailsResponseWorkersItemsAws{InstanceType: "m5.xlarge"},
+						CPU:    &models.V4ClusterDetailsResponseWorkersItemsCPU{Cores: 4},
+						Memory: &models.V4ClusterDetailsResponseWorkersItemsMemory{SizeGb: 16},
+					},
+					{
+						Aws:    &models.V4ClusterDetailsResponseWorkersItemsAws{InstanceType: "m5.xlarge"},
+						CPU:    &models.V4ClusterDetailsResponseWorkersItemsCPU{Cores: 4},
+						Memory: &models.V4ClusterDetailsResponseWorkersItemsMemory{SizeGb: 16},
+					},
+				},
+			},
+			&types.ClusterDefinitionV4{
+				Name:              "My cluster",
+				Owner:             "acme",
+				ReleaseVersion:    "8.5.0",
+				AvailabilityZones: 2,
+				Scaling:           types.ScalingDefinition{Min: 3, Max: 6},
+				Workers: []types.NodeDefinition{
+					{AWS: types.AWSSpecificDefinition{InstanceType: "m5.xlarge"}},
+				},
+			},
+		},
+		// KVM cluster, described by resources, with worker labels.
+		{
+			&models.V4ClusterDetailsResponse{
+				ID:             "k8s01",
+				Name:           "On-prem",
+				Owner:          "acme",
+				ReleaseVersion: "9.0.0",
+				Scaling:        &models.V4ClusterDetailsResponseScaling{Min: &min, Max: 3},
+				Workers: []*models.V4ClusterDetailsResponseWorkersItems{
+					{
+						CPU:     &models.V4ClusterDetailsResponseWorkersItemsCPU{Cores: 4},
+						Memory:  &models.V4ClusterDetailsResponseWorkersItemsMemory{SizeGb: 8},
+						Storage: &models.V4ClusterDetailsResponseWorkersItemsStorage{SizeGb: 20},
+						Labels:  map[string]interface{}{"nodetype": "standard"},
+					},
+				},
+			},
+			&types.ClusterDefinitionV4{
+				Name:           "On-prem",
+				Owner:          "acme",
+				ReleaseVersion: "9.0.0",
+				Scaling:        types.ScalingDefinition{Min: 3, Max: 3},
+				Workers: []types.NodeDefinition{
+					{
+						CPU:     types.CPUDefinition{Cores: 4},
+						Memory:  types.MemoryDefinition{SizeGB: 8},
+						Storage: types.StorageDefinition{SizeGB: 20},
+						Labels:  map[string]string{"nodetype": "standard"},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, FromV4Cluster(tc.cluster)); diff != "" {
+				t.Errorf("Definition unequal. (-expected +got):\n%s", diff)
+			}
+		})
+	}
+}
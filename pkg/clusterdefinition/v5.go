@@ -0,0 +1,82 @@
+package clusterdefinition
+
+import (
+	"github.com/giantswarm/gsclientgen/v2/models"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+// FromV5Cluster creates a definition describing the current state of a v5 cluster.
+func FromV5Cluster(cluster *models.V5ClusterDetailsResponse, nodePools []*models.V5GetNodePoolsResponseItems) *types.ClusterDefinitionV5 {
+	def := &types.ClusterDefinitionV5{
+		APIVersion:     "v5",
+		Name:           cluster.Name,
+		Owner:          cluster.Owner,
+		ReleaseVersion: cluster.ReleaseVersion,
+	}
+
+	if cluster.MasterNodes != nil {
+		ha := cluster.MasterNodes.HighAvailability
+		def.MasterNodes = &types.MasterNodes{
+			HighAvailability:  &ha,
+			AvailabilityZones: cluster.MasterNodes.AvailabilityZones,
+		}
+	} else if cluster.Master != nil {
+		def.Master = &types.MasterDefinition{AvailabilityZone: cluster.Master.AvailabilityZone}
+	}
+
+	if len(cluster.Labels) > 0 {
+		def.Labels = map[string]*string{}
+		for key, value := range cluster.Labels {
+			v := value
+			def.Labels[key] = &v
+		}
+	}
+
+	for _, np := range nodePools {
+		npDef := &types.NodePoolDefinition{
+			Name: np.Name,
+		}
+
+		if len(np.AvailabilityZones) > 0 {
+			npDef.AvailabilityZones = &types.AvailabilityZonesDefinition{
+				Zones: np.AvailabilityZones,
+			}
+		}
+
+		if np.Scaling != nil {
+			npDef.Scaling = &types.NodePoolScalingDefinition{Min: np.Scaling.Min, Max: np.Scaling.Max}
+		}
+
+		if np.NodeSpec != nil {
+			npDef.NodeSpec = &types.NodeSpec{}
+			if np.NodeSpec.Aws != nil {
+				npDef.NodeSpec.AWS = &types.AWSSpecificDefinition{
+					InstanceType:          np.NodeSpec.Aws.InstanceType,
+					UseAlikeInstanceTypes: np.NodeSpec.Aws.UseAlikeInstanceTypes,
+				}
+				if np.NodeSpec.Aws.InstanceDistribution != nil {
+					npDef.NodeSpec.AWS.InstanceDistribution = &types.AWSInstanceDistribution{
+						OnDemandBaseCapacity:                np.NodeSpec.Aws.InstanceDistribution.OnDemandBaseCapacity,
+						OnDemandPercentageAboveBaseCapacity: np.NodeSpec.Aws.InstanceDistribution.OnDemandPercentageAboveBaseCapacity,
+					}
+				}
+			}
+			if np.NodeSpec.Azure != nil {
+				npDef.NodeSpec.Azure = &types.AzureSpecificDefinition{
+					VMSize: np.NodeSpec.Azure.VMSize,
+				}
+				if np.NodeSpec.Azure.SpotInstances != nil {
+					npDef.NodeSpec.Azure.AzureSpotInstances = &types.AzureSpotInstances{
+						Enabled:  np.NodeSpec.Azure.SpotInstances.Enabled,
+						MaxPrice: np.NodeSpec.Azure.SpotInstances.MaxPrice,
+					}
+				}
+			}
+		}
+
+		def.NodePools = append(def.NodePools, npDef)
+	}
+
+	return def
+}
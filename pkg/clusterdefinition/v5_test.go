@@ -0,0 +1,56 @@
+package clusterdefinition
+
+import (
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/gsctl/commands/types"
+)
+
+func TestFromV5Cluster(t *testing.T) {
+	min := int64(2)
+	cluster := &models.V5ClusterDetailsResponse{
+		ID:             "f01r4",
+		Name:           "My cluster",
+		Owner:          "acme",
+		ReleaseVersion: "11.0.0",
+		MasterNodes:    &models.V5ClusterDetailsResponseMasterNodes{HighAvailability: true, AvailabilityZones: []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}},
+		Labels:         map[string]string{"environment": "testing"},
+	}
+	nodePools := []*models.V5GetNodePoolsResponseItems{
+		{
+			ID:                "a7rc4",
+			Name:              "general",
+			AvailabilityZones: []string{"eu-west-1a"},
+			Scaling:           &models.V5GetNodePoolsResponseItemsScaling{Min: &min, Max: 5},
+			NodeSpec: &models.V5GetNodePoolsResponseItemsNodeSpec{
+				Aws: &models.V5GetNodePoolsResponseItemsNodeSpecAws{InstanceType: "m5.xlarge"},
+			},
+		},
+	}
+
+	ha := true
+	environment := "testing"
+	expected := &types.ClusterDefinitionV5{
+		APIVersion:     "v5",
+		Name:           "My cluster",
+		Owner:          "acme",
+		ReleaseVersion: "11.0.0",
+		MasterNodes:    &types.MasterNodes{HighAvailability: &ha, AvailabilityZones: []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}},
+		Labels:         map[string]*string{"environment": &environment},
+		NodePools: []*types.NodePoolDefinition{
+			{
+				Name:              "general",
+				AvailabilityZones: &types.AvailabilityZonesDefinition{Zones: []string{"eu-west-1a"}},
+				Scaling:           &types.NodePoolScalingDefinition{Min: toInt64Ptr(2), Max: 5},
+				NodeSpec:          &types.NodeSpec{AWS: &types.AWSSpecificDefinition{InstanceType: "m5.xlarge"}},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expected, FromV5Cluster(cluster, nodePools)); diff != "" {
+		t.Errorf("Definition unequal. (-expected +got):\n%s", diff)
+	}
+}
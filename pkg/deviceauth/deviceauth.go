@@ -0,0 +1,348 @@
+// Package deviceauth implements the OAuth2 Device Authorization Grant
+// (RFC 8628) against an OpenID Connect identity provider.
+//
+// Unlike the authorization code flow with PKCE, the device flow needs
+// neither a browser on the machine gsctl runs on nor a local callback
+// server. The user opens a verification URL on any device, enters the
+// user code shown, and gsctl polls the token endpoint until the user has
+// approved or denied the request. This makes SSO usable on jump hosts and
+// in containers.
+//
+// Endpoints are found via OpenID Connect discovery, so any provider
+// supporting the device flow can be used, including a fake one in tests.
+// The ID token received is not verified here. Callers verify it the same way
+// as ID tokens obtained via PKCE, i. e. using oidc.ParseIDToken.
+package deviceauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// DefaultIssuer is the issuer URL of Giant Swarm's identity provider.
+	DefaultIssuer = "https://giantswarm.eu.auth0.com/"
+
+	// DefaultClientID is the client ID of gsctl at Giant Swarm's identity
+	// provider. It is the same one used for SSO via PKCE.
+	DefaultClientID = "zQiFLUnrTFQwrybYzeY53hWWfhOKWRAU"
+
+	// DefaultScope is requested if no scope is configured. offline_access
+	// makes the provider issue a refresh token.
+	DefaultScope = "openid email profile user_metadata https://giantswarm.io offline_access"
+
+	// GrantType is the grant type used to poll for the token.
+	GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultInterval is the polling interval used if the provider does
+	// not specify one, as defined in RFC 8628 section 3.2.
+	defaultInterval = 5 * time.Second
+
+	// slowDownIncrement is added to the polling interval whenever the
+	// provider responds with 'slow_down'.
+	slowDownIncrement = 5 * time.Second
+
+	// maxResponseSize limits the size of responses read.
+	maxResponseSize = 1024 * 1024
+)
+
+// Config is the configuration for a Flow.
+type Config struct {
+	// Issuer is the identity provider's issuer URL. The discovery document
+	// is expected under <Issuer>/.well-known/openid-configuration.
+	// Defaults to DefaultIssuer.
+	Issuer string
+	// ClientID identifies gsctl at the identity provider. Defaults to
+	// DefaultClientID.
+	ClientID string
+	// Scope is the space separated list of scopes requested. Defaults to
+	// DefaultScope.
+	Scope string
+	// Audience is the API the access token is requested for, i. e. the
+	// API endpoint URL.
+	Audience string
+	// HTTPClient is used for all requests. Defaults to a client with a
+	// timeout of 30 seconds.
+	HTTPClient *http.Client
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+	// Sleep waits between polls. Defaults to time.Sleep.
+	Sleep func(time.Duration)
+}
+
+// Flow executes the device authorization flow.
+type Flow struct {
+	issuer     string
+	clientID   string
+	scope      string
+	audience   string
+	httpClient *http.Client
+	now        func() time.Time
+	sleep      func(time.Duration)
+
+	provider *providerMetadata
+}
+
+// providerMetadata holds the parts of the discovery document we use.
+type providerMetadata struct {
+	Issuer                      string `json:"issuer"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// Authorization is the device authorization response. The user has to
+// open VerificationURI and enter UserCode, or open VerificationURIComplete
+// if given.
+type Authorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+
+	// VerificationURL is used instead of VerificationURI by some
+	// providers implementing a draft version of the standard.
+	VerificationURL string `json:"verification_url"`
+}
+
+// Token is the token response received after the user approved the
+// request.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// errorResponse is the error response defined in RFC 6749 section 5.2.
+type errorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// New creates a new Flow.
+func New(config Config) (*Flow, error) {
+	f := &Flow{
+		issuer:     strings.TrimSuffix(config.Issuer, "/"),
+		clientID:   config.ClientID,
+		scope:      config.Scope,
+		audience:   config.Audience,
+		httpClient: config.HTTPClient,
+		now:        config.Now,
+		sleep:      config.Sleep,
+	}
+
+	if f.issuer == "" {
+		f.issuer = strings.TrimSuffix(DefaultIssuer, "/")
+	}
+	if f.clientID == "" {
+		f.clientID = DefaultClientID
+	}
+	if f.scope == "" {
+		f.scope = DefaultScope
+	}
+	if f.httpClient == nil {
+		f.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if f.now == nil {
+		f.now = time.Now
+	}
+	if f.sleep == nil {
+		f.sleep = time.Sleep
+	}
+
+	u, err := url.Parse(f.issuer)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, microerror.Maskf(invalidConfigError, "issuer %q is not a valid URL", config.Issuer)
+	}
+
+	return f, nil
+}
+
+// Discover fetches the provider's discovery document. It is called by
+// Authorize if needed.
+func (f *Flow) Discover() error {
+	if f.provider != nil {
+		return nil
+	}
+
+	res, err := f.httpClient.Get(f.issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return microerror.Maskf(discoveryFailedError, err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return microerror.Maskf(discoveryFailedError, "unexpected status %s", res.Status)
+	}
+
+	p := &providerMetadata{}
+	err = json.NewDecoder(http.MaxBytesReader(nil, res.Body, maxResponseSize)).Decode(p)
+	if err != nil {
+		return microerror.Maskf(discoveryFailedError, "could not parse discovery document: %s", err.Error())
+	}
+
+	if p.DeviceAuthorizationEndpoint == "" {
+		return microerror.Maskf(notSupportedError, "the identity provider %s does not offer a device authorization endpoint", f.issuer)
+	}
+	if p.TokenEndpoint == "" {
+		return microerror.Maskf(discoveryFailedError, "the discovery document lacks the token endpoint")
+	}
+	if strings.TrimSuffix(p.Issuer, "/") != f.issuer {
+		return microerror.Maskf(discoveryFailedError, "the discovery document is for issuer %q instead of %q", p.Issuer, f.issuer)
+	}
+
+	f.provider = p
+
+	return nil
+}
+
+// Authorize requests a device code and user code.
+func (f *Flow) Authorize() (*Authorization, error) {
+	err := f.Discover()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	params := url.Values{}
+	params.Set("client_id", f.clientID)
+	params.Set("scope", f.scope)
+	if f.audience != "" {
+		params.Set("audience", f.audience)
+	}
+
+	a := &Authorization{}
+	status, body, err := f.postForm(f.provider.DeviceAuthorizationEndpoint, params)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if status != http.StatusOK {
+		return nil, microerror.Maskf(requestFailedError, "device authorization failed: %s", describeError(status, body))
+	}
+
+	err = json.Unmarshal(body, a)
+	if err != nil {
+		return nil, microerror.Maskf(requestFailedError, "could not parse device authorization response: %s", err.Error())
+	}
+	if a.VerificationURI == "" {
+		a.VerificationURI = a.VerificationURL
+	}
+	if a.DeviceCode == "" || a.UserCode == "" || a.VerificationURI == "" {
+		return nil, microerror.Maskf(requestFailedError, "the device authorization response is incomplete")
+	}
+
+	return a, nil
+}
+
+// Poll polls the token endpoint until the user approved or denied the
+// request, or the device code expired.
+func (f *Flow) Poll(a *Authorization) (*Token, error) {
+	err := f.Discover()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	interval := defaultInterval
+	if a.Interval > 0 {
+		interval = time.Duration(a.Interval) * time.Second
+	}
+
+	var deadline time.Time
+	if a.ExpiresIn > 0 {
+		deadline = f.now().Add(time.Duration(a.ExpiresIn) * time.Second)
+	}
+
+	params := url.Values{}
+	params.Set("grant_type", GrantType)
+	params.Set("device_code", a.DeviceCode)
+	params.Set("client_id", f.clientID)
+
+	for {
+		f.sleep(interval)
+
+		if !deadline.IsZero() && f.now().After(deadline) {
+			return nil, microerror.Maskf(expiredTokenError, "the user code %s has expired", a.UserCode)
+		}
+
+		status, body, err := f.postForm(f.provider.TokenEndpoint, params)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if status == http.StatusOK {
+			t := &Token{}
+			err = json.Unmarshal(body, t)
+			if err != nil {
+				return nil, microerror.Maskf(requestFailedError, "could not parse token response: %s", err.Error())
+			}
+			if t.AccessToken == "" {
+				return nil, microerror.Maskf(requestFailedError, "the token response contains no access token")
+			}
+
+			return t, nil
+		}
+
+		e := errorResponse{}
+		_ = json.Unmarshal(body, &e)
+
+		switch e.Error {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += slowDownIncrement
+			continue
+		case "access_denied":
+			return nil, microerror.Maskf(accessDeniedError, "the request was denied")
+		case "expired_token":
+			return nil, microerror.Maskf(expiredTokenError, "the user code %s has expired", a.UserCode)
+		}
+
+		return nil, microerror.Maskf(requestFailedError, "token request failed: %s", describeError(status, body))
+	}
+}
+
+// postForm sends a form encoded POST request and returns status and body.
+func (f *Flow) postForm(endpoint string, params url.Values) (int, []byte, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return 0, nil, microerror.Maskf(requestFailedError, err.Error())
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := f.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, microerror.Maskf(requestFailedError, err.Error())
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, res.Body, maxResponseSize))
+	if err != nil {
+		return 0, nil, microerror.Maskf(requestFailedError, err.Error())
+	}
+
+	return res.StatusCode, body, nil
+}
+
+// describeError returns the error description from an error response, or
+// the status code if the body is not an error response.
+func describeError(status int, body []byte) string {
+	e := errorResponse{}
+	if json.Unmarshal(body, &e) == nil && e.Error != "" {
+		if e.ErrorDescription != "" {
+			return fmt.Sprintf("%s (%s)", e.ErrorDescription, e.Error)
+		}
+		return e.Error
+	}
+
+	return fmt.Sprintf("unexpected status %d", status)
+}
@@ -0,0 +1,181 @@
+package deviceauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsctl/testutils/fakeoidc"
+)
+
+// newTestFlow returns a flow against the given server which does not sleep,
+// but records the intervals it would have slept for.
+func newTestFlow(t *testing.T, url, clientID string, slept *[]time.Duration) *Flow {
+	now := time.Now()
+	f, err := New(Config{
+		Issuer:   url,
+		ClientID: clientID,
+		Audience: "https://api.example.com",
+		Now:      func() time.Time { return now },
+		Sleep: func(d time.Duration) {
+			*slept = append(*slept, d)
+			now = now.Add(d)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+// TestFlow tests the complete flow against the fake identity provider.
+func TestFlow(t *testing.T) {
+	idp, err := fakeoidc.New(fakeoidc.Config{PendingPolls: 2, Email: "jane@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(idp)
+	defer ts.Close()
+
+	slept := []time.Duration{}
+	f := newTestFlow(t, ts.URL, fakeoidc.DefaultClientID, &slept)
+
+	a, err := f.Authorize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if a.UserCode != fakeoidc.DefaultUserCode {
+		t.Errorf("Expected user code %q, got %q", fakeoidc.DefaultUserCode, a.UserCode)
+	}
+	if a.VerificationURI != ts.URL+fakeoidc.VerificationPath {
+		t.Errorf("Unexpected verification URI %q", a.VerificationURI)
+	}
+
+	token, err := f.Poll(a)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if idp.Polls() != 3 || len(slept) != 3 || slept[0] != 5*time.Second {
+		t.Errorf("Expected 3 polls with 5s interval, got %d polls, slept %v", idp.Polls(), slept)
+	}
+	if token.AccessToken == "" || token.RefreshToken == "" {
+		t.Errorf("Expected access and refresh token, got %#v", token)
+	}
+
+	idToken, err := idp.ParseIDToken(token.IDToken)
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if idToken.Email != "jane@example.com" {
+		t.Errorf("Expected email 'jane@example.com', got %q", idToken.Email)
+	}
+}
+
+// TestFlowDenied tests the user denying the request.
+func TestFlowDenied(t *testing.T) {
+	idp, err := fakeoidc.New(fakeoidc.Config{Deny: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(idp)
+	defer ts.Close()
+
+	slept := []time.Duration{}
+	f := newTestFlow(t, ts.URL, fakeoidc.DefaultClientID, &slept)
+
+	a, err := f.Authorize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	_, err = f.Poll(a)
+	if !IsAccessDenied(err) {
+		t.Errorf("Expected accessDeniedError, got %#v", err)
+	}
+}
+
+// TestFlowExpired tests the device code expiring before the user approved.
+func TestFlowExpired(t *testing.T) {
+	idp, err := fakeoidc.New(fakeoidc.Config{PendingPolls: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(idp)
+	defer ts.Close()
+
+	slept := []time.Duration{}
+	f := newTestFlow(t, ts.URL, fakeoidc.DefaultClientID, &slept)
+
+	a, err := f.Authorize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	_, err = f.Poll(a)
+	if !IsExpiredToken(err) {
+		t.Errorf("Expected expiredTokenError, got %#v", err)
+	}
+	// expires_in is 900 seconds, polling every 5 seconds
+	if idp.Polls() != 180 {
+		t.Errorf("Expected 180 polls, got %d", idp.Polls())
+	}
+}
+
+// TestSlowDown tests that the polling interval is increased on 'slow_down'.
+func TestSlowDown(t *testing.T) {
+	polls := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Write([]byte(`{"issuer": "` + ts.URL + `", "device_authorization_endpoint": "` + ts.URL + `/device", "token_endpoint": "` + ts.URL + `/token"}`))
+		case "/token":
+			polls++
+			if polls == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "slow_down"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "access-token", "token_type": "Bearer"}`))
+		}
+	}))
+	defer ts.Close()
+
+	slept := []time.Duration{}
+	f := newTestFlow(t, ts.URL, "client", &slept)
+
+	_, err := f.Poll(&Authorization{DeviceCode: "code", UserCode: "code", Interval: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %#v", err)
+	}
+	if len(slept) != 2 || slept[0] != 2*time.Second || slept[1] != 7*time.Second {
+		t.Errorf("Expected intervals [2s 7s], got %v", slept)
+	}
+}
+
+// TestNotSupported tests a provider without device authorization endpoint.
+func TestNotSupported(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"issuer": "` + ts.URL + `", "token_endpoint": "` + ts.URL + `/token"}`))
+	}))
+	defer ts.Close()
+
+	slept := []time.Duration{}
+	f := newTestFlow(t, ts.URL, "client", &slept)
+
+	_, err := f.Authorize()
+	if !IsNotSupported(err) {
+		t.Errorf("Expected notSupportedError, got %#v", err)
+	}
+}
+
+// TestNewInvalidIssuer tests the validation of the issuer URL.
+func TestNewInvalidIssuer(t *testing.T) {
+	_, err := New(Config{Issuer: "not a url"})
+	if !IsInvalidConfig(err) {
+		t.Errorf("Expected invalidConfigError, got %#v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package deviceauth
+
+import "github.com/giantswarm/microerror"
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var discoveryFailedError = &microerror.Error{
+	Kind: "discoveryFailedError",
+}
+
+// IsDiscoveryFailed asserts discoveryFailedError.
+func IsDiscoveryFailed(err error) bool {
+	return microerror.Cause(err) == discoveryFailedError
+}
+
+var notSupportedError = &microerror.Error{
+	Kind: "notSupportedError",
+}
+
+// IsNotSupported asserts notSupportedError.
+func IsNotSupported(err error) bool {
+	return microerror.Cause(err) == notSupportedError
+}
+
+var requestFailedError = &microerror.Error{
+	Kind: "requestFailedError",
+}
+
+// IsRequestFailed asserts requestFailedError.
+func IsRequestFailed(err error) bool {
+	return microerror.Cause(err) == requestFailedError
+}
+
+var accessDeniedError = &microerror.Error{
+	Kind: "accessDeniedError",
+}
+
+// IsAccessDenied asserts accessDeniedError.
+func IsAccessDenied(err error) bool {
+	return microerror.Cause(err) == accessDeniedError
+}
+
+var expiredTokenError = &microerror.Error{
+	Kind: "expiredTokenError",
+}
+
+// IsExpiredToken asserts expiredTokenError.
+func IsExpiredToken(err error) bool {
+	return microerror.Cause(err) == expiredTokenError
+}
@@ -0,0 +1,21 @@
+package execcredential
+
+import "github.com/giantswarm/microerror"
+
+var invalidCacheError = &microerror.Error{
+	Kind: "invalidCacheError",
+}
+
+// IsInvalidCache asserts invalidCacheError.
+func IsInvalidCache(err error) bool {
+	return microerror.Cause(err) == invalidCacheError
+}
+
+var notFoundError = &microerror.Error{
+	Kind: "notFoundError",
+}
+
+// IsNotFound asserts notFoundError.
+func IsNotFound(err error) bool {
+	return microerror.Cause(err) == notFoundError
+}
@@ -0,0 +1,207 @@
+// Package execcredential caches the client certificates handed to kubectl
+// by 'gsctl kubeconfig-credential', and formats them as ExecCredential.
+//
+// kubectl calls the exec plugin configured in the kubeconfig user entry
+// whenever it needs credentials, and uses them until the expiration
+// timestamp given. We cache key pairs on disk, so that not every kubectl
+// invocation creates a new key pair, and create a new one shortly before
+// the cached one expires.
+package execcredential
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+
+	"github.com/giantswarm/gsctl/pkg/kubeconfigfile"
+)
+
+const (
+	// APIVersion is the ExecCredential API version we implement.
+	APIVersion = "client.authentication.k8s.io/v1beta1"
+
+	// DirName is the name of the cache directory within the gsctl config
+	// directory.
+	DirName = "credentials"
+
+	// DefaultTTL is the default lifetime of the key pairs created.
+	DefaultTTL = 12 * time.Hour
+
+	// RefreshBefore is how long before expiry a credential is replaced.
+	RefreshBefore = 5 * time.Minute
+
+	// LockTimeout is the time to wait for another process creating a key
+	// pair for the same cluster.
+	LockTimeout = 30 * time.Second
+)
+
+// Credential is a client certificate and key for a cluster.
+type Credential struct {
+	Endpoint              string    `json:"endpoint"`
+	ClusterID             string    `json:"cluster_id"`
+	KeyPairID             string    `json:"key_pair_id"`
+	ClientCertificateData string    `json:"client_certificate_data"`
+	ClientKeyData         string    `json:"client_key_data"`
+	Expiry                time.Time `json:"expiry"`
+}
+
+// New returns a credential for a key pair just created. The expiry is taken
+// from the certificate, falling back to the TTL given.
+func New(endpoint, clusterID, keyPairID, certificateData, keyData string, ttl time.Duration, now time.Time) *Credential {
+	c := &Credential{
+		Endpoint:              endpoint,
+		ClusterID:             clusterID,
+		KeyPairID:             keyPairID,
+		ClientCertificateData: certificateData,
+		ClientKeyData:         keyData,
+		Expiry:                now.Add(ttl),
+	}
+
+	if cert, err := kubeconfigfile.ParseCertificate([]byte(certificateData)); err == nil {
+		c.Expiry = cert.NotAfter
+	}
+
+	return c
+}
+
+// FilePath returns the cache file path for a cluster. The endpoint is part
+// of the name, as cluster IDs are only unique per installation.
+func FilePath(dir, endpoint, clusterID string) string {
+	sum := sha256.Sum256([]byte(strings.TrimRight(endpoint, "/")))
+	return path.Join(dir, clusterID+"-"+hex.EncodeToString(sum[:])[:8]+".json")
+}
+
+// Files returns the paths of all cache files, sorted. If clusterID is not
+// empty, only the files for that cluster are returned.
+func Files(fs afero.Fs, dir, clusterID string) ([]string, error) {
+	infos, err := afero.ReadDir(fs, dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var paths []string
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".json") {
+			continue
+		}
+		if clusterID != "" && FileClusterID(info.Name()) != clusterID {
+			continue
+		}
+		paths = append(paths, path.Join(dir, info.Name()))
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// FileClusterID returns the cluster ID from a cache file name.
+func FileClusterID(filePath string) string {
+	return strings.SplitN(path.Base(filePath), "-", 2)[0]
+}
+
+// Load reads the cached credential for a cluster.
+func Load(fs afero.Fs, dir, endpoint, clusterID string) (*Credential, error) {
+	p := FilePath(dir, endpoint, clusterID)
+
+	data, err := afero.ReadFile(fs, p)
+	if os.IsNotExist(err) {
+		return nil, microerror.Maskf(notFoundError, "no credential cached for cluster %s", clusterID)
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	c := &Credential{}
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return nil, microerror.Maskf(invalidCacheError, "%s could not be parsed: %s", p, err.Error())
+	}
+
+	return c, nil
+}
+
+// Store writes a credential to the cache. The file is replaced atomically
+// and readable by the user only, as it contains a private key.
+func Store(fs afero.Fs, dir string, c *Credential) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = fs.MkdirAll(dir, 0700)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	p := FilePath(dir, c.Endpoint, c.ClusterID)
+	err = afero.WriteFile(fs, p+".tmp", data, 0600)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	err = fs.Rename(p+".tmp", p)
+	if err != nil {
+		_ = fs.Remove(p + ".tmp")
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// Lock locks the cache file for a cluster, so that kubectl processes started
+// in parallel don't all create a key pair. Callers load the cache again after
+// locking. The returned function releases the lock.
+func Lock(fs afero.Fs, dir, endpoint, clusterID string, timeout time.Duration) (func() error, error) {
+	err := fs.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	lockPath, err := kubeconfigfile.LockFile(fs, FilePath(dir, endpoint, clusterID), timeout)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return func() error { return fs.Remove(lockPath) }, nil
+}
+
+// Valid returns true if the credential can still be used at the given time.
+func (c *Credential) Valid(now time.Time) bool {
+	return now.Add(RefreshBefore).Before(c.Expiry)
+}
+
+// ExecCredential returns the credential in the format kubectl expects on
+// the plugin's STDOUT. kubectl calls the plugin again when the expiration
+// timestamp has passed, which is shortly before the certificate expires.
+func (c *Credential) ExecCredential() ([]byte, error) {
+	expiry := metav1.NewTime(c.Expiry.Add(-RefreshBefore).UTC())
+
+	execCredential := clientauthv1beta1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       "ExecCredential",
+		},
+		Status: &clientauthv1beta1.ExecCredentialStatus{
+			ExpirationTimestamp:   &expiry,
+			ClientCertificateData: c.ClientCertificateData,
+			ClientKeyData:         c.ClientKeyData,
+		},
+	}
+
+	data, err := json.Marshal(execCredential)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return data, nil
+}
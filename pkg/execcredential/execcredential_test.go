@@ -0,0 +1,114 @@
+package execcredential
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/giantswarm/gsctl/testutils"
+)
+
+// TestNew tests that the expiry is taken from the certificate.
+func TestNew(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	notAfter := now.Add(3 * time.Hour)
+
+	c := New("https://api.example.com", "abc12", "id", testutils.CertificatePEM(t, notAfter), "key", 12*time.Hour, now)
+	if !c.Expiry.Equal(notAfter) {
+		t.Errorf("Expected expiry %s, got %s", notAfter, c.Expiry)
+	}
+
+	c = New("https://api.example.com", "abc12", "id", "not a certificate", "key", 12*time.Hour, now)
+	if !c.Expiry.Equal(now.Add(12 * time.Hour)) {
+		t.Errorf("Expected expiry from TTL, got %s", c.Expiry)
+	}
+
+	if c.Valid(now.Add(12*time.Hour - RefreshBefore)) {
+		t.Error("Expected credential not to be valid shortly before expiry")
+	}
+	if !c.Valid(now.Add(11 * time.Hour)) {
+		t.Error("Expected credential to be valid")
+	}
+}
+
+// TestStoreLoad tests the cache files.
+func TestStoreLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := Load(fs, "/credentials", "https://api.example.com", "abc12")
+	if !IsNotFound(err) {
+		t.Errorf("Expected notFoundError, got %#v", err)
+	}
+
+	a := New("https://api.example.com", "abc12", "id-a", "cert", "key", time.Hour, now)
+	b := New("https://other.example.com/", "abc12", "id-b", "cert", "key", time.Hour, now)
+	for _, c := range []*Credential{a, b} {
+		err = Store(fs, "/credentials", c)
+		if err != nil {
+			t.Fatalf("Unexpected error %#v", err)
+		}
+	}
+
+	loaded, err := Load(fs, "/credentials", "https://api.example.com/", "abc12")
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if diff := cmp.Diff(a, loaded); diff != "" {
+		t.Errorf("Credential not as expected: (-want +got):\n%s", diff)
+	}
+
+	files, _ := Files(fs, "/credentials", "abc12")
+	if len(files) != 2 || FileClusterID(files[0]) != "abc12" {
+		t.Errorf("Unexpected files %v", files)
+	}
+	info, _ := fs.Stat(files[0])
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %s", info.Mode().Perm())
+	}
+	if files, _ := Files(fs, "/credentials", "def34"); len(files) != 0 {
+		t.Errorf("Expected no files for other clusters, got %v", files)
+	}
+
+	_ = afero.WriteFile(fs, FilePath("/credentials", "https://api.example.com", "abc12"), []byte("{"), 0600)
+	_, err = Load(fs, "/credentials", "https://api.example.com", "abc12")
+	if !IsInvalidCache(err) {
+		t.Errorf("Expected invalidCacheError, got %#v", err)
+	}
+}
+
+// TestExecCredential tests the output read by kubectl.
+func TestExecCredential(t *testing.T) {
+	c := &Credential{
+		ClientCertificateData: "cert",
+		ClientKeyData:         "key",
+		Expiry:                time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	data, err := c.ExecCredential()
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	var got map[string]interface{}
+	err = json.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"apiVersion": "client.authentication.k8s.io/v1beta1",
+		"kind":       "ExecCredential",
+		"spec":       map[string]interface{}{},
+		"status": map[string]interface{}{
+			"clientCertificateData": "cert",
+			"clientKeyData":         "key",
+			"expirationTimestamp":   "2020-01-01T11:55:00Z",
+		},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("ExecCredential not as expected: (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,80 @@
+package kubeconfigfile
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"path/filepath"
+
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ClientCertificate returns the parsed client certificate of a user entry,
+// either from embedded data or from the referenced file. Relative file paths
+// are interpreted relative to the kubeconfig file.
+func ClientCertificate(fs afero.Fs, kubeconfigPath string, authInfo *clientcmdapi.AuthInfo) (*x509.Certificate, error) {
+	data := authInfo.ClientCertificateData
+	if len(data) == 0 {
+		if authInfo.ClientCertificate == "" {
+			return nil, microerror.Maskf(clientCertificateMissingError, "no client certificate configured")
+		}
+
+		certPath := resolvePath(kubeconfigPath, authInfo.ClientCertificate)
+
+		var err error
+		data, err = afero.ReadFile(fs, certPath)
+		if err != nil {
+			return nil, microerror.Maskf(invalidCertificateError, "could not read %s", certPath)
+		}
+	}
+
+	return ParseCertificate(data)
+}
+
+// ParseCertificate parses the first certificate from PEM data.
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, microerror.Maskf(invalidCertificateError, "no PEM encoded certificate found")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, microerror.Maskf(invalidCertificateError, err.Error())
+	}
+
+	return cert, nil
+}
+
+// ReferencedFiles returns the absolute paths of all certificate and key
+// files referenced by cluster and user entries.
+func ReferencedFiles(files []*File) map[string]bool {
+	referenced := map[string]bool{}
+
+	add := func(kubeconfigPath, p string) {
+		if p != "" {
+			referenced[resolvePath(kubeconfigPath, p)] = true
+		}
+	}
+
+	for _, f := range files {
+		for _, c := range f.Config.Clusters {
+			add(f.Path, c.CertificateAuthority)
+		}
+		for _, a := range f.Config.AuthInfos {
+			add(f.Path, a.ClientCertificate)
+			add(f.Path, a.ClientKey)
+		}
+	}
+
+	return referenced
+}
+
+// resolvePath interprets p relative to the kubeconfig file's directory.
+func resolvePath(kubeconfigPath, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(filepath.Dir(kubeconfigPath), p)
+}
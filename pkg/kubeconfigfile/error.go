@@ -0,0 +1,57 @@
+package kubeconfigfile
+
+import "github.com/giantswarm/microerror"
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var invalidKubeconfigError = &microerror.Error{
+	Kind: "invalidKubeconfigError",
+}
+
+// IsInvalidKubeconfig asserts invalidKubeconfigError.
+func IsInvalidKubeconfig(err error) bool {
+	return microerror.Cause(err) == invalidKubeconfigError
+}
+
+var lockedError = &microerror.Error{
+	Kind: "lockedError",
+}
+
+// IsLocked asserts lockedError.
+func IsLocked(err error) bool {
+	return microerror.Cause(err) == lockedError
+}
+
+var writeError = &microerror.Error{
+	Kind: "writeError",
+}
+
+// IsWrite asserts writeError.
+func IsWrite(err error) bool {
+	return microerror.Cause(err) == writeError
+}
+
+var clientCertificateMissingError = &microerror.Error{
+	Kind: "clientCertificateMissingError",
+}
+
+// IsClientCertificateMissing asserts clientCertificateMissingError.
+func IsClientCertificateMissing(err error) bool {
+	return microerror.Cause(err) == clientCertificateMissingError
+}
+
+var invalidCertificateError = &microerror.Error{
+	Kind: "invalidCertificateError",
+}
+
+// IsInvalidCertificate asserts invalidCertificateError.
+func IsInvalidCertificate(err error) bool {
+	return microerror.Cause(err) == invalidCertificateError
+}
@@ -0,0 +1,79 @@
+package kubeconfigfile
+
+import (
+	"sort"
+	"strings"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// NamePrefix is the prefix of the cluster, user and context names
+	// created by 'gsctl create kubeconfig'.
+	NamePrefix = "giantswarm-"
+
+	userSuffix = "-user"
+)
+
+// ClusterEntries are the entries 'gsctl create kubeconfig' adds for one
+// cluster. Names are empty if the entry does not exist.
+type ClusterEntries struct {
+	ClusterID    string
+	ClusterName  string
+	AuthInfoName string
+	// ContextNames are all contexts using the cluster entry, including
+	// the ones with a custom name.
+	ContextNames []string
+}
+
+// FindClusterEntries returns the entries of all Giant Swarm clusters in the
+// given configuration, sorted by cluster ID.
+func FindClusterEntries(c *clientcmdapi.Config) []*ClusterEntries {
+	byID := map[string]*ClusterEntries{}
+	get := func(clusterID string) *ClusterEntries {
+		if _, ok := byID[clusterID]; !ok {
+			byID[clusterID] = &ClusterEntries{ClusterID: clusterID}
+		}
+		return byID[clusterID]
+	}
+
+	for name := range c.Clusters {
+		if strings.HasPrefix(name, NamePrefix) {
+			get(strings.TrimPrefix(name, NamePrefix)).ClusterName = name
+		}
+	}
+	for name := range c.AuthInfos {
+		if strings.HasPrefix(name, NamePrefix) && strings.HasSuffix(name, userSuffix) {
+			clusterID := strings.TrimSuffix(strings.TrimPrefix(name, NamePrefix), userSuffix)
+			get(clusterID).AuthInfoName = name
+		}
+	}
+	for name, context := range c.Contexts {
+		if strings.HasPrefix(context.Cluster, NamePrefix) {
+			e := get(strings.TrimPrefix(context.Cluster, NamePrefix))
+			e.ContextNames = append(e.ContextNames, name)
+		}
+	}
+
+	entries := make([]*ClusterEntries, 0, len(byID))
+	for _, e := range byID {
+		sort.Strings(e.ContextNames)
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ClusterID < entries[j].ClusterID })
+
+	return entries
+}
+
+// DeleteClusterEntries removes the given entries from all files.
+func (k *Kubeconfig) DeleteClusterEntries(e *ClusterEntries) {
+	for _, name := range e.ContextNames {
+		k.DeleteContext(name)
+	}
+	if e.AuthInfoName != "" {
+		k.DeleteAuthInfo(e.AuthInfoName)
+	}
+	if e.ClusterName != "" {
+		k.DeleteCluster(e.ClusterName)
+	}
+}
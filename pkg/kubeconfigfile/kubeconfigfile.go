@@ -0,0 +1,450 @@
+// Package kubeconfigfile loads, modifies and writes kubeconfig files the
+// way kubectl does, without needing the kubectl binary.
+//
+// Several files can be in use at the same time via the $KUBECONFIG
+// environment variable. Entries defined in several files are taken from the
+// first one. Changed entries are written back to the file defining them, new
+// entries go to the default file.
+//
+// Files are locked while in use, using a '<path>.lock' file like kubectl
+// does. Before a file is changed, its previous content is kept in
+// '<path>.bak'. Files are replaced atomically.
+package kubeconfigfile
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/spf13/afero"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultLockTimeout is the time to wait for other processes to release
+	// a lock, if not configured.
+	DefaultLockTimeout = 10 * time.Second
+
+	lockSuffix   = ".lock"
+	backupSuffix = ".bak"
+	tempSuffix   = ".tmp"
+
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+// Paths returns the kubeconfig file paths in order of precedence, given the
+// value of the $KUBECONFIG environment variable and the home directory.
+// Files don't have to exist.
+func Paths(kubeconfigEnv, homeDir string) []string {
+	if kubeconfigEnv == "" {
+		return []string{filepath.Join(homeDir, ".kube", "config")}
+	}
+
+	var paths []string
+	seen := map[string]bool{}
+	for _, p := range filepath.SplitList(kubeconfigEnv) {
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+
+	return paths
+}
+
+// Config is the configuration for Open.
+type Config struct {
+	// FileSystem is the file system the files are on.
+	FileSystem afero.Fs
+
+	// Paths are the kubeconfig files in order of precedence, see Paths.
+	Paths []string
+
+	// LockTimeout is the maximum time to wait for locks held by other
+	// processes. Defaults to DefaultLockTimeout.
+	LockTimeout time.Duration
+}
+
+// File is a single kubeconfig file.
+type File struct {
+	// Path is the file's path.
+	Path string
+
+	// Config is the parsed content. Missing files have an empty config.
+	Config *clientcmdapi.Config
+
+	// Exists is false if the file did not exist when loading.
+	Exists bool
+
+	changed bool
+}
+
+// Kubeconfig is a set of kubeconfig files, loaded and locked.
+type Kubeconfig struct {
+	fs    afero.Fs
+	files []*File
+	locks []string
+}
+
+// Open locks and loads the kubeconfig files. Close must be called to
+// release the locks.
+func Open(c Config) (*Kubeconfig, error) {
+	if c.FileSystem == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.FileSystem must not be empty", c)
+	}
+	if len(c.Paths) == 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Paths must not be empty", c)
+	}
+	if c.LockTimeout == 0 {
+		c.LockTimeout = DefaultLockTimeout
+	}
+
+	k := &Kubeconfig{fs: c.FileSystem}
+
+	for _, p := range c.Paths {
+		err := k.lock(p, c.LockTimeout)
+		if err != nil {
+			k.Close()
+			return nil, microerror.Mask(err)
+		}
+
+		f, err := load(c.FileSystem, p)
+		if err != nil {
+			k.Close()
+			return nil, microerror.Mask(err)
+		}
+		k.files = append(k.files, f)
+	}
+
+	return k, nil
+}
+
+// Load reads the kubeconfig files without locking them, e. g. to look up
+// contexts. Files which don't exist are skipped.
+func Load(fs afero.Fs, paths []string) ([]*File, error) {
+	var files []*File
+
+	for _, p := range paths {
+		f, err := load(fs, p)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if f.Exists {
+			files = append(files, f)
+		}
+	}
+
+	return files, nil
+}
+
+func load(fs afero.Fs, p string) (*File, error) {
+	data, err := afero.ReadFile(fs, p)
+	if os.IsNotExist(err) {
+		return &File{Path: p, Config: clientcmdapi.NewConfig()}, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	c, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, microerror.Maskf(invalidKubeconfigError, "%s could not be parsed: %s", p, err.Error())
+	}
+
+	return &File{Path: p, Config: c, Exists: true}, nil
+}
+
+// lock locks the given path, see LockFile.
+func (k *Kubeconfig) lock(p string, timeout time.Duration) error {
+	lockPath, err := LockFile(k.fs, p, timeout)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	k.locks = append(k.locks, lockPath)
+
+	return nil
+}
+
+// LockFile creates the lock file '<path>.lock' for the given path, waiting up
+// to timeout for other processes to remove it. It returns the lock file's
+// path, which the caller removes to release the lock.
+func LockFile(fs afero.Fs, p string, timeout time.Duration) (string, error) {
+	lockPath := p + lockSuffix
+	deadline := time.Now().Add(timeout)
+
+	err := fs.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	for {
+		// Not all afero file systems support O_EXCL, so we check first.
+		exists, err := afero.Exists(fs, lockPath)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+		if !exists {
+			f, err := fs.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+			if err == nil {
+				f.Close()
+				return lockPath, nil
+			}
+			if !os.IsExist(err) {
+				return "", microerror.Mask(err)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return "", microerror.Maskf(lockedError, "%s is locked by another process. If that's not the case, remove %s.", p, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Close releases the locks.
+func (k *Kubeconfig) Close() error {
+	var firstErr error
+	for _, lockPath := range k.locks {
+		err := k.fs.Remove(lockPath)
+		if err != nil && firstErr == nil {
+			firstErr = microerror.Mask(err)
+		}
+	}
+	k.locks = nil
+
+	return firstErr
+}
+
+// Files returns all files, including the ones which don't exist yet.
+func (k *Kubeconfig) Files() []*File {
+	return k.files
+}
+
+// Merged returns the effective configuration, as kubectl sees it.
+func (k *Kubeconfig) Merged() *clientcmdapi.Config {
+	return Merge(k.files)
+}
+
+// Merge combines the given files into the effective configuration. Entries
+// defined in several files are taken from the first one.
+func Merge(files []*File) *clientcmdapi.Config {
+	merged := clientcmdapi.NewConfig()
+
+	for _, f := range files {
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = f.Config.CurrentContext
+		}
+		for name, c := range f.Config.Clusters {
+			if _, ok := merged.Clusters[name]; !ok {
+				merged.Clusters[name] = c
+			}
+		}
+		for name, a := range f.Config.AuthInfos {
+			if _, ok := merged.AuthInfos[name]; !ok {
+				merged.AuthInfos[name] = a
+			}
+		}
+		for name, c := range f.Config.Contexts {
+			if _, ok := merged.Contexts[name]; !ok {
+				merged.Contexts[name] = c
+			}
+		}
+	}
+
+	return merged
+}
+
+// defaultFile returns the file new entries are written to. Like kubectl,
+// this is the first existing file, or the last one if none exists.
+func (k *Kubeconfig) defaultFile() *File {
+	for _, f := range k.files {
+		if f.Exists {
+			return f
+		}
+	}
+	return k.files[len(k.files)-1]
+}
+
+// fileDefining returns the first file for which has returns true, or the
+// default file.
+func (k *Kubeconfig) fileDefining(has func(c *clientcmdapi.Config) bool) *File {
+	for _, f := range k.files {
+		if has(f.Config) {
+			return f
+		}
+	}
+	return k.defaultFile()
+}
+
+// SetCluster adds or replaces a cluster entry.
+func (k *Kubeconfig) SetCluster(name string, cluster *clientcmdapi.Cluster) {
+	f := k.fileDefining(func(c *clientcmdapi.Config) bool {
+		_, ok := c.Clusters[name]
+		return ok
+	})
+	f.Config.Clusters[name] = cluster
+	f.changed = true
+}
+
+// SetAuthInfo adds or replaces a user entry.
+func (k *Kubeconfig) SetAuthInfo(name string, authInfo *clientcmdapi.AuthInfo) {
+	f := k.fileDefining(func(c *clientcmdapi.Config) bool {
+		_, ok := c.AuthInfos[name]
+		return ok
+	})
+	f.Config.AuthInfos[name] = authInfo
+	f.changed = true
+}
+
+// SetContext adds or replaces a context entry.
+func (k *Kubeconfig) SetContext(name string, context *clientcmdapi.Context) {
+	f := k.fileDefining(func(c *clientcmdapi.Config) bool {
+		_, ok := c.Contexts[name]
+		return ok
+	})
+	f.Config.Contexts[name] = context
+	f.changed = true
+}
+
+// UseContext sets the current context, in the file where it's currently set.
+func (k *Kubeconfig) UseContext(name string) {
+	f := k.fileDefining(func(c *clientcmdapi.Config) bool {
+		return c.CurrentContext != ""
+	})
+	f.Config.CurrentContext = name
+	f.changed = true
+}
+
+// AuthInfo returns a user entry and the file defining it, or nil if there
+// is no such entry.
+func (k *Kubeconfig) AuthInfo(name string) (*clientcmdapi.AuthInfo, *File) {
+	for _, f := range k.files {
+		if a, ok := f.Config.AuthInfos[name]; ok {
+			return a, f
+		}
+	}
+	return nil, nil
+}
+
+// DeleteCluster removes a cluster entry from all files.
+func (k *Kubeconfig) DeleteCluster(name string) {
+	for _, f := range k.files {
+		if _, ok := f.Config.Clusters[name]; ok {
+			delete(f.Config.Clusters, name)
+			f.changed = true
+		}
+	}
+}
+
+// DeleteAuthInfo removes a user entry from all files.
+func (k *Kubeconfig) DeleteAuthInfo(name string) {
+	for _, f := range k.files {
+		if _, ok := f.Config.AuthInfos[name]; ok {
+			delete(f.Config.AuthInfos, name)
+			f.changed = true
+		}
+	}
+}
+
+// DeleteContext removes a context entry from all files. If it is the
+// current context, the current context gets unset.
+func (k *Kubeconfig) DeleteContext(name string) {
+	for _, f := range k.files {
+		if _, ok := f.Config.Contexts[name]; ok {
+			delete(f.Config.Contexts, name)
+			f.changed = true
+		}
+		if f.Config.CurrentContext == name {
+			f.Config.CurrentContext = ""
+			f.changed = true
+		}
+	}
+}
+
+// MarkChanged makes Save write the given file, after its Config has been
+// modified directly.
+func (k *Kubeconfig) MarkChanged(f *File) {
+	f.changed = true
+}
+
+// Save writes all changed files. The previous content of an existing file is
+// kept as backup.
+func (k *Kubeconfig) Save() error {
+	for _, f := range k.files {
+		if !f.changed {
+			continue
+		}
+
+		data, err := Marshal(f.Config)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		if f.Exists {
+			previous, err := afero.ReadFile(k.fs, f.Path)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			err = writeAtomically(k.fs, f.Path+backupSuffix, previous)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+		}
+
+		err = writeAtomically(k.fs, f.Path, data)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		f.Exists = true
+		f.changed = false
+	}
+
+	return nil
+}
+
+// writeAtomically writes a file via a temporary file, so that readers never
+// see partial content.
+func writeAtomically(fs afero.Fs, p string, data []byte) error {
+	err := afero.WriteFile(fs, p+tempSuffix, data, 0600)
+	if err != nil {
+		return microerror.Maskf(writeError, "%s could not be written: %s", p, err.Error())
+	}
+
+	err = fs.Rename(p+tempSuffix, p)
+	if err != nil {
+		_ = fs.Remove(p + tempSuffix)
+		return microerror.Maskf(writeError, "%s could not be written: %s", p, err.Error())
+	}
+
+	return nil
+}
+
+// Marshal serializes a kubeconfig as YAML in the v1 format.
+func Marshal(c *clientcmdapi.Config) ([]byte, error) {
+	v1Config := &clientcmdapiv1.Config{}
+	err := clientcmdlatest.Scheme.Convert(c, v1Config, nil)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	v1Config.APIVersion = clientcmdlatest.Version
+	v1Config.Kind = "Config"
+
+	// Keep the output stable.
+	sort.Slice(v1Config.Clusters, func(i, j int) bool { return v1Config.Clusters[i].Name < v1Config.Clusters[j].Name })
+	sort.Slice(v1Config.AuthInfos, func(i, j int) bool { return v1Config.AuthInfos[i].Name < v1Config.AuthInfos[j].Name })
+	sort.Slice(v1Config.Contexts, func(i, j int) bool { return v1Config.Contexts[i].Name < v1Config.Contexts[j].Name })
+
+	data, err := yaml.Marshal(v1Config)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return data, nil
+}
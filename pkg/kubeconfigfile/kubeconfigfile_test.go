@@ -0,0 +1,264 @@
+package kubeconfigfile
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	firstYAML = `apiVersion: v1
+kind: Config
+clusters:
+- name: giantswarm-abc12
+  cluster:
+    server: https://api.abc12.example.com
+contexts:
+- name: giantswarm-abc12
+  context:
+    cluster: giantswarm-abc12
+    user: giantswarm-abc12-user
+    namespace: monitoring
+users:
+- name: giantswarm-abc12-user
+  user:
+    token: old
+`
+	secondYAML = `apiVersion: v1
+kind: Config
+current-context: other
+clusters:
+- name: other
+  cluster:
+    server: https://other.example.com
+contexts:
+- name: other
+  context:
+    cluster: other
+    user: other
+users:
+- name: other
+  user:
+    token: other
+`
+)
+
+// TestPaths tests the handling of the $KUBECONFIG variable.
+func TestPaths(t *testing.T) {
+	var testCases = []struct {
+		env      string
+		expected []string
+	}{
+		{"", []string{"/home/user/.kube/config"}},
+		{"/a", []string{"/a"}},
+		{"/a:/b", []string{"/a", "/b"}},
+		{"/a::/b:/a", []string{"/a", "/b"}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			paths := Paths(tc.env, "/home/user")
+			if diff := cmp.Diff(tc.expected, paths); diff != "" {
+				t.Errorf("Case %d - Paths not as expected: (-want +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+// TestOpenSave tests that entries are written to the right files.
+func TestOpenSave(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/kube/first", []byte(firstYAML), 0600)
+	_ = afero.WriteFile(fs, "/kube/second", []byte(secondYAML), 0600)
+	paths := []string{"/kube/missing", "/kube/first", "/kube/second"}
+
+	k, err := Open(Config{FileSystem: fs, Paths: paths})
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	merged := k.Merged()
+	if merged.CurrentContext != "other" || len(merged.Clusters) != 2 {
+		t.Errorf("Unexpected merged config %#v", merged)
+	}
+
+	// Existing entry, defined in the first file.
+	context := merged.Contexts["giantswarm-abc12"].DeepCopy()
+	context.AuthInfo = "giantswarm-abc12-user"
+	k.SetContext("giantswarm-abc12", context)
+
+	// New entry, goes to the first existing file.
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = "https://api.def34.example.com"
+	k.SetCluster("giantswarm-def34", cluster)
+
+	// The current context is set in the second file.
+	k.UseContext("giantswarm-abc12")
+
+	err = k.Save()
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	err = k.Close()
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	files, err := Load(fs, paths)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+
+	first := files[0].Config
+	if first.Clusters["giantswarm-def34"] == nil || first.Contexts["giantswarm-abc12"].Namespace != "monitoring" {
+		t.Errorf("Unexpected first file %#v", first)
+	}
+	if first.CurrentContext != "" {
+		t.Errorf("Expected no current context in the first file, got %q", first.CurrentContext)
+	}
+	if files[1].Config.CurrentContext != "giantswarm-abc12" {
+		t.Errorf("Expected current context in the second file, got %q", files[1].Config.CurrentContext)
+	}
+
+	backup, _ := afero.ReadFile(fs, "/kube/first.bak")
+	if string(backup) != firstYAML {
+		t.Errorf("Expected backup of the first file, got %q", backup)
+	}
+	for _, p := range []string{"/kube/missing", "/kube/first.lock", "/kube/first.tmp"} {
+		if exists, _ := afero.Exists(fs, p); exists {
+			t.Errorf("Expected %s not to exist", p)
+		}
+	}
+	info, _ := fs.Stat("/kube/first")
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %s", info.Mode().Perm())
+	}
+}
+
+// TestOpenNew tests creating a file when none exists.
+func TestOpenNew(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	k, err := Open(Config{FileSystem: fs, Paths: []string{"/home/user/.kube/config"}})
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	defer k.Close()
+
+	k.SetContext("test", clientcmdapi.NewContext())
+	k.UseContext("test")
+	err = k.Save()
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	data, _ := afero.ReadFile(fs, "/home/user/.kube/config")
+	if !strings.Contains(string(data), "current-context: test") {
+		t.Errorf("Unexpected content %q", data)
+	}
+	if exists, _ := afero.Exists(fs, "/home/user/.kube/config.bak"); exists {
+		t.Error("Expected no backup for a new file")
+	}
+}
+
+// TestOpenErrors tests locking and parsing errors.
+func TestOpenErrors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/kube/locked.lock", []byte{}, 0600)
+	_ = afero.WriteFile(fs, "/kube/invalid", []byte("clusters: {"), 0600)
+
+	var testCases = []struct {
+		config       Config
+		errorMatcher func(error) bool
+	}{
+		{Config{Paths: []string{"/kube/config"}}, IsInvalidConfig},
+		{Config{FileSystem: fs}, IsInvalidConfig},
+		{Config{FileSystem: fs, Paths: []string{"/kube/locked"}, LockTimeout: 10 * time.Millisecond}, IsLocked},
+		{Config{FileSystem: fs, Paths: []string{"/kube/invalid"}}, IsInvalidKubeconfig},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			_, err := Open(tc.config)
+			if !tc.errorMatcher(err) {
+				t.Errorf("Case %d - Error did not match expected type. Got %#v", i, err)
+			}
+		})
+	}
+
+	// Locks taken before the error are released.
+	_, _ = Open(Config{FileSystem: fs, Paths: []string{"/kube/other", "/kube/invalid"}})
+	if exists, _ := afero.Exists(fs, "/kube/other.lock"); exists {
+		t.Error("Expected lock to be released")
+	}
+}
+
+// TestMarshal tests that the output is sorted and in the v1 format.
+func TestMarshal(t *testing.T) {
+	c := clientcmdapi.NewConfig()
+	for _, name := range []string{"b", "a"} {
+		cluster := clientcmdapi.NewCluster()
+		cluster.Server = "https://" + name
+		cluster.CertificateAuthorityData = []byte("ca")
+		c.Clusters[name] = cluster
+	}
+
+	data, err := Marshal(c)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+
+	s := string(data)
+	if !strings.Contains(s, "apiVersion: v1") || !strings.Contains(s, "kind: Config") || !strings.Contains(s, "certificate-authority-data: Y2E=") {
+		t.Errorf("Unexpected output:\n%s", s)
+	}
+	if strings.Index(s, "https://a") > strings.Index(s, "https://b") {
+		t.Errorf("Expected sorted clusters:\n%s", s)
+	}
+}
+
+// TestClusterEntries tests finding and deleting the entries of a cluster.
+func TestClusterEntries(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/kube/first", []byte(firstYAML), 0600)
+	_ = afero.WriteFile(fs, "/kube/second", []byte(strings.Replace(secondYAML, "current-context: other", "current-context: giantswarm-abc12", 1)), 0600)
+
+	k, err := Open(Config{FileSystem: fs, Paths: []string{"/kube/first", "/kube/second"}})
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	defer k.Close()
+
+	entries := FindClusterEntries(k.Merged())
+	expected := []*ClusterEntries{{
+		ClusterID:    "abc12",
+		ClusterName:  "giantswarm-abc12",
+		AuthInfoName: "giantswarm-abc12-user",
+		ContextNames: []string{"giantswarm-abc12"},
+	}}
+	if diff := cmp.Diff(expected, entries); diff != "" {
+		t.Fatalf("Entries not as expected: (-want +got):\n%s", diff)
+	}
+
+	k.DeleteClusterEntries(entries[0])
+	merged := k.Merged()
+	if len(merged.Clusters) != 1 || len(merged.AuthInfos) != 1 || len(merged.Contexts) != 1 || merged.CurrentContext != "" {
+		t.Errorf("Unexpected config after deletion %#v", merged)
+	}
+
+	referenced := ReferencedFiles([]*File{{Path: "/kube/config", Config: &clientcmdapi.Config{
+		Clusters:  map[string]*clientcmdapi.Cluster{"a": {CertificateAuthority: "certs/ca.crt"}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"a": {ClientCertificate: "/certs/client.crt", ClientKey: "/certs/client.key"}},
+	}}})
+	if diff := cmp.Diff(map[string]bool{"/kube/certs/ca.crt": true, "/certs/client.crt": true, "/certs/client.key": true}, referenced); diff != "" {
+		t.Errorf("Referenced files not as expected: (-want +got):\n%s", diff)
+	}
+}
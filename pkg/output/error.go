@@ -0,0 +1,23 @@
+package output
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+var invalidFormatError = &microerror.Error{
+	Kind: "invalidFormatError",
+}
+
+// IsInvalidFormat asserts invalidFormatError.
+func IsInvalidFormat(err error) bool {
+	return microerror.Cause(err) == invalidFormatError
+}
+
+var executionFailedError = &microerror.Error{
+	Kind: "executionFailedError",
+}
+
+// IsExecutionFailed asserts executionFailedError.
+func IsExecutionFailed(err error) bool {
+	return microerror.Cause(err) == executionFailedError
+}
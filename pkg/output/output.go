@@ -0,0 +1,373 @@
+// Package output renders command results in the format selected via the
+// --output flag of list and show commands.
+//
+// The formats 'table' and 'wide' are rendered by the commands themselves.
+// All other formats work on the JSON representation of the result, so field
+// names in expressions are the ones found in JSON output.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/giantswarm/columnize"
+	"github.com/giantswarm/microerror"
+	yaml "gopkg.in/yaml.v2"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/giantswarm/gsctl/formatting"
+)
+
+const (
+	// FormatTable is the default, human-friendly table output.
+	FormatTable = "table"
+	// FormatWide is table output with additional columns.
+	FormatWide = "wide"
+	// FormatJSON is JSON output.
+	FormatJSON = "json"
+	// FormatYAML is YAML output.
+	FormatYAML = "yaml"
+	// FormatName prints only the identifier of each item, one per line.
+	FormatName = "name"
+	// FormatCustomColumns prints a table with user-defined columns,
+	// e. g. 'custom-columns=ID:.id,NAME:.name'.
+	FormatCustomColumns = "custom-columns"
+	// FormatTemplate executes a Go template, e. g. 'template={{.id}}'.
+	FormatTemplate = "template"
+	// FormatJSONPath evaluates a JSONPath expression, e. g. 'jsonpath={.id}'.
+	FormatJSONPath = "jsonpath"
+
+	// naString is printed for fields without a value in custom columns.
+	naString = "n/a"
+)
+
+// FlagUsage is the help text for the --output flag.
+const FlagUsage = "Output format. One of: table, wide, json, yaml, name, custom-columns=<HEADER>:<jsonpath>[,...], template=<go-template>, jsonpath=<expression>."
+
+// Printer prints data in one of the structured formats.
+type Printer struct {
+	format string
+
+	columns  []customColumn
+	template *template.Template
+	jsonPath *jsonpath.JSONPath
+}
+
+type customColumn struct {
+	header   string
+	jsonPath *jsonpath.JSONPath
+}
+
+// NewPrinter parses the given output format and returns a Printer for it.
+// An empty format is treated as 'table'.
+func NewPrinter(format string) (*Printer, error) {
+	name, spec := format, ""
+	if i := strings.Index(format, "="); i >= 0 {
+		name, spec = format[:i], format[i+1:]
+	}
+
+	p := &Printer{format: name}
+
+	switch name {
+	case "":
+		p.format = FormatTable
+	case FormatTable, FormatWide, FormatJSON, FormatYAML, FormatName:
+		if spec != "" {
+			return nil, microerror.Maskf(invalidFormatError, "output format '%s' does not take an argument", name)
+		}
+	case FormatCustomColumns:
+		columns, err := parseCustomColumns(spec)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		p.columns = columns
+	case FormatTemplate:
+		if spec == "" {
+			return nil, microerror.Maskf(invalidFormatError, "please specify a template, e. g. 'template={{.id}}'")
+		}
+		t, err := template.New("output").Parse(spec)
+		if err != nil {
+			return nil, microerror.Maskf(invalidFormatError, "template could not be parsed: %s", err.Error())
+		}
+		p.template = t
+	case FormatJSONPath:
+		if spec == "" {
+			return nil, microerror.Maskf(invalidFormatError, "please specify an expression, e. g. 'jsonpath={.id}'")
+		}
+		jp, err := parseJSONPath(spec)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		p.jsonPath = jp
+	default:
+		return nil, microerror.Maskf(invalidFormatError, "output format '%s' is unknown", format)
+	}
+
+	return p, nil
+}
+
+// Format returns the name of the format, without arguments.
+func (p *Printer) Format() string {
+	return p.format
+}
+
+// IsTable returns true if the command should render a table itself,
+// which is the case for the 'table' and 'wide' formats.
+func (p *Printer) IsTable() bool {
+	return p.format == FormatTable || p.format == FormatWide
+}
+
+// IsTableFormat returns true if the given format is rendered as a table by
+// the command itself. Invalid formats are not considered table formats.
+func IsTableFormat(format string) bool {
+	p, err := NewPrinter(format)
+	return err == nil && p.IsTable()
+}
+
+// IsWideFormat returns true if the given format is 'wide'.
+func IsWideFormat(format string) bool {
+	p, err := NewPrinter(format)
+	return err == nil && p.IsWide()
+}
+
+// NoticeWriter returns the writer for informational messages like
+// deprecation notices. For structured formats this is stderr, so that
+// stdout only contains the machine-readable result.
+func NoticeWriter(format string) io.Writer {
+	p, err := NewPrinter(format)
+	if err == nil && !p.IsTable() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// IsWide returns true for the 'wide' format.
+func (p *Printer) IsWide() bool {
+	return p.format == FormatWide
+}
+
+// Print writes data in the printer's format. namePath is a JSONPath
+// expression selecting the identifier printed in 'name' format, e. g. ".id".
+func (p *Printer) Print(w io.Writer, data interface{}, namePath string) error {
+	// Print empty lists as such, not as null.
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		data = []interface{}{}
+	}
+
+	if p.format == FormatJSON {
+		out, err := json.MarshalIndent(data, formatting.OutputJSONPrefix, formatting.OutputJSONIndent)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		fmt.Fprintln(w, string(out))
+		return nil
+	}
+
+	generic, err := toGeneric(data)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	switch p.format {
+	case FormatYAML:
+		out, err := yaml.Marshal(yamlNumbers(generic))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		fmt.Fprint(w, string(out))
+	case FormatName:
+		jp, err := parseJSONPath(namePath)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		for _, item := range items(generic) {
+			err = jp.Execute(w, item)
+			if err != nil {
+				return microerror.Maskf(executionFailedError, err.Error())
+			}
+			fmt.Fprintln(w)
+		}
+	case FormatCustomColumns:
+		return p.printCustomColumns(w, generic)
+	case FormatTemplate:
+		err = p.template.Execute(w, generic)
+		if err != nil {
+			return microerror.Maskf(executionFailedError, err.Error())
+		}
+	case FormatJSONPath:
+		err = p.jsonPath.Execute(w, generic)
+		if err != nil {
+			return microerror.Maskf(executionFailedError, err.Error())
+		}
+		fmt.Fprintln(w)
+	default:
+		return microerror.Maskf(invalidFormatError, "output format '%s' must be rendered as a table", p.format)
+	}
+
+	return nil
+}
+
+// Sprint returns data in the printer's format, without a trailing newline.
+func (p *Printer) Sprint(data interface{}, namePath string) (string, error) {
+	var buf bytes.Buffer
+
+	err := p.Print(&buf, data, namePath)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func (p *Printer) printCustomColumns(w io.Writer, generic interface{}) error {
+	headers := make([]string, 0, len(p.columns))
+	for _, c := range p.columns {
+		headers = append(headers, c.header)
+	}
+
+	rows := []string{strings.Join(headers, "|")}
+
+	for _, item := range items(generic) {
+		fields := make([]string, 0, len(p.columns))
+		for _, c := range p.columns {
+			results, err := c.jsonPath.FindResults(item)
+			if err != nil {
+				return microerror.Maskf(executionFailedError, err.Error())
+			}
+
+			var values []string
+			for _, r := range results {
+				for _, v := range r {
+					values = append(values, stringValue(v.Interface()))
+				}
+			}
+
+			value := strings.Join(values, ",")
+			if value == "" {
+				value = naString
+			}
+			fields = append(fields, value)
+		}
+		rows = append(rows, strings.Join(fields, "|"))
+	}
+
+	config := columnize.DefaultConfig()
+	config.Glue = "   "
+	fmt.Fprintln(w, columnize.Format(rows, config))
+
+	return nil
+}
+
+// parseCustomColumns parses a column spec like 'ID:.id,NAME:.name'.
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	if spec == "" {
+		return nil, microerror.Maskf(invalidFormatError, "please specify columns, e. g. 'custom-columns=ID:.id,NAME:.name'")
+	}
+
+	var columns []customColumn
+	for _, part := range strings.Split(spec, ",") {
+		parts := strings.SplitN(part, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, microerror.Maskf(invalidFormatError, "custom column '%s' must have the form <HEADER>:<jsonpath>", part)
+		}
+
+		jp, err := parseJSONPath(parts[1])
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		columns = append(columns, customColumn{header: parts[0], jsonPath: jp})
+	}
+
+	return columns, nil
+}
+
+// parseJSONPath parses a JSONPath expression. Curly braces are optional,
+// so both '{.id}' and '.id' are accepted.
+func parseJSONPath(expression string) (*jsonpath.JSONPath, error) {
+	if !strings.Contains(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+
+	jp := jsonpath.New("output").AllowMissingKeys(true)
+	err := jp.Parse(expression)
+	if err != nil {
+		return nil, microerror.Maskf(invalidFormatError, "JSONPath expression '%s' could not be parsed: %s", expression, err.Error())
+	}
+
+	return jp, nil
+}
+
+// toGeneric converts data to its JSON representation in terms of maps,
+// slices, and basic types. Numbers are represented as json.Number, so they
+// are printed exactly as in JSON output.
+func toGeneric(data interface{}) (interface{}, error) {
+	j, err := json.Marshal(data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	decoder.UseNumber()
+
+	var generic interface{}
+	err = decoder.Decode(&generic)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return generic, nil
+}
+
+// yamlNumbers replaces json.Number values by int64 or float64 values,
+// as they would otherwise be marshalled to YAML as strings.
+func yamlNumbers(generic interface{}) interface{} {
+	switch value := generic.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	case map[string]interface{}:
+		for k, v := range value {
+			value[k] = yamlNumbers(v)
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = yamlNumbers(v)
+		}
+	}
+
+	return generic
+}
+
+// items returns the elements of a list, or the object itself as the only item.
+func items(generic interface{}) []interface{} {
+	if list, ok := generic.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{generic}
+}
+
+func stringValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case map[string]interface{}, []interface{}:
+		j, _ := json.Marshal(value)
+		return string(j)
+	default:
+		var buf bytes.Buffer
+		fmt.Fprint(&buf, value)
+		return buf.String()
+	}
+}
@@ -0,0 +1,148 @@
+package output
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testItem struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Workers int64             `json:"workers"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func TestNewPrinter(t *testing.T) {
+	var testCases = []struct {
+		format         string
+		expectedFormat string
+		errorMatcher   func(error) bool
+	}{
+		{"", FormatTable, nil},
+		{"table", FormatTable, nil},
+		{"wide", FormatWide, nil},
+		{"json", FormatJSON, nil},
+		{"yaml", FormatYAML, nil},
+		{"name", FormatName, nil},
+		{"custom-columns=ID:.id,NAME:.name", FormatCustomColumns, nil},
+		{"template={{.id}}", FormatTemplate, nil},
+		{"jsonpath={.id}", FormatJSONPath, nil},
+		{"xml", "", IsInvalidFormat},
+		{"json=foo", "", IsInvalidFormat},
+		{"custom-columns=", "", IsInvalidFormat},
+		{"custom-columns=ID", "", IsInvalidFormat},
+		{"template={{.id", "", IsInvalidFormat},
+		{"jsonpath=", "", IsInvalidFormat},
+		{"jsonpath={.id", "", IsInvalidFormat},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			p, err := NewPrinter(tc.format)
+			if tc.errorMatcher != nil {
+				if !tc.errorMatcher(err) {
+					t.Errorf("Case %d - Error did not match expectation. Got %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+			if p.Format() != tc.expectedFormat {
+				t.Errorf("Case %d - Expected format %q, got %q", i, tc.expectedFormat, p.Format())
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	list := []testItem{
+		{ID: "a1b2c", Name: "Production", Workers: 12, Labels: map[string]string{"env": "prod"}},
+		{ID: "x9y8z", Name: "Test", Workers: 1000000},
+	}
+
+	var testCases = []struct {
+		format   string
+		data     interface{}
+		expected string
+	}{
+		{
+			"json",
+			list[1],
+			`{
+  "id": "x9y8z",
+  "name": "Test",
+  "workers": 1000000
+}
+`,
+		},
+		{
+			"json",
+			[]testItem(nil),
+			"[]\n",
+		},
+		{
+			"yaml",
+			list,
+			`- id: a1b2c
+  labels:
+    env: prod
+  name: Production
+  workers: 12
+- id: x9y8z
+  name: Test
+  workers: 1000000
+`,
+		},
+		{
+			"name",
+			list,
+			"a1b2c\nx9y8z\n",
+		},
+		{
+			"custom-columns=ID:.id,WORKERS:.workers,ENV:.labels.env",
+			list,
+			`ID      WORKERS   ENV
+a1b2c   12        prod
+x9y8z   1000000   n/a
+`,
+		},
+		{
+			"template={{range .}}{{.name}}={{.workers}};{{end}}",
+			list,
+			"Production=12;Test=1000000;",
+		},
+		{
+			"jsonpath={.id}",
+			list[0],
+			"a1b2c\n",
+		},
+		{
+			"jsonpath={range [*]}{.id}{' '}{end}",
+			list,
+			"a1b2c x9y8z \n",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			p, err := NewPrinter(tc.format)
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+
+			var buf bytes.Buffer
+			err = p.Print(&buf, tc.data, ".id")
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+
+			if diff := cmp.Diff(tc.expected, buf.String()); diff != "" {
+				t.Errorf("Case %d - Output unequal. (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
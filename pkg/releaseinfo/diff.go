@@ -0,0 +1,353 @@
+package releaseinfo
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/fatih/color"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/util"
+)
+
+const kubernetesComponentName = "kubernetes"
+
+// ComponentChange describes how the version of a component differs between
+// two releases. FromVersion is empty for added components, ToVersion is
+// empty for removed components.
+type ComponentChange struct {
+	Name        string `json:"name"`
+	FromVersion string `json:"from_version,omitempty"`
+	ToVersion   string `json:"to_version,omitempty"`
+}
+
+// ChangelogEntry is a single changelog item of a release.
+type ChangelogEntry struct {
+	Version     string `json:"version"`
+	Component   string `json:"component"`
+	Description string `json:"description"`
+}
+
+// ReleaseDiff describes the differences between two releases.
+type ReleaseDiff struct {
+	From ReleaseData `json:"from"`
+	To   ReleaseData `json:"to"`
+
+	// Components contains changed, added and removed components only.
+	Components []ComponentChange `json:"components"`
+
+	// Changelog contains the entries of all releases after From up to and
+	// including To.
+	Changelog []ChangelogEntry `json:"changelog"`
+
+	// UpgradePath lists the releases to upgrade through, ending with To.
+	// It is empty if To is not newer than From.
+	UpgradePath []string `json:"upgrade_path"`
+}
+
+// Diff compares two releases.
+func (ri *ReleaseInfo) Diff(from, to string) (*ReleaseDiff, error) {
+	fromRelease, err := ri.getReleaseForVersion(from)
+	if err != nil {
+		return nil, microerror.Maskf(versionNotFoundError, "release %s", from)
+	}
+	toRelease, err := ri.getReleaseForVersion(to)
+	if err != nil {
+		return nil, microerror.Maskf(versionNotFoundError, "release %s", to)
+	}
+
+	d := &ReleaseDiff{
+		Components:  diffComponents(fromRelease.Components, toRelease.Components),
+		Changelog:   changelog(ri.releases, from, to),
+		UpgradePath: UpgradePath(ri.releases, from, to),
+	}
+
+	d.From, err = ri.releaseDataOrVersion(from)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	d.To, err = ri.releaseDataOrVersion(to)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return d, nil
+}
+
+// releaseDataOrVersion returns the release data, or only the version if the
+// release has no Kubernetes component.
+func (ri *ReleaseInfo) releaseDataOrVersion(version string) (ReleaseData, error) {
+	rd, err := ri.GetReleaseData(version)
+	if IsComponentNotFound(err) {
+		return ReleaseData{Version: version}, nil
+	} else if err != nil {
+		return ReleaseData{}, microerror.Mask(err)
+	}
+
+	return rd, nil
+}
+
+// UpgradePath returns the releases to upgrade through to get from one
+// release to another, ending with the target release. Releases are only
+// skipped if that is advisable, which is not the case if a major release
+// or a Kubernetes minor version would be skipped. Intermediate releases must
+// be active and production ready. If no intermediate release can be
+// reached in an advisable way, the next release in order is used. The
+// result is empty if to is not newer than from.
+func UpgradePath(releases []*models.V4ReleaseListItem, from, to string) []string {
+	if comp, err := util.CompareVersions(to, from); err != nil || comp < 1 {
+		return nil
+	}
+
+	byVersion := map[string]*models.V4ReleaseListItem{}
+	var candidates []string
+	for _, r := range releases {
+		if r.Version == nil {
+			continue
+		}
+		byVersion[*r.Version] = r
+
+		if r.Active && isProductionReady(*r.Version) {
+			candidates = append(candidates, *r.Version)
+		}
+	}
+
+	// Try the highest releases first.
+	sort.Slice(candidates, func(i, j int) bool {
+		return util.VersionSortComp(candidates[j], candidates[i])
+	})
+
+	var path []string
+	current := from
+	for !canSkipTo(byVersion, current, to) {
+		next := ""
+		lowest := ""
+		for _, candidate := range candidates {
+			if !isBetween(candidate, current, to) {
+				continue
+			}
+			lowest = candidate
+			if next == "" && canSkipTo(byVersion, current, candidate) {
+				next = candidate
+			}
+		}
+
+		// Without an advisable hop, take the smallest step possible.
+		if next == "" {
+			next = lowest
+		}
+		if next == "" {
+			break
+		}
+
+		path = append(path, next)
+		current = next
+	}
+
+	return append(path, to)
+}
+
+// canSkipTo returns true if upgrading directly from one release to another
+// is advisable.
+func canSkipTo(releases map[string]*models.V4ReleaseListItem, from, to string) bool {
+	fromVersion, err := semver.NewVersion(from)
+	if err != nil {
+		return true
+	}
+	toVersion, err := semver.NewVersion(to)
+	if err != nil {
+		return true
+	}
+
+	if toVersion.Major() > fromVersion.Major()+1 {
+		return false
+	}
+
+	fromK8s := componentVersion(releases[from], kubernetesComponentName)
+	toK8s := componentVersion(releases[to], kubernetesComponentName)
+	if fromK8s == nil || toK8s == nil {
+		return true
+	}
+
+	return toK8s.Major() != fromK8s.Major() || toK8s.Minor() <= fromK8s.Minor()+1
+}
+
+// componentVersion returns the version of the named component, or nil.
+func componentVersion(release *models.V4ReleaseListItem, name string) *semver.Version {
+	if release == nil {
+		return nil
+	}
+
+	for _, c := range release.Components {
+		if c.Name != nil && *c.Name == name && c.Version != nil {
+			v, err := semver.NewVersion(*c.Version)
+			if err != nil {
+				return nil
+			}
+			return v
+		}
+	}
+
+	return nil
+}
+
+// isBetween returns true if from < version < to.
+func isBetween(version, from, to string) bool {
+	return util.VersionSortComp(from, version) && util.VersionSortComp(version, to)
+}
+
+func isProductionReady(version string) bool {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+
+	return v.Prerelease() == "" && v.Metadata() == ""
+}
+
+// diffComponents returns the components differing between two releases,
+// sorted by name.
+func diffComponents(from, to []*models.V4ReleaseListItemComponentsItems) []ComponentChange {
+	fromVersions := componentVersions(from)
+	toVersions := componentVersions(to)
+
+	var names []string
+	for name := range fromVersions {
+		names = append(names, name)
+	}
+	for name := range toVersions {
+		if _, ok := fromVersions[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []ComponentChange{}
+	for _, name := range names {
+		if fromVersions[name] == toVersions[name] {
+			continue
+		}
+
+		changes = append(changes, ComponentChange{
+			Name:        name,
+			FromVersion: fromVersions[name],
+			ToVersion:   toVersions[name],
+		})
+	}
+
+	return changes
+}
+
+func componentVersions(components []*models.V4ReleaseListItemComponentsItems) map[string]string {
+	versions := map[string]string{}
+	for _, c := range components {
+		if c.Name != nil && c.Version != nil {
+			versions[*c.Name] = *c.Version
+		}
+	}
+
+	return versions
+}
+
+// changelog aggregates the changelogs of all production ready releases
+// after from up to and including to, in ascending order. If to is older
+// than from, the releases after to up to and including from are used.
+func changelog(releases []*models.V4ReleaseListItem, from, to string) []ChangelogEntry {
+	low, high := from, to
+	if util.VersionSortComp(to, from) {
+		low, high = to, from
+	}
+
+	var included []*models.V4ReleaseListItem
+	for _, r := range releases {
+		if r.Version == nil {
+			continue
+		}
+		if *r.Version != high && (!isProductionReady(*r.Version) || !isBetween(*r.Version, low, high)) {
+			continue
+		}
+
+		included = append(included, r)
+	}
+
+	sort.Slice(included, func(i, j int) bool {
+		return util.VersionSortComp(*included[i].Version, *included[j].Version)
+	})
+
+	entries := []ChangelogEntry{}
+	for _, r := range included {
+		for _, c := range r.Changelog {
+			entries = append(entries, ChangelogEntry{
+				Version:     *r.Version,
+				Component:   c.Component,
+				Description: c.Description,
+			})
+		}
+	}
+
+	return entries
+}
+
+// Print writes the differences in a human readable form.
+func (d *ReleaseDiff) Print(w io.Writer) {
+	fmt.Fprintf(w, "%s %s\n", color.YellowString("Kubernetes:"), formatKubernetesChange(d.From, d.To))
+
+	fmt.Fprintf(w, "%s\n", color.YellowString("Components:"))
+	if len(d.Components) == 0 {
+		fmt.Fprintln(w, "  no changes")
+	}
+	for _, c := range d.Components {
+		switch {
+		case c.FromVersion == "":
+			fmt.Fprintf(w, "  %s %s (added)\n", color.YellowString(c.Name+":"), c.ToVersion)
+		case c.ToVersion == "":
+			fmt.Fprintf(w, "  %s %s (removed)\n", color.YellowString(c.Name+":"), c.FromVersion)
+		default:
+			fmt.Fprintf(w, "  %s %s -> %s\n", color.YellowString(c.Name+":"), c.FromVersion, c.ToVersion)
+		}
+	}
+
+	fmt.Fprintf(w, "%s\n", color.YellowString("Changelog:"))
+	if len(d.Changelog) == 0 {
+		fmt.Fprintln(w, "  no entries")
+	}
+	version := ""
+	for _, entry := range d.Changelog {
+		if entry.Version != version {
+			version = entry.Version
+			fmt.Fprintf(w, "  %s\n", color.CyanString(version))
+		}
+		fmt.Fprintf(w, "    - %s: %s\n", entry.Component, entry.Description)
+	}
+
+	if len(d.UpgradePath) > 1 {
+		fmt.Fprintf(w, "%s\n", color.YellowString("Upgrade path:"))
+		fmt.Fprintf(w, "  %s\n", strings.Join(append([]string{d.From.Version}, d.UpgradePath...), " -> "))
+		fmt.Fprintf(w, "  Skipping releases is not advisable here, please upgrade through each of these releases.\n")
+	}
+}
+
+func formatKubernetesChange(from, to ReleaseData) string {
+	if from.K8sVersion == to.K8sVersion {
+		return formatKubernetesVersion(to) + " (unchanged)"
+	}
+
+	return formatKubernetesVersion(from) + " -> " + formatKubernetesVersion(to)
+}
+
+func formatKubernetesVersion(rd ReleaseData) string {
+	switch {
+	case rd.K8sVersion == "":
+		return "n/a"
+	case rd.IsK8sVersionEOL:
+		return fmt.Sprintf("%s (end of life)", rd.K8sVersion)
+	case rd.K8sVersionEOLDate != "":
+		return fmt.Sprintf("%s (end of life on %s)", rd.K8sVersion, rd.K8sVersionEOLDate)
+	}
+
+	return rd.K8sVersion
+}
@@ -0,0 +1,137 @@
+package releaseinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/giantswarm/gsctl/client"
+)
+
+func makeRelease(version, k8sVersion string, active bool) *models.V4ReleaseListItem {
+	return &models.V4ReleaseListItem{
+		Active:  active,
+		Version: toStringPtr(version),
+		Components: []*models.V4ReleaseListItemComponentsItems{
+			{
+				Name:    toStringPtr("kubernetes"),
+				Version: toStringPtr(k8sVersion),
+			},
+		},
+	}
+}
+
+func TestUpgradePath(t *testing.T) {
+	releases := []*models.V4ReleaseListItem{
+		makeRelease("9.0.0", "1.14.6", true),
+		makeRelease("9.1.0", "1.14.9", true),
+		makeRelease("10.0.0", "1.15.5", true),
+		makeRelease("10.1.0", "1.15.11", false),
+		makeRelease("11.0.0", "1.16.3", true),
+		makeRelease("11.1.0-beta", "1.17.0", true),
+		makeRelease("11.1.0", "1.16.9", true),
+		makeRelease("12.0.0", "1.17.2", true),
+	}
+
+	testCases := []struct {
+		from     string
+		to       string
+		expected []string
+	}{
+		// Patch and minor upgrades are direct.
+		{"9.0.0", "9.1.0", []string{"9.1.0"}},
+		{"11.0.0", "11.1.0", []string{"11.1.0"}},
+		// One Kubernetes minor version at a time.
+		{"9.0.0", "11.0.0", []string{"10.0.0", "11.0.0"}},
+		// Inactive and pre-releases are not used as hops.
+		{"9.1.0", "12.0.0", []string{"10.0.0", "11.1.0", "12.0.0"}},
+		// Major releases are not skipped.
+		{"9.0.0", "12.0.0", []string{"10.0.0", "11.1.0", "12.0.0"}},
+		// Downgrades and no-ops have no path.
+		{"12.0.0", "9.0.0", nil},
+		{"9.0.0", "9.0.0", nil},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.from+"-"+tc.to, func(t *testing.T) {
+			path := UpgradePath(releases, tc.from, tc.to)
+			if diff := cmp.Diff(tc.expected, path); diff != "" {
+				t.Errorf("Case %d - Unexpected path (-expected +got):\n%s", i, diff)
+			}
+		})
+	}
+}
+
+func TestReleaseInfo_Diff(t *testing.T) {
+	releases := []*models.V4ReleaseListItem{
+		makeRelease("1.0.0", "1.15.1", true),
+		makeRelease("1.1.0", "1.15.3", true),
+		makeRelease("2.0.0", "1.16.0", true),
+	}
+	releases[0].Components = append(releases[0].Components, &models.V4ReleaseListItemComponentsItems{Name: toStringPtr("calico"), Version: toStringPtr("3.9.0")})
+	releases[1].Components = append(releases[1].Components, &models.V4ReleaseListItemComponentsItems{Name: toStringPtr("calico"), Version: toStringPtr("3.9.0")})
+	releases[2].Components = append(releases[2].Components, &models.V4ReleaseListItemComponentsItems{Name: toStringPtr("coredns"), Version: toStringPtr("1.6.5")})
+	releases[1].Changelog = []*models.V4ReleaseListItemChangelogItems{{Component: "kubernetes", Description: "Updated to 1.15.3."}}
+	releases[2].Changelog = []*models.V4ReleaseListItemChangelogItems{{Component: "kubernetes", Description: "Updated to 1.16.0."}}
+
+	releasesResponse, err := json.Marshal(releases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v4/releases/":
+			w.WriteHeader(http.StatusOK)
+			w.Write(releasesResponse)
+		case "/v4/info/":
+			w.WriteHeader(http.StatusOK)
+			w.Write(makeInfoResponse(k8sVersionConfig{version: "1.15", eolDate: "2020-10-20"}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	clientWrapper, err := client.NewWithConfig(mockServer.URL, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	ri, err := New(Config{ClientWrapper: clientWrapper})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	d, err := ri.Diff("1.0.0", "2.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := &ReleaseDiff{
+		From: ReleaseData{Version: "1.0.0", K8sVersion: "1.15.1", K8sVersionEOLDate: "2020-10-20", IsK8sVersionEOL: true},
+		To:   ReleaseData{Version: "2.0.0", K8sVersion: "1.16.0"},
+		Components: []ComponentChange{
+			{Name: "calico", FromVersion: "3.9.0"},
+			{Name: "coredns", ToVersion: "1.6.5"},
+			{Name: "kubernetes", FromVersion: "1.15.1", ToVersion: "1.16.0"},
+		},
+		Changelog: []ChangelogEntry{
+			{Version: "1.1.0", Component: "kubernetes", Description: "Updated to 1.15.3."},
+			{Version: "2.0.0", Component: "kubernetes", Description: "Updated to 1.16.0."},
+		},
+		UpgradePath: []string{"2.0.0"},
+	}
+	if diff := cmp.Diff(expected, d); diff != "" {
+		t.Errorf("Unexpected diff (-expected +got):\n%s", diff)
+	}
+
+	_, err = ri.Diff("1.0.0", "3.0.0")
+	if !IsVersionNotFound(err) {
+		t.Errorf("Expected version not found error, got %#v", err)
+	}
+}
@@ -33,10 +33,10 @@ type ReleaseInfo struct {
 }
 
 type ReleaseData struct {
-	Version           string
-	K8sVersion        string
-	K8sVersionEOLDate string
-	IsK8sVersionEOL   bool
+	Version           string `json:"version"`
+	K8sVersion        string `json:"kubernetes_version"`
+	K8sVersionEOLDate string `json:"kubernetes_version_eol_date"`
+	IsK8sVersionEOL   bool   `json:"kubernetes_version_is_eol"`
 }
 
 func New(c Config) (*ReleaseInfo, error) {
@@ -69,7 +69,7 @@ func (ri *ReleaseInfo) GetReleaseData(version string) (ReleaseData, error) {
 		return ReleaseData{}, microerror.Mask(err)
 	}
 
-	k8sComponent, err := ri.getReleaseComponent("kubernetes", release.Components)
+	k8sComponent, err := ri.getReleaseComponent(kubernetesComponentName, release.Components)
 	if err != nil {
 		return ReleaseData{}, microerror.Mask(err)
 	}
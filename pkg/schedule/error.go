@@ -0,0 +1,21 @@
+package schedule
+
+import "github.com/giantswarm/microerror"
+
+var invalidWindowError = &microerror.Error{
+	Kind: "invalidWindowError",
+}
+
+// IsInvalidWindow asserts invalidWindowError.
+func IsInvalidWindow(err error) bool {
+	return microerror.Cause(err) == invalidWindowError
+}
+
+var windowClosedError = &microerror.Error{
+	Kind: "windowClosedError",
+}
+
+// IsWindowClosed asserts windowClosedError.
+func IsWindowClosed(err error) bool {
+	return microerror.Cause(err) == windowClosedError
+}
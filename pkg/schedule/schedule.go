@@ -0,0 +1,230 @@
+// Package schedule waits for a point in time or a recurring maintenance
+// window, e. g. to start a cluster upgrade.
+package schedule
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// MaxSleep is the longest time slept at once. Waiting in short steps
+	// makes sure that we notice when the system clock jumps, e. g. after
+	// the machine resumed from sleep.
+	MaxSleep = time.Minute
+
+	timeFormat = "Mon 2006-01-02 15:04 MST"
+)
+
+var windowRegex = regexp.MustCompile(`^(?:([A-Za-z]+)\s+)?(\d{1,2}):(\d{2})\s*-\s*(\d{1,2}):(\d{2})(?:\s+(\S+))?$`)
+
+// Window is a maintenance window recurring every week or every day.
+type Window struct {
+	// daily is true if the window is open every day, not only on weekday.
+	daily    bool
+	weekday  time.Weekday
+	start    time.Duration
+	duration time.Duration
+	location *time.Location
+	text     string
+}
+
+// ParseWindow parses a window in the format '[<weekday>] <HH:MM>-<HH:MM> [<time zone>]',
+// e. g. 'Sat 02:00-04:00 UTC'. Without weekday, the window recurs daily.
+// The time zone is UTC if not given. A window ending before its start
+// ends on the following day.
+func ParseWindow(s string) (*Window, error) {
+	text := strings.TrimSpace(s)
+	matches := windowRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return nil, microerror.Maskf(invalidWindowError, "'%s' does not match the format '[<weekday>] <HH:MM>-<HH:MM> [<time zone>]'", s)
+	}
+
+	w := &Window{
+		daily:    matches[1] == "",
+		location: time.UTC,
+		text:     text,
+	}
+
+	if !w.daily {
+		weekday, ok := parseWeekday(matches[1])
+		if !ok {
+			return nil, microerror.Maskf(invalidWindowError, "unknown weekday '%s'", matches[1])
+		}
+		w.weekday = weekday
+	}
+
+	start, err := parseTimeOfDay(matches[2], matches[3])
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	end, err := parseTimeOfDay(matches[4], matches[5])
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	w.start = start
+	w.duration = end - start
+	if w.duration <= 0 {
+		w.duration += 24 * time.Hour
+	}
+
+	if matches[6] != "" {
+		w.location, err = time.LoadLocation(matches[6])
+		if err != nil {
+			return nil, microerror.Maskf(invalidWindowError, "unknown time zone '%s'", matches[6])
+		}
+	}
+
+	return w, nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := d.String()
+		if strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return d, true
+		}
+	}
+
+	return 0, false
+}
+
+func parseTimeOfDay(hours, minutes string) (time.Duration, error) {
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	if h > 23 || m > 59 {
+		return 0, microerror.Maskf(invalidWindowError, "invalid time of day '%s:%s'", hours, minutes)
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// String returns the window as given.
+func (w *Window) String() string {
+	return w.text
+}
+
+// Next returns the period of the window that is open at t or, if the
+// window is closed at t, the next one.
+func (w *Window) Next(t time.Time) Period {
+	local := t.In(w.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, w.location)
+
+	// Start one day early, to find a window that opened yesterday and
+	// is still open.
+	for i := -1; i <= 7; i++ {
+		day := midnight.AddDate(0, 0, i)
+		if !w.daily && day.Weekday() != w.weekday {
+			continue
+		}
+
+		// time.Date instead of Add, so that DST changes are respected.
+		start := time.Date(day.Year(), day.Month(), day.Day(), int(w.start/time.Hour), int(w.start%time.Hour/time.Minute), 0, 0, w.location)
+		end := start.Add(w.duration)
+		if end.After(t) {
+			return Period{Start: start, End: end}
+		}
+	}
+
+	// Not reached, as the window recurs at least weekly.
+	return Period{}
+}
+
+// Period is a time span in which an action may start.
+type Period struct {
+	Start time.Time
+	// End is the time after which the action must not start anymore. Zero
+	// means there is no end.
+	End time.Time
+}
+
+// String returns a human readable representation.
+func (p Period) String() string {
+	if p.End.IsZero() {
+		return p.Start.Format(timeFormat)
+	}
+
+	return fmt.Sprintf("%s - %s", p.Start.Format(timeFormat), p.End.Format(timeFormat))
+}
+
+// Config is the configuration for a Waiter.
+type Config struct {
+	// Output receives progress messages. Defaults to os.Stdout.
+	Output io.Writer
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+	// Sleep pauses for the given duration. Defaults to time.Sleep.
+	Sleep func(time.Duration)
+}
+
+// Waiter waits for a period to start.
+type Waiter struct {
+	output io.Writer
+	now    func() time.Time
+	sleep  func(time.Duration)
+}
+
+// New creates a new Waiter.
+func New(config Config) *Waiter {
+	w := &Waiter{
+		output: config.Output,
+		now:    config.Now,
+		sleep:  config.Sleep,
+	}
+
+	if w.output == nil {
+		w.output = os.Stdout
+	}
+	if w.now == nil {
+		w.now = time.Now
+	}
+	if w.sleep == nil {
+		w.sleep = time.Sleep
+	}
+
+	return w
+}
+
+// Now returns the current time.
+func (w *Waiter) Now() time.Time {
+	return w.now()
+}
+
+// WaitForStart blocks until the period has started. It returns a
+// windowClosedError if the period has ended already.
+func (w *Waiter) WaitForStart(p Period) error {
+	if remaining := p.Start.Sub(w.now()); remaining > 0 {
+		fmt.Fprintf(w.output, "Waiting until %s (%s from now).\n", p.Start.Format(timeFormat), remaining.Round(time.Second))
+	}
+
+	for {
+		remaining := p.Start.Sub(w.now())
+		if remaining <= 0 {
+			break
+		}
+		if remaining > MaxSleep {
+			remaining = MaxSleep
+		}
+
+		w.sleep(remaining)
+	}
+
+	return w.CheckOpen(p)
+}
+
+// CheckOpen returns a windowClosedError if the period has ended.
+func (w *Waiter) CheckOpen(p Period) error {
+	if !p.End.IsZero() && !w.now().Before(p.End) {
+		return microerror.Maskf(windowClosedError, "ended at %s", p.End.Format(timeFormat))
+	}
+
+	return nil
+}
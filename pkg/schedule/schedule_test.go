@@ -0,0 +1,146 @@
+package schedule
+
+import (
+	"io/ioutil"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
+
+// TestParseWindow tests parsing of valid and invalid windows.
+func TestParseWindow(t *testing.T) {
+	var testCases = []struct {
+		input    string
+		valid    bool
+		daily    bool
+		weekday  time.Weekday
+		start    time.Duration
+		duration time.Duration
+		location string
+	}{
+		{"Sat 02:00-04:00 UTC", true, false, time.Saturday, 2 * time.Hour, 2 * time.Hour, "UTC"},
+		{"saturday 2:30 - 4:00", true, false, time.Saturday, 150 * time.Minute, 90 * time.Minute, "UTC"},
+		{"22:00-02:00 Europe/Berlin", true, true, time.Sunday, 22 * time.Hour, 4 * time.Hour, "Europe/Berlin"},
+		{"Sat", false, false, 0, 0, 0, ""},
+		{"Xyz 02:00-04:00", false, false, 0, 0, 0, ""},
+		{"Sat 25:00-04:00", false, false, 0, 0, 0, ""},
+		{"Sat 02:00-04:00 Mars/Olympus", false, false, 0, 0, 0, ""},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			w, err := ParseWindow(tc.input)
+			if !tc.valid {
+				if !IsInvalidWindow(err) {
+					t.Errorf("Case %d - Expected invalid window error, got %#v", i, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Case %d - Unexpected error %#v", i, err)
+			}
+
+			if w.daily != tc.daily || (!tc.daily && w.weekday != tc.weekday) {
+				t.Errorf("Case %d - Unexpected day daily=%v weekday=%s", i, w.daily, w.weekday)
+			}
+			if w.start != tc.start || w.duration != tc.duration {
+				t.Errorf("Case %d - Unexpected start %s or duration %s", i, w.start, w.duration)
+			}
+			if w.location.String() != tc.location {
+				t.Errorf("Case %d - Unexpected location %s", i, w.location)
+			}
+		})
+	}
+}
+
+// TestWindowNext tests finding the open or next period of a window.
+func TestWindowNext(t *testing.T) {
+	w, err := ParseWindow("Sat 02:00-04:00 UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var testCases = []struct {
+		now   string
+		start string
+	}{
+		// Wednesday
+		{"2020-09-16T12:00:00Z", "2020-09-19T02:00:00Z"},
+		// Saturday, before the window
+		{"2020-09-19T01:00:00Z", "2020-09-19T02:00:00Z"},
+		// Saturday, window open
+		{"2020-09-19T03:00:00Z", "2020-09-19T02:00:00Z"},
+		// Saturday, window closed
+		{"2020-09-19T04:00:00Z", "2020-09-26T02:00:00Z"},
+	}
+
+	for i, tc := range testCases {
+		p := w.Next(mustParse(t, tc.now))
+		if !p.Start.Equal(mustParse(t, tc.start)) {
+			t.Errorf("Case %d - Expected start %s, got %s", i, tc.start, p.Start)
+		}
+		if p.End.Sub(p.Start) != 2*time.Hour {
+			t.Errorf("Case %d - Unexpected end %s", i, p.End)
+		}
+	}
+
+	// A daily window open across midnight.
+	w, err = ParseWindow("22:00-02:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := w.Next(mustParse(t, "2020-09-19T01:00:00Z"))
+	if !p.Start.Equal(mustParse(t, "2020-09-18T22:00:00Z")) {
+		t.Errorf("Expected the window opened the day before, got %s", p.Start)
+	}
+}
+
+// TestWaitForStart tests waiting with a fake clock.
+func TestWaitForStart(t *testing.T) {
+	now := mustParse(t, "2020-09-19T01:00:00Z")
+	var slept time.Duration
+	waiter := New(Config{
+		Output: ioutil.Discard,
+		Now:    func() time.Time { return now },
+		Sleep: func(d time.Duration) {
+			if d > MaxSleep {
+				t.Errorf("Slept %s at once", d)
+			}
+			slept += d
+			now = now.Add(d)
+		},
+	})
+
+	p := Period{Start: mustParse(t, "2020-09-19T02:00:00Z"), End: mustParse(t, "2020-09-19T04:00:00Z")}
+	err := waiter.WaitForStart(p)
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if slept != time.Hour {
+		t.Errorf("Expected to sleep 1h, slept %s", slept)
+	}
+
+	// The window closes while we're not looking.
+	now = mustParse(t, "2020-09-19T05:00:00Z")
+	err = waiter.WaitForStart(p)
+	if !IsWindowClosed(err) {
+		t.Errorf("Expected window closed error, got %#v", err)
+	}
+
+	// Without an end, the period never closes.
+	err = waiter.WaitForStart(Period{Start: p.Start})
+	if err != nil {
+		t.Errorf("Unexpected error %#v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package selfupdate
+
+import "github.com/giantswarm/microerror"
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var latestVersionUnknownError = &microerror.Error{
+	Kind: "latestVersionUnknownError",
+}
+
+// IsLatestVersionUnknown asserts latestVersionUnknownError.
+func IsLatestVersionUnknown(err error) bool {
+	return microerror.Cause(err) == latestVersionUnknownError
+}
+
+var releaseNotFoundError = &microerror.Error{
+	Kind: "releaseNotFoundError",
+}
+
+// IsReleaseNotFound asserts releaseNotFoundError.
+func IsReleaseNotFound(err error) bool {
+	return microerror.Cause(err) == releaseNotFoundError
+}
+
+var downloadFailedError = &microerror.Error{
+	Kind: "downloadFailedError",
+}
+
+// IsDownloadFailed asserts downloadFailedError.
+func IsDownloadFailed(err error) bool {
+	return microerror.Cause(err) == downloadFailedError
+}
+
+var checksumMissingError = &microerror.Error{
+	Kind: "checksumMissingError",
+}
+
+// IsChecksumMissing asserts checksumMissingError.
+func IsChecksumMissing(err error) bool {
+	return microerror.Cause(err) == checksumMissingError
+}
+
+var checksumMismatchError = &microerror.Error{
+	Kind: "checksumMismatchError",
+}
+
+// IsChecksumMismatch asserts checksumMismatchError.
+func IsChecksumMismatch(err error) bool {
+	return microerror.Cause(err) == checksumMismatchError
+}
+
+var binaryNotFoundError = &microerror.Error{
+	Kind: "binaryNotFoundError",
+}
+
+// IsBinaryNotFound asserts binaryNotFoundError.
+func IsBinaryNotFound(err error) bool {
+	return microerror.Cause(err) == binaryNotFoundError
+}
+
+var noBackupError = &microerror.Error{
+	Kind: "noBackupError",
+}
+
+// IsNoBackup asserts noBackupError.
+func IsNoBackup(err error) bool {
+	return microerror.Cause(err) == noBackupError
+}
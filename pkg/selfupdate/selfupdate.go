@@ -0,0 +1,374 @@
+// Package selfupdate downloads gsctl releases and replaces the running
+// binary with them.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// DefaultBaseURL is the URL releases are published under.
+	DefaultBaseURL = "https://github.com/giantswarm/gsctl/releases"
+
+	// BackupSuffix is appended to the executable path to keep the previous
+	// binary for rollbacks.
+	BackupSuffix = ".previous"
+
+	// maxArchiveSize limits the size of downloads.
+	maxArchiveSize = 200 * 1024 * 1024
+
+	binaryName = "gsctl"
+)
+
+// Config is the configuration for an Updater.
+type Config struct {
+	// BaseURL is the release base URL. Releases are expected under
+	// <BaseURL>/download/<version>/, the latest release is found by the
+	// redirect of <BaseURL>/latest. Defaults to DefaultBaseURL.
+	BaseURL string
+	// Executable is the path of the binary to replace. Defaults to the
+	// running executable.
+	Executable string
+	// HTTPClient is used for downloads and for looking up the latest
+	// version. Defaults to a client with a timeout of five minutes.
+	HTTPClient *http.Client
+	// OS and Arch select the release artifact. Default to the platform
+	// gsctl was built for.
+	OS   string
+	Arch string
+}
+
+// Updater downloads releases and replaces the executable.
+type Updater struct {
+	baseURL    string
+	executable string
+	httpClient *http.Client
+	os         string
+	arch       string
+}
+
+// New creates a new Updater.
+func New(config Config) (*Updater, error) {
+	u := &Updater{
+		baseURL:    strings.TrimSuffix(config.BaseURL, "/"),
+		executable: config.Executable,
+		httpClient: config.HTTPClient,
+		os:         config.OS,
+		arch:       config.Arch,
+	}
+
+	if u.baseURL == "" {
+		u.baseURL = DefaultBaseURL
+	}
+	if u.httpClient == nil {
+		u.httpClient = &http.Client{Timeout: 5 * time.Minute}
+	}
+	if u.os == "" {
+		u.os = runtime.GOOS
+	}
+	if u.arch == "" {
+		u.arch = runtime.GOARCH
+	}
+
+	if u.executable == "" {
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		u.executable = executable
+	}
+
+	// Replace the actual file, not a symlink pointing to it.
+	executable, err := filepath.EvalSymlinks(u.executable)
+	if err != nil {
+		return nil, microerror.Maskf(invalidConfigError, "executable %s: %s", u.executable, err.Error())
+	}
+	u.executable = executable
+
+	return u, nil
+}
+
+// Executable returns the path of the binary to replace.
+func (u *Updater) Executable() string {
+	return u.executable
+}
+
+// BackupPath returns the path the previous binary is kept under.
+func (u *Updater) BackupPath() string {
+	return u.executable + BackupSuffix
+}
+
+// HasBackup returns true if a previous binary exists.
+func (u *Updater) HasBackup() bool {
+	_, err := os.Stat(u.BackupPath())
+	return err == nil
+}
+
+// LatestVersion returns the version of the latest release. The configured
+// HTTP client is used, so its timeout and transport apply.
+func (u *Updater) LatestVersion() (string, error) {
+	client := *u.httpClient
+	client.CheckRedirect = noRedirect
+
+	return latestVersion(&client, u.baseURL+"/latest")
+}
+
+// LatestVersion resolves the latest version from the redirect of the given
+// URL, e. g. https://github.com/giantswarm/gsctl/releases/latest. A timeout
+// of zero means no timeout.
+func LatestVersion(url string, timeout time.Duration) (string, error) {
+	client := &http.Client{
+		CheckRedirect: noRedirect,
+		Timeout:       timeout,
+	}
+
+	return latestVersion(client, url)
+}
+
+// noRedirect makes an HTTP client return redirects instead of following
+// them.
+func noRedirect(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+func latestVersion(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+	defer resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", microerror.Maskf(latestVersionUnknownError, "no redirect from %s", url)
+	}
+
+	parts := strings.Split(location, "/")
+	return parts[len(parts)-1], nil
+}
+
+// ArchiveName returns the name of the release artifact for the configured
+// platform.
+func (u *Updater) ArchiveName(version string) string {
+	extension := "tar.gz"
+	if u.os == "windows" {
+		extension = "zip"
+	}
+
+	return fmt.Sprintf("%s-%s-%s-%s.%s", binaryName, version, u.os, u.arch, extension)
+}
+
+// ChecksumsName returns the name of the checksums file of a release.
+func ChecksumsName(version string) string {
+	return fmt.Sprintf("%s-%s-checksums.txt", binaryName, version)
+}
+
+// Update downloads the given version, verifies its checksum and replaces
+// the executable with it. The previous binary is kept under BackupPath.
+func (u *Updater) Update(version string) error {
+	archiveName := u.ArchiveName(version)
+
+	checksums, err := u.download(version, ChecksumsName(version))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	expected, err := findChecksum(checksums, archiveName)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	archive, err := u.download(version, archiveName)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	sum := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return microerror.Maskf(checksumMismatchError, "%s has checksum %s, expected %s", archiveName, actual, expected)
+	}
+
+	binary, err := u.extract(archive)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return microerror.Mask(u.replace(binary))
+}
+
+// Rollback swaps the executable with the previous binary. Rolling back
+// twice restores the updated binary.
+func (u *Updater) Rollback() error {
+	if !u.HasBackup() {
+		return microerror.Maskf(noBackupError, "%s does not exist", u.BackupPath())
+	}
+
+	swap := u.executable + ".swap"
+	err := os.Rename(u.executable, swap)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = os.Rename(u.BackupPath(), u.executable)
+	if err != nil {
+		// Restore the executable.
+		_ = os.Rename(swap, u.executable)
+		return microerror.Mask(err)
+	}
+
+	return microerror.Mask(os.Rename(swap, u.BackupPath()))
+}
+
+func (u *Updater) download(version, name string) ([]byte, error) {
+	url := fmt.Sprintf("%s/download/%s/%s", u.baseURL, version, name)
+
+	resp, err := u.httpClient.Get(url)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, microerror.Maskf(releaseNotFoundError, "%s not found", url)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, microerror.Maskf(downloadFailedError, "%s returned status %d", url, resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if len(data) > maxArchiveSize {
+		return nil, microerror.Maskf(downloadFailedError, "%s is larger than %d bytes", url, maxArchiveSize)
+	}
+
+	return data, nil
+}
+
+// findChecksum returns the SHA256 checksum of the named file from a file
+// in the format of sha256sum.
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+
+	return "", microerror.Maskf(checksumMissingError, "no checksum for %s", name)
+}
+
+// extract returns the gsctl binary contained in the archive.
+func (u *Updater) extract(archive []byte) ([]byte, error) {
+	if u.os == "windows" {
+		return extractZip(archive, binaryName+".exe")
+	}
+
+	return extractTarGz(archive, binaryName)
+}
+
+func extractTarGz(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, microerror.Maskf(binaryNotFoundError, err.Error())
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, microerror.Maskf(binaryNotFoundError, err.Error())
+		}
+
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == name {
+			return ioutil.ReadAll(io.LimitReader(tr, maxArchiveSize))
+		}
+	}
+
+	return nil, microerror.Maskf(binaryNotFoundError, "%s not found in archive", name)
+}
+
+func extractZip(archive []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, microerror.Maskf(binaryNotFoundError, err.Error())
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || path.Base(f.Name) != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		defer rc.Close()
+
+		return ioutil.ReadAll(io.LimitReader(rc, maxArchiveSize))
+	}
+
+	return nil, microerror.Maskf(binaryNotFoundError, "%s not found in archive", name)
+}
+
+// replace writes the binary next to the executable and renames it into
+// place, so that the executable is never left half-written.
+func (u *Updater) replace(binary []byte) error {
+	dir := filepath.Dir(u.executable)
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(u.executable)+".new-")
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	_, err = tmp.Write(binary)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = os.Chmod(tmpPath, 0755)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Keep the current binary for rollbacks. Renaming works for running
+	// executables on Windows, too.
+	err = os.Rename(u.executable, u.BackupPath())
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = os.Rename(tmpPath, u.executable)
+	if err != nil {
+		// Restore the current binary.
+		_ = os.Rename(u.BackupPath(), u.executable)
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
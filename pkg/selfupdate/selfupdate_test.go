@@ -0,0 +1,302 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func tarGz(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tw.Write(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	gz.Close()
+
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.Write(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+
+	return buf.Bytes()
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// releaseServer serves the given files for version 1.2.3, with 1.2.3 being
+// the latest release.
+func releaseServer(files map[string][]byte) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/tag/1.2.3")
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("/download/1.2.3/", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+// tempExecutable creates a fake executable with the given content.
+func tempExecutable(t *testing.T, content string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "selfupdate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "gsctl")
+	err = ioutil.WriteFile(path, []byte(content), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// TestUpdateAndRollback tests replacing the executable and swapping back.
+func TestUpdateAndRollback(t *testing.T) {
+	archive := tarGz(t, "gsctl-1.2.3-linux-amd64/gsctl", []byte("new"))
+	ts := releaseServer(map[string][]byte{
+		"gsctl-1.2.3-linux-amd64.tar.gz": archive,
+		"gsctl-1.2.3-checksums.txt":      []byte(fmt.Sprintf("%s  gsctl-1.2.3-darwin-amd64.tar.gz\n%s  gsctl-1.2.3-linux-amd64.tar.gz\n", checksum([]byte("other")), checksum(archive))),
+	})
+	defer ts.Close()
+
+	executable := tempExecutable(t, "old")
+	u, err := New(Config{BaseURL: ts.URL, Executable: executable, OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := u.LatestVersion()
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if version != "1.2.3" {
+		t.Errorf("Expected latest version 1.2.3, got %q", version)
+	}
+
+	if u.HasBackup() {
+		t.Error("Expected no backup before the update")
+	}
+
+	err = u.Update("1.2.3")
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if got := readFile(t, executable); got != "new" {
+		t.Errorf("Expected the new binary, got %q", got)
+	}
+	if got := readFile(t, u.BackupPath()); got != "old" {
+		t.Errorf("Expected the old binary as backup, got %q", got)
+	}
+	info, err := os.Stat(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected the binary to be executable, got mode %s", info.Mode())
+	}
+
+	err = u.Rollback()
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if got := readFile(t, executable); got != "old" {
+		t.Errorf("Expected the old binary after rollback, got %q", got)
+	}
+	if got := readFile(t, u.BackupPath()); got != "new" {
+		t.Errorf("Expected the new binary as backup after rollback, got %q", got)
+	}
+}
+
+// TestUpdateWindows tests extracting the binary from a zip archive.
+func TestUpdateWindows(t *testing.T) {
+	archive := zipArchive(t, "gsctl-1.2.3-windows-amd64/gsctl.exe", []byte("new"))
+	ts := releaseServer(map[string][]byte{
+		"gsctl-1.2.3-windows-amd64.zip": archive,
+		"gsctl-1.2.3-checksums.txt":     []byte(fmt.Sprintf("%s *gsctl-1.2.3-windows-amd64.zip\n", checksum(archive))),
+	})
+	defer ts.Close()
+
+	executable := tempExecutable(t, "old")
+	u, err := New(Config{BaseURL: ts.URL, Executable: executable, OS: "windows", Arch: "amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = u.Update("1.2.3")
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if got := readFile(t, executable); got != "new" {
+		t.Errorf("Expected the new binary, got %q", got)
+	}
+}
+
+// TestUpdateFailures tests that failed updates leave the executable alone.
+func TestUpdateFailures(t *testing.T) {
+	archive := tarGz(t, "gsctl-1.2.3-linux-amd64/gsctl", []byte("new"))
+	emptyArchive := tarGz(t, "gsctl-1.2.3-linux-amd64/README.md", []byte("readme"))
+
+	var testCases = []struct {
+		name       string
+		version    string
+		files      map[string][]byte
+		errorMatch func(error) bool
+	}{
+		{
+			"checksum mismatch",
+			"1.2.3",
+			map[string][]byte{
+				"gsctl-1.2.3-linux-amd64.tar.gz": archive,
+				"gsctl-1.2.3-checksums.txt":      []byte(checksum([]byte("tampered")) + "  gsctl-1.2.3-linux-amd64.tar.gz\n"),
+			},
+			IsChecksumMismatch,
+		},
+		{
+			"checksum missing",
+			"1.2.3",
+			map[string][]byte{
+				"gsctl-1.2.3-linux-amd64.tar.gz": archive,
+				"gsctl-1.2.3-checksums.txt":      []byte(checksum(archive) + "  gsctl-1.2.3-darwin-amd64.tar.gz\n"),
+			},
+			IsChecksumMissing,
+		},
+		{
+			"binary missing",
+			"1.2.3",
+			map[string][]byte{
+				"gsctl-1.2.3-linux-amd64.tar.gz": emptyArchive,
+				"gsctl-1.2.3-checksums.txt":      []byte(checksum(emptyArchive) + "  gsctl-1.2.3-linux-amd64.tar.gz\n"),
+			},
+			IsBinaryNotFound,
+		},
+		{
+			"release not found",
+			"9.9.9",
+			map[string][]byte{},
+			IsReleaseNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := releaseServer(tc.files)
+			defer ts.Close()
+
+			executable := tempExecutable(t, "old")
+			u, err := New(Config{BaseURL: ts.URL, Executable: executable, OS: "linux", Arch: "amd64"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = u.Update(tc.version)
+			if !tc.errorMatch(err) {
+				t.Errorf("Unexpected error %#v", err)
+			}
+			if got := readFile(t, executable); got != "old" {
+				t.Errorf("Expected the executable to be unchanged, got %q", got)
+			}
+			if u.HasBackup() {
+				t.Error("Expected no backup")
+			}
+		})
+	}
+}
+
+// TestRollbackWithoutBackup tests the error when there is nothing to roll
+// back to.
+func TestRollbackWithoutBackup(t *testing.T) {
+	u, err := New(Config{Executable: tempExecutable(t, "old")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = u.Rollback()
+	if !IsNoBackup(err) {
+		t.Errorf("Expected no backup error, got %#v", err)
+	}
+}
+
+// TestLatestVersionUsesHTTPClient tests that looking up the latest version
+// respects the timeout of the configured HTTP client.
+func TestLatestVersionUsesHTTPClient(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	httpClient := &http.Client{Timeout: 50 * time.Millisecond}
+	u, err := New(Config{BaseURL: ts.URL, Executable: tempExecutable(t, "old"), HTTPClient: httpClient})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = u.LatestVersion()
+	if err == nil {
+		t.Fatal("Expected a timeout error, got nil")
+	}
+	if httpClient.CheckRedirect != nil {
+		t.Error("Expected the configured HTTP client to be left unchanged")
+	}
+}
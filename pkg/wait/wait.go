@@ -0,0 +1,340 @@
+// Package wait polls the API until an asynchronous cluster or node pool
+// operation has completed.
+package wait
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/util"
+)
+
+const (
+	// DefaultInterval is the time between two polls.
+	DefaultInterval = 15 * time.Second
+
+	// DefaultTimeout is the time after which waiting is given up.
+	DefaultTimeout = 30 * time.Minute
+
+	activityName = "wait"
+
+	conditionCreated = "Created"
+	conditionUpdated = "Updated"
+)
+
+// Config is the configuration for a Waiter.
+type Config struct {
+	// ClientWrapper is the API client to poll with.
+	ClientWrapper *client.Wrapper
+	// Interval is the time between two polls. Defaults to DefaultInterval.
+	Interval time.Duration
+	// Output receives progress messages. Defaults to os.Stdout.
+	Output io.Writer
+	// Timeout is the maximum time to wait. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Waiter polls the API until a condition is met or the timeout expires.
+type Waiter struct {
+	clientWrapper *client.Wrapper
+	interval      time.Duration
+	output        io.Writer
+	timeout       time.Duration
+}
+
+// checkFunc is called on every poll. It returns whether the awaited state
+// has been reached and a short description of the current state.
+type checkFunc func() (done bool, state string, err error)
+
+// New creates a new Waiter.
+func New(config Config) (*Waiter, error) {
+	if config.ClientWrapper == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ClientWrapper must not be empty", config)
+	}
+
+	w := &Waiter{
+		clientWrapper: config.ClientWrapper,
+		interval:      config.Interval,
+		output:        config.Output,
+		timeout:       config.Timeout,
+	}
+
+	if w.interval == 0 {
+		w.interval = DefaultInterval
+	}
+	if w.output == nil {
+		w.output = os.Stdout
+	}
+	if w.timeout == 0 {
+		w.timeout = DefaultTimeout
+	}
+
+	return w, nil
+}
+
+// ClusterCreated waits until the cluster has reached the Created condition.
+func (w *Waiter) ClusterCreated(clusterID string) error {
+	return w.poll(fmt.Sprintf("cluster '%s' to be created", clusterID), func() (bool, string, error) {
+		details, isV5, err := w.getClusterV5(clusterID)
+		if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		if isV5 {
+			for _, c := range details.Conditions {
+				if c.Condition == conditionCreated {
+					return true, conditionCreated, nil
+				}
+			}
+
+			return false, latestConditionV5(details.Conditions), nil
+		}
+
+		status, err := w.clientWrapper.GetClusterStatus(clusterID, w.auxParams())
+		if clienterror.IsNotFoundError(err) {
+			// The status is not available in the first minutes of cluster creation.
+			return false, "status not yet available", nil
+		} else if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		if status.Cluster == nil {
+			return false, "status not yet available", nil
+		}
+		if status.Cluster.HasCreatedCondition() {
+			return true, conditionCreated, nil
+		}
+
+		return false, latestConditionV4(status), nil
+	})
+}
+
+// ClusterUpgraded waits until the cluster runs the given release version.
+// For v5 clusters, lastUpdated is the time of the latest Updated condition
+// before the upgrade has been requested, see LastUpdated. Only a newer
+// Updated condition marks this upgrade as completed.
+func (w *Waiter) ClusterUpgraded(clusterID, version string, lastUpdated time.Time) error {
+	return w.poll(fmt.Sprintf("cluster '%s' to be upgraded to %s", clusterID, version), func() (bool, string, error) {
+		details, isV5, err := w.getClusterV5(clusterID)
+		if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		if isV5 {
+			state := fmt.Sprintf("release %s, %s", details.ReleaseVersion, latestConditionV5(details.Conditions))
+			if details.ReleaseVersion != version {
+				return false, state, nil
+			}
+			for _, c := range details.Conditions {
+				if c.Condition == conditionUpdated && util.ParseDate(c.LastTransitionTime).After(lastUpdated) {
+					return true, state, nil
+				}
+			}
+
+			return false, state, nil
+		}
+
+		status, err := w.clientWrapper.GetClusterStatus(clusterID, w.auxParams())
+		if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+		if status.Cluster == nil {
+			return false, "status not yet available", nil
+		}
+
+		latest := status.Cluster.LatestVersion()
+		state := fmt.Sprintf("release %s, %s", latest, latestConditionV4(status))
+
+		return latest == version, state, nil
+	})
+}
+
+// ClusterDeleted waits until the cluster can no longer be found.
+func (w *Waiter) ClusterDeleted(clusterID string) error {
+	return w.poll(fmt.Sprintf("cluster '%s' to be deleted", clusterID), func() (bool, string, error) {
+		details, isV5, err := w.getClusterV5(clusterID)
+		if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+		if isV5 {
+			return false, latestConditionV5(details.Conditions), nil
+		}
+
+		status, err := w.clientWrapper.GetClusterStatus(clusterID, w.auxParams())
+		if clienterror.IsNotFoundError(err) {
+			return true, "deleted", nil
+		} else if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		return false, latestConditionV4(status), nil
+	})
+}
+
+// ClusterScaled waits until the number of worker nodes of a cluster
+// without node pools is within the given range.
+func (w *Waiter) ClusterScaled(clusterID string, min, max int64) error {
+	return w.poll(fmt.Sprintf("cluster '%s' to have between %d and %d worker nodes", clusterID, min, max), func() (bool, string, error) {
+		status, err := w.clientWrapper.GetClusterStatus(clusterID, w.auxParams())
+		if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+		if status.Cluster == nil {
+			return false, "status not yet available", nil
+		}
+
+		var workers int64
+		for _, node := range status.Cluster.Nodes {
+			// Count all nodes as workers which are not explicitly marked as master.
+			if role, ok := node.Labels["role"]; ok && role == "master" {
+				continue
+			}
+			workers++
+		}
+
+		return workers >= min && workers <= max, fmt.Sprintf("%d worker nodes", workers), nil
+	})
+}
+
+// NodePoolReady waits until the number of ready nodes in a node pool is
+// within the pool's scaling range.
+func (w *Waiter) NodePoolReady(clusterID, nodePoolID string) error {
+	return w.poll(fmt.Sprintf("node pool '%s' in cluster '%s' to be ready", nodePoolID, clusterID), func() (bool, string, error) {
+		response, err := w.clientWrapper.GetNodePools(clusterID, w.auxParams())
+		if err != nil {
+			return false, "", microerror.Mask(err)
+		}
+
+		for _, np := range response.Payload {
+			if np.ID != nodePoolID {
+				continue
+			}
+
+			var min, max, ready int64
+			if np.Scaling != nil {
+				max = np.Scaling.Max
+				if np.Scaling.Min != nil {
+					min = *np.Scaling.Min
+				}
+			}
+			if np.Status != nil {
+				ready = np.Status.NodesReady
+			}
+
+			state := fmt.Sprintf("%d nodes ready, scaling range %d-%d", ready, min, max)
+
+			return ready >= min && ready <= max, state, nil
+		}
+
+		return false, "node pool not yet listed", nil
+	})
+}
+
+// poll calls check until it reports completion, returns an error, or the
+// timeout expires. Progress is printed after every call.
+func (w *Waiter) poll(description string, check checkFunc) error {
+	start := time.Now()
+
+	for {
+		done, state, err := check()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		elapsed := time.Since(start)
+
+		if done {
+			fmt.Fprintf(w.output, "Done waiting for %s after %s.\n", description, elapsed.Round(time.Second))
+			return nil
+		}
+
+		fmt.Fprintf(w.output, "Waiting for %s (%s elapsed): %s\n", description, elapsed.Round(time.Second), state)
+
+		if elapsed+w.interval > w.timeout {
+			return microerror.Maskf(timeoutError, "gave up waiting for %s after %s", description, w.timeout)
+		}
+
+		time.Sleep(w.interval)
+	}
+}
+
+// getClusterV5 fetches v5 cluster details. The second return value is false
+// if the cluster is not a v5 cluster or does not exist.
+func (w *Waiter) getClusterV5(clusterID string) (*models.V5ClusterDetailsResponse, bool, error) {
+	response, err := w.clientWrapper.GetClusterV5(clusterID, w.auxParams())
+	if err == nil {
+		return response.Payload, true, nil
+	}
+
+	// 404 means the cluster is either a v4 cluster or does not exist. 400
+	// means that v5 is not supported by the provider.
+	if clienterror.IsNotFoundError(err) || clienterror.IsBadRequestError(err) || clienterror.IsMalformedResponse(err) {
+		return nil, false, nil
+	}
+
+	return nil, false, microerror.Mask(err)
+}
+
+func (w *Waiter) auxParams() *client.AuxiliaryParams {
+	auxParams := w.clientWrapper.DefaultAuxiliaryParams()
+	auxParams.ActivityName = activityName
+
+	return auxParams
+}
+
+// LastUpdated returns the time of the latest Updated condition of a v5
+// cluster, or the zero time if there is none. Recording it before requesting
+// an upgrade lets ClusterUpgraded compare timestamps of the API only, so that
+// the local clock doesn't matter.
+func LastUpdated(details *models.V5ClusterDetailsResponse) time.Time {
+	var latest time.Time
+	for _, c := range details.Conditions {
+		if c.Condition != conditionUpdated {
+			continue
+		}
+		if t := util.ParseDate(c.LastTransitionTime); t.After(latest) {
+			latest = t
+		}
+	}
+
+	return latest
+}
+
+// latestConditionV5 returns the name of the most recent condition.
+func latestConditionV5(conditions []*models.V5ClusterDetailsResponseConditionsItems) string {
+	if len(conditions) == 0 {
+		return "no condition yet"
+	}
+
+	sorted := make([]*models.V5ClusterDetailsResponseConditionsItems, len(conditions))
+	copy(sorted, conditions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return util.ParseDate(sorted[i].LastTransitionTime).After(util.ParseDate(sorted[j].LastTransitionTime))
+	})
+
+	return sorted[0].Condition
+}
+
+// latestConditionV4 returns the type of the most recent condition.
+func latestConditionV4(status *client.ClusterStatus) string {
+	if status == nil || status.Cluster == nil || len(status.Cluster.Conditions) == 0 {
+		return "no condition yet"
+	}
+
+	latest := status.Cluster.Conditions[0]
+	for _, c := range status.Cluster.Conditions[1:] {
+		if c.LastTransitionTime.After(latest.LastTransitionTime.Time) {
+			latest = c
+		}
+	}
+
+	return latest.Type
+}
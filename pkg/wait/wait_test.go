@@ -0,0 +1,269 @@
+package wait
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+
+	"github.com/giantswarm/gsctl/client"
+)
+
+// response is a canned mock server response.
+type response struct {
+	status int
+	body   string
+}
+
+// sequenceServer returns a mock server which answers each path with the
+// given responses in order, repeating the last one once exhausted.
+func sequenceServer(t *testing.T, responses map[string][]response) *httptest.Server {
+	var mu sync.Mutex
+	calls := map[string]int{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		seq, ok := responses[r.URL.Path]
+		if !ok {
+			t.Errorf("Unsupported route %s called in mock server", r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		i := calls[r.URL.Path]
+		if i >= len(seq) {
+			i = len(seq) - 1
+		}
+		calls[r.URL.Path]++
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(seq[i].status)
+		w.Write([]byte(seq[i].body))
+	}))
+}
+
+var notFound = response{http.StatusNotFound, `{"code": "RESOURCE_NOT_FOUND", "message": "Not found"}`}
+
+func newTestWaiter(t *testing.T, url string, timeout time.Duration) (*Waiter, *bytes.Buffer) {
+	clientWrapper, err := client.NewWithConfig(url, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	output := &bytes.Buffer{}
+	w, err := New(Config{
+		ClientWrapper: clientWrapper,
+		Interval:      time.Millisecond,
+		Output:        output,
+		Timeout:       timeout,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return w, output
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{})
+	if !IsInvalidConfig(err) {
+		t.Errorf("expected invalidConfigError, got %#v", err)
+	}
+}
+
+func TestClusterCreated(t *testing.T) {
+	testCases := []struct {
+		name      string
+		responses map[string][]response
+	}{
+		{
+			name: "case 0: v5 cluster",
+			responses: map[string][]response{
+				"/v5/clusters/abc12/": {
+					{http.StatusOK, `{"id": "abc12", "conditions": []}`},
+					{http.StatusOK, `{"id": "abc12", "conditions": [{"condition": "Creating", "last_transition_time": "2020-05-01T10:00:00Z"}]}`},
+					{http.StatusOK, `{"id": "abc12", "conditions": [{"condition": "Created", "last_transition_time": "2020-05-01T10:20:00Z"}, {"condition": "Creating", "last_transition_time": "2020-05-01T10:00:00Z"}]}`},
+				},
+			},
+		},
+		{
+			name: "case 1: v4 cluster",
+			responses: map[string][]response{
+				"/v5/clusters/abc12/": {notFound},
+				"/v4/clusters/abc12/status/": {
+					notFound,
+					{http.StatusOK, `{"cluster": {"conditions": [{"type": "Creating", "status": "True", "lastTransitionTime": "2020-05-01T10:00:00Z"}]}}`},
+					{http.StatusOK, `{"cluster": {"conditions": [{"type": "Created", "status": "True", "lastTransitionTime": "2020-05-01T10:20:00Z"}]}}`},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockServer := sequenceServer(t, tc.responses)
+			defer mockServer.Close()
+
+			w, output := newTestWaiter(t, mockServer.URL, time.Minute)
+
+			err := w.ClusterCreated("abc12")
+			if err != nil {
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if !strings.Contains(output.String(), "Waiting for cluster 'abc12' to be created") {
+				t.Errorf("expected progress output, got %q", output.String())
+			}
+			if !strings.Contains(output.String(), "Done waiting") {
+				t.Errorf("expected completion output, got %q", output.String())
+			}
+		})
+	}
+}
+
+func TestClusterUpgraded(t *testing.T) {
+	lastUpdated := time.Date(2020, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		responses map[string][]response
+	}{
+		{
+			name: "case 0: v5 cluster, earlier Updated condition is ignored",
+			responses: map[string][]response{
+				"/v5/clusters/abc12/": {
+					{http.StatusOK, `{"id": "abc12", "release_version": "11.0.0", "conditions": [{"condition": "Updated", "last_transition_time": "2020-04-01T10:00:00Z"}]}`},
+					{http.StatusOK, `{"id": "abc12", "release_version": "11.1.0", "conditions": [{"condition": "Updated", "last_transition_time": "2020-04-01T10:00:00Z"}]}`},
+					{http.StatusOK, `{"id": "abc12", "release_version": "11.1.0", "conditions": [{"condition": "Updated", "last_transition_time": "2020-04-01T10:00:01Z"}, {"condition": "Updated", "last_transition_time": "2020-04-01T10:00:00Z"}]}`},
+				},
+			},
+		},
+		{
+			name: "case 1: v4 cluster",
+			responses: map[string][]response{
+				"/v5/clusters/abc12/": {notFound},
+				"/v4/clusters/abc12/status/": {
+					{http.StatusOK, `{"cluster": {"versions": [{"semver": "9.0.0", "lastTransitionTime": "2020-04-01T10:00:00Z"}]}}`},
+					{http.StatusOK, `{"cluster": {"versions": [{"semver": "9.0.0", "lastTransitionTime": "2020-04-01T10:00:00Z"}, {"semver": "11.1.0", "lastTransitionTime": "2020-05-01T12:30:00Z"}]}}`},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockServer := sequenceServer(t, tc.responses)
+			defer mockServer.Close()
+
+			w, output := newTestWaiter(t, mockServer.URL, time.Minute)
+
+			err := w.ClusterUpgraded("abc12", "11.1.0", lastUpdated)
+			if err != nil {
+				t.Fatalf("unexpected error: %#v", err)
+			}
+			if strings.Count(output.String(), "Waiting for") < 1 {
+				t.Errorf("expected progress output, got %q", output.String())
+			}
+		})
+	}
+}
+
+func TestLastUpdated(t *testing.T) {
+	details := &models.V5ClusterDetailsResponse{
+		Conditions: []*models.V5ClusterDetailsResponseConditionsItems{
+			{Condition: "Updated", LastTransitionTime: "2020-04-01T10:00:00Z"},
+			{Condition: "Created", LastTransitionTime: "2020-06-01T10:00:00Z"},
+			{Condition: "Updated", LastTransitionTime: "2020-05-01T10:00:00Z"},
+		},
+	}
+
+	expected := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	if got := LastUpdated(details); !got.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if got := LastUpdated(&models.V5ClusterDetailsResponse{}); !got.IsZero() {
+		t.Errorf("expected zero time, got %s", got)
+	}
+}
+
+func TestClusterDeleted(t *testing.T) {
+	mockServer := sequenceServer(t, map[string][]response{
+		"/v5/clusters/abc12/": {
+			{http.StatusOK, `{"id": "abc12", "conditions": [{"condition": "Deleting", "last_transition_time": "2020-05-01T10:00:00Z"}]}`},
+			notFound,
+		},
+		"/v4/clusters/abc12/status/": {notFound},
+	})
+	defer mockServer.Close()
+
+	w, _ := newTestWaiter(t, mockServer.URL, time.Minute)
+
+	err := w.ClusterDeleted("abc12")
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+}
+
+func TestClusterScaled(t *testing.T) {
+	mockServer := sequenceServer(t, map[string][]response{
+		"/v4/clusters/abc12/status/": {
+			{http.StatusOK, `{"cluster": {"nodes": [{"name": "m1", "labels": {"role": "master"}}, {"name": "w1", "labels": {"role": "worker"}}]}}`},
+			{http.StatusOK, `{"cluster": {"nodes": [{"name": "m1", "labels": {"role": "master"}}, {"name": "w1"}, {"name": "w2"}, {"name": "w3"}]}}`},
+		},
+	})
+	defer mockServer.Close()
+
+	w, output := newTestWaiter(t, mockServer.URL, time.Minute)
+
+	err := w.ClusterScaled("abc12", 3, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	if !strings.Contains(output.String(), "1 worker nodes") {
+		t.Errorf("expected progress output, got %q", output.String())
+	}
+}
+
+func TestNodePoolReady(t *testing.T) {
+	mockServer := sequenceServer(t, map[string][]response{
+		"/v5/clusters/abc12/nodepools/": {
+			{http.StatusOK, `[]`},
+			{http.StatusOK, `[{"id": "np1", "scaling": {"min": 2, "max": 4}, "status": {"nodes": 2, "nodes_ready": 1}}]`},
+			{http.StatusOK, `[{"id": "np1", "scaling": {"min": 2, "max": 4}, "status": {"nodes": 2, "nodes_ready": 2}}]`},
+		},
+	})
+	defer mockServer.Close()
+
+	w, output := newTestWaiter(t, mockServer.URL, time.Minute)
+
+	err := w.NodePoolReady("abc12", "np1")
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	if !strings.Contains(output.String(), "1 nodes ready, scaling range 2-4") {
+		t.Errorf("expected progress output, got %q", output.String())
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	mockServer := sequenceServer(t, map[string][]response{
+		"/v5/clusters/abc12/": {
+			{http.StatusOK, `{"id": "abc12", "conditions": [{"condition": "Creating", "last_transition_time": "2020-05-01T10:00:00Z"}]}`},
+		},
+	})
+	defer mockServer.Close()
+
+	w, _ := newTestWaiter(t, mockServer.URL, 20*time.Millisecond)
+
+	err := w.ClusterCreated("abc12")
+	if !IsTimeout(err) {
+		t.Errorf("expected timeoutError, got %#v", err)
+	}
+}
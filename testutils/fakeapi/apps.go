@@ -0,0 +1,162 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+)
+
+const appStatusDeployed = "DEPLOYED"
+
+// AddApp adds an app to the cluster with the given ID. It returns false if
+// the cluster does not exist.
+func (s *Server) AddApp(clusterID string, app *models.V4GetClusterAppsResponseItems) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c, ok := s.clusters[clusterID]
+	if !ok {
+		return false
+	}
+
+	c.apps = append(c.apps, app)
+
+	return true
+}
+
+func (s *Server) routeApps(w http.ResponseWriter, r *http.Request, c *cluster, path []string) {
+	if len(path) == 0 {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		if c.apps == nil {
+			writeJSON(w, http.StatusOK, []*models.V4GetClusterAppsResponseItems{})
+			return
+		}
+		writeJSON(w, http.StatusOK, c.apps)
+		return
+	}
+
+	name := path[0]
+	index := -1
+	for i, app := range c.apps {
+		if app.Metadata != nil && app.Metadata.Name == name {
+			index = i
+		}
+	}
+
+	if len(path) == 2 && path[1] == "config" {
+		if index < 0 {
+			writeNotFound(w, "app")
+			return
+		}
+		s.routeAppConfig(w, r, c.apps[index])
+		return
+	} else if len(path) > 1 {
+		writeNotFound(w, "route")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if index >= 0 {
+			writeError(w, http.StatusConflict, "RESOURCE_ALREADY_EXISTS", fmt.Sprintf("The app '%s' already exists.", name))
+			return
+		}
+		s.createApp(w, r, c, name)
+	case http.MethodPatch:
+		if index < 0 {
+			writeNotFound(w, "app")
+			return
+		}
+		s.modifyApp(w, r, c.apps[index])
+	case http.MethodDelete:
+		if index < 0 {
+			writeNotFound(w, "app")
+			return
+		}
+		c.apps = append(c.apps[:index], c.apps[index+1:]...)
+		writeJSON(w, http.StatusOK, &models.V4GenericResponse{Code: "RESOURCE_DELETED", Message: fmt.Sprintf("The app '%s' has been deleted.", name)})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request, c *cluster, name string) {
+	var body models.V4CreateAppRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	spec := body.Spec
+	if spec == nil || spec.Catalog == nil || spec.Name == nil || spec.Namespace == nil || spec.Version == nil {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "The app spec must contain catalog, name, namespace and version.")
+		return
+	}
+
+	app := &models.V4GetClusterAppsResponseItems{
+		Metadata: &models.V4GetClusterAppsResponseItemsMetadata{Name: name},
+		Spec: &models.V4GetClusterAppsResponseItemsSpec{
+			Catalog:   *spec.Catalog,
+			Name:      *spec.Name,
+			Namespace: *spec.Namespace,
+			Version:   *spec.Version,
+		},
+		Status: &models.V4GetClusterAppsResponseItemsStatus{
+			AppVersion: *spec.Version,
+			Version:    *spec.Version,
+			Release: &models.V4GetClusterAppsResponseItemsStatusRelease{
+				LastDeployed: nowString(),
+				Status:       appStatusDeployed,
+			},
+		},
+	}
+	c.apps = append(c.apps, app)
+
+	writeJSON(w, http.StatusOK, app)
+}
+
+func (s *Server) modifyApp(w http.ResponseWriter, r *http.Request, app *models.V4GetClusterAppsResponseItems) {
+	var body models.V4ModifyAppRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	if body.Spec != nil && body.Spec.Version != "" {
+		app.Spec.Version = body.Spec.Version
+		if app.Status != nil {
+			app.Status.Version = body.Spec.Version
+			app.Status.AppVersion = body.Spec.Version
+			if app.Status.Release != nil {
+				app.Status.Release.LastDeployed = nowString()
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, app)
+}
+
+// routeAppConfig handles the user values config map of an app. The values
+// themselves are not kept, only the reference to the config map.
+func (s *Server) routeAppConfig(w http.ResponseWriter, r *http.Request, app *models.V4GetClusterAppsResponseItems) {
+	switch r.Method {
+	case http.MethodPut, http.MethodPatch:
+		if app.Spec.UserConfig == nil {
+			app.Spec.UserConfig = &models.V4GetClusterAppsResponseItemsSpecUserConfig{}
+		}
+		app.Spec.UserConfig.Configmap = &models.V4GetClusterAppsResponseItemsSpecUserConfigConfigmap{
+			Name:      fmt.Sprintf("%s-user-values", app.Metadata.Name),
+			Namespace: app.Spec.Namespace,
+		}
+		writeJSON(w, http.StatusOK, &models.V4GenericResponse{Code: "RESOURCE_CREATED", Message: "The app config has been set."})
+	case http.MethodDelete:
+		if app.Spec.UserConfig != nil {
+			app.Spec.UserConfig.Configmap = nil
+		}
+		writeJSON(w, http.StatusOK, &models.V4GenericResponse{Code: "RESOURCE_DELETED", Message: "The app config has been deleted."})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
@@ -0,0 +1,593 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/giantswarm/apiextensions/v2/pkg/apis/provider/v1alpha1"
+	"github.com/giantswarm/gsclientgen/v2/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/giantswarm/gsctl/util"
+)
+
+const (
+	conditionCreated = "Created"
+	conditionUpdated = "Updated"
+)
+
+// cluster is the state kept for one cluster. Clusters with node pools are
+// served via the V5 routes, others via the V4 routes.
+type cluster struct {
+	id             string
+	name           string
+	owner          string
+	releaseVersion string
+	createDate     string
+	credentialID   string
+	nodePools      bool
+	labels         map[string]string
+	conditions     []*models.V5ClusterDetailsResponseConditionsItems
+	versions       []*models.V5ClusterDetailsResponseVersionsItems
+
+	// Fields only used for clusters without node pools.
+	availabilityZones []string
+	scalingMin        int64
+	scalingMax        int64
+	instanceType      string
+
+	// Fields only used for clusters with node pools.
+	masterAvailabilityZones []string
+	haMasters               bool
+	nodePoolList            []*models.V5GetNodePoolResponse
+
+	keyPairs []*models.V4GetKeyPairsResponseItems
+	apps     []*models.V4GetClusterAppsResponseItems
+}
+
+// AddClusterV4 adds a cluster without node pools to the server state, based
+// on the given details. Missing ID and creation date are filled in. The
+// cluster ID is returned.
+func (s *Server) AddClusterV4(details *models.V4ClusterDetailsResponse) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c := s.newCluster(details.ID, details.Name, details.Owner, details.ReleaseVersion, false)
+	if details.CreateDate != "" {
+		c.createDate = details.CreateDate
+	}
+	c.credentialID = details.CredentialID
+	if len(details.AvailabilityZones) > 0 {
+		c.availabilityZones = details.AvailabilityZones
+	}
+	if details.Scaling != nil {
+		c.scalingMax = details.Scaling.Max
+		if details.Scaling.Min != nil {
+			c.scalingMin = *details.Scaling.Min
+		}
+	}
+
+	return c.id
+}
+
+// AddClusterV5 adds a cluster with node pools to the server state, based on
+// the given details. Missing ID and creation date are filled in. The cluster
+// ID is returned.
+func (s *Server) AddClusterV5(details *models.V5ClusterDetailsResponse) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c := s.newCluster(details.ID, details.Name, details.Owner, details.ReleaseVersion, true)
+	if details.CreateDate != "" {
+		c.createDate = details.CreateDate
+	}
+	c.credentialID = details.CredentialID
+	for k, v := range details.Labels {
+		c.labels[k] = v
+	}
+	if details.MasterNodes != nil {
+		c.haMasters = details.MasterNodes.HighAvailability
+		c.masterAvailabilityZones = details.MasterNodes.AvailabilityZones
+	}
+
+	return c.id
+}
+
+// newCluster creates a cluster in the Created state and adds it to the
+// server state. The caller must hold the mutex.
+func (s *Server) newCluster(id, name, owner, releaseVersion string, nodePools bool) *cluster {
+	if id == "" {
+		id = s.randomID(5)
+	}
+	if name == "" {
+		name = "Unnamed cluster"
+	}
+	if releaseVersion == "" {
+		releaseVersion = s.latestRelease()
+	}
+
+	now := nowString()
+	zones := s.availabilityZones()
+
+	c := &cluster{
+		id:             id,
+		name:           name,
+		owner:          owner,
+		releaseVersion: releaseVersion,
+		createDate:     now,
+		nodePools:      nodePools,
+		labels:         map[string]string{},
+		conditions: []*models.V5ClusterDetailsResponseConditionsItems{
+			{Condition: conditionCreated, LastTransitionTime: now},
+		},
+		versions: []*models.V5ClusterDetailsResponseVersionsItems{
+			{Version: releaseVersion, LastTransitionTime: now},
+		},
+
+		availabilityZones:       zones[:1],
+		scalingMin:              3,
+		scalingMax:              3,
+		instanceType:            s.defaultInstanceType(),
+		masterAvailabilityZones: zones[:1],
+	}
+
+	s.clusters[id] = c
+
+	return c
+}
+
+func (s *Server) routeClustersV4(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			s.getClusters(w, r)
+		case http.MethodPost:
+			s.createClusterV4(w, r)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	c, ok := s.clusters[path[0]]
+	if !ok {
+		writeNotFound(w, "cluster")
+		return
+	}
+
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			if c.nodePools {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "This cluster uses node pools. Please use the v5 API.")
+				return
+			}
+			writeJSON(w, http.StatusOK, c.v4Details())
+		case http.MethodPatch:
+			s.modifyClusterV4(w, r, c)
+		case http.MethodDelete:
+			delete(s.clusters, c.id)
+			writeJSON(w, http.StatusAccepted, &models.V4GenericResponse{Code: "RESOURCE_DELETION_STARTED", Message: fmt.Sprintf("The cluster with ID '%s' is being deleted.", c.id)})
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	switch path[1] {
+	case "status":
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, c.status())
+	case "key-pairs":
+		s.routeKeyPairs(w, r, c, path[2:])
+	case "apps":
+		s.routeApps(w, r, c, path[2:])
+	default:
+		writeNotFound(w, "route")
+	}
+}
+
+func (s *Server) routeClustersV5(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w)
+			return
+		}
+		s.createClusterV5(w, r)
+		return
+	}
+
+	if path[0] == "by_label" {
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w)
+			return
+		}
+		s.getClustersByLabel(w, r)
+		return
+	}
+
+	c, ok := s.clusters[path[0]]
+	if !ok {
+		writeNotFound(w, "cluster")
+		return
+	}
+
+	if len(path) == 1 {
+		if !c.nodePools {
+			// The V5 API only knows clusters with node pools.
+			writeNotFound(w, "cluster")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, c.v5Details())
+		case http.MethodPatch:
+			s.modifyClusterV5(w, r, c)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	switch path[1] {
+	case "labels":
+		s.routeLabels(w, r, c)
+	case "nodepools":
+		s.routeNodePools(w, r, c, path[2:])
+	case "apps":
+		s.routeApps(w, r, c, path[2:])
+	default:
+		writeNotFound(w, "route")
+	}
+}
+
+func (s *Server) getClusters(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.clusterList(func(*cluster) bool { return true }))
+}
+
+func (s *Server) getClustersByLabel(w http.ResponseWriter, r *http.Request) {
+	var body models.V5ListClustersByLabelRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+	if body.Labels == nil {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "The labels selector must not be empty.")
+		return
+	}
+
+	selector, err := labels.Parse(*body.Labels)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The labels selector is invalid: %s", err.Error()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.clusterList(func(c *cluster) bool {
+		return c.nodePools && selector.Matches(labels.Set(c.labels))
+	}))
+}
+
+// clusterList returns list items for all clusters matching the filter,
+// sorted by ID.
+func (s *Server) clusterList(filter func(*cluster) bool) []*models.V4ClusterListItem {
+	items := []*models.V4ClusterListItem{}
+	for _, c := range s.clusters {
+		if filter(c) {
+			items = append(items, c.listItem())
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items
+}
+
+func (s *Server) createClusterV4(w http.ResponseWriter, r *http.Request) {
+	var body models.V4AddClusterRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	owner := ""
+	if body.Owner != nil {
+		owner = *body.Owner
+	}
+	if !s.validateClusterCreation(w, owner, body.ReleaseVersion, false) {
+		return
+	}
+
+	c := s.newCluster("", body.Name, owner, body.ReleaseVersion, false)
+	if body.AvailabilityZones > 0 {
+		zones := s.availabilityZones()
+		if int(body.AvailabilityZones) < len(zones) {
+			zones = zones[:body.AvailabilityZones]
+		}
+		c.availabilityZones = zones
+	}
+	if body.Scaling != nil {
+		if body.Scaling.Min != nil {
+			c.scalingMin = *body.Scaling.Min
+		}
+		if body.Scaling.Max > 0 {
+			c.scalingMax = body.Scaling.Max
+		}
+	}
+	if len(body.Workers) > 0 && body.Workers[0].Aws != nil && body.Workers[0].Aws.InstanceType != "" {
+		c.instanceType = body.Workers[0].Aws.InstanceType
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/v4/clusters/%s/", c.id))
+	writeJSON(w, http.StatusCreated, &models.V4GenericResponse{Code: "RESOURCE_CREATED", Message: fmt.Sprintf("A new cluster has been created with ID '%s'.", c.id)})
+}
+
+func (s *Server) createClusterV5(w http.ResponseWriter, r *http.Request) {
+	var body models.V5AddClusterRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	owner := ""
+	if body.Owner != nil {
+		owner = *body.Owner
+	}
+	if !s.validateClusterCreation(w, owner, body.ReleaseVersion, true) {
+		return
+	}
+
+	c := s.newCluster("", body.Name, owner, body.ReleaseVersion, true)
+	if body.MasterNodes != nil && body.MasterNodes.HighAvailability != nil && *body.MasterNodes.HighAvailability {
+		c.haMasters = true
+		c.masterAvailabilityZones = s.availabilityZones()
+	} else if body.Master != nil && body.Master.AvailabilityZone != "" {
+		c.masterAvailabilityZones = []string{body.Master.AvailabilityZone}
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/v5/clusters/%s/", c.id))
+	writeJSON(w, http.StatusCreated, c.v5Details())
+}
+
+// validateClusterCreation checks the owner and release of a new cluster and
+// writes an error response if they are invalid.
+func (s *Server) validateClusterCreation(w http.ResponseWriter, owner, releaseVersion string, nodePools bool) bool {
+	if owner == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "The owner organization must be given.")
+		return false
+	}
+	if !s.hasOrganization(owner) {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The organization '%s' does not exist.", owner))
+		return false
+	}
+
+	if releaseVersion == "" {
+		releaseVersion = s.latestRelease()
+	}
+	if !s.hasRelease(releaseVersion) {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The release version '%s' does not exist.", releaseVersion))
+		return false
+	}
+
+	supportsNodePools := !util.VersionSortComp(releaseVersion, NodePoolsReleaseVersionMinimum)
+	if nodePools && !supportsNodePools {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("Node pools require release %s or newer.", NodePoolsReleaseVersionMinimum))
+		return false
+	}
+	if !nodePools && supportsNodePools {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("Clusters with release %s or newer must be created using the v5 API.", NodePoolsReleaseVersionMinimum))
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) modifyClusterV4(w http.ResponseWriter, r *http.Request, c *cluster) {
+	if c.nodePools {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "This cluster uses node pools. Please use the v5 API.")
+		return
+	}
+
+	var body models.V4ModifyClusterRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	if body.Owner != "" {
+		if !s.hasOrganization(body.Owner) {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The organization '%s' does not exist.", body.Owner))
+			return
+		}
+		c.owner = body.Owner
+	}
+	if body.Scaling != nil {
+		min, max := c.scalingMin, c.scalingMax
+		if body.Scaling.Min != nil {
+			min = *body.Scaling.Min
+		}
+		if body.Scaling.Max > 0 {
+			max = body.Scaling.Max
+		}
+		if min > max {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "The minimum number of workers must not be greater than the maximum.")
+			return
+		}
+		c.scalingMin, c.scalingMax = min, max
+	}
+	if !s.applyNameAndRelease(w, c, body.Name, body.ReleaseVersion) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.v4Details())
+}
+
+func (s *Server) modifyClusterV5(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var body models.V5ModifyClusterRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	if body.MasterNodes != nil && body.MasterNodes.HighAvailability && !c.haMasters {
+		c.haMasters = true
+		c.masterAvailabilityZones = s.availabilityZones()
+	}
+	if !s.applyNameAndRelease(w, c, body.Name, body.ReleaseVersion) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.v5Details())
+}
+
+// applyNameAndRelease renames and upgrades a cluster. The upgrade is
+// finished immediately.
+func (s *Server) applyNameAndRelease(w http.ResponseWriter, c *cluster, name, releaseVersion string) bool {
+	if releaseVersion != "" && releaseVersion != c.releaseVersion {
+		if !s.hasRelease(releaseVersion) {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The release version '%s' does not exist.", releaseVersion))
+			return false
+		}
+
+		now := nowString()
+		c.releaseVersion = releaseVersion
+		c.versions = append(c.versions, &models.V5ClusterDetailsResponseVersionsItems{Version: releaseVersion, LastTransitionTime: now})
+		c.conditions = append(c.conditions, &models.V5ClusterDetailsResponseConditionsItems{Condition: conditionUpdated, LastTransitionTime: now})
+	}
+	if name != "" {
+		c.name = name
+	}
+
+	return true
+}
+
+func (c *cluster) listItem() *models.V4ClusterListItem {
+	item := &models.V4ClusterListItem{
+		CreateDate:     c.createDate,
+		ID:             c.id,
+		Name:           c.name,
+		Owner:          c.owner,
+		Path:           fmt.Sprintf("/v4/clusters/%s/", c.id),
+		ReleaseVersion: c.releaseVersion,
+	}
+	if c.nodePools {
+		item.Labels = c.labels
+	}
+
+	return item
+}
+
+func (c *cluster) v4Details() *models.V4ClusterDetailsResponse {
+	details := &models.V4ClusterDetailsResponse{
+		APIEndpoint:       c.apiEndpoint(),
+		AvailabilityZones: c.availabilityZones,
+		CreateDate:        c.createDate,
+		CredentialID:      c.credentialID,
+		ID:                c.id,
+		Name:              c.name,
+		Owner:             c.owner,
+		ReleaseVersion:    c.releaseVersion,
+		Scaling: &models.V4ClusterDetailsResponseScaling{
+			Min: int64Ptr(c.scalingMin),
+			Max: c.scalingMax,
+		},
+	}
+
+	for i := int64(0); i < c.scalingMin; i++ {
+		worker := &models.V4ClusterDetailsResponseWorkersItems{}
+		if c.instanceType != "" {
+			worker.Aws = &models.V4ClusterDetailsResponseWorkersItemsAws{InstanceType: c.instanceType}
+		}
+		details.Workers = append(details.Workers, worker)
+	}
+
+	return details
+}
+
+func (c *cluster) v5Details() *models.V5ClusterDetailsResponse {
+	numReady := int8(1)
+	if c.haMasters {
+		numReady = int8(len(c.masterAvailabilityZones))
+	}
+
+	details := &models.V5ClusterDetailsResponse{
+		APIEndpoint:    c.apiEndpoint(),
+		Conditions:     c.conditions,
+		CreateDate:     c.createDate,
+		CredentialID:   c.credentialID,
+		ID:             c.id,
+		Labels:         c.labels,
+		Name:           c.name,
+		Owner:          c.owner,
+		ReleaseVersion: c.releaseVersion,
+		Versions:       c.versions,
+		MasterNodes: &models.V5ClusterDetailsResponseMasterNodes{
+			AvailabilityZones: c.masterAvailabilityZones,
+			HighAvailability:  c.haMasters,
+			NumReady:          &numReady,
+		},
+	}
+	if !c.haMasters && len(c.masterAvailabilityZones) > 0 {
+		details.Master = &models.V5ClusterDetailsResponseMaster{AvailabilityZone: c.masterAvailabilityZones[0]}
+	}
+
+	return details
+}
+
+// status returns the cluster status as served by the V4 status endpoint.
+func (c *cluster) status() map[string]*v1alpha1.StatusCluster {
+	status := &v1alpha1.StatusCluster{}
+
+	for _, condition := range c.conditions {
+		status.Conditions = append(status.Conditions, v1alpha1.StatusClusterCondition{
+			LastTransitionTime: metav1.NewTime(util.ParseDate(condition.LastTransitionTime)),
+			Status:             v1alpha1.StatusClusterStatusTrue,
+			Type:               condition.Condition,
+		})
+	}
+	// Newest first, so that the latest version wins between versions with
+	// the same timestamp.
+	for i := len(c.versions) - 1; i >= 0; i-- {
+		t := metav1.NewTime(util.ParseDate(c.versions[i].LastTransitionTime))
+		status.Versions = append(status.Versions, v1alpha1.StatusClusterVersion{
+			Date:               t,
+			LastTransitionTime: t,
+			Semver:             c.versions[i].Version,
+		})
+	}
+
+	status.Nodes = append(status.Nodes, v1alpha1.StatusClusterNode{
+		Name:   fmt.Sprintf("master-%s-0", c.id),
+		Labels: map[string]string{"role": "master"},
+	})
+	for i := int64(0); i < c.workerCount(); i++ {
+		status.Nodes = append(status.Nodes, v1alpha1.StatusClusterNode{
+			Name:   fmt.Sprintf("worker-%s-%d", c.id, i),
+			Labels: map[string]string{"role": "worker"},
+		})
+	}
+
+	return map[string]*v1alpha1.StatusCluster{"cluster": status}
+}
+
+// workerCount returns the number of ready worker nodes.
+func (c *cluster) workerCount() int64 {
+	if !c.nodePools {
+		return c.scalingMin
+	}
+
+	var count int64
+	for _, np := range c.nodePoolList {
+		if np.Status != nil {
+			count += np.Status.NodesReady
+		}
+	}
+
+	return count
+}
+
+func (c *cluster) apiEndpoint() string {
+	return fmt.Sprintf("https://api.%s.k8s.fake.example.com", c.id)
+}
@@ -0,0 +1,398 @@
+// Package fakeapi provides an in-memory fake of the Giant Swarm API. It
+// keeps clusters, node pools, key pairs, apps, labels, organizations and
+// releases in memory and implements the V4/V5 routes used by gsctl, so that
+// tests and the 'gsctl dev mock-api' command can exercise whole workflows
+// without a live installation.
+//
+// Any auth token is accepted, and creating an auth token succeeds for any
+// email and password.
+//
+// State transitions happen immediately. A cluster has the Created condition
+// right after creation, an upgrade is finished as soon as it is requested,
+// and deleted resources are gone right away.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+
+	"github.com/giantswarm/gsctl/util"
+)
+
+const (
+	// DefaultProvider is the provider reported if none is configured.
+	DefaultProvider = "aws"
+
+	// DefaultInstallationName is the installation name reported if none is
+	// configured.
+	DefaultInstallationName = "fake"
+
+	// DefaultOrganization is the organization available if none are
+	// configured.
+	DefaultOrganization = "acme"
+
+	// NodePoolsReleaseVersionMinimum is the lowest release version creating
+	// clusters with node pools.
+	NodePoolsReleaseVersionMinimum = "10.0.0"
+
+	// HAMastersReleaseVersionMinimum is the lowest release version supporting
+	// master node high availability.
+	HAMastersReleaseVersionMinimum = "11.4.0"
+
+	idCharacters = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// Config is the configuration for a fake API server.
+type Config struct {
+	// Provider is the provider to emulate, one of "aws", "azure" or "kvm".
+	// Defaults to DefaultProvider.
+	Provider string
+
+	// InstallationName is the installation name reported by the info
+	// endpoint. Defaults to DefaultInstallationName.
+	InstallationName string
+
+	// Organizations are the IDs of the organizations available. Defaults to
+	// DefaultOrganization.
+	Organizations []string
+
+	// Releases are the releases available. Defaults to DefaultReleases().
+	Releases []*models.V4ReleaseListItem
+
+	// Logger, if set, receives one line per request handled.
+	Logger io.Writer
+}
+
+// Server is a fake Giant Swarm API server. It implements http.Handler.
+type Server struct {
+	installationName string
+	logger           io.Writer
+	provider         string
+
+	// mutex guards all fields below.
+	mutex         sync.Mutex
+	clusters      map[string]*cluster
+	credentials   map[string][]*models.V4GetCredentialResponse
+	organizations []string
+	random        *rand.Rand
+	releases      []*models.V4ReleaseListItem
+	signer        *signer
+}
+
+// New creates a new fake API server.
+func New(config Config) *Server {
+	s := &Server{
+		installationName: config.InstallationName,
+		logger:           config.Logger,
+		provider:         config.Provider,
+
+		clusters:      map[string]*cluster{},
+		credentials:   map[string][]*models.V4GetCredentialResponse{},
+		organizations: config.Organizations,
+		random:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		releases:      config.Releases,
+	}
+
+	if s.installationName == "" {
+		s.installationName = DefaultInstallationName
+	}
+	if s.provider == "" {
+		s.provider = DefaultProvider
+	}
+	if len(s.organizations) == 0 {
+		s.organizations = []string{DefaultOrganization}
+	}
+	if len(s.releases) == 0 {
+		s.releases = DefaultReleases()
+	}
+
+	return s
+}
+
+// DefaultReleases returns the releases available by default.
+func DefaultReleases() []*models.V4ReleaseListItem {
+	return []*models.V4ReleaseListItem{
+		newRelease("9.3.0", "2020-01-15T10:00:00Z", "1.15.5", false),
+		newRelease("11.5.0", "2020-06-02T10:00:00Z", "1.16.9", true),
+		newRelease("12.1.0", "2020-08-20T10:00:00Z", "1.17.9", true),
+	}
+}
+
+func newRelease(version, timestamp, kubernetesVersion string, active bool) *models.V4ReleaseListItem {
+	return &models.V4ReleaseListItem{
+		Active:    active,
+		Timestamp: stringPtr(timestamp),
+		Version:   stringPtr(version),
+		Components: []*models.V4ReleaseListItemComponentsItems{
+			{Name: stringPtr("kubernetes"), Version: stringPtr(kubernetesVersion)},
+		},
+		Changelog: []*models.V4ReleaseListItemChangelogItems{
+			{Component: "kubernetes", Description: fmt.Sprintf("Updated to %s.", kubernetesVersion)},
+		},
+	}
+}
+
+// ServeHTTP dispatches a request to the matching route handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	if s.logger != nil {
+		defer func() {
+			fmt.Fprintf(s.logger, "%s %s %s %d\n", time.Now().Format(time.RFC3339), r.Method, r.URL.Path, rec.status)
+		}()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if r.URL.Path == "/v4/auth-tokens/" && r.Method == http.MethodPost {
+		s.createAuthToken(rec, r)
+		return
+	}
+
+	if r.Header.Get("Authorization") == "" {
+		writeError(rec, http.StatusUnauthorized, "PERMISSION_DENIED", "The requested resource cannot be accessed using the provided authentication details.")
+		return
+	}
+
+	if len(segments) < 2 {
+		writeNotFound(rec, "route")
+		return
+	}
+
+	version, resource, rest := segments[0], segments[1], segments[2:]
+
+	switch {
+	case version == "v4" && resource == "auth-tokens":
+		s.deleteAuthToken(rec, r)
+	case version == "v4" && resource == "info":
+		s.getInfo(rec, r)
+	case version == "v4" && resource == "releases":
+		s.getReleases(rec, r)
+	case version == "v4" && resource == "organizations":
+		s.routeOrganizations(rec, r, rest)
+	case version == "v4" && resource == "clusters":
+		s.routeClustersV4(rec, r, rest)
+	case version == "v5" && resource == "clusters":
+		s.routeClustersV5(rec, r, rest)
+	default:
+		writeNotFound(rec, "route")
+	}
+}
+
+func (s *Server) createAuthToken(w http.ResponseWriter, r *http.Request) {
+	var body models.V4CreateAuthTokenRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+	if body.Email == "" || body.PasswordBase64 == "" {
+		writeError(w, http.StatusUnauthorized, "PERMISSION_DENIED", "Email or password incorrect.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &models.V4CreateAuthTokenResponse{AuthToken: s.randomID(32)})
+}
+
+func (s *Server) deleteAuthToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &models.V4GenericResponse{Code: "RESOURCE_DELETED", Message: "The authentication token has been successfully deleted."})
+}
+
+func (s *Server) getInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	zones := s.availabilityZones()
+	defaultZones := int64(1)
+	maxZones := int64(len(zones))
+
+	info := &models.V4InfoResponse{
+		General: &models.V4InfoResponseGeneral{
+			AvailabilityZones: &models.V4InfoResponseGeneralAvailabilityZones{
+				Default: &defaultZones,
+				Max:     &maxZones,
+				Zones:   zones,
+			},
+			InstallationName: s.installationName,
+			Provider:         s.provider,
+		},
+		Features: &models.V4InfoResponseFeatures{
+			HaMasters: &models.V4InfoResponseFeaturesHaMasters{ReleaseVersionMinimum: HAMastersReleaseVersionMinimum},
+			Nodepools: &models.V4InfoResponseFeaturesNodepools{ReleaseVersionMinimum: NodePoolsReleaseVersionMinimum},
+		},
+		Workers: &models.V4InfoResponseWorkers{
+			CountPerCluster: &models.V4InfoResponseWorkersCountPerCluster{Default: 3, Max: 20},
+		},
+	}
+
+	switch s.provider {
+	case "aws":
+		info.Workers.InstanceType = &models.V4InfoResponseWorkersInstanceType{
+			Default: s.defaultInstanceType(),
+			Options: []string{"m5.large", "m5.xlarge", "m5.2xlarge"},
+		}
+	case "azure":
+		info.Workers.VMSize = &models.V4InfoResponseWorkersVMSize{
+			Default: s.defaultInstanceType(),
+			Options: []string{"Standard_D4s_v3", "Standard_D8s_v3"},
+		}
+	}
+
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *Server) getReleases(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.releases)
+}
+
+// availabilityZones returns the zones of the emulated region.
+func (s *Server) availabilityZones() []string {
+	switch s.provider {
+	case "aws":
+		return []string{"eu-central-1a", "eu-central-1b", "eu-central-1c"}
+	case "azure":
+		return []string{"1", "2", "3"}
+	}
+
+	return []string{"default"}
+}
+
+// defaultInstanceType returns the default worker instance type or VM size.
+func (s *Server) defaultInstanceType() string {
+	switch s.provider {
+	case "aws":
+		return "m5.xlarge"
+	case "azure":
+		return "Standard_D4s_v3"
+	}
+
+	return ""
+}
+
+// latestRelease returns the version of the newest active release.
+func (s *Server) latestRelease() string {
+	var versions []string
+	for _, r := range s.releases {
+		if r.Active && r.Version != nil {
+			versions = append(versions, *r.Version)
+		}
+	}
+	if len(versions) == 0 {
+		return ""
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return util.VersionSortComp(versions[i], versions[j])
+	})
+
+	return versions[len(versions)-1]
+}
+
+// hasRelease returns true if the given release version exists.
+func (s *Server) hasRelease(version string) bool {
+	for _, r := range s.releases {
+		if r.Version != nil && *r.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasOrganization returns true if the given organization exists.
+func (s *Server) hasOrganization(id string) bool {
+	for _, o := range s.organizations {
+		if o == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// randomID returns a random lowercase alphanumeric string of length n,
+// starting with a letter.
+func (s *Server) randomID(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		chars := idCharacters
+		if i == 0 {
+			chars = idCharacters[:26]
+		}
+		b[i] = chars[s.random.Intn(len(chars))]
+	}
+
+	return string(b)
+}
+
+// statusRecorder remembers the status code written, for logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func readBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The request body could not be parsed: %s", err.Error()))
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, &models.V4GenericResponse{Code: code, Message: message})
+}
+
+func writeNotFound(w http.ResponseWriter, kind string) {
+	writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", fmt.Sprintf("The %s could not be found.", kind))
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "The method is not supported for this resource.")
+}
+
+func nowString() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,286 @@
+package fakeapi
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+
+	"github.com/giantswarm/gsctl/client"
+	"github.com/giantswarm/gsctl/client/clienterror"
+	"github.com/giantswarm/gsctl/pkg/wait"
+)
+
+func newClient(t *testing.T, url string) *client.Wrapper {
+	clientWrapper, err := client.New(&client.Configuration{
+		Endpoint: url,
+		AuthHeaderGetter: func() (string, error) {
+			return "giantswarm test-token", nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return clientWrapper
+}
+
+// TestClusterLifecycleV5 creates, scales, upgrades and deletes a cluster
+// with node pools.
+func TestClusterLifecycleV5(t *testing.T) {
+	ts := httptest.NewServer(New(Config{}))
+	defer ts.Close()
+
+	clientWrapper := newClient(t, ts.URL)
+
+	created, err := clientWrapper.CreateClusterV5(&models.V5AddClusterRequest{
+		Name:           "My cluster",
+		Owner:          stringPtr(DefaultOrganization),
+		ReleaseVersion: "11.5.0",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterID := created.Payload.ID
+
+	waiter, err := wait.New(wait.Config{ClientWrapper: clientWrapper, Interval: time.Millisecond, Output: ioutil.Discard, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = waiter.ClusterCreated(clusterID)
+	if err != nil {
+		t.Fatalf("Unexpected error waiting for creation: %#v", err)
+	}
+
+	nodePool, err := clientWrapper.CreateNodePool(clusterID, &models.V5AddNodePoolRequest{
+		Name:    "workers",
+		Scaling: &models.V5AddNodePoolRequestScaling{Min: int64Ptr(2), Max: 5},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodePool.Payload.Status.NodesReady != 2 {
+		t.Errorf("Expected 2 nodes ready, got %d", nodePool.Payload.Status.NodesReady)
+	}
+
+	_, err = clientWrapper.ModifyNodePool(clusterID, nodePool.Payload.ID, &models.V5ModifyNodePoolRequest{
+		Scaling: &models.V5ModifyNodePoolRequestScaling{Min: int64Ptr(4), Max: 6},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = waiter.NodePoolReady(clusterID, nodePool.Payload.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error waiting for node pool: %#v", err)
+	}
+
+	details, err := clientWrapper.GetClusterV5(clusterID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastUpdated := wait.LastUpdated(details.Payload)
+	_, err = clientWrapper.ModifyClusterV5(clusterID, &models.V5ModifyClusterRequest{ReleaseVersion: "12.1.0"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = waiter.ClusterUpgraded(clusterID, "12.1.0", lastUpdated)
+	if err != nil {
+		t.Fatalf("Unexpected error waiting for upgrade: %#v", err)
+	}
+
+	_, err = clientWrapper.DeleteCluster(clusterID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = waiter.ClusterDeleted(clusterID)
+	if err != nil {
+		t.Fatalf("Unexpected error waiting for deletion: %#v", err)
+	}
+
+	list, err := clientWrapper.GetClusters(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Payload) != 0 {
+		t.Errorf("Expected no clusters, got %d", len(list.Payload))
+	}
+}
+
+// TestClusterLifecycleV4 creates, scales, upgrades and deletes a cluster
+// without node pools.
+func TestClusterLifecycleV4(t *testing.T) {
+	ts := httptest.NewServer(New(Config{}))
+	defer ts.Close()
+
+	clientWrapper := newClient(t, ts.URL)
+
+	created, err := clientWrapper.CreateClusterV4(&models.V4AddClusterRequest{
+		Owner:          stringPtr(DefaultOrganization),
+		ReleaseVersion: "9.3.0",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segments := strings.Split(strings.Trim(created.Location, "/"), "/")
+	clusterID := segments[len(segments)-1]
+
+	waiter, err := wait.New(wait.Config{ClientWrapper: clientWrapper, Interval: time.Millisecond, Output: ioutil.Discard, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = waiter.ClusterCreated(clusterID)
+	if err != nil {
+		t.Fatalf("Unexpected error waiting for creation: %#v", err)
+	}
+
+	_, err = clientWrapper.ModifyClusterV4(clusterID, &models.V4ModifyClusterRequest{
+		Scaling: &models.V4ModifyClusterRequestScaling{Min: int64Ptr(5), Max: 5},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = waiter.ClusterScaled(clusterID, 5, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error waiting for scaling: %#v", err)
+	}
+
+	_, err = clientWrapper.ModifyClusterV4(clusterID, &models.V4ModifyClusterRequest{ReleaseVersion: "11.5.0"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = waiter.ClusterUpgraded(clusterID, "11.5.0", time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error waiting for upgrade: %#v", err)
+	}
+
+	_, err = clientWrapper.DeleteCluster(clusterID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = clientWrapper.GetClusterV4(clusterID, nil)
+	if !clienterror.IsNotFoundError(err) {
+		t.Errorf("Expected not found error, got %#v", err)
+	}
+}
+
+// TestErrors checks error responses.
+func TestErrors(t *testing.T) {
+	ts := httptest.NewServer(New(Config{}))
+	defer ts.Close()
+
+	clientWrapper := newClient(t, ts.URL)
+
+	// The client has no specific handling for these 400 responses, so we
+	// check the message.
+	_, err := clientWrapper.CreateClusterV5(&models.V5AddClusterRequest{Owner: stringPtr("unknown")}, nil)
+	if err == nil || !strings.Contains(err.Error(), "The organization 'unknown' does not exist.") {
+		t.Errorf("Expected error for unknown owner, got %#v", err)
+	}
+
+	_, err = clientWrapper.CreateClusterV4(&models.V4AddClusterRequest{Owner: stringPtr(DefaultOrganization), ReleaseVersion: "12.1.0"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "must be created using the v5 API") {
+		t.Errorf("Expected error for v4 cluster with node pools release, got %#v", err)
+	}
+
+	_, err = clientWrapper.GetClusterV5("nope", nil)
+	if !clienterror.IsNotFoundError(err) {
+		t.Errorf("Expected not found error, got %#v", err)
+	}
+
+	// Requests without authorization are rejected.
+	resp, err := http.Get(ts.URL + "/v4/clusters/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", resp.StatusCode)
+	}
+}
+
+// TestKeyPairsAppsLabels checks key pair creation, apps and labels.
+func TestKeyPairsAppsLabels(t *testing.T) {
+	server := New(Config{})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	clusterID := server.AddClusterV5(&models.V5ClusterDetailsResponse{Owner: DefaultOrganization, ReleaseVersion: "12.1.0"})
+	clientWrapper := newClient(t, ts.URL)
+
+	keyPair, err := clientWrapper.CreateKeyPair(clusterID, &models.V4AddKeyPairRequest{
+		Description:              stringPtr("test"),
+		CertificateOrganizations: "system:masters",
+		TTLHours:                 2,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode([]byte(keyPair.Payload.ClientCertificateData))
+	if block == nil {
+		t.Fatal("Expected PEM encoded client certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Subject.Organization[0] != "system:masters" {
+		t.Errorf("Unexpected certificate organizations %v", cert.Subject.Organization)
+	}
+	if time.Until(cert.NotAfter) > 2*time.Hour {
+		t.Errorf("Unexpected certificate expiry %s", cert.NotAfter)
+	}
+
+	keyPairs, err := clientWrapper.GetKeyPairs(clusterID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keyPairs.Payload) != 1 || keyPairs.Payload[0].ID != keyPair.Payload.ID {
+		t.Errorf("Unexpected key pairs %#v", keyPairs.Payload)
+	}
+
+	_, err = clientWrapper.CreateApp(clusterID, "my-app", &models.V4CreateAppRequest{
+		Spec: &models.V4CreateAppRequestSpec{
+			Catalog:   stringPtr("giantswarm"),
+			Name:      stringPtr("nginx-ingress-controller-app"),
+			Namespace: stringPtr("kube-system"),
+			Version:   stringPtr("1.0.0"),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := clientWrapper.GetAppStatus(clusterID, "my-app", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != appStatusDeployed {
+		t.Errorf("Expected app status %q, got %q", appStatusDeployed, status)
+	}
+
+	_, err = clientWrapper.UpdateClusterLabels(clusterID, &models.V5SetClusterLabelsRequest{
+		Labels: map[string]*string{"environment": stringPtr("testing")},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := clientWrapper.GetClustersByLabel(&models.V5ListClustersByLabelRequest{Labels: stringPtr("environment=testing")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Payload) != 1 || list.Payload[0].ID != clusterID {
+		t.Errorf("Unexpected clusters by label %#v", list.Payload)
+	}
+	list, err = clientWrapper.GetClustersByLabel(&models.V5ListClustersByLabelRequest{Labels: stringPtr("environment=production")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Payload) != 0 {
+		t.Errorf("Expected no clusters by label, got %d", len(list.Payload))
+	}
+}
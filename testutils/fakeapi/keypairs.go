@@ -0,0 +1,187 @@
+package fakeapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+)
+
+// defaultKeyPairTTLHours is the key pair lifetime if none is requested.
+const defaultKeyPairTTLHours = 24
+
+// signer issues client certificates from a self-signed CA.
+type signer struct {
+	caCert    *x509.Certificate
+	caKey     *ecdsa.PrivateKey
+	caCertPEM string
+}
+
+func newSigner(installationName string) (*signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s fake CA", installationName)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signer{
+		caCert:    cert,
+		caKey:     key,
+		caCertPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}, nil
+}
+
+// issue creates a client certificate and key in PEM format, and an ID
+// derived from the certificate.
+func (s *signer) issue(commonName string, organizations []string, ttl time.Duration) (string, string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: organizations},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, s.caCert, &key.PublicKey, s.caKey)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	sum := sha1.Sum(der)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	return strings.Join(parts, ":"), certPEM, keyPEM, nil
+}
+
+func (s *Server) routeKeyPairs(w http.ResponseWriter, r *http.Request, c *cluster, path []string) {
+	if len(path) > 0 {
+		writeNotFound(w, "route")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if c.keyPairs == nil {
+			writeJSON(w, http.StatusOK, []*models.V4GetKeyPairsResponseItems{})
+			return
+		}
+		writeJSON(w, http.StatusOK, c.keyPairs)
+	case http.MethodPost:
+		s.createKeyPair(w, r, c)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) createKeyPair(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var body models.V4AddKeyPairRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+	if body.Description == nil || *body.Description == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "The key pair description must not be empty.")
+		return
+	}
+
+	if s.signer == nil {
+		signer, err := newSigner(s.installationName)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+			return
+		}
+		s.signer = signer
+	}
+
+	ttlHours := int64(body.TTLHours)
+	if ttlHours <= 0 {
+		ttlHours = defaultKeyPairTTLHours
+	}
+
+	commonName := fmt.Sprintf("user.api.%s.k8s.fake.example.com", c.id)
+	if body.CnPrefix != "" {
+		commonName = body.CnPrefix + "." + commonName
+	}
+
+	var organizations []string
+	for _, o := range strings.Split(body.CertificateOrganizations, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			organizations = append(organizations, o)
+		}
+	}
+
+	id, certPEM, keyPEM, err := s.signer.issue(commonName, organizations, time.Duration(ttlHours)*time.Hour)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+
+	now := nowString()
+	c.keyPairs = append(c.keyPairs, &models.V4GetKeyPairsResponseItems{
+		CertificateOrganizations: body.CertificateOrganizations,
+		CommonName:               commonName,
+		CreateDate:               now,
+		Description:              *body.Description,
+		ID:                       id,
+		TTLHours:                 ttlHours,
+	})
+
+	writeJSON(w, http.StatusOK, &models.V4AddKeyPairResponse{
+		CertificateAuthorityData: s.signer.caCertPEM,
+		ClientCertificateData:    certPEM,
+		ClientKeyData:            keyPEM,
+		CreateDate:               now,
+		Description:              *body.Description,
+		ID:                       id,
+		TTLHours:                 ttlHours,
+	})
+}
@@ -0,0 +1,37 @@
+package fakeapi
+
+import (
+	"net/http"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+)
+
+func (s *Server) routeLabels(w http.ResponseWriter, r *http.Request, c *cluster) {
+	if !c.nodePools {
+		writeNotFound(w, "cluster")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, &models.V5ClusterLabelsResponse{Labels: c.labels})
+	case http.MethodPut:
+		var body models.V5SetClusterLabelsRequest
+		if !readBody(w, r, &body) {
+			return
+		}
+
+		// A null value removes the label.
+		for k, v := range body.Labels {
+			if v == nil {
+				delete(c.labels, k)
+			} else {
+				c.labels[k] = *v
+			}
+		}
+
+		writeJSON(w, http.StatusOK, &models.V5ClusterLabelsResponse{Labels: c.labels})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
@@ -0,0 +1,186 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+)
+
+// AddNodePool adds a node pool to the cluster with the given ID. A missing
+// node pool ID is filled in. The node pool ID is returned, or an empty
+// string if the cluster does not exist.
+func (s *Server) AddNodePool(clusterID string, nodePool *models.V5GetNodePoolResponse) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c, ok := s.clusters[clusterID]
+	if !ok {
+		return ""
+	}
+
+	if nodePool.ID == "" {
+		nodePool.ID = s.randomID(4)
+	}
+	c.nodePoolList = append(c.nodePoolList, nodePool)
+
+	return nodePool.ID
+}
+
+func (s *Server) routeNodePools(w http.ResponseWriter, r *http.Request, c *cluster, path []string) {
+	if !c.nodePools {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "This cluster does not support node pools.")
+		return
+	}
+
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			if c.nodePoolList == nil {
+				writeJSON(w, http.StatusOK, []*models.V5GetNodePoolResponse{})
+				return
+			}
+			writeJSON(w, http.StatusOK, c.nodePoolList)
+		case http.MethodPost:
+			s.createNodePool(w, r, c)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	index := -1
+	for i, np := range c.nodePoolList {
+		if np.ID == path[0] {
+			index = i
+		}
+	}
+	if index < 0 {
+		writeNotFound(w, "node pool")
+		return
+	}
+	np := c.nodePoolList[index]
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, np)
+	case http.MethodPatch:
+		s.modifyNodePool(w, r, np)
+	case http.MethodDelete:
+		c.nodePoolList = append(c.nodePoolList[:index], c.nodePoolList[index+1:]...)
+		writeJSON(w, http.StatusAccepted, &models.V4GenericResponse{Code: "RESOURCE_DELETION_STARTED", Message: fmt.Sprintf("The node pool with ID '%s' is being deleted.", np.ID)})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) createNodePool(w http.ResponseWriter, r *http.Request, c *cluster) {
+	var body models.V5AddNodePoolRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	np := &models.V5GetNodePoolResponse{
+		ID:   s.randomID(4),
+		Name: body.Name,
+		NodeSpec: &models.V5GetNodePoolResponseNodeSpec{
+			VolumeSizesGb: &models.V5GetNodePoolResponseNodeSpecVolumeSizesGb{Docker: 100, Kubelet: 100},
+		},
+		Scaling: &models.V5GetNodePoolResponseScaling{Min: int64Ptr(3), Max: 10},
+		Subnet:  fmt.Sprintf("10.1.%d.0/24", len(c.nodePoolList)),
+	}
+	if np.Name == "" {
+		np.Name = "Unnamed node pool"
+	}
+
+	if body.Scaling != nil {
+		if body.Scaling.Min != nil {
+			np.Scaling.Min = body.Scaling.Min
+		}
+		if body.Scaling.Max > 0 {
+			np.Scaling.Max = body.Scaling.Max
+		}
+	}
+	if *np.Scaling.Min > np.Scaling.Max {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "The minimum number of nodes must not be greater than the maximum.")
+		return
+	}
+
+	zones := s.availabilityZones()
+	np.AvailabilityZones = zones[:1]
+	if body.AvailabilityZones != nil {
+		if len(body.AvailabilityZones.Zones) > 0 {
+			np.AvailabilityZones = body.AvailabilityZones.Zones
+		} else if body.AvailabilityZones.Number > 0 && int(body.AvailabilityZones.Number) <= len(zones) {
+			np.AvailabilityZones = zones[:body.AvailabilityZones.Number]
+		}
+	}
+
+	instanceType := s.defaultInstanceType()
+	if s.provider == "aws" {
+		np.NodeSpec.Aws = &models.V5GetNodePoolResponseNodeSpecAws{InstanceType: instanceType}
+		if body.NodeSpec != nil && body.NodeSpec.Aws != nil {
+			if body.NodeSpec.Aws.InstanceType != "" {
+				instanceType = body.NodeSpec.Aws.InstanceType
+				np.NodeSpec.Aws.InstanceType = instanceType
+			}
+			if body.NodeSpec.Aws.UseAlikeInstanceTypes != nil {
+				np.NodeSpec.Aws.UseAlikeInstanceTypes = *body.NodeSpec.Aws.UseAlikeInstanceTypes
+			}
+		}
+	} else if s.provider == "azure" {
+		np.NodeSpec.Azure = &models.V5GetNodePoolResponseNodeSpecAzure{VMSize: instanceType}
+		if body.NodeSpec != nil && body.NodeSpec.Azure != nil && body.NodeSpec.Azure.VMSize != "" {
+			instanceType = body.NodeSpec.Azure.VMSize
+			np.NodeSpec.Azure.VMSize = instanceType
+		}
+	}
+
+	np.Status = &models.V5GetNodePoolResponseStatus{InstanceTypes: []string{instanceType}}
+	setNodePoolNodes(np)
+
+	c.nodePoolList = append(c.nodePoolList, np)
+
+	w.Header().Set("Location", fmt.Sprintf("/v5/clusters/%s/nodepools/%s/", c.id, np.ID))
+	writeJSON(w, http.StatusCreated, np)
+}
+
+func (s *Server) modifyNodePool(w http.ResponseWriter, r *http.Request, np *models.V5GetNodePoolResponse) {
+	var body models.V5ModifyNodePoolRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	if body.Scaling != nil {
+		min, max := *np.Scaling.Min, np.Scaling.Max
+		if body.Scaling.Min != nil {
+			min = *body.Scaling.Min
+		}
+		if body.Scaling.Max > 0 {
+			max = body.Scaling.Max
+		}
+		if min > max {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "The minimum number of nodes must not be greater than the maximum.")
+			return
+		}
+		np.Scaling.Min = int64Ptr(min)
+		np.Scaling.Max = max
+		setNodePoolNodes(np)
+	}
+	if body.Name != "" {
+		np.Name = body.Name
+	}
+
+	writeJSON(w, http.StatusOK, np)
+}
+
+// setNodePoolNodes lets the node pool run the minimum number of nodes, all
+// of them ready.
+func setNodePoolNodes(np *models.V5GetNodePoolResponse) {
+	if np.Status == nil {
+		np.Status = &models.V5GetNodePoolResponseStatus{}
+	}
+
+	np.Status.Nodes = *np.Scaling.Min
+	np.Status.NodesReady = *np.Scaling.Min
+}
@@ -0,0 +1,119 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/giantswarm/gsclientgen/v2/models"
+)
+
+// AddOrganization adds an organization, unless it exists already.
+func (s *Server) AddOrganization(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.hasOrganization(id) {
+		s.organizations = append(s.organizations, id)
+	}
+}
+
+func (s *Server) routeOrganizations(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+
+		items := []*models.V4OrganizationListItem{}
+		for _, id := range s.organizations {
+			items = append(items, &models.V4OrganizationListItem{ID: id})
+		}
+		writeJSON(w, http.StatusOK, items)
+		return
+	}
+
+	orgID := path[0]
+	if !s.hasOrganization(orgID) {
+		writeNotFound(w, "organization")
+		return
+	}
+
+	if len(path) < 2 || path[1] != "credentials" {
+		writeNotFound(w, "route")
+		return
+	}
+
+	if len(path) == 2 {
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w)
+			return
+		}
+		s.addCredentials(w, r, orgID)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+	for _, credential := range s.credentials[orgID] {
+		if credential.ID == path[2] {
+			writeJSON(w, http.StatusOK, credential)
+			return
+		}
+	}
+
+	writeNotFound(w, "credential")
+}
+
+func (s *Server) addCredentials(w http.ResponseWriter, r *http.Request, orgID string) {
+	var body models.V4AddCredentialsRequest
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	if len(s.credentials[orgID]) > 0 {
+		writeError(w, http.StatusConflict, "RESOURCE_ALREADY_EXISTS", fmt.Sprintf("The organization '%s' already has credentials.", orgID))
+		return
+	}
+	if body.Provider == nil || *body.Provider != s.provider {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", fmt.Sprintf("The credentials must be for provider '%s'.", s.provider))
+		return
+	}
+
+	credential := &models.V4GetCredentialResponse{
+		ID:       s.randomID(6),
+		Provider: s.provider,
+	}
+	if body.Aws != nil && body.Aws.Roles != nil {
+		credential.Aws = &models.V4GetCredentialResponseAws{Roles: &models.V4GetCredentialResponseAwsRoles{}}
+		if body.Aws.Roles.Admin != nil {
+			credential.Aws.Roles.Admin = *body.Aws.Roles.Admin
+		}
+		if body.Aws.Roles.Awsoperator != nil {
+			credential.Aws.Roles.Awsoperator = *body.Aws.Roles.Awsoperator
+		}
+	}
+	if body.Azure != nil && body.Azure.Credential != nil {
+		credential.Azure = &models.V4GetCredentialResponseAzure{
+			Credential: &models.V4GetCredentialResponseAzureCredential{
+				ClientID:       stringValue(body.Azure.Credential.ClientID),
+				SubscriptionID: stringValue(body.Azure.Credential.SubscriptionID),
+				TenantID:       stringValue(body.Azure.Credential.TenantID),
+			},
+		}
+	}
+
+	s.credentials[orgID] = append(s.credentials[orgID], credential)
+
+	w.Header().Set("Location", fmt.Sprintf("/v4/organizations/%s/credentials/%s/", orgID, credential.ID))
+	writeJSON(w, http.StatusCreated, &models.V4GenericResponse{Code: "RESOURCE_CREATED", Message: "The credentials have been created."})
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,325 @@
+// Package fakeoidc provides a fake OpenID Connect identity provider
+// supporting the OAuth2 Device Authorization Grant, so that the device
+// flow used by 'gsctl login --sso --device' can be tested without a real
+// identity provider.
+//
+// The issuer URL is derived from the Host header of each request, so the
+// server can be used with httptest.NewServer without further setup. Tokens
+// are JWTs signed with an RSA key generated on creation and published via
+// the JWKS endpoint.
+package fakeoidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/gscliauth/oidc"
+)
+
+const (
+	// DefaultClientID is the client ID accepted if none is configured.
+	DefaultClientID = "fake-client-id"
+
+	// DefaultEmail is the email address of the user signing in if none is
+	// configured.
+	DefaultEmail = "user@example.com"
+
+	// DefaultUserCode is the user code issued if none is configured.
+	DefaultUserCode = "WDJB-MJHT"
+
+	// DeviceAuthorizationPath is the path of the device authorization
+	// endpoint.
+	DeviceAuthorizationPath = "/oauth/device/code"
+
+	// TokenPath is the path of the token endpoint.
+	TokenPath = "/oauth/token"
+
+	// JWKSPath is the path of the JWKS endpoint.
+	JWKSPath = "/.well-known/jwks.json"
+
+	// VerificationPath is the path of the verification URI shown to users.
+	VerificationPath = "/activate"
+
+	keyID = "fake-key"
+)
+
+// Config is the configuration for a fake identity provider.
+type Config struct {
+	// ClientID is the only client ID accepted. Defaults to DefaultClientID.
+	ClientID string
+
+	// Email is the email claim of the ID tokens issued. Defaults to
+	// DefaultEmail.
+	Email string
+
+	// UserCode is the user code issued. Defaults to DefaultUserCode.
+	UserCode string
+
+	// PendingPolls is the number of token requests answered with
+	// 'authorization_pending' before the user is considered to have
+	// approved the request.
+	PendingPolls int
+
+	// Deny makes the user deny the request instead of approving it.
+	Deny bool
+
+	// Interval is the polling interval in seconds returned to clients.
+	// Defaults to 5.
+	Interval int
+
+	// TokenTTL is the lifetime of the tokens issued. Defaults to one hour.
+	TokenTTL time.Duration
+}
+
+// Server is a fake identity provider. It implements http.Handler.
+type Server struct {
+	clientID     string
+	email        string
+	userCode     string
+	pendingPolls int
+	deny         bool
+	interval     int
+	tokenTTL     time.Duration
+	key          *rsa.PrivateKey
+
+	// mutex guards all fields below.
+	mutex       sync.Mutex
+	deviceCodes map[string]string
+	polls       int
+}
+
+// New creates a new fake identity provider.
+func New(config Config) (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		clientID:     config.ClientID,
+		email:        config.Email,
+		userCode:     config.UserCode,
+		pendingPolls: config.PendingPolls,
+		deny:         config.Deny,
+		interval:     config.Interval,
+		tokenTTL:     config.TokenTTL,
+		key:          key,
+		deviceCodes:  map[string]string{},
+	}
+
+	if s.clientID == "" {
+		s.clientID = DefaultClientID
+	}
+	if s.email == "" {
+		s.email = DefaultEmail
+	}
+	if s.userCode == "" {
+		s.userCode = DefaultUserCode
+	}
+	if s.interval == 0 {
+		s.interval = 5
+	}
+	if s.tokenTTL == 0 {
+		s.tokenTTL = time.Hour
+	}
+
+	return s, nil
+}
+
+// Polls returns the number of token requests received for device codes.
+func (s *Server) Polls() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.polls
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	issuer := "http://" + r.Host
+
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"issuer":                        issuer + "/",
+			"device_authorization_endpoint": issuer + DeviceAuthorizationPath,
+			"token_endpoint":                issuer + TokenPath,
+			"jwks_uri":                      issuer + JWKSPath,
+			"grant_types_supported":         []string{"urn:ietf:params:oauth:grant-type:device_code", "refresh_token"},
+		})
+	case JWKSPath:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": keyID,
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+			}},
+		})
+	case DeviceAuthorizationPath:
+		s.deviceAuthorization(w, r, issuer)
+	case TokenPath:
+		s.token(w, r, issuer)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not_found"})
+	}
+}
+
+func (s *Server) deviceAuthorization(w http.ResponseWriter, r *http.Request, issuer string) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostFormValue("client_id") != s.clientID {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mutex.Lock()
+	deviceCode := fmt.Sprintf("device-code-%d", len(s.deviceCodes)+1)
+	s.deviceCodes[deviceCode] = r.PostFormValue("audience")
+	s.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":               deviceCode,
+		"user_code":                 s.userCode,
+		"verification_uri":          issuer + VerificationPath,
+		"verification_uri_complete": issuer + VerificationPath + "?user_code=" + s.userCode,
+		"expires_in":                900,
+		"interval":                  s.interval,
+	})
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request, issuer string) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostFormValue("client_id") != s.clientID {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostFormValue("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	s.mutex.Lock()
+	audience, ok := s.deviceCodes[r.PostFormValue("device_code")]
+	s.polls++
+	polls := s.polls
+	s.mutex.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if polls <= s.pendingPolls {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+		return
+	}
+	if s.deny {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "access_denied", "error_description": "User denied the request"})
+		return
+	}
+
+	now := time.Now()
+	expiry := now.Add(s.tokenTTL)
+
+	accessToken := s.sign(map[string]interface{}{
+		"iss": issuer + "/",
+		"sub": "fake|" + s.email,
+		"aud": audience,
+		"iat": now.Unix(),
+		"exp": expiry.Unix(),
+	})
+	idToken := s.sign(map[string]interface{}{
+		"iss":   issuer + "/",
+		"sub":   "fake|" + s.email,
+		"aud":   s.clientID,
+		"iat":   now.Unix(),
+		"exp":   expiry.Unix(),
+		"email": s.email,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  accessToken,
+		"id_token":      idToken,
+		"refresh_token": fmt.Sprintf("refresh-token-%d", polls),
+		"token_type":    "Bearer",
+		"expires_in":    int(s.tokenTTL.Seconds()),
+	})
+}
+
+// ParseIDToken verifies the signature, audience and expiry of an ID token
+// issued by this server and returns its email claim, like oidc.ParseIDToken
+// does for tokens issued by Giant Swarm's identity provider.
+func (s *Server) ParseIDToken(rawToken string) (*oidc.IDToken, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("not a JWT")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(&s.key.PublicKey, crypto.SHA256, digest[:], signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	claims := struct {
+		Audience string `json:"aud"`
+		Expiry   int64  `json:"exp"`
+		Email    string `json:"email"`
+	}{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Audience != s.clientID {
+		return nil, fmt.Errorf("not issued for client %q", s.clientID)
+	}
+	if time.Now().After(time.Unix(claims.Expiry, 0)) {
+		return nil, fmt.Errorf("expired")
+	}
+
+	return &oidc.IDToken{Email: claims.Email}, nil
+}
+
+// sign returns an RS256 signed JWT with the given claims.
+func (s *Server) sign(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyID})
+	payload, _ := json.Marshal(claims)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -2,11 +2,22 @@ package testutils
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"path"
 	"strings"
+	"testing"
+	"time"
 	"unicode"
 
 	"github.com/giantswarm/gscliauth/config"
@@ -134,3 +145,51 @@ func TempClusterCache(fs afero.Fs, cacheYAML string) (string, error) {
 func Int64Value(x int64) *int64 {
 	return &x
 }
+
+// ClientCertificate returns a self-signed client certificate for user
+// 'user.test' in group 'system:masters', valid from notBefore to notAfter,
+// and its private key, both PEM encoded.
+func ClientCertificate(t testing.TB, notBefore, notAfter time.Time) (certPEM, keyPEM string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "user.test", Organization: []string{"system:masters"}},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	return certPEM, keyPEM
+}
+
+// CertificatePEM returns a PEM encoded client certificate valid until
+// notAfter, see ClientCertificate.
+func CertificatePEM(t testing.TB, notAfter time.Time) string {
+	t.Helper()
+
+	certPEM, _ := ClientCertificate(t, notAfter.Add(-time.Hour), notAfter)
+	return certPEM
+}
+
+// JWT returns an unsigned JWT expiring at the given time, like the access
+// tokens obtained via SSO.
+func JWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp": %d}`, exp.Unix())))
+	return "eyJhbGciOiJub25lIn0." + payload + ".signature"
+}
@@ -1,8 +1,13 @@
 package testutils
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"testing"
+	"time"
+
+	"github.com/giantswarm/gsctl/pkg/authtoken"
 )
 
 func TestCaptureOutput(t *testing.T) {
@@ -46,3 +51,36 @@ func TestInt64ValueZero(t *testing.T) {
 		t.Errorf("Expected %v, got %v", input, output)
 	}
 }
+
+func TestClientCertificate(t *testing.T) {
+	notBefore := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.Add(12 * time.Hour)
+
+	certPEM, keyPEM := ClientCertificate(t, notBefore, notAfter)
+
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		t.Fatalf("Expected PEM encoded certificate, got %q", certPEM)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cert.NotBefore.Equal(notBefore) || !cert.NotAfter.Equal(notAfter) {
+		t.Errorf("Expected validity %v - %v, got %v - %v", notBefore, notAfter, cert.NotBefore, cert.NotAfter)
+	}
+
+	block, _ = pem.Decode([]byte(keyPEM))
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		t.Errorf("Expected PEM encoded EC private key, got %q", keyPEM)
+	}
+}
+
+func TestJWT(t *testing.T) {
+	input := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	output, ok := authtoken.Expiry(JWT(input))
+	if !ok || !output.Equal(input) {
+		t.Errorf("Expected %v, got %v", input, output)
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/giantswarm/microerror"
 	"github.com/spf13/afero"
 
 	"github.com/giantswarm/gsctl/formatting"
@@ -49,3 +52,36 @@ func StoreClientKey(fs afero.Fs, certsDirPath, clusterID, keyPairID, data string
 	fileName := clusterID + "-" + formatting.CleanKeypairID(keyPairID)[:10] + "-client.key"
 	return writeCredentialFile(fs, certsDirPath, fileName, data)
 }
+
+// CredentialFiles returns the paths of all certificate and key files stored
+// by the functions above, sorted. If clusterID is not empty, only the files
+// for that cluster are returned.
+func CredentialFiles(fs afero.Fs, certsDirPath, clusterID string) ([]string, error) {
+	infos, err := afero.ReadDir(fs, certsDirPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var paths []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !(strings.HasSuffix(name, ".crt") || strings.HasSuffix(name, ".key")) {
+			continue
+		}
+		if clusterID != "" && CredentialFileClusterID(name) != clusterID {
+			continue
+		}
+		paths = append(paths, path.Join(certsDirPath, name))
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// CredentialFileClusterID returns the cluster ID from the name of a file
+// stored by the functions above.
+func CredentialFileClusterID(filePath string) string {
+	return strings.SplitN(path.Base(filePath), "-", 2)[0]
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+)
+
+// TestCredentialFiles tests listing stored credential files.
+func TestCredentialFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	paths, err := CredentialFiles(fs, "/certs", "")
+	if err != nil || len(paths) != 0 {
+		t.Errorf("Expected no files and no error for a missing directory, got %v, %#v", paths, err)
+	}
+
+	StoreCaCertificate(fs, "/certs", "abc12", "ca")
+	StoreClientCertificate(fs, "/certs", "abc12", "a1:b2:c3:d4:e5:f6", "cert")
+	StoreClientKey(fs, "/certs", "abc12", "a1:b2:c3:d4:e5:f6", "key")
+	StoreCaCertificate(fs, "/certs", "def34", "ca")
+	_ = afero.WriteFile(fs, "/certs/README", []byte{}, 0600)
+
+	paths, err = CredentialFiles(fs, "/certs", "")
+	if err != nil {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	expected := []string{
+		"/certs/abc12-a1b2c3d4e5-client.crt",
+		"/certs/abc12-a1b2c3d4e5-client.key",
+		"/certs/abc12-ca.crt",
+		"/certs/def34-ca.crt",
+	}
+	if diff := cmp.Diff(expected, paths); diff != "" {
+		t.Errorf("Paths not as expected: (-want +got):\n%s", diff)
+	}
+
+	paths, _ = CredentialFiles(fs, "/certs", "def34")
+	if diff := cmp.Diff([]string{"/certs/def34-ca.crt"}, paths); diff != "" {
+		t.Errorf("Paths not as expected: (-want +got):\n%s", diff)
+	}
+}
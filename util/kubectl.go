@@ -1,7 +1,5 @@
 package util
 
-// TODO: not sure how the Kubectl wrapper functions deal with whitespace in arguments.
-
 import (
 	"os/exec"
 	"syscall"
@@ -30,51 +28,3 @@ func CheckKubectl() bool {
 	}
 	return true
 }
-
-// KubectlSetCluster is a wrapper for the `kubectl config set-cluster` command
-func KubectlSetCluster(clusterID, apiEndpoint, caCertificatePath string) error {
-	clusterName := "giantswarm-" + clusterID
-	serverArgument := "--server=" + apiEndpoint
-	certificateAuthorityArgument := "--certificate-authority=" + caCertificatePath
-	cmd := exec.Command(binaryName,
-		"config", "set-cluster",
-		clusterName,
-		serverArgument,
-		certificateAuthorityArgument)
-	err := cmd.Run()
-	return err
-}
-
-// KubectlSetCredentials is a wrapper for the `kubectl config set-credentials` command
-func KubectlSetCredentials(clusterID, keyPath, certificatePath string) error {
-	clientKeyArgument := "--client-key=" + keyPath
-	clientCertificateArgument := "--client-certificate=" + certificatePath
-	userName := "giantswarm-" + clusterID + "-user"
-	cmd := exec.Command(binaryName,
-		"config", "set-credentials",
-		userName,
-		clientKeyArgument,
-		clientCertificateArgument)
-	err := cmd.Run()
-	return err
-}
-
-// KubectlSetContext is a wrapper for the `kubectl config set-context` command
-func KubectlSetContext(contextName, clusterID string) error {
-	clusterArgument := "--cluster=giantswarm-" + clusterID
-	userArgument := "--user=giantswarm-" + clusterID + "-user"
-	cmd := exec.Command(binaryName,
-		"config", "set-context",
-		contextName,
-		clusterArgument,
-		userArgument)
-	err := cmd.Run()
-	return err
-}
-
-// KubectlUseContext applies the context for the given cluster ID
-func KubectlUseContext(contextName string) error {
-	cmd := exec.Command(binaryName, "config", "use-context", contextName)
-	err := cmd.Run()
-	return err
-}